@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/name"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const appVersion = "0.0.1"
+
+func main() {
+	nn, err := new()
+	if err != nil {
+		nn.app.FatalUsage("%s\n", err)
+	}
+
+	names := nn.args
+	if nn.ecosystem == "" {
+		if len(names) == 0 {
+			nn.app.FatalUsage("You must pass an ecosystem, either as the first argument or with --ecosystem.\n")
+		}
+		nn.ecosystem = names[0]
+		names = names[1:]
+	}
+	if !knownEcosystem(nn.ecosystem) {
+		nn.app.FatalUsage("Unknown ecosystem requested: %s\n", nn.ecosystem)
+	}
+	if nn.stdin && len(names) > 0 {
+		nn.app.FatalUsage("You cannot pass names as arguments when using --stdin.\n")
+	}
+	if !nn.stdin && len(names) == 0 {
+		nn.app.FatalUsage("You must pass one or more names to normalize, or use --stdin.\n")
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	failed := false
+	normalize := func(n string) {
+		if !nn.normalize(w, n) {
+			failed = true
+		}
+	}
+
+	if nn.stdin {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if n := strings.TrimSpace(scanner.Text()); n != "" {
+				normalize(n)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			log.Fatalf("Error reading names from stdin: %s", err)
+		}
+	} else {
+		for _, n := range names {
+			normalize(n)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		log.Fatalf("Error writing output: %s", err)
+	}
+	if failed && nn.strict {
+		os.Exit(1)
+	}
+}
+
+func knownEcosystem(eco string) bool {
+	for _, e := range name.Ecosystems() {
+		if e == eco {
+			return true
+		}
+	}
+	return false
+}
+
+type normalizename struct {
+	app       *kingpin.Application
+	ecosystem string
+	stdin     bool
+	json      bool
+	strict    bool
+	args      []string
+}
+
+type result struct {
+	Original   string `json:"original"`
+	Normalized string `json:"normalized"`
+	Ecosystem  string `json:"ecosystem"`
+	Error      string `json:"error,omitempty"`
+}
+
+// normalize writes the normalized form of n to w, and returns false if n is
+// not a valid name. Invalid names are still normalized, and the error is
+// written to stderr, or included in the output with --json.
+func (nn *normalizename) normalize(w io.Writer, n string) bool {
+	// The ecosystem has already been checked, so this can only fail for
+	// ecosystems that reject invalid names when normalizing, such as hex. The
+	// name is left as it is then, and Validate reports why below.
+	normalized, err := name.Normalize(nn.ecosystem, n)
+	if err != nil {
+		normalized = n
+	}
+	r := result{
+		Original:   n,
+		Normalized: normalized,
+		Ecosystem:  nn.ecosystem,
+	}
+	if err = name.Validate(nn.ecosystem, n); err != nil {
+		r.Error = err.Error()
+	}
+
+	if nn.json {
+		var j []byte
+		j, err = json.Marshal(r)
+		if err != nil {
+			log.Fatalf("Error marshalling %+v as JSON: %s", r, err)
+		}
+		fmt.Fprintln(w, string(j))
+	} else {
+		if r.Error != "" {
+			fmt.Fprintf(os.Stderr, "%s\n", r.Error)
+		}
+		fmt.Fprintln(w, r.Normalized)
+	}
+	return r.Error == ""
+}
+
+const extraDocs = `
+
+This command prints the normalized form of each package name it is given, one
+per line. The ecosystem is given as the first argument, or with --ecosystem.
+Names are read from the remaining arguments, or one per line from stdin with
+--stdin.
+
+Names that are not valid in the ecosystem are still normalized, and a warning
+is printed to stderr. With --strict the command exits with a non-zero status
+if any name is invalid.
+
+With --json each name is printed as a JSON object on its own line, with these
+keys:
+
+  * "original" - The name that was passed in.
+  * "normalized" - The normalized name.
+  * "ecosystem" - The ecosystem.
+  * "error" - Why the name is invalid. This is omitted for valid names.
+
+The following ecosystems are available:
+
+`
+
+func new() (*normalizename, error) {
+	var docs strings.Builder
+	docs.WriteString(extraDocs)
+	for _, e := range name.Ecosystems() {
+		fmt.Fprintf(&docs, "  * %s\n", e)
+	}
+
+	app := kingpin.New("normalizename", "A command line tool for normalizing package names.").
+		Author("ActiveState, Inc. <info@activestate.com>").
+		Version(appVersion).
+		UsageWriter(os.Stdout).
+		UsageTemplate(kingpin.DefaultUsageTemplate + docs.String())
+	app.HelpFlag.Short('h')
+
+	nn := &normalizename{app: app}
+	app.Flag("ecosystem", "The ecosystem the names are from").Short('e').StringVar(&nn.ecosystem)
+	app.Flag("stdin", "Read names from stdin, one per line").BoolVar(&nn.stdin)
+	app.Flag("json", "Print each name as a JSON object").BoolVar(&nn.json)
+	app.Flag("strict", "Exit with a non-zero status if any name is invalid").BoolVar(&nn.strict)
+
+	args := app.Arg(
+		"ecosystem and names",
+		"The ecosystem, unless --ecosystem is used, followed by the names to normalize",
+	).Strings()
+
+	_, err := app.Parse(os.Args[1:])
+
+	nn.args = *args
+
+	return nn, err
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var binary string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "normalizename")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	binary = filepath.Join(dir, "normalizename")
+	if out, err := exec.Command("go", "build", "-o", binary, ".").CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "building normalizename failed: %s\n%s", err, out)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+type runResult struct {
+	stdout, stderr string
+	exitCode       int
+}
+
+func run(t *testing.T, stdin string, args ...string) runResult {
+	cmd := exec.Command(binary, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	r := runResult{stdout: stdout.String(), stderr: stderr.String()}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		r.exitCode = exitErr.ExitCode()
+	} else {
+		require.NoError(t, err)
+	}
+	return r
+}
+
+// These are the same as the cases in pkg/name.
+var pythonCases = map[string]string{
+	"flask":                                  "flask",
+	"Flask":                                  "flask",
+	"FLASK":                                  "flask",
+	"backports.ssl":                          "backports-ssl",
+	"backports-----ssl":                      "backports-ssl",
+	"backports.SSL":                          "backports-ssl",
+	"Backports.SSL":                          "backports-ssl",
+	"backports-datetime-fromisoformat":       "backports-datetime-fromisoformat",
+	"backports-datetime_fromisoformat":       "backports-datetime-fromisoformat",
+	"BACKPORTS-DATETIME-FROMISOFORMAT":       "backports-datetime-fromisoformat",
+	"BACKPORTS-.-DATETIME__-.-FROMISOFORMAT": "backports-datetime-fromisoformat",
+}
+
+func TestArgs(t *testing.T) {
+	for from, norm := range pythonCases {
+		r := run(t, "", "python", from)
+		assert.Equal(t, 0, r.exitCode, from)
+		assert.Equal(t, norm+"\n", r.stdout, from)
+		assert.Equal(t, "", r.stderr, from)
+	}
+
+	r := run(t, "", "python", "Flask", "backports.SSL")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, "flask\nbackports-ssl\n", r.stdout)
+
+	r = run(t, "", "--ecosystem", "python", "Flask", "backports.SSL")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, "flask\nbackports-ssl\n", r.stdout)
+}
+
+func TestStdin(t *testing.T) {
+	var in, expected strings.Builder
+	for from, norm := range pythonCases {
+		fmt.Fprintf(&in, "%s\n", from)
+		fmt.Fprintf(&expected, "%s\n", norm)
+	}
+	in.WriteString("\n  \n")
+
+	r := run(t, in.String(), "--ecosystem", "python", "--stdin")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, expected.String(), r.stdout)
+	assert.Equal(t, "", r.stderr)
+}
+
+func TestJSON(t *testing.T) {
+	r := run(t, "Flask\n_flask\n", "-e", "python", "--stdin", "--json")
+	assert.Equal(t, 0, r.exitCode)
+
+	lines := strings.Split(strings.TrimSuffix(r.stdout, "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	var objects []map[string]string
+	for _, l := range lines {
+		var o map[string]string
+		require.NoError(t, json.Unmarshal([]byte(l), &o), l)
+		objects = append(objects, o)
+	}
+	assert.Equal(t, map[string]string{"original": "Flask", "normalized": "flask", "ecosystem": "python"}, objects[0])
+	assert.Equal(t, "_flask", objects[1]["original"])
+	assert.Equal(t, "-flask", objects[1]["normalized"])
+	assert.Equal(t, "python", objects[1]["ecosystem"])
+	assert.NotEmpty(t, objects[1]["error"])
+}
+
+func TestStrict(t *testing.T) {
+	r := run(t, "", "python", "Flask", "flask_")
+	assert.Equal(t, 0, r.exitCode, "invalid names only fail with --strict")
+	assert.Equal(t, "flask\nflask-\n", r.stdout)
+	assert.Contains(t, r.stderr, `"flask_" is not a valid Python package name`)
+
+	r = run(t, "", "--strict", "python", "Flask", "flask_")
+	assert.Equal(t, 1, r.exitCode)
+	assert.Equal(t, "flask\nflask-\n", r.stdout)
+
+	r = run(t, "", "--strict", "python", "Flask", "zope.interface")
+	assert.Equal(t, 0, r.exitCode)
+}
+
+func TestUsageErrors(t *testing.T) {
+	for name, args := range map[string][]string{
+		"no arguments":      {},
+		"no names":          {"python"},
+		"unknown ecosystem": {"cobol", "Flask"},
+		"stdin and names":   {"--stdin", "python", "Flask"},
+	} {
+		r := run(t, "", args...)
+		assert.Equal(t, 1, r.exitCode, name)
+		assert.Contains(t, r.stderr, "usage: normalizename", name)
+		assert.Contains(t, r.stderr, "The following ecosystems are available:\n\n  * cargo\n  * composer\n  * debian\n  * go\n  * hex\n  * julia\n  * npm\n  * pub\n  * python\n", name)
+	}
+}
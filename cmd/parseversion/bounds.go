@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+// inRange returns true if v is within the range set by --min and --max. A nil
+// min or max means there is no bound on that side.
+func (pv *parseversion) inRange(v, min, max *version.Version) bool {
+	if min != nil {
+		cmp := version.Compare(v, min)
+		if cmp < 0 || (cmp == 0 && pv.exclusiveMin) {
+			return false
+		}
+	}
+	if max != nil {
+		cmp := version.Compare(v, max)
+		if cmp > 0 || (cmp == 0 && pv.exclusiveMax) {
+			return false
+		}
+	}
+	return true
+}
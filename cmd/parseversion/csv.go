@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+const (
+	argsInput = "args"
+	csvInput  = "csv"
+)
+
+// input is a version to parse, and the type to parse it as. Index is the
+// position of the type/version pair in the arguments, or of the row in CSV
+// input, starting from zero.
+type input struct {
+	typ, version string
+	index        int
+}
+
+// argInputs returns the type/version pairs passed as arguments.
+func (pv *parseversion) argInputs() []input {
+	count := len(pv.args)
+	if count%2 == 1 || count == 0 {
+		pv.usageError("You must pass one or more pairs of arguments, where each pair consists of a type and version string.")
+	}
+
+	inputs := make([]input, 0, count/2)
+	for i := 0; i < count; i += 2 {
+		inputs = append(inputs, input{typ: pv.args[i], version: pv.args[i+1], index: i / 2})
+	}
+	return inputs
+}
+
+// csvInputs reads the versions to parse from CSV in r. The version in each row
+// is in the --version-column column, and the type is in the --type-column
+// column if there is one and it is not empty, and is --type otherwise. Rows
+// are counted from zero, not counting the header.
+func (pv *parseversion) csvInputs(r io.Reader) []input {
+	if len(pv.args) > 0 {
+		pv.usageError("You cannot pass type/version pairs as arguments with --input=csv.")
+	}
+	if pv.versionColumn == "" {
+		pv.usageError("You must pass --version-column with --input=csv.")
+	}
+	if pv.typ == "" && pv.typeColumn == "" {
+		pv.usageError("You must pass --type or --type-column with --input=csv.")
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	var header []string
+	if pv.hasHeader {
+		var err error
+		header, err = cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			pv.fail(ioKind, "", -1, "Error reading CSV header: %s", err)
+		}
+	}
+	versionColumn := pv.column("--version-column", pv.versionColumn, header)
+	typeColumn := -1
+	if pv.typeColumn != "" {
+		typeColumn = pv.column("--type-column", pv.typeColumn, header)
+	}
+
+	var inputs []input
+	for row := 0; ; row++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return inputs
+		}
+		if err != nil {
+			pv.fail(ioKind, "", row, "Error reading CSV row %d: %s", row, err)
+		}
+
+		if versionColumn >= len(record) {
+			pv.fail(usageKind, "", row, "CSV row %d has no column %d for the version", row, versionColumn)
+		}
+		in := input{typ: pv.typ, version: record[versionColumn], index: row}
+		if in.version == "" {
+			if !pv.keepGoing {
+				pv.fail(usageKind, "", row, "CSV row %d has an empty version", row)
+			}
+			pv.warn("", row, "Skipping CSV row %d, which has an empty version", row)
+			continue
+		}
+
+		if typeColumn >= 0 {
+			if typeColumn >= len(record) {
+				pv.fail(usageKind, "", row, "CSV row %d has no column %d for the type", row, typeColumn)
+			}
+			if record[typeColumn] != "" {
+				in.typ = record[typeColumn]
+			} else if in.typ == "" {
+				pv.fail(usageKind, in.version, row, "CSV row %d has an empty type and no --type was given", row)
+			}
+		}
+
+		inputs = append(inputs, in)
+	}
+}
+
+// column returns the index of the column named by value, which is either a
+// column index starting from zero or the name of a column in the header.
+func (pv *parseversion) column(flag, value string, header []string) int {
+	if i, err := strconv.Atoi(value); err == nil {
+		if i < 0 {
+			pv.usageError("%s must not be negative", flag)
+		}
+		return i
+	}
+
+	if header == nil {
+		pv.usageError("%s can only be a column name with --has-header", flag)
+	}
+	for i, name := range header {
+		if name == value {
+			return i
+		}
+	}
+	pv.usageError("There is no column named %s in the CSV header", value)
+	return -1
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/ActiveState/langtools/pkg/version"
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -12,10 +14,21 @@ import (
 
 const appVersion = "0.0.7"
 
+// parserFor returns a func that parses versions of the named type, which is
+// one of the names returned by version.TypeNames, and false if the type is
+// not known.
+func parserFor(typ string) (func(string) (*version.Version, error), bool) {
+	pa, ok := version.ParsedAsFromName(typ)
+	if !ok {
+		return nil, false
+	}
+	return func(s string) (*version.Version, error) { return version.ParseComparable(pa, s) }, true
+}
+
 func main() {
 	pv, err := new()
 	if err != nil {
-		pv.app.FatalUsage("%s\n", err)
+		pv.usageError("%s", err)
 	}
 
 	if pv.printVersion {
@@ -23,54 +36,231 @@ func main() {
 		os.Exit(0)
 	}
 
-	count := len(pv.args)
-	if count%2 == 1 || count == 0 {
-		pv.app.FatalUsage("You must pass one or more pairs of arguments, where each pair consists of a type and version string.\n")
-	}
-
-	var output []*version.Version
-	for i := 0; i < count; i += 2 {
-		typ := pv.args[i]
-		ver := pv.args[i+1]
-
-		var parsed *version.Version
-
-		switch typ {
-		case "generic":
-			parsed, err = version.ParseGeneric(ver)
-		case "semver":
-			parsed, err = version.ParseSemVer(ver)
-		case "perl":
-			parsed, err = version.ParsePerl(ver)
-		case "php":
-			parsed, err = version.ParsePHP(ver)
-		case "python":
-			parsed, err = version.ParsePython(ver)
-		case "ruby":
-			parsed, err = version.ParseRuby(ver)
-		default:
-			pv.app.FatalUsage("Unknown version type requested: %s\n", typ)
+	if pv.command == serveCommand {
+		err = newHTTPServer(pv.listen, newServer(pv.maxBodyBytes, pv.maxVersions), pv.timeouts).ListenAndServe()
+		pv.ioError(err)
+	}
+
+	if pv.command == statsCommand {
+		pv.runStats()
+		os.Exit(0)
+	}
+
+	if pv.command == schemaCommand {
+		if _, err = fmt.Print(version.OutputJSONSchema); err != nil {
+			pv.ioError(err)
+		}
+		os.Exit(0)
+	}
+
+	var inputs []input
+	if pv.input == csvInput {
+		inputs = pv.csvInputs(os.Stdin)
+	} else {
+		inputs = pv.argInputs()
+	}
+
+	var mins, maxes, pivots map[string]*version.Version
+	if pv.min != "" {
+		mins = pv.parseAsTypes("the minimum", pv.min, inputs, 1)
+	}
+	if pv.max != "" {
+		maxes = pv.parseAsTypes("the maximum", pv.max, inputs, 1)
+	}
+	// A pivot that cannot be parsed exits with a status of 2, so that it can
+	// be told apart from versions that are less than the pivot.
+	if pv.pivot != "" {
+		pivots = pv.parseAsTypes("the pivot", pv.pivot, inputs, 2)
+	}
+
+	out := []output{}
+	belowPivot := false
+	filtered := 0
+	for _, in := range inputs {
+		parse, ok := parserFor(in.typ)
+		if !ok {
+			pv.fail(usageKind, in.typ, in.index, "Unknown version type requested: %s", in.typ)
 		}
 
+		parsed, err := parse(in.version)
 		if err != nil {
-			pv.app.FatalUsage("Error parsing %s as %s: %s\n", ver, typ, err)
+			pv.fail(parseKind, in.version, in.index, "Error parsing %s as %s: %s", in.version, in.typ, err)
+		}
+
+		if !pv.inRange(parsed, mins[in.typ], maxes[in.typ]) {
+			filtered++
+			continue
 		}
 
-		output = append(output, parsed)
+		o := output{Version: parsed}
+		if pivot := pivots[in.typ]; pivot != nil {
+			o.Pivot = comparePivot(parsed, pivot)
+			belowPivot = belowPivot || o.Pivot == pivotLT
+		}
+		out = append(out, o)
 	}
 
-	j, err := json.Marshal(output)
+	if pv.verbose && (pv.min != "" || pv.max != "") {
+		fmt.Fprintf(os.Stderr, "Filtered out %d of %d versions outside of the range\n", filtered, len(inputs))
+	}
+
+	if pv.quiet {
+		if belowPivot {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	j, err := json.Marshal(out)
 	if err != nil {
-		log.Fatalf("Error marshalling %+v as JSON: %s", output, err)
+		pv.fail(ioKind, "", -1, "Error marshalling %+v as JSON: %s", out, err)
 	}
 
-	fmt.Println(string(j))
+	if _, err = fmt.Println(string(j)); err != nil {
+		pv.ioError(err)
+	}
 }
 
+// parseAsTypes parses value, which is described by name in error messages, as
+// each of the types in inputs, and returns the parsed versions by type. Types
+// that are not known are skipped, because they are reported when the inputs
+// are parsed. If value cannot be parsed as one of the types this exits with
+// the given status.
+func (pv *parseversion) parseAsTypes(name, value string, inputs []input, status int) map[string]*version.Version {
+	parsed := map[string]*version.Version{}
+	types := []string{}
+	if pv.typ != "" {
+		types = append(types, pv.typ)
+	}
+	for _, in := range inputs {
+		types = append(types, in.typ)
+	}
+
+	for _, typ := range types {
+		parse, ok := parserFor(typ)
+		if !ok || parsed[typ] != nil {
+			continue
+		}
+		v, err := parse(value)
+		if err != nil {
+			pv.failWithStatus(status, parseKind, value, -1, "Error parsing %s %s as %s: %s", name, value, typ, err)
+		}
+		parsed[typ] = v
+	}
+	return parsed
+}
+
+const schemaCommand = "schema"
+
 type parseversion struct {
 	app          *kingpin.Application
 	printVersion bool
+	jsonErrors   bool
+	command      string
 	args         []string
+	listen       string
+	maxBodyBytes int64
+	maxVersions  int
+	timeouts     serverTimeouts
+
+	input         string
+	typ           string
+	versionColumn string
+	typeColumn    string
+	hasHeader     bool
+	keepGoing     bool
+	pivot         string
+	quiet         bool
+	min           string
+	max           string
+	exclusiveMin  bool
+	exclusiveMax  bool
+	verbose       bool
+
+	statsType     string
+	statsVersions []string
+	stdin         bool
+	statsJSON     bool
+	median        bool
+}
+
+// The kinds of error reported with --json-errors.
+const (
+	usageKind   = "usage"
+	parseKind   = "parse"
+	ioKind      = "io"
+	warningKind = "warning"
+)
+
+// jsonError is what is written to stderr for an error with --json-errors.
+// Input and Index are only set for errors caused by one of the type/version
+// pairs, and Index is the position of the pair, starting from zero.
+type jsonError struct {
+	Error string `json:"error"`
+	Kind  string `json:"kind"`
+	Input string `json:"input,omitempty"`
+	Index *int   `json:"index,omitempty"`
+}
+
+// fail reports an error and exits with a status of 1. With --json-errors the
+// error is written to stderr as a single line of JSON. Otherwise usage and
+// parse errors are reported along with the usage text, and I/O errors are
+// logged. If index is less than zero the error is not caused by a particular
+// pair.
+func (pv *parseversion) fail(kind, input string, index int, format string, args ...interface{}) {
+	pv.failWithStatus(1, kind, input, index, format, args...)
+}
+
+// failWithStatus works like fail, but exits with the given status.
+func (pv *parseversion) failWithStatus(status int, kind, input string, index int, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if !pv.jsonErrors {
+		if kind == ioKind {
+			log.Fatal(msg)
+		}
+		pv.app.Terminate(func(int) { os.Exit(status) })
+		pv.app.FatalUsage("%s\n", msg)
+	}
+
+	e := jsonError{Error: msg, Kind: kind, Input: input}
+	if index >= 0 {
+		e.Index = &index
+	}
+	j, err := json.Marshal(e)
+	if err != nil {
+		log.Fatalf("Error marshalling %+v as JSON: %s", e, err)
+	}
+	fmt.Fprintln(os.Stderr, string(j))
+	os.Exit(status)
+}
+
+// warn reports a problem that does not stop the command. With --json-errors
+// the warning is written to stderr as a single line of JSON, with a kind of
+// "warning".
+func (pv *parseversion) warn(input string, index int, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if !pv.jsonErrors {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+		return
+	}
+
+	e := jsonError{Error: msg, Kind: warningKind, Input: input}
+	if index >= 0 {
+		e.Index = &index
+	}
+	j, err := json.Marshal(e)
+	if err != nil {
+		log.Fatalf("Error marshalling %+v as JSON: %s", e, err)
+	}
+	fmt.Fprintln(os.Stderr, string(j))
+}
+
+func (pv *parseversion) usageError(format string, args ...interface{}) {
+	pv.fail(usageKind, "", -1, format, args...)
+}
+
+func (pv *parseversion) ioError(err error) {
+	pv.fail(ioKind, "", -1, "%s", err)
 }
 
 const extraDocs = `
@@ -84,31 +274,143 @@ two keys:
     stringified decimal number. Taken as a whole, this array can be sorted
     _numerically_ against other versions of the same package.
 
-The following version types are available:
+Run "parseversion schema" to print a JSON Schema describing this output.
+
+With --pivot, each object also has a "pivot" key, which is "lt", "eq" or "gt"
+as the version is less than, equal to, or greater than the pivot version. The
+pivot is parsed as the same type as each version it is compared to. With
+--quiet nothing is written to stdout, and the exit status is 0 only if every
+version parsed and none of them is less than the pivot. If the pivot cannot be
+parsed the exit status is 2.
+
+With --min and --max, versions less than the minimum or greater than the
+maximum are left out of the output. Both are inclusive, unless --exclusive-min
+or --exclusive-max is passed. Like the pivot, they are parsed as the same type
+as each version they are compared to, and versions that differ only by
+trailing zeros are equal, so "--max 2.0" includes "2.0.0". With --verbose the
+number of versions that were left out is written to stderr.
+
+With --json-errors, errors are written to stderr as a single line of JSON
+instead of as text, and nothing is written to stdout. The JSON object has
+these keys:
+
+  * "error" - The error message.
+  * "kind" - One of "usage", "parse" or "io".
+  * "input" - The type or version that caused the error, if any.
+  * "index" - The position of the type/version pair that caused the error,
+    starting from zero, if any.
+
+The exit status is 1 for every kind of error. Warnings, which do not stop the
+command, are written in the same way with a kind of "warning".
+
+With --input=csv, versions are read from CSV on stdin instead of from the
+arguments. --version-column names the column containing the versions, either
+as an index starting from zero or, with --has-header, as the name of a column
+in the first row. Versions are parsed as --type, unless --type-column names a
+column containing the type, in which case its value is used for each row where
+it is not empty. A row with an empty version is an error, unless --keep-going
+is passed, in which case it is skipped with a warning. The output is in the
+same order as the rows, and the "index" of an error is the row number,
+starting from zero and not counting the header.
+
+Run "parseversion stats <type>" to print a summary of a list of versions, read
+from the arguments or, with --stdin, one per line from stdin. The summary has
+the number of versions parsed, the number that could not be parsed, the least
+and greatest versions, the greatest version that is not a pre-release, and the
+number of pre-releases. For Perl and Python it also counts how many versions
+were parsed as each scheme, such as PythonPEP440 and PythonLegacy. Only the
+current least and greatest versions are kept as the versions are read, unless
+--median is passed. With --json the summary is printed as a JSON object.
+
+Run "parseversion serve" to serve an HTTP API instead. It accepts POST
+requests with a JSON body like {"type": "python", "versions": ["1.0", "2.0b1"]}
+at these paths:
+
+  * /parse - Responds with the same JSON as the parse command.
+  * /sort - Responds with the same JSON, with the versions sorted in ascending
+    order.
+  * /compare - Takes exactly two versions, and responds with {"result": N},
+    where N is -1, 0 or 1 as the first version is less than, equal to, or
+    greater than the second.
+
+Errors are reported with a 4xx status and the same JSON object that
+--json-errors writes, where "index" is the position of the version in the
+request. Connections from clients that are slow to send a request, or to read
+the response, are closed after --read-header-timeout, --read-timeout and
+--write-timeout, and idle connections after --idle-timeout.
+
+The following version types are available. Perl and Python versions may use
+any of the schemes for that language, and cpe versions are the version and
+update attributes of a CPE name joined with a colon, such as "1.1.1:k".
 
-  * semver - A version following the semver specification (https://semver.org/)
-  * python - A Python PEP440 or legacy version
-  * perl - A Perl module version
-  * generic - Anything not covered by another type, such as C libraries, etc.
 `
 
 func new() (*parseversion, error) {
+	var docs strings.Builder
+	docs.WriteString(extraDocs)
+	for _, typ := range version.TypeNames() {
+		fmt.Fprintf(&docs, "  * %s\n", typ)
+	}
+
 	app := kingpin.New("parseversion", "A command line tool for parsing version strings.").
 		Author("ActiveState, Inc. <info@activestate.com>").
 		Version(appVersion).
 		UsageWriter(os.Stdout).
-		UsageTemplate(kingpin.DefaultUsageTemplate + extraDocs)
+		UsageTemplate(kingpin.DefaultUsageTemplate + docs.String())
 	app.HelpFlag.Short('h')
 
-	args := app.Arg(
+	pv := &parseversion{app: app}
+	app.Flag("json-errors", "Write errors to stderr as JSON").BoolVar(&pv.jsonErrors)
+
+	parse := app.Command("parse", "Parse versions and print them as JSON. This is the default command.").Default()
+	args := parse.Arg(
 		"type/version pairs",
 		"One or more pairs of version types and versions to parse",
-	).Required().Strings()
+	).Strings()
+	parse.Flag("input", "Where to read versions from, either args or csv").Default(argsInput).EnumVar(&pv.input, argsInput, csvInput)
+	parse.Flag("type", "The type of the versions in CSV input").StringVar(&pv.typ)
+	parse.Flag("version-column", "The name or index of the CSV column containing versions").StringVar(&pv.versionColumn)
+	parse.Flag("type-column", "The name or index of the CSV column containing types, which overrides --type").StringVar(&pv.typeColumn)
+	parse.Flag("has-header", "The first row of the CSV input is a header").BoolVar(&pv.hasHeader)
+	parse.Flag("keep-going", "Skip CSV rows with an empty version instead of failing").BoolVar(&pv.keepGoing)
+	parse.Flag("pivot", "Compare each version against this version").StringVar(&pv.pivot)
+	parse.Flag("quiet", "Do not print the parsed versions, only set the exit status").Short('q').BoolVar(&pv.quiet)
+	parse.Flag("min", "Leave out versions less than this version").StringVar(&pv.min)
+	parse.Flag("max", "Leave out versions greater than this version").StringVar(&pv.max)
+	parse.Flag("exclusive-min", "Also leave out versions equal to --min").BoolVar(&pv.exclusiveMin)
+	parse.Flag("exclusive-max", "Also leave out versions equal to --max").BoolVar(&pv.exclusiveMax)
+	parse.Flag("verbose", "Report how many versions --min and --max left out on stderr").Short('v').BoolVar(&pv.verbose)
 
-	pv := &parseversion{app: app}
+	app.Command(schemaCommand, "Print a JSON Schema describing the output of the parse command.")
+
+	stats := app.Command(statsCommand, "Print a summary of a list of versions of one type.")
+	stats.Arg("type", "The type of the versions").Required().StringVar(&pv.statsType)
+	stats.Arg("versions", "The versions to summarize").StringsVar(&pv.statsVersions)
+	stats.Flag("stdin", "Read versions from stdin, one per line").BoolVar(&pv.stdin)
+	stats.Flag("json", "Print the summary as JSON").BoolVar(&pv.statsJSON)
+	stats.Flag("median", "Also print the median version, which means keeping every version in memory").BoolVar(&pv.median)
+
+	serve := app.Command(serveCommand, "Serve an HTTP API for parsing, sorting and comparing versions.")
+	serve.Flag("listen", "The address to listen on").Default(":8080").StringVar(&pv.listen)
+	serve.Flag("max-body-bytes", "The largest request body to accept").Default(strconv.Itoa(defaultMaxBodyBytes)).Int64Var(&pv.maxBodyBytes)
+	serve.Flag("max-versions", "The most versions to accept in one request").Default(strconv.Itoa(defaultMaxVersions)).IntVar(&pv.maxVersions)
+	serve.Flag("read-header-timeout", "The longest to wait for the headers of a request").Default(defaultReadHeaderTimeout.String()).DurationVar(&pv.timeouts.readHeader)
+	serve.Flag("read-timeout", "The longest to wait for a whole request, including the body").Default(defaultReadTimeout.String()).DurationVar(&pv.timeouts.read)
+	serve.Flag("write-timeout", "The longest to spend writing a response").Default(defaultWriteTimeout.String()).DurationVar(&pv.timeouts.write)
+	serve.Flag("idle-timeout", "The longest to keep an idle connection open for the next request").Default(defaultIdleTimeout.String()).DurationVar(&pv.timeouts.idle)
 
-	_, err := app.Parse(os.Args[1:])
+	command, err := app.Parse(os.Args[1:])
+	if err != nil && !pv.jsonErrors {
+		// Kingpin stops at the first bad argument, so the flag may not have
+		// been seen yet.
+		for _, a := range os.Args[1:] {
+			if a == "--json-errors" {
+				pv.jsonErrors = true
+			}
+		}
+	}
 
+	pv.command = command
 	pv.args = *args
 
 	return pv, err
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var binary string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "parseversion")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	binary = filepath.Join(dir, "parseversion")
+	if out, err := exec.Command("go", "build", "-o", binary, ".").CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "building parseversion failed: %s\n%s", err, out)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+type runResult struct {
+	stdout, stderr string
+	exitCode       int
+}
+
+func run(t *testing.T, args ...string) runResult {
+	return runWithStdin(t, "", args...)
+}
+
+func runWithStdin(t *testing.T, stdin string, args ...string) runResult {
+	cmd := exec.Command(binary, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	r := runResult{stdout: stdout.String(), stderr: stderr.String()}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		r.exitCode = exitErr.ExitCode()
+	} else {
+		require.NoError(t, err)
+	}
+	return r
+}
+
+func TestParse(t *testing.T) {
+	r := run(t, "semver", "1.2.3", "generic", "1.0-alpha")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, "", r.stderr)
+	assert.Equal(t, `[{"version":"1.2.3","sortable_version":["1","2","3"]},{"version":"1.0-alpha","sortable_version":["1","0","-26"]}]`+"\n", r.stdout)
+	assert.NoError(t, version.ValidateOutputJSON([]byte(r.stdout)))
+
+	r = run(t, "--json-errors", "parse", "semver", "1.2.3")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, "", r.stderr)
+	assert.Equal(t, `[{"version":"1.2.3","sortable_version":["1","2","3"]}]`+"\n", r.stdout)
+
+	r = run(t, "go", "v1.2.3", "python", "1.0-foo", "maven", "1.0-SNAPSHOT")
+	assert.Equal(t, 0, r.exitCode, "every type in version.TypeNames can be requested")
+	assert.Equal(t, "", r.stderr)
+	assert.Contains(t, r.stdout, `{"version":"1.0-foo",`, "python accepts legacy versions")
+}
+
+func TestSchema(t *testing.T) {
+	r := run(t, "schema")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, version.OutputJSONSchema, r.stdout)
+}
+
+func TestErrors(t *testing.T) {
+	r := run(t, "semver", "1.2.3", "semver", "1.0")
+	assert.Equal(t, 1, r.exitCode)
+	assert.Equal(t, "", r.stdout)
+	assert.Contains(t, r.stderr, "parseversion: error: Error parsing 1.0 as semver:")
+	assert.Contains(t, r.stderr, "usage: parseversion")
+	assert.Contains(t, r.stderr, "The following version types are available.")
+	assert.Contains(t, r.stderr, "\n\n  * conan\n  * cpe\n")
+	assert.Contains(t, r.stderr, "  * semver\n  * windowsfileversion\n")
+}
+
+func TestJSONErrors(t *testing.T) {
+	index := func(i int) *int { return &i }
+	tests := map[string]struct {
+		args     []string
+		expected jsonError
+	}{
+		"bad version": {
+			[]string{"semver", "1.2.3", "semver", "1.0"},
+			jsonError{
+				Error: "Error parsing 1.0 as semver: Version does not match semver regex: 1.0",
+				Kind:  "parse",
+				Input: "1.0",
+				Index: index(1),
+			},
+		},
+		"unknown type": {
+			[]string{"cobol", "1.0"},
+			jsonError{
+				Error: "Unknown version type requested: cobol",
+				Kind:  "usage",
+				Input: "cobol",
+				Index: index(0),
+			},
+		},
+		"odd number of arguments": {
+			[]string{"semver", "1.2.3", "semver"},
+			jsonError{
+				Error: "You must pass one or more pairs of arguments, where each pair consists of a type and version string.",
+				Kind:  "usage",
+			},
+		},
+		"unknown flag": {
+			[]string{"--bogus", "semver", "1.2.3"},
+			jsonError{
+				Error: "unknown long flag '--bogus'",
+				Kind:  "usage",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := run(t, append([]string{"--json-errors"}, tt.args...)...)
+			assert.Equal(t, 1, r.exitCode)
+			assert.Equal(t, "", r.stdout)
+
+			require.True(t, bytes.HasSuffix([]byte(r.stderr), []byte("\n")))
+			assert.NotContains(t, r.stderr[:len(r.stderr)-1], "\n", "stderr is a single line")
+
+			var actual jsonError
+			require.NoError(t, json.Unmarshal([]byte(r.stderr), &actual))
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+
+	r := run(t, "semver", "1.2.3", "--bogus", "--json-errors")
+	assert.Equal(t, 1, r.exitCode)
+	assert.Equal(t, `{"error":"unknown long flag '--bogus'","kind":"usage"}`+"\n", r.stderr, "--json-errors is seen after an earlier bad flag")
+}
+
+const testCSV = `name,version,notes,type
+flask,1.0,"web, micro",
+"requests, http",2.0b1,"quoted ""notes"", with commas",
+rails,5.0.0.beta1,"ruby, not python",ruby
+legacy,1.0-foo-bar,,
+`
+
+func originals(t *testing.T, out string) []string {
+	var vs []*version.Version
+	require.NoError(t, json.Unmarshal([]byte(out), &vs), out)
+	var o []string
+	for _, v := range vs {
+		o = append(o, v.Original)
+	}
+	return o
+}
+
+func TestCSVInput(t *testing.T) {
+	r := runWithStdin(t, testCSV, "--input=csv", "--type", "python", "--version-column", "version", "--type-column", "type", "--has-header")
+	assert.Equal(t, 0, r.exitCode, r.stderr)
+	assert.Equal(t, "", r.stderr)
+	assert.Equal(t, []string{"1.0", "2.0b1", "5.0.0.beta1", "1.0-foo-bar"}, originals(t, r.stdout))
+
+	var vs []*version.Version
+	require.NoError(t, json.Unmarshal([]byte(r.stdout), &vs))
+	expected, err := version.ParseRuby("5.0.0.beta1")
+	require.NoError(t, err)
+	assert.Equal(t, expected.Segments(), vs[2].Segments(), "the type column overrides --type")
+
+	r = runWithStdin(t, testCSV, "parse", "--input", "csv", "--type", "semver", "--version-column", "1", "--has-header", "--json-errors")
+	assert.Equal(t, 1, r.exitCode)
+	assert.Equal(t, `{"error":"Error parsing 1.0 as semver: Version does not match semver regex: 1.0","kind":"parse","input":"1.0","index":0}`+"\n", r.stderr)
+
+	noHeader := strings.SplitN(testCSV, "\n", 2)[1]
+	r = runWithStdin(t, noHeader, "--input=csv", "--type", "generic", "--version-column", "1")
+	assert.Equal(t, 0, r.exitCode, r.stderr)
+	assert.Equal(t, []string{"1.0", "2.0b1", "5.0.0.beta1", "1.0-foo-bar"}, originals(t, r.stdout))
+
+	r = runWithStdin(t, "", "--input=csv", "--type", "generic", "--version-column", "0", "--has-header")
+	assert.Equal(t, 0, r.exitCode, r.stderr)
+	assert.Equal(t, "[]\n", r.stdout)
+}
+
+func TestCSVInputEmptyVersions(t *testing.T) {
+	in := "a,1.0\nb,\nc,2.0\n"
+	args := []string{"--input=csv", "--type", "generic", "--version-column", "1"}
+
+	r := runWithStdin(t, in, args...)
+	assert.Equal(t, 1, r.exitCode)
+	assert.Equal(t, "", r.stdout)
+	assert.Contains(t, r.stderr, "CSV row 1 has an empty version")
+
+	r = runWithStdin(t, in, append(args, "--keep-going")...)
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, []string{"1.0", "2.0"}, originals(t, r.stdout))
+	assert.Equal(t, "Warning: Skipping CSV row 1, which has an empty version\n", r.stderr)
+
+	r = runWithStdin(t, in, append(args, "--keep-going", "--json-errors")...)
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, `{"error":"Skipping CSV row 1, which has an empty version","kind":"warning","index":1}`+"\n", r.stderr)
+
+	r = runWithStdin(t, in, append(args, "--json-errors")...)
+	assert.Equal(t, 1, r.exitCode)
+	assert.Equal(t, `{"error":"CSV row 1 has an empty version","kind":"usage","index":1}`+"\n", r.stderr)
+}
+
+func TestCSVInputUsageErrors(t *testing.T) {
+	for name, tt := range map[string]struct {
+		args     []string
+		expected string
+	}{
+		"pairs and csv":         {[]string{"--input=csv", "--type", "semver", "--version-column", "0", "semver", "1.0.0"}, "You cannot pass type/version pairs"},
+		"no version column":     {[]string{"--input=csv", "--type", "semver"}, "You must pass --version-column"},
+		"no type":               {[]string{"--input=csv", "--version-column", "0"}, "You must pass --type or --type-column"},
+		"name without a header": {[]string{"--input=csv", "--type", "semver", "--version-column", "version"}, "--version-column can only be a column name with --has-header"},
+		"unknown column":        {[]string{"--input=csv", "--type", "semver", "--version-column", "v", "--has-header"}, "There is no column named v"},
+		"column out of range":   {[]string{"--input=csv", "--type", "semver", "--version-column", "9"}, "CSV row 0 has no column 9"},
+		"unknown input":         {[]string{"--input=xml"}, "enum value must be one of args,csv"},
+	} {
+		r := runWithStdin(t, "version\n1.0.0\n", tt.args...)
+		assert.Equal(t, 1, r.exitCode, name)
+		assert.Contains(t, r.stderr, tt.expected, name)
+	}
+}
+
+func TestPivot(t *testing.T) {
+	r := run(t, "--pivot", "2.0.0", "python", "1.0", "python", "2.0", "python", "3.0a1", "semver", "2.0.1")
+	assert.Equal(t, 0, r.exitCode, r.stderr)
+	assert.Equal(t, "", r.stderr)
+
+	var actual []struct {
+		Version string `json:"version"`
+		Pivot   string `json:"pivot"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(r.stdout), &actual))
+	require.Len(t, actual, 4)
+	for i, expected := range []string{"lt", "eq", "gt", "gt"} {
+		assert.Equal(t, expected, actual[i].Pivot, actual[i].Version)
+	}
+
+	r = run(t, "semver", "1.0.0")
+	assert.NotContains(t, r.stdout, "pivot", "there is no pivot key without --pivot")
+
+	r = run(t, "--quiet", "--pivot", "2.0", "python", "2.0", "python", "2.1")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, "", r.stdout)
+
+	r = run(t, "-q", "--pivot", "2.0", "python", "2.0", "python", "1.9", "python", "2.1")
+	assert.Equal(t, 1, r.exitCode, "a version is less than the pivot")
+	assert.Equal(t, "", r.stdout)
+	assert.Equal(t, "", r.stderr)
+
+	r = runWithStdin(t, "1.0\n2.0\n", "--input=csv", "--type", "generic", "--version-column", "0", "--pivot", "1.5")
+	assert.Equal(t, 0, r.exitCode, r.stderr)
+	assert.Contains(t, r.stdout, `"version":"1.0","sortable_version":["1"],"pivot":"lt"`)
+	assert.Contains(t, r.stdout, `"version":"2.0","sortable_version":["2"],"pivot":"gt"`)
+}
+
+func TestPivotErrors(t *testing.T) {
+	r := run(t, "--pivot", "2.0", "python", "1.0", "semver", "1.0.0")
+	assert.Equal(t, 2, r.exitCode)
+	assert.Equal(t, "", r.stdout)
+	assert.Contains(t, r.stderr, "parseversion: error: Error parsing the pivot 2.0 as semver:")
+
+	r = run(t, "--json-errors", "-q", "--pivot", "2.0", "semver", "1.0.0")
+	assert.Equal(t, 2, r.exitCode)
+	assert.Equal(t, `{"error":"Error parsing the pivot 2.0 as semver: Version does not match semver regex: 2.0","kind":"parse","input":"2.0"}`+"\n", r.stderr)
+
+	r = run(t, "-q", "--pivot", "1.0.0", "semver", "1.0")
+	assert.Equal(t, 1, r.exitCode, "an input that cannot be parsed is still a status of 1")
+
+	r = runWithStdin(t, "", "--input=csv", "--type", "semver", "--version-column", "0", "--pivot", "2.0")
+	assert.Equal(t, 2, r.exitCode, "the pivot is parsed as --type even without any rows")
+}
+
+func TestMinMax(t *testing.T) {
+	pairs := []string{"python", "1.3", "python", "1.4", "python", "1.4.0", "python", "1.9", "python", "2.0.0", "python", "2.0.post1"}
+	tests := map[string]struct {
+		flags    []string
+		expected []string
+	}{
+		"no bounds":      {nil, []string{"1.3", "1.4", "1.4.0", "1.9", "2.0.0", "2.0.post1"}},
+		"inclusive":      {[]string{"--min", "1.4", "--max", "2.0"}, []string{"1.4", "1.4.0", "1.9", "2.0.0"}},
+		"exclusive min":  {[]string{"--min", "1.4", "--exclusive-min"}, []string{"1.9", "2.0.0", "2.0.post1"}},
+		"exclusive max":  {[]string{"--max", "2.0", "--exclusive-max"}, []string{"1.3", "1.4", "1.4.0", "1.9"}},
+		"exclusive both": {[]string{"--min", "1.4.0.0", "--max", "2", "--exclusive-min", "--exclusive-max"}, []string{"1.9"}},
+		"empty range":    {[]string{"--min", "2.1", "--max", "1.0"}, nil},
+	}
+	for name, tt := range tests {
+		r := run(t, append(tt.flags, pairs...)...)
+		assert.Equal(t, 0, r.exitCode, name)
+		assert.Equal(t, "", r.stderr, name)
+		assert.Equal(t, tt.expected, originals(t, r.stdout), name)
+	}
+
+	r := run(t, "--max", "2.0-beta", "generic", "2.0-alpha", "python", "2.0a1", "generic", "2.0", "python", "2.0b1")
+	assert.Equal(t, 0, r.exitCode, r.stderr)
+	assert.Equal(t, []string{"2.0-alpha", "2.0a1"}, originals(t, r.stdout), "the max is parsed as each version's type")
+
+	r = run(t, "-v", "--min", "1.0.1", "--max", "2.0.0", "semver", "1.0.0", "semver", "1.5.0", "semver", "3.0.0")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, []string{"1.5.0"}, originals(t, r.stdout))
+	assert.Equal(t, "Filtered out 2 of 3 versions outside of the range\n", r.stderr)
+
+	r = run(t, "--min", "1.0", "semver", "1.0.0")
+	assert.Equal(t, 1, r.exitCode)
+	assert.Contains(t, r.stderr, "Error parsing the minimum 1.0 as semver:")
+
+	r = run(t, "-q", "--pivot", "2.0", "--min", "2.0", "python", "1.0", "python", "2.1")
+	assert.Equal(t, 0, r.exitCode, "versions outside of the range are not compared to the pivot")
+}
+
+func TestStats(t *testing.T) {
+	r := runWithStdin(t, strings.Join(pythonCorpus, "\n"), "stats", "python", "--stdin", "--json", "--median")
+	assert.Equal(t, 0, r.exitCode, r.stderr)
+	assert.Equal(t, "", r.stderr)
+
+	var actual stats
+	require.NoError(t, json.Unmarshal([]byte(r.stdout), &actual))
+	assert.Equal(t, stats{
+		Count:    96,
+		Failures: 0,
+		// "  hmm" is the least version in the corpus, but leading space is
+		// trimmed from each line.
+		Min:          "a cat is fine too",
+		Max:          "1!1.2.rev33+123456",
+		LatestStable: "1!1.2.rev33+123456",
+		PreReleases:  34,
+		Median:       "1.1.dev1",
+		Schemes:      map[string]int{"PythonLegacy": 24, "PythonPEP440": 72},
+	}, actual)
+
+	r = run(t, "stats", "semver", "1.0.0", "2.0.0-rc.1", "1.0", "0.9.0", "bogus")
+	assert.Equal(t, 0, r.exitCode, r.stderr)
+	assert.Equal(t, `Count:          3
+Failures:       2
+Min:            0.9.0
+Max:            2.0.0-rc.1
+Latest stable:  1.0.0
+Pre-releases:   1
+`, r.stdout)
+
+	r = run(t, "stats", "perl", "--json", "v1.2.3", "1.5", "1.002004_01")
+	assert.Equal(t, 0, r.exitCode, r.stderr)
+	assert.Equal(t, `{"count":3,"failures":0,"min":"v1.2.3","max":"1.5","latest_stable":"1.5","pre_releases":1,"schemes":{"PerlDecimal":2,"PerlVString":1}}`+"\n", r.stdout)
+
+	r = run(t, "stats", "generic", "--json", "--median")
+	assert.Equal(t, 0, r.exitCode, r.stderr)
+	assert.Equal(t, `{"count":0,"failures":0,"pre_releases":0}`+"\n", r.stdout)
+}
+
+func TestStatsErrors(t *testing.T) {
+	r := run(t, "stats", "cobol", "1.0")
+	assert.Equal(t, 1, r.exitCode)
+	assert.Contains(t, r.stderr, "Unknown version type requested: cobol")
+
+	r = runWithStdin(t, "1.0\n", "stats", "generic", "--stdin", "2.0")
+	assert.Equal(t, 1, r.exitCode)
+	assert.Contains(t, r.stderr, "You cannot pass versions as arguments with --stdin.")
+
+	r = run(t, "stats")
+	assert.Equal(t, 1, r.exitCode)
+	assert.Contains(t, r.stderr, "required argument 'type' not provided")
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+// The values of the "pivot" key in the output, for a version that is less
+// than, equal to, or greater than the pivot.
+const (
+	pivotLT = "lt"
+	pivotEQ = "eq"
+	pivotGT = "gt"
+)
+
+// output is a parsed version as it is written by the parse command. Pivot is
+// only set with --pivot.
+type output struct {
+	*version.Version
+	Pivot string `json:"pivot,omitempty"`
+}
+
+// comparePivot returns how v compares to pivot, as one of pivotLT, pivotEQ or
+// pivotGT.
+func comparePivot(v, pivot *version.Version) string {
+	switch cmp := version.Compare(v, pivot); {
+	case cmp < 0:
+		return pivotLT
+	case cmp > 0:
+		return pivotGT
+	default:
+		return pivotEQ
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+const (
+	serveCommand = "serve"
+
+	defaultMaxBodyBytes = 1 << 20
+	defaultMaxVersions  = 10000
+
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultReadTimeout       = 30 * time.Second
+	defaultWriteTimeout      = 30 * time.Second
+	defaultIdleTimeout       = 2 * time.Minute
+)
+
+// serverTimeouts limit how long the serve command waits on a client, so that
+// slow or idle clients cannot hold connections open forever.
+type serverTimeouts struct {
+	readHeader time.Duration
+	read       time.Duration
+	write      time.Duration
+	idle       time.Duration
+}
+
+// newHTTPServer returns a server for handler on addr with the given
+// timeouts.
+func newHTTPServer(addr string, handler http.Handler, timeouts serverTimeouts) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: timeouts.readHeader,
+		ReadTimeout:       timeouts.read,
+		WriteTimeout:      timeouts.write,
+		IdleTimeout:       timeouts.idle,
+	}
+}
+
+// request is the body of a request to any of the server's endpoints.
+type request struct {
+	Type     string   `json:"type"`
+	Versions []string `json:"versions"`
+}
+
+// compareResponse is the body of a successful response from /compare. Result
+// is -1, 0 or 1 as the first version is less than, equal to, or greater than
+// the second.
+type compareResponse struct {
+	Result int `json:"result"`
+}
+
+// server handles the HTTP API of the serve command:
+//
+//   - POST /parse parses each version and responds with the same JSON as the
+//     parse command.
+//   - POST /sort does the same, but sorts the versions in ascending order.
+//   - POST /compare takes exactly two versions and responds with a
+//     compareResponse.
+//
+// Errors are reported with a 4xx status and the same JSON object that
+// --json-errors writes, where index is the position of the version in the
+// request.
+type server struct {
+	maxBodyBytes int64
+	maxVersions  int
+	mux          *http.ServeMux
+}
+
+func newServer(maxBodyBytes int64, maxVersions int) *server {
+	s := &server{
+		maxBodyBytes: maxBodyBytes,
+		maxVersions:  maxVersions,
+		mux:          http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/parse", s.handle(s.parse))
+	s.mux.HandleFunc("/sort", s.handle(s.sort))
+	s.mux.HandleFunc("/compare", s.handle(s.compare))
+	return s
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handlerError is an error to report to the client.
+type handlerError struct {
+	status int
+	jsonError
+}
+
+func newHandlerError(status int, kind, input string, index int, format string, args ...interface{}) *handlerError {
+	e := &handlerError{
+		status:    status,
+		jsonError: jsonError{Error: fmt.Sprintf(format, args...), Kind: kind, Input: input},
+	}
+	if index >= 0 {
+		e.Index = &index
+	}
+	return e
+}
+
+// handle returns an http.HandlerFunc that decodes the request body, parses
+// the versions in it, and passes them to f. The value that f returns is
+// written as the JSON response.
+func (s *server) handle(f func([]*version.Version) (interface{}, *handlerError)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, jsonError{Error: fmt.Sprintf("%s requires a POST request", r.URL.Path), Kind: usageKind})
+			return
+		}
+
+		vs, herr := s.parseRequest(w, r)
+		if herr == nil {
+			var resp interface{}
+			resp, herr = f(vs)
+			if herr == nil {
+				writeJSON(w, http.StatusOK, resp)
+				return
+			}
+		}
+		writeJSON(w, herr.status, herr.jsonError)
+	}
+}
+
+func (s *server) parseRequest(w http.ResponseWriter, r *http.Request) ([]*version.Version, *handlerError) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, s.maxBodyBytes))
+	if err != nil {
+		// MaxBytesReader does not return a distinct error type in the Go
+		// releases we support, so any read error is reported as this.
+		return nil, newHandlerError(http.StatusRequestEntityTooLarge, ioKind, "", -1, "Error reading request body, which may be larger than the limit of %d bytes: %s", s.maxBodyBytes, err)
+	}
+
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, newHandlerError(http.StatusBadRequest, usageKind, "", -1, "Error decoding request body as JSON: %s", err)
+	}
+	if len(req.Versions) > s.maxVersions {
+		return nil, newHandlerError(http.StatusRequestEntityTooLarge, usageKind, "", -1, "A request can contain at most %d versions, but this one contains %d", s.maxVersions, len(req.Versions))
+	}
+
+	parse, ok := parserFor(req.Type)
+	if !ok {
+		return nil, newHandlerError(http.StatusBadRequest, usageKind, req.Type, -1, "Unknown version type requested: %s", req.Type)
+	}
+
+	vs := make([]*version.Version, len(req.Versions))
+	for i, ver := range req.Versions {
+		v, err := parse(ver)
+		if err != nil {
+			return nil, newHandlerError(http.StatusBadRequest, parseKind, ver, i, "Error parsing %s as %s: %s", ver, req.Type, err)
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+func (s *server) parse(vs []*version.Version) (interface{}, *handlerError) {
+	return vs, nil
+}
+
+func (s *server) sort(vs []*version.Version) (interface{}, *handlerError) {
+	if err := version.Sort(vs); err != nil {
+		return nil, newHandlerError(http.StatusBadRequest, parseKind, "", -1, "Error sorting versions: %s", err)
+	}
+	return vs, nil
+}
+
+func (s *server) compare(vs []*version.Version) (interface{}, *handlerError) {
+	if len(vs) != 2 {
+		return nil, newHandlerError(http.StatusBadRequest, usageKind, "", -1, "/compare requires exactly 2 versions, but got %d", len(vs))
+	}
+	return compareResponse{Result: version.Compare(vs[0], vs[1])}, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// The status has been sent, so there is nothing useful to do if
+	// encoding fails.
+	_ = json.NewEncoder(w).Encode(v)
+}
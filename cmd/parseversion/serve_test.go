@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func post(t *testing.T, s *server, path, body string) (int, string) {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	resp := rec.Result()
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"), path)
+	b, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(b)
+}
+
+func assertJSONError(t *testing.T, expected jsonError, body string) {
+	var actual jsonError
+	require.NoError(t, json.Unmarshal([]byte(body), &actual), body)
+	assert.Equal(t, expected, actual)
+}
+
+func TestServeParse(t *testing.T) {
+	s := newServer(defaultMaxBodyBytes, defaultMaxVersions)
+
+	status, body := post(t, s, "/parse", `{"type":"python","versions":["2.0b1","1.0"]}`)
+	assert.Equal(t, http.StatusOK, status)
+
+	expected, err := json.Marshal([]*version.Version{parseOrFatal(t, "python", "2.0b1"), parseOrFatal(t, "python", "1.0")})
+	require.NoError(t, err)
+	assert.Equal(t, string(expected)+"\n", body, "the output is the same as the parse command's")
+	assert.NoError(t, version.ValidateOutputJSON([]byte(body)))
+
+	status, body = post(t, s, "/parse", `{"type":"semver","versions":[]}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "[]\n", body)
+}
+
+func TestServeSort(t *testing.T) {
+	s := newServer(defaultMaxBodyBytes, defaultMaxVersions)
+
+	status, body := post(t, s, "/sort", `{"type":"python","versions":["2.0","1.0","2.0b1","1.0.post1"]}`)
+	assert.Equal(t, http.StatusOK, status)
+
+	var actual []*version.Version
+	require.NoError(t, json.Unmarshal([]byte(body), &actual))
+	var originals []string
+	for _, v := range actual {
+		originals = append(originals, v.Original)
+	}
+	assert.Equal(t, []string{"1.0", "1.0.post1", "2.0b1", "2.0"}, originals)
+}
+
+func TestServeCompare(t *testing.T) {
+	s := newServer(defaultMaxBodyBytes, defaultMaxVersions)
+
+	for body, expected := range map[string]string{
+		`{"type":"semver","versions":["1.0.0","2.0.0"]}`:       `{"result":-1}`,
+		`{"type":"generic","versions":["1.0","1.0.0"]}`:        `{"result":0}`,
+		`{"type":"ruby","versions":["1.0.0","1.0.0.beta1"]}`:   `{"result":1}`,
+		`{"type":"python","versions":["1.0.dev1","1.0a1"]}`:    `{"result":-1}`,
+		`{"type":"php","versions":["1.0.0-p1","1.0.0-patch"]}`: `{"result":1}`,
+	} {
+		status, actual := post(t, s, "/compare", body)
+		assert.Equal(t, http.StatusOK, status, body)
+		assert.Equal(t, expected+"\n", actual, body)
+	}
+
+	status, body := post(t, s, "/compare", `{"type":"semver","versions":["1.0.0"]}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assertJSONError(t, jsonError{Error: "/compare requires exactly 2 versions, but got 1", Kind: "usage"}, body)
+}
+
+func TestServeErrors(t *testing.T) {
+	s := newServer(100, 3)
+	one := 1
+
+	for _, path := range []string{"/parse", "/sort", "/compare"} {
+		status, body := post(t, s, path, `{"type":"cobol","versions":["1.0","2.0"]}`)
+		assert.Equal(t, http.StatusBadRequest, status, path)
+		assertJSONError(t, jsonError{Error: "Unknown version type requested: cobol", Kind: "usage", Input: "cobol"}, body)
+
+		status, body = post(t, s, path, `{"type":"semver","versions":["1.0.0","1.0"]}`)
+		assert.Equal(t, http.StatusBadRequest, status, path)
+		assertJSONError(t, jsonError{
+			Error: "Error parsing 1.0 as semver: Version does not match semver regex: 1.0",
+			Kind:  "parse",
+			Input: "1.0",
+			Index: &one,
+		}, body)
+
+		status, body = post(t, s, path, `{"type":"semver","versions":["1.0.0","1.0.1","1.0.2","1.0.3"]}`)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status, path)
+		assertJSONError(t, jsonError{Error: "A request can contain at most 3 versions, but this one contains 4", Kind: "usage"}, body)
+
+		status, body = post(t, s, path, `{"type":"semver","versions":["`+strings.Repeat("1", 100)+`"]}`)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status, path)
+		assert.Contains(t, body, `"kind":"io"`, path)
+
+		status, body = post(t, s, path, `{"type":`)
+		assert.Equal(t, http.StatusBadRequest, status, path)
+		assert.Contains(t, body, `"kind":"usage"`, path)
+
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code, path)
+		assert.Equal(t, http.MethodPost, rec.Header().Get("Allow"), path)
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/nope", strings.NewReader("{}")))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestNewHTTPServer(t *testing.T) {
+	handler := newServer(defaultMaxBodyBytes, defaultMaxVersions)
+	hs := newHTTPServer(":8080", handler, serverTimeouts{
+		readHeader: time.Second,
+		read:       2 * time.Second,
+		write:      3 * time.Second,
+		idle:       4 * time.Second,
+	})
+	assert.Equal(t, ":8080", hs.Addr)
+	assert.Equal(t, handler, hs.Handler)
+	assert.Equal(t, time.Second, hs.ReadHeaderTimeout)
+	assert.Equal(t, 2*time.Second, hs.ReadTimeout)
+	assert.Equal(t, 3*time.Second, hs.WriteTimeout)
+	assert.Equal(t, 4*time.Second, hs.IdleTimeout)
+}
+
+func parseOrFatal(t *testing.T, typ, ver string) *version.Version {
+	parse, ok := parserFor(typ)
+	require.True(t, ok, typ)
+	v, err := parse(ver)
+	require.NoError(t, err)
+	return v
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+const statsCommand = "stats"
+
+// subSchemes is the set of types whose versions can be parsed as more than
+// one ParsedAs, for which stats reports a breakdown by ParsedAs.
+var subSchemes = map[string]bool{
+	"perl":   true,
+	"python": true,
+}
+
+// stats summarizes a list of versions. Count is the number of versions that
+// were parsed, and does not include Failures. Min, Max, LatestStable and
+// Median are the original strings, and are empty if there is no such version.
+type stats struct {
+	Count        int            `json:"count"`
+	Failures     int            `json:"failures"`
+	Min          string         `json:"min,omitempty"`
+	Max          string         `json:"max,omitempty"`
+	LatestStable string         `json:"latest_stable,omitempty"`
+	PreReleases  int            `json:"pre_releases"`
+	Median       string         `json:"median,omitempty"`
+	Schemes      map[string]int `json:"schemes,omitempty"`
+}
+
+// statsCollector builds stats one version at a time. Only the current min,
+// max and latest stable versions are kept, unless the median is wanted, in
+// which case every version must be kept to sort them.
+type statsCollector struct {
+	typ    string
+	parse  func(string) (*version.Version, error)
+	median bool
+
+	stats
+	min, max, latestStable *version.Version
+	all                    version.CompactSet
+}
+
+func newStatsCollector(typ string, median bool) (*statsCollector, bool) {
+	parse, ok := parserFor(typ)
+	if !ok {
+		return nil, false
+	}
+	c := &statsCollector{typ: typ, parse: parse, median: median}
+	if subSchemes[typ] {
+		c.Schemes = map[string]int{}
+	}
+	return c, true
+}
+
+func (c *statsCollector) add(s string) error {
+	v, err := c.parse(s)
+	if err != nil {
+		c.Failures++
+		return nil
+	}
+
+	c.Count++
+	if c.min == nil || version.Compare(v, c.min) < 0 {
+		c.min = v
+	}
+	if c.max == nil || version.Compare(v, c.max) > 0 {
+		c.max = v
+	}
+	if v.IsPreRelease() {
+		c.PreReleases++
+	} else if c.latestStable == nil || version.Compare(v, c.latestStable) > 0 {
+		c.latestStable = v
+	}
+	if c.Schemes != nil {
+		c.Schemes[v.ParsedAs.String()]++
+	}
+	if c.median {
+		return c.all.Add(v)
+	}
+	return nil
+}
+
+func (c *statsCollector) result() stats {
+	s := c.stats
+	for _, v := range []struct {
+		from *version.Version
+		to   *string
+	}{{c.min, &s.Min}, {c.max, &s.Max}, {c.latestStable, &s.LatestStable}} {
+		if v.from != nil {
+			*v.to = v.from.Original
+		}
+	}
+	if c.median && c.all.Len() > 0 {
+		c.all.Sort()
+		s.Median = c.all.At((c.all.Len() - 1) / 2).Original
+	}
+	return s
+}
+
+// runStats prints stats for the versions passed as arguments, or read from
+// stdin one per line with --stdin.
+func (pv *parseversion) runStats() {
+	c, ok := newStatsCollector(pv.statsType, pv.median)
+	if !ok {
+		pv.fail(usageKind, pv.statsType, -1, "Unknown version type requested: %s", pv.statsType)
+	}
+
+	add := func(s string) {
+		if err := c.add(s); err != nil {
+			pv.fail(ioKind, s, -1, "Error storing %s: %s", s, err)
+		}
+	}
+	if pv.stdin {
+		if len(pv.statsVersions) > 0 {
+			pv.usageError("You cannot pass versions as arguments with --stdin.")
+		}
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if l := strings.TrimSpace(scanner.Text()); l != "" {
+				add(l)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			pv.fail(ioKind, "", -1, "Error reading versions from stdin: %s", err)
+		}
+	} else {
+		for _, s := range pv.statsVersions {
+			add(s)
+		}
+	}
+
+	s := c.result()
+	if pv.statsJSON {
+		j, err := json.Marshal(s)
+		if err != nil {
+			pv.fail(ioKind, "", -1, "Error marshalling %+v as JSON: %s", s, err)
+		}
+		if _, err = fmt.Println(string(j)); err != nil {
+			pv.ioError(err)
+		}
+		return
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	writeStats(w, s, pv.median)
+	if err := w.Flush(); err != nil {
+		pv.ioError(err)
+	}
+}
+
+func writeStats(w io.Writer, s stats, median bool) {
+	fmt.Fprintf(w, "Count:          %d\n", s.Count)
+	fmt.Fprintf(w, "Failures:       %d\n", s.Failures)
+	fmt.Fprintf(w, "Min:            %s\n", s.Min)
+	fmt.Fprintf(w, "Max:            %s\n", s.Max)
+	fmt.Fprintf(w, "Latest stable:  %s\n", s.LatestStable)
+	fmt.Fprintf(w, "Pre-releases:   %d\n", s.PreReleases)
+	if median {
+		fmt.Fprintf(w, "Median:         %s\n", s.Median)
+	}
+	if s.Schemes != nil {
+		var names []string
+		for name := range s.Schemes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintln(w, "Schemes:")
+		for _, name := range names {
+			fmt.Fprintf(w, "  %-14s%d\n", name+":", s.Schemes[name])
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+// These are copied from the ordering tests in pkg/version. Each list is in
+// ascending order with no equal versions.
+
+var semVerOrder = []string{
+	"0.0.0-foo",
+	"0.0.0",
+	"0.0.1",
+	"0.1.2",
+	"0.9.0",
+	"0.9.9",
+	"0.10.0",
+	"0.99.0",
+	"1.0.0-alpha",
+	"1.0.0-alpha.0",
+	"1.0.0-alpha.1",
+	"1.0.0-alpha.100",
+	"1.0.0-alpha.100.0",
+	"1.0.0-alpha.100.a",
+	"1.0.0-alpha.beta",
+	"1.0.0-beta",
+	"1.0.0-beta.2",
+	"1.0.0-beta.11",
+	"1.0.0-rc.1",
+	"1.0.0",
+	"1.0.1",
+	"1.2.2",
+	"1.2.3-4",
+	"1.2.3-5",
+	"1.2.3-4-foo",
+	"1.2.3-5-Foo",
+	"1.2.3-5-foo",
+	"1.2.3-R2",
+	"1.2.3-a",
+	"1.2.3-a.0",
+	"1.2.3-a.5",
+	"1.2.3-a.10",
+	"1.2.3-a.100",
+	"1.2.3-a.b",
+	"1.2.3-a.b.c.5.d.100",
+	"1.2.3-a.b.c.10.d.5",
+	"1.2.3-alpha.0.2",
+	"1.2.3-alpha.0.pr.1",
+	"1.2.3-alpha.0.pr.2",
+	"1.2.3-asdf",
+	"1.2.3-pre",
+	"1.2.3-r100",
+	"1.2.3-r2",
+	"1.2.3",
+	"1.2.4-1",
+	"1.2.4",
+	"2.0.0",
+	"2.3.4",
+	"2.7.2+asdf",
+	"3.0.0",
+	"9.9.9-alpha.0.pr.1",
+}
+
+var rubyOrder = []string{
+	"0.0.beta",
+	"0.beta.1",
+	"0",
+	"1.A",
+	"1.0.a",
+	"1-a",
+	"1.0.0-alpha",
+	"1.0.0-alpha.1",
+	"1.0.0-beta.2",
+	"1.0.0-beta.11",
+	"1.0.0-rc.1",
+	"1.0.0-1",
+	"1",
+	"1.1.rc10",
+	"1.1",
+	"1.2.0.a",
+	"1.2.b1",
+	"1.2.d.42",
+	"1.2.pre.1",
+	"1.2",
+	"1.2.3.a.4",
+	"1.2.3",
+	"1.3",
+	"1.8.2.A",
+	"1.8.2.a",
+	"1.8.2.a9",
+	"1.8.2.a10",
+	"1.8.2.b",
+	"1.8.2",
+	"1.9.a",
+	"1.9.0.dev",
+	"1.9.3.alpha.5",
+	"1.9.3",
+	"2.9.b",
+	"2.9",
+	"5.a",
+	"5.0.0.rc2",
+	"5.x",
+	"5",
+	"5.1",
+	"5.2.4.a",
+	"5.2.4.a10",
+	"0005.2.4",
+	"5.3",
+	"6",
+	"9.8.7",
+	"9.8.8",
+	"22.1.50.0.d",
+	"22.1.50.0",
+}
+
+var phpOrder = []string{
+	"0000000",
+	"0",
+	"0000000000001",
+	"1.0.0.dev",
+	"1.0.0.alpha",
+	"1.0.0.alpha00000000000",
+	"1.0.0.alpha1",
+	"1.0.0.alpha2.99.1",
+	"1.0.0.beta",
+	"1.0.0.beta0.09",
+	"1.0.0.beta009",
+	"1.0.0.RC",
+	"1.0.0",
+	"1.0.0.p",
+	"1.0.0.patch0",
+	"1.0.0.patch1.0",
+	"1.0.0.patch2",
+	"1.0.0.1",
+	"1.2.3",
+	"1.2.3.4",
+	"2.0.0.RC",
+	"2.0.0-stable",
+	"2.0.0.pl",
+	"2.1",
+	"2.2",
+	"2.2.p",
+	"2.2.0.1",
+	"4.3.0",
+	"5.3.dev",
+	"5.3.0",
+	"5.4",
+	"5.9999999",
+	"5.9999999.9999999",
+	"5.9999999.9999999.p",
+	"5.9999999.9999999.9999999",
+	"5.9999999.9999999.9999999.p",
+	"5.10000000",
+	"5.10000001",
+	"6.0",
+	"2010-01-02-dev",
+	"2010-01-02-a",
+	"2010-01-02",
+	"2010.01.02.dev",
+	"2010.01.02.a",
+	"2010.01.02-STABLE",
+	"2010.01.02.p",
+	"2010.01.02.p0",
+	"2010.01.02.p1",
+	"2010-01-02-p",
+	"2010-01-02-p0",
+	"2010-01-02-p1",
+	"2010.1.555",
+	"2010.10.200",
+	"2010.11",
+	"20112.dev",
+	"20112.0alpha",
+	"20112.beta",
+	"20112.",
+	"20112.0p",
+	"20112.10.10.10",
+	"20112.203040dev",
+	"20112.203040alpha",
+	"20112.203040.0beta",
+	"20112.203040",
+	"20112.203040.p1",
+	"20112.203040.0p0123",
+	"20113",
+	"201101",
+	"201102.dev",
+	"201102.alpha",
+	"201102.beta",
+	"201102.",
+	"201102.0alpha",
+	"201102.0p",
+	"201102.10.10.10",
+	"201102.203040dev",
+	"201102.203040alpha",
+	"201102.203040",
+	"201102.203040.0beta",
+	"201102.203040.0",
+	"201102.203040.0p0123",
+	"201102-203040-p",
+	"201102-203040-p1",
+	"201102-p",
+	"201103",
+	"2010101",
+	"2010102.dev",
+	"2010102.beta",
+	"2010102.",
+	"2010102-p",
+	"20100101",
+	"20100102.dev",
+	"20100102.alpha",
+	"20100102.beta",
+	"20100102.",
+	"20100102.0alpha",
+	"20100102.0p",
+	"20100102.10.10.10",
+	"20100102.203040dev",
+	"20100102.203040alpha",
+	"20100102.203040",
+	"20100102.203040.0beta",
+	"20100102.203040.0",
+	"20100102.203040.0p0123",
+	"20100102-203040-p",
+	"20100102-203040-p1",
+	"20100102-p",
+	"20100103",
+	"201000101",
+	"201000102.dev",
+	"201000102.alpha",
+	"201000102.beta",
+	"201000102.",
+	"201000102-p",
+	"201000103",
+	"2010000101",
+	"2010000102.dev",
+	"2010000102.alpha",
+	"2010000102.beta",
+	"2010000102.",
+	"2010000102.0alpha",
+	"2010000102.0p",
+	"2010000102.10.10.10",
+	"2010000102.203040dev",
+	"2010000102.203040alpha",
+	"2010000102.203040",
+	"2010000102.203040.0beta",
+	"2010000102.203040.0",
+	"2010000102.203040.0p0123",
+	"2010000102-203040-p",
+	"2010000102-203040-p1",
+	"2010000102-999999999-p1",
+	"2010000102-p",
+	"2010000103",
+}
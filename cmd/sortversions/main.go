@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const appVersion = "0.0.1"
+
+// parserFor returns a func that parses versions of the named type, which is
+// one of the names returned by version.TypeNames, and false if the type is
+// not known. These are the same types that parseversion accepts.
+func parserFor(typ string) (func(string) (*version.Version, error), bool) {
+	pa, ok := version.ParsedAsFromName(typ)
+	if !ok {
+		return nil, false
+	}
+	return func(s string) (*version.Version, error) { return version.ParseComparable(pa, s) }, true
+}
+
+// autoTypes is the order in which --auto tries each type. Types that accept
+// fewer versions come first, so that the most specific type wins.
+var autoTypes = []string{"semver", "php", "perl", "ruby", "python", "generic"}
+
+func main() {
+	sv, err := new()
+	if err != nil {
+		sv.app.FatalUsage("%s\n", err)
+	}
+
+	if sv.auto == (sv.typ != "") {
+		sv.app.FatalUsage("You must pass exactly one of --type or --auto.\n")
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	var lines func(func(string))
+	if sv.auto {
+		// The type depends on every line, so they must all be read before
+		// any can be parsed.
+		var all []string
+		readLines(scanner, func(l string) { all = append(all, l) })
+		sv.typ = detectType(all)
+		lines = func(f func(string)) {
+			for _, l := range all {
+				f(l)
+			}
+		}
+	} else {
+		if _, ok := parserFor(sv.typ); !ok {
+			sv.app.FatalUsage("Unknown version type requested: %s\n", sv.typ)
+		}
+		lines = func(f func(string)) { readLines(scanner, f) }
+	}
+
+	var (
+		set         version.CompactSet
+		failures    int
+		unparseable []string
+	)
+	parse, _ := parserFor(sv.typ)
+	lines(func(l string) {
+		v, err := parse(l)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing %s as %s: %s\n", l, sv.typ, err)
+			failures++
+			if sv.lenient {
+				unparseable = append(unparseable, l)
+			}
+			return
+		}
+		if sv.stableOnly && v.IsPreRelease() {
+			return
+		}
+		if err := set.Add(v); err != nil {
+			log.Fatalf("Error storing %s: %s", l, err)
+		}
+	})
+
+	set.Sort()
+	sorted := make([]string, 0, set.Len())
+	for i := 0; i < set.Len(); i++ {
+		if sv.unique && i > 0 && set.At(i).Compare(set.At(i-1)) == 0 {
+			continue
+		}
+		sorted = append(sorted, set.At(i).Original)
+	}
+	if sv.reverse {
+		for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+			sorted[i], sorted[j] = sorted[j], sorted[i]
+		}
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	for _, l := range append(sorted, unparseable...) {
+		fmt.Fprintln(w, l)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalf("Error writing output: %s", err)
+	}
+
+	if failures > 0 && !sv.lenient {
+		os.Exit(1)
+	}
+}
+
+// readLines calls f with each line read by scanner, without leading and
+// trailing space. Empty lines are skipped.
+func readLines(scanner *bufio.Scanner, f func(string)) {
+	for scanner.Scan() {
+		if l := strings.TrimSpace(scanner.Text()); l != "" {
+			f(l)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatalf("Error reading versions from stdin: %s", err)
+	}
+}
+
+// detectType returns the type in autoTypes that can parse the most lines. If
+// more than one type can parse the same number, the first one wins.
+func detectType(lines []string) string {
+	best, bestCount := autoTypes[0], -1
+	for _, typ := range autoTypes {
+		parse, _ := parserFor(typ)
+		count := 0
+		for _, l := range lines {
+			if _, err := parse(l); err == nil {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = typ, count
+		}
+	}
+	return best
+}
+
+type sortversions struct {
+	app        *kingpin.Application
+	typ        string
+	auto       bool
+	reverse    bool
+	unique     bool
+	stableOnly bool
+	lenient    bool
+}
+
+const extraDocs = `
+
+This command reads versions from stdin, one per line, and prints them sorted
+in ascending order. Lines that cannot be parsed are reported on stderr and
+left out of the output, and the command exits with a non-zero status. With
+--lenient they are printed after the sorted versions, in the order they were
+read, and the exit status is zero.
+
+With --auto the type is the one that can parse the most lines, trying semver,
+php, perl, ruby, python and generic in that order. This means that all of the
+input must be read before any of it is parsed. Pass --type when the type is
+known.
+
+The following version types are available. Perl and Python versions may use
+any of the schemes for that language, and cpe versions are the version and
+update attributes of a CPE name joined with a colon, such as "1.1.1:k".
+
+`
+
+func new() (*sortversions, error) {
+	var docs strings.Builder
+	docs.WriteString(extraDocs)
+	for _, typ := range version.TypeNames() {
+		fmt.Fprintf(&docs, "  * %s\n", typ)
+	}
+
+	app := kingpin.New("sortversions", "A command line tool for sorting version strings.").
+		Author("ActiveState, Inc. <info@activestate.com>").
+		Version(appVersion).
+		UsageWriter(os.Stdout).
+		UsageTemplate(kingpin.DefaultUsageTemplate + docs.String())
+	app.HelpFlag.Short('h')
+
+	sv := &sortversions{app: app}
+	app.Flag("type", "The type of the versions").Short('t').StringVar(&sv.typ)
+	app.Flag("auto", "Detect the type of the versions").BoolVar(&sv.auto)
+	app.Flag("reverse", "Sort in descending order").Short('r').BoolVar(&sv.reverse)
+	app.Flag("unique", "Only print the first of each set of equal versions").Short('u').BoolVar(&sv.unique)
+	app.Flag("stable-only", "Leave out pre-release versions").BoolVar(&sv.stableOnly)
+	app.Flag("lenient", "Print unparseable lines at the end instead of failing").BoolVar(&sv.lenient)
+
+	_, err := app.Parse(os.Args[1:])
+
+	return sv, err
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var binary string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "sortversions")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	binary = filepath.Join(dir, "sortversions")
+	if out, err := exec.Command("go", "build", "-o", binary, ".").CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "building sortversions failed: %s\n%s", err, out)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+type runResult struct {
+	stdout, stderr string
+	exitCode       int
+}
+
+func run(t *testing.T, stdin []string, args ...string) runResult {
+	cmd := exec.Command(binary, args...)
+	cmd.Stdin = strings.NewReader(lines(stdin))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	r := runResult{stdout: stdout.String(), stderr: stderr.String()}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		r.exitCode = exitErr.ExitCode()
+	} else {
+		require.NoError(t, err)
+	}
+	return r
+}
+
+func lines(ls []string) string {
+	if len(ls) == 0 {
+		return ""
+	}
+	return strings.Join(ls, "\n") + "\n"
+}
+
+func shuffled(ls []string, seed int64) []string {
+	s := append([]string{}, ls...)
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+	return s
+}
+
+func reversed(ls []string) []string {
+	r := make([]string, len(ls))
+	for i, l := range ls {
+		r[len(ls)-1-i] = l
+	}
+	return r
+}
+
+var corpora = map[string][]string{
+	"semver": semVerOrder,
+	"ruby":   rubyOrder,
+	"php":    phpOrder,
+}
+
+func TestSortCorpora(t *testing.T) {
+	for typ, expected := range corpora {
+		t.Run(typ, func(t *testing.T) {
+			for seed := int64(1); seed <= 3; seed++ {
+				in := shuffled(expected, seed)
+
+				r := run(t, in, "--type", typ)
+				assert.Equal(t, 0, r.exitCode)
+				assert.Equal(t, "", r.stderr)
+				assert.Equal(t, lines(expected), r.stdout)
+
+				r = run(t, in, "--type", typ, "--reverse")
+				assert.Equal(t, 0, r.exitCode)
+				assert.Equal(t, lines(reversed(expected)), r.stdout)
+			}
+		})
+	}
+}
+
+func TestAuto(t *testing.T) {
+	for typ, expected := range corpora {
+		r := run(t, shuffled(expected, 1), "--auto")
+		assert.Equal(t, 0, r.exitCode, typ)
+		assert.Equal(t, lines(expected), r.stdout, typ)
+	}
+}
+
+func TestTypes(t *testing.T) {
+	r := run(t, []string{"1.0-sp1", "1.0", "1.0-SNAPSHOT"}, "--type", "maven")
+	assert.Equal(t, 0, r.exitCode, "every type in version.TypeNames can be passed to --type")
+	assert.Equal(t, lines([]string{"1.0-SNAPSHOT", "1.0", "1.0-sp1"}), r.stdout)
+
+	r = run(t, []string{"2.0", "1.0-foo", "1.5"}, "--type", "python")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, lines([]string{"1.0-foo", "1.5", "2.0"}), r.stdout, "python accepts legacy versions")
+}
+
+func TestUnique(t *testing.T) {
+	in := []string{"1.2", "1.10", "1.2.0", "1.2.0.0", "1.10.0", "1.1"}
+
+	r := run(t, in, "-t", "generic")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, lines([]string{"1.1", "1.2", "1.2.0", "1.2.0.0", "1.10", "1.10.0"}), r.stdout, "equal versions keep their input order")
+
+	r = run(t, in, "-t", "generic", "--unique")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, lines([]string{"1.1", "1.2", "1.10"}), r.stdout, "the first of each set of equal versions is kept")
+
+	r = run(t, in, "-t", "generic", "--unique", "--reverse")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, lines([]string{"1.10", "1.2", "1.1"}), r.stdout)
+}
+
+func TestStableOnly(t *testing.T) {
+	in := shuffled(semVerOrder, 1)
+	var expected []string
+	for _, v := range semVerOrder {
+		if !strings.Contains(v, "-") {
+			expected = append(expected, v)
+		}
+	}
+
+	r := run(t, in, "-t", "semver", "--stable-only")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, lines(expected), r.stdout)
+
+	r = run(t, []string{"1.0.0", "1.0.0.RC1", "1.0.0-p1", "1.0.0-beta"}, "-t", "php", "--stable-only")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, lines([]string{"1.0.0", "1.0.0-p1"}), r.stdout)
+}
+
+func TestUnparseable(t *testing.T) {
+	in := []string{"2.0.0", "not a version", "1.0.0", "", "  1.5.0  ", "1.0", "0.1.0"}
+
+	r := run(t, in, "-t", "semver")
+	assert.Equal(t, 1, r.exitCode)
+	assert.Equal(t, lines([]string{"0.1.0", "1.0.0", "1.5.0", "2.0.0"}), r.stdout)
+	assert.Contains(t, r.stderr, "Error parsing not a version as semver")
+	assert.Contains(t, r.stderr, "Error parsing 1.0 as semver")
+
+	r = run(t, in, "-t", "semver", "--lenient")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, lines([]string{"0.1.0", "1.0.0", "1.5.0", "2.0.0", "not a version", "1.0"}), r.stdout)
+	assert.Contains(t, r.stderr, "Error parsing not a version as semver")
+
+	r = run(t, in, "-t", "semver", "--lenient", "--reverse")
+	assert.Equal(t, 0, r.exitCode)
+	assert.Equal(t, lines([]string{"2.0.0", "1.5.0", "1.0.0", "0.1.0", "not a version", "1.0"}), r.stdout, "unparseable lines stay in input order")
+}
+
+func TestUsageErrors(t *testing.T) {
+	for name, args := range map[string][]string{
+		"no type":       {},
+		"type and auto": {"--type", "semver", "--auto"},
+		"unknown type":  {"--type", "cobol"},
+	} {
+		r := run(t, []string{"1.0.0"}, args...)
+		assert.Equal(t, 1, r.exitCode, name)
+		assert.Contains(t, r.stderr, "usage: sortversions", name)
+		assert.Contains(t, r.stderr, "\n\n  * conan\n  * cpe\n", name)
+		assert.Equal(t, "", r.stdout, name)
+	}
+}
@@ -3,10 +3,15 @@ module github.com/ActiveState/langtools
 go 1.12
 
 require (
+	github.com/Masterminds/semver/v3 v3.1.1
 	github.com/alecthomas/template v0.0.0-20190718012654-fb15b899a751 // indirect
 	github.com/alecthomas/units v0.0.0-20190924025748-f65c72e2690d // indirect
+	github.com/apache/arrow/go/arrow v0.0.0-20201229220542-30ce2eb5d4dc
 	github.com/ericlagergren/decimal v0.0.0-20191206042408-88212e6cfca9
+	github.com/pelletier/go-toml v1.9.5
 	github.com/stretchr/testify v1.4.0
-	golang.org/x/text v0.3.2
+	github.com/xeipuuv/gojsonschema v1.2.0
+	go.mongodb.org/mongo-driver v1.3.7
+	golang.org/x/text v0.3.3
 	gopkg.in/alecthomas/kingpin.v2 v2.2.6
 )
@@ -0,0 +1,74 @@
+package artifact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/name"
+)
+
+var (
+	// composerPlatformPackage matches the names of Composer's platform
+	// packages, which are provided by the system rather than installed. This
+	// is the pattern Composer uses.
+	composerPlatformPackage = regexp.MustCompile(`(?i)^(?:php(?:-64bit|-ipv6|-zts|-debug)?|hhvm|(?:ext|lib)-[a-z0-9](?:[_.-]?[a-z0-9]+)*|composer(?:-(?:plugin|runtime)-api)?)$`)
+	// composerStability matches a stability flag at the end of a constraint.
+	composerStability = regexp.MustCompile(`(?i)@(stable|RC|beta|alpha|dev)$`)
+)
+
+// ComposerRequirement is a requirement on a Composer package, such as
+// "symfony/console:^6.2".
+type ComposerRequirement struct {
+	// Name is the package name normalized with name.NormalizeComposer.
+	Name string
+	// Platform is true if the package is a platform package, such as "php",
+	// "ext-json" or "lib-curl".
+	Platform bool
+	// Constraint is the version constraint, without any stability flag. It is
+	// "*" if the requirement has no constraint, or if it only has a stability
+	// flag.
+	Constraint string
+	// Stability is the stability flag from the end of the constraint, such
+	// as "beta" for "^1.0@beta", in lower case. It is empty if there is no
+	// flag, or if the constraint has more than one, as in
+	// "^1.0@beta || ^2.0@dev", in which case they are all left in
+	// Constraint.
+	Stability string
+}
+
+// ParseComposerRequirement parses a requirement in the "name:constraint"
+// form used by "composer require", such as "symfony/console:^6.2" or
+// "php:>=8.1". A name with no constraint, such as "symfony/console", means
+// any version. Space around the colon and constraint is ignored.
+//
+// The name must be a valid Composer package name, or a platform package. The
+// constraint is not parsed, but it cannot be empty if there is a colon.
+func ParseComposerRequirement(s string) (*ComposerRequirement, error) {
+	n, constraint := s, "*"
+	if i := strings.IndexByte(s, ':'); i >= 0 {
+		n, constraint = s[:i], strings.TrimSpace(s[i+1:])
+		if constraint == "" {
+			return nil, fmt.Errorf("empty constraint in composer requirement: %s", s)
+		}
+	}
+
+	req := &ComposerRequirement{Name: name.NormalizeComposer(n)}
+	if composerPlatformPackage.MatchString(req.Name) {
+		req.Platform = true
+	} else if err := name.ValidateComposer(req.Name); err != nil {
+		return nil, fmt.Errorf("invalid name in composer requirement %s: %s", s, err)
+	}
+
+	if strings.Count(constraint, "@") == 1 {
+		if m := composerStability.FindStringSubmatchIndex(constraint); m != nil {
+			req.Stability = strings.ToLower(constraint[m[2]:m[3]])
+			constraint = strings.TrimSpace(constraint[:m[0]])
+			if constraint == "" {
+				constraint = "*"
+			}
+		}
+	}
+	req.Constraint = constraint
+	return req, nil
+}
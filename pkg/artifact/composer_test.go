@@ -0,0 +1,51 @@
+package artifact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseComposerRequirement(t *testing.T) {
+	tests := map[string]ComposerRequirement{
+		"symfony/console:^6.2":       {Name: "symfony/console", Constraint: "^6.2"},
+		"Monolog/Monolog : ~2.9 ":    {Name: "monolog/monolog", Constraint: "~2.9"},
+		"laravel/framework":          {Name: "laravel/framework", Constraint: "*"},
+		"phpunit/phpunit:^1.0@beta":  {Name: "phpunit/phpunit", Constraint: "^1.0", Stability: "beta"},
+		"doctrine/orm:3.0.x-dev@DEV": {Name: "doctrine/orm", Constraint: "3.0.x-dev", Stability: "dev"},
+		"doctrine/dbal:@dev":         {Name: "doctrine/dbal", Constraint: "*", Stability: "dev"},
+		"guzzlehttp/guzzle:^6.0@beta || ^7.0@dev": {
+			Name: "guzzlehttp/guzzle", Constraint: "^6.0@beta || ^7.0@dev",
+		},
+		"symfony/yaml:>=5.4 <7.0":  {Name: "symfony/yaml", Constraint: ">=5.4 <7.0"},
+		"php:>=8.1":                {Name: "php", Platform: true, Constraint: ">=8.1"},
+		"php-64bit":                {Name: "php-64bit", Platform: true, Constraint: "*"},
+		"ext-json:*":               {Name: "ext-json", Platform: true, Constraint: "*"},
+		"EXT-mbstring":             {Name: "ext-mbstring", Platform: true, Constraint: "*"},
+		"lib-curl:>=7.60":          {Name: "lib-curl", Platform: true, Constraint: ">=7.60"},
+		"composer-plugin-api:^2.0": {Name: "composer-plugin-api", Platform: true, Constraint: "^2.0"},
+	}
+
+	for s, expected := range tests {
+		req, err := ParseComposerRequirement(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, &expected, req, s)
+	}
+}
+
+func TestParseComposerRequirementErrors(t *testing.T) {
+	for s, expected := range map[string]string{
+		"symfony/console:":    "empty constraint in composer requirement: symfony/console:",
+		"console:^6.2":        `invalid name in composer requirement console:^6.2: "console" is not a valid Composer package name: it has no vendor`,
+		"symfony/con sole":    `invalid name in composer requirement symfony/con sole: "symfony/con sole" is not a valid Composer package name: ' ' at position 11 is not allowed`,
+		":^6.2":               "invalid name in composer requirement :^6.2: a Composer package name cannot be empty",
+		"-symfony/console:^1": `invalid name in composer requirement -symfony/console:^1: "-symfony/console" is not a valid Composer package name: it cannot start with '-'`,
+		"ext-:*":              `invalid name in composer requirement ext-:*: "ext-" is not a valid Composer package name: it has no vendor`,
+	} {
+		_, err := ParseComposerRequirement(s)
+		if assert.Error(t, err, s) {
+			assert.Equal(t, expected, err.Error(), s)
+		}
+	}
+}
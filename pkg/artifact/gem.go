@@ -0,0 +1,62 @@
+package artifact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+var (
+	// gemName matches the names that RubyGems allows. A name must also contain
+	// at least one letter.
+	gemName = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+	// gemPlatform matches the platforms that appear in gem file names, which
+	// are either a bare name like "java", or a CPU and OS with an optional
+	// version, such as "x86_64-linux", "arm64-darwin" or "x64-mingw-ucrt".
+	gemPlatform = regexp.MustCompile(`^(?:java|jruby|dalvik[0-9]*|[a-z0-9_]+-[a-z][a-z0-9_.]*(?:-[a-z0-9_.]+)?)$`)
+)
+
+// ParseGemFilename parses the file name of a RubyGems package, such as
+// "nokogiri-1.15.4-x86_64-linux.gem", without any directory. It returns the
+// gem name, the version parsed with version.ParseRuby, and the platform,
+// which is empty for pure Ruby gems.
+//
+// RubyGems replaces any hyphens in a version with ".pre.", so the version in
+// a file name never contains one, but both names and platforms can. The
+// hyphen-separated parts of the file name are tried as the version in turn
+// from the right, and the first one that is a valid version with a valid name
+// before it and either nothing or a valid platform after it is used.
+//
+// It returns an error if fn does not end with .gem, or if there is no way to
+// split it into a valid name, version and optional platform.
+func ParseGemFilename(fn string) (string, *version.Version, string, error) {
+	if !strings.HasSuffix(fn, ".gem") {
+		return "", nil, "", fmt.Errorf("not a gem file name: %s", fn)
+	}
+	parts := strings.Split(strings.TrimSuffix(fn, ".gem"), "-")
+
+	for i := len(parts) - 1; i > 0; i-- {
+		ver := parts[i]
+		if ver == "" || ver[0] < '0' || ver[0] > '9' {
+			continue
+		}
+		n := strings.Join(parts[:i], "-")
+		if !gemName.MatchString(n) || strings.IndexFunc(n, unicode.IsLetter) < 0 {
+			continue
+		}
+		platform := strings.Join(parts[i+1:], "-")
+		if platform != "" && !gemPlatform.MatchString(platform) {
+			continue
+		}
+		v, err := version.ParseRuby(ver)
+		if err != nil {
+			continue
+		}
+		return n, v, platform, nil
+	}
+
+	return "", nil, "", fmt.Errorf("cannot split gem file name into a name, version and platform: %s", fn)
+}
@@ -0,0 +1,57 @@
+package artifact
+
+import (
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGemFilename(t *testing.T) {
+	tests := []struct {
+		filename, name, version, platform string
+	}{
+		{"rack-3.0.8.gem", "rack", "3.0.8", ""},
+		{"net-ssh-7.2.0.gem", "net-ssh", "7.2.0", ""},
+		{"google-protobuf-3.24.4-arm64-darwin.gem", "google-protobuf", "3.24.4", "arm64-darwin"},
+		{"nokogiri-1.15.4-x86_64-linux.gem", "nokogiri", "1.15.4", "x86_64-linux"},
+		{"nokogiri-1.15.4-java.gem", "nokogiri", "1.15.4", "java"},
+		{"sqlite3-1.6.6-x64-mingw-ucrt.gem", "sqlite3", "1.6.6", "x64-mingw-ucrt"},
+		{"ffi-1.16.3-x86-mingw32.gem", "ffi", "1.16.3", "x86-mingw32"},
+		{"grpc-1.59.2-x86_64-linux-musl.gem", "grpc", "1.59.2", "x86_64-linux-musl"},
+		{"rails-7.1.0.rc1.gem", "rails", "7.1.0.rc1", ""},
+		{"rspec-3-rails-1.0.0.gem", "rspec-3-rails", "1.0.0", ""},
+		{"foo-1-2.gem", "foo-1", "2", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			n, v, platform, err := ParseGemFilename(tt.filename)
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, n)
+			assert.Equal(t, tt.platform, platform)
+
+			expected, err := version.ParseRuby(tt.version)
+			require.NoError(t, err)
+			assert.Equal(t, expected, v)
+		})
+	}
+}
+
+func TestParseGemFilenameErrors(t *testing.T) {
+	for _, fn := range []string{
+		"",
+		"not_a_gem.txt",
+		"rack.gem",
+		"rack-.gem",
+		"-3.0.8.gem",
+		"123-3.0.8.gem",
+		"rack-latest.gem",
+		"rack-3.0.8-Not A Platform.gem",
+		"rack-3.0.8.tar.gz",
+	} {
+		_, _, _, err := ParseGemFilename(fn)
+		assert.Error(t, err, fn)
+	}
+}
@@ -0,0 +1,115 @@
+package artifact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/name"
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+var (
+	// goPseudoVersion matches a pseudo-version, which Go makes up for a
+	// commit that has no tag. This is the pattern golang.org/x/mod/module
+	// uses.
+	goPseudoVersion = regexp.MustCompile(`^v[0-9]+\.(0\.0-|\d+\.\d+-([^+]*\.)?0\.)\d{14}-[A-Za-z0-9]+(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+	// goMajorSuffix matches the major version suffix of a module path, such
+	// as "/v2", or ".v2" for gopkg.in paths.
+	goMajorSuffix = regexp.MustCompile(`[/.](v[0-9]+)$`)
+	// goMajor matches the major version of a Go module version.
+	goMajor = regexp.MustCompile(`^(v[0-9]+)\.`)
+)
+
+// GoModuleSpec is a Go module path with an optional version, such as
+// "golang.org/x/crypto@v0.14.0".
+type GoModuleSpec struct {
+	// Path is the module path, as given.
+	Path string
+	// EscapedPath is the path escaped with name.EscapeGoModule, as module
+	// proxies and the module cache use it.
+	EscapedPath string
+	// Version is the version parsed with version.ParseGo. It is nil if the
+	// spec has no version.
+	Version *version.Version
+	// IsPseudoVersion is true if the version is a pseudo-version, like
+	// "v0.0.0-20220314234659-1baeb1ce4c0b".
+	IsPseudoVersion bool
+	// IsIncompatible is true if the version has the "+incompatible" suffix,
+	// which Go adds to v2 and later versions of modules with no major
+	// version suffix in their path.
+	IsIncompatible bool
+}
+
+// ParseGoModuleSpec parses a module path with an optional version, as in
+// "github.com/Hashicorp/terraform@v1.5.7" or "golang.org/x/text". The version
+// must be a module version, not a query like "latest" or a branch name.
+//
+// It returns an error if the path is not a valid module path, if the version
+// is not a valid module version, or if the major version of the version does
+// not match the path's major version suffix as Go requires. For example,
+// "example.com/mod/v2" needs a v2 version, and "example.com/mod" needs a v0
+// or v1 version, or an "+incompatible" one.
+func ParseGoModuleSpec(s string) (*GoModuleSpec, error) {
+	at := strings.IndexByte(s, '@')
+	path, ver := s, ""
+	if at >= 0 {
+		path, ver = s[:at], s[at+1:]
+	}
+
+	escaped, err := name.EscapeGoModule(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid module path in Go module spec %s: %s", s, err)
+	}
+	spec := &GoModuleSpec{Path: path, EscapedPath: escaped}
+	if at < 0 {
+		return spec, nil
+	}
+
+	v, err := version.ParseGo(ver)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version in Go module spec %s: %s", s, err)
+	}
+	spec.Version = v
+	spec.IsPseudoVersion = goPseudoVersion.MatchString(ver)
+	spec.IsIncompatible = strings.HasSuffix(ver, "+incompatible")
+
+	if err := checkGoPathMajor(path, ver, spec.IsIncompatible); err != nil {
+		return nil, fmt.Errorf("invalid Go module spec %s: %s", s, err)
+	}
+	return spec, nil
+}
+
+// checkGoPathMajor returns an error if the major version of ver does not
+// match the major version suffix of path, following the rules of
+// golang.org/x/mod/module.CheckPathMajor.
+func checkGoPathMajor(path, ver string, incompatible bool) error {
+	major := goMajor.FindStringSubmatch(ver)[1]
+
+	pathMajor := ""
+	if m := goMajorSuffix.FindStringSubmatch(path); m != nil {
+		if strings.HasPrefix(path, "gopkg.in/") || m[0][0] == '/' {
+			pathMajor = m[1]
+		}
+	}
+
+	compatible := major == "v0" || major == "v1"
+	switch {
+	case pathMajor == "" && incompatible && compatible:
+		return fmt.Errorf("version %s cannot be +incompatible, as its major version is %s", ver, major)
+	case pathMajor == "":
+		if compatible || incompatible {
+			return nil
+		}
+		return fmt.Errorf("version %s needs a /%s suffix on the module path, or +incompatible", ver, major)
+	case incompatible:
+		return fmt.Errorf("version %s cannot be +incompatible, as the module path has a major version suffix", ver)
+	case major == pathMajor:
+		return nil
+	case pathMajor == "v1" && strings.HasPrefix(ver, "v0.0.0-") && strings.HasPrefix(path, "gopkg.in/"):
+		// Old versions of Go made v0.0.0 pseudo-versions for gopkg.in .v1
+		// paths.
+		return nil
+	}
+	return fmt.Errorf("version %s does not match the %s major version suffix of the module path", ver, pathMajor)
+}
@@ -0,0 +1,74 @@
+package artifact
+
+import (
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGoModuleSpec(t *testing.T) {
+	tests := []struct {
+		spec         string
+		path         string
+		escapedPath  string
+		version      string
+		pseudo       bool
+		incompatible bool
+	}{
+		{"golang.org/x/text@v0.14.0", "golang.org/x/text", "golang.org/x/text", "v0.14.0", false, false},
+		{"github.com/Hashicorp/terraform@v1.5.7", "github.com/Hashicorp/terraform", "github.com/!hashicorp/terraform", "v1.5.7", false, false},
+		{"github.com/BurntSushi/toml@v1.3.2", "github.com/BurntSushi/toml", "github.com/!burnt!sushi/toml", "v1.3.2", false, false},
+		{"golang.org/x/crypto@v0.0.0-20220314234659-1baeb1ce4c0b", "golang.org/x/crypto", "golang.org/x/crypto", "v0.0.0-20220314234659-1baeb1ce4c0b", true, false},
+		{"github.com/pkg/errors@v0.9.2-0.20201214064552-5dd12d0cfe7f", "github.com/pkg/errors", "github.com/pkg/errors", "v0.9.2-0.20201214064552-5dd12d0cfe7f", true, false},
+		{"github.com/docker/docker@v24.0.7+incompatible", "github.com/docker/docker", "github.com/docker/docker", "v24.0.7+incompatible", false, true},
+		{"github.com/docker/docker@v20.10.3-0.20210216175712-646072ed6524+incompatible", "github.com/docker/docker", "github.com/docker/docker", "v20.10.3-0.20210216175712-646072ed6524+incompatible", true, true},
+		{"github.com/go-redis/redis/v8@v8.11.5", "github.com/go-redis/redis/v8", "github.com/go-redis/redis/v8", "v8.11.5", false, false},
+		{"github.com/Masterminds/semver/v3@v3.2.0-rc.1", "github.com/Masterminds/semver/v3", "github.com/!masterminds/semver/v3", "v3.2.0-rc.1", false, false},
+		{"gopkg.in/yaml.v3@v3.0.1", "gopkg.in/yaml.v3", "gopkg.in/yaml.v3", "v3.0.1", false, false},
+		{"gopkg.in/check.v1@v0.0.0-20161208181325-20d25e280405", "gopkg.in/check.v1", "gopkg.in/check.v1", "v0.0.0-20161208181325-20d25e280405", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			spec, err := ParseGoModuleSpec(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.path, spec.Path)
+			assert.Equal(t, tt.escapedPath, spec.EscapedPath)
+			assert.Equal(t, tt.pseudo, spec.IsPseudoVersion)
+			assert.Equal(t, tt.incompatible, spec.IsIncompatible)
+
+			expected, err := version.ParseGo(tt.version)
+			require.NoError(t, err)
+			assert.Equal(t, expected, spec.Version)
+		})
+	}
+}
+
+func TestParseGoModuleSpecWithoutVersion(t *testing.T) {
+	spec, err := ParseGoModuleSpec("github.com/Azure/azure-sdk-for-go")
+	require.NoError(t, err)
+	assert.Equal(t, &GoModuleSpec{Path: "github.com/Azure/azure-sdk-for-go", EscapedPath: "github.com/!azure/azure-sdk-for-go"}, spec)
+}
+
+func TestParseGoModuleSpecErrors(t *testing.T) {
+	for s, expected := range map[string]string{
+		"@v1.0.0":                                          "invalid module path in Go module spec @v1.0.0: a Go package name cannot be empty",
+		"golang.org//text@v0.14.0":                         "invalid module path in Go module spec golang.org//text@v0.14.0: \"golang.org//text\" is not a valid Go package name: it cannot start with '/'",
+		"golang.org/x/text@":                               "invalid version in Go module spec golang.org/x/text@: go module version does not start with v: ",
+		"golang.org/x/text@latest":                         "invalid version in Go module spec golang.org/x/text@latest: go module version does not start with v: latest",
+		"golang.org/x/text@v0.14":                          "invalid version in Go module spec golang.org/x/text@v0.14: Version does not match semver regex: 0.14",
+		"golang.org/x/text@0.14.0":                         "invalid version in Go module spec golang.org/x/text@0.14.0: go module version does not start with v: 0.14.0",
+		"github.com/docker/docker@v24.0.7":                 "invalid Go module spec github.com/docker/docker@v24.0.7: version v24.0.7 needs a /v24 suffix on the module path, or +incompatible",
+		"github.com/pkg/errors@v0.9.1+incompatible":        "invalid Go module spec github.com/pkg/errors@v0.9.1+incompatible: version v0.9.1+incompatible cannot be +incompatible, as its major version is v0",
+		"github.com/go-redis/redis/v8@v9.0.0":              "invalid Go module spec github.com/go-redis/redis/v8@v9.0.0: version v9.0.0 does not match the v8 major version suffix of the module path",
+		"github.com/go-redis/redis/v8@v8.0.0+incompatible": "invalid Go module spec github.com/go-redis/redis/v8@v8.0.0+incompatible: version v8.0.0+incompatible cannot be +incompatible, as the module path has a major version suffix",
+		"gopkg.in/yaml.v3@v2.4.0":                          "invalid Go module spec gopkg.in/yaml.v3@v2.4.0: version v2.4.0 does not match the v3 major version suffix of the module path",
+	} {
+		_, err := ParseGoModuleSpec(s)
+		if assert.Error(t, err, s) {
+			assert.Equal(t, expected, err.Error(), s)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package artifact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+var (
+	// mavenID matches a Maven groupId, artifactId, packaging or classifier.
+	mavenID = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	// mavenVersion matches the characters that can appear in a version in a
+	// Maven coordinate, which are fewer than ParseMaven accepts.
+	mavenVersion = regexp.MustCompile(`^[A-Za-z0-9_.+-]+$`)
+	// mavenTimestampedSnapshot matches the version of a snapshot deployed to
+	// a repository, such as "1.0-20230917.123456-3", where the "SNAPSHOT" of
+	// "1.0-SNAPSHOT" is replaced by a timestamp and build number.
+	mavenTimestampedSnapshot = regexp.MustCompile(`-[0-9]{8}\.[0-9]{6}-[0-9]+$`)
+)
+
+// mavenFields are the names of the fields before the version in a Maven
+// coordinate, in order.
+var mavenFields = []string{"groupId", "artifactId", "packaging", "classifier"}
+
+// MavenGAV is a Maven coordinate, such as
+// "org.apache.commons:commons-lang3:3.13.0".
+type MavenGAV struct {
+	GroupID    string
+	ArtifactID string
+	// Packaging and Classifier are empty if the coordinate does not have
+	// them.
+	Packaging  string
+	Classifier string
+	// Version is the version parsed with version.ParseMaven. It is nil if the
+	// version is the LATEST or RELEASE meta version.
+	Version *version.Version
+	// MetaVersion is "LATEST" or "RELEASE" if the coordinate has one of those
+	// meta versions instead of a version, and is empty otherwise.
+	MetaVersion string
+	// Snapshot is true if the version is a snapshot, either one ending in
+	// "-SNAPSHOT" or a timestamped one like "1.0-20230917.123456-3".
+	Snapshot bool
+}
+
+// ParseMavenGAV parses a Maven coordinate in one of the forms
+// "groupId:artifactId:version", "groupId:artifactId:packaging:version" and
+// "groupId:artifactId:packaging:classifier:version".
+//
+// Maven coordinates are case sensitive, so the group and artifact IDs are
+// validated but returned as they are. The version is parsed with
+// version.ParseMaven, so it is ordered as Maven orders it. The LATEST and
+// RELEASE meta versions are not parsed, and are returned in MetaVersion
+// instead.
+func ParseMavenGAV(s string) (*MavenGAV, error) {
+	fields := strings.Split(s, ":")
+	if len(fields) < 3 || len(fields) > 5 {
+		return nil, fmt.Errorf("maven coordinate must have 3, 4 or 5 fields separated by colons, but has %d: %s", len(fields), s)
+	}
+	for i, f := range fields[:len(fields)-1] {
+		if !mavenID.MatchString(f) {
+			return nil, fmt.Errorf("invalid %s in maven coordinate %s: %q", mavenFields[i], s, f)
+		}
+	}
+	ver := fields[len(fields)-1]
+	if !mavenVersion.MatchString(ver) {
+		return nil, fmt.Errorf("invalid version in maven coordinate %s: %q", s, ver)
+	}
+
+	gav := &MavenGAV{GroupID: fields[0], ArtifactID: fields[1]}
+	if len(fields) > 3 {
+		gav.Packaging = fields[2]
+	}
+	if len(fields) > 4 {
+		gav.Classifier = fields[3]
+	}
+
+	if ver == "LATEST" || ver == "RELEASE" {
+		gav.MetaVersion = ver
+		return gav, nil
+	}
+	v, err := version.ParseMaven(ver)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version in maven coordinate %s: %s", s, err)
+	}
+	gav.Version = v
+	gav.Snapshot = strings.HasSuffix(ver, "-SNAPSHOT") || mavenTimestampedSnapshot.MatchString(ver)
+	return gav, nil
+}
+
+// Canonical returns the coordinate in the shortest of the forms accepted by
+// ParseMavenGAV that includes all of its fields.
+func (gav *MavenGAV) Canonical() string {
+	fields := []string{gav.GroupID, gav.ArtifactID}
+	if gav.Packaging != "" {
+		fields = append(fields, gav.Packaging)
+		if gav.Classifier != "" {
+			fields = append(fields, gav.Classifier)
+		}
+	}
+	if gav.MetaVersion != "" {
+		fields = append(fields, gav.MetaVersion)
+	} else if gav.Version != nil {
+		fields = append(fields, gav.Version.Original)
+	}
+	return strings.Join(fields, ":")
+}
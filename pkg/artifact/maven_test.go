@@ -0,0 +1,117 @@
+package artifact
+
+import (
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/ActiveState/langtools/pkg/version/versiontest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMavenGAV(t *testing.T) {
+	tests := []struct {
+		coordinate string
+		expected   MavenGAV
+		version    string
+	}{
+		{
+			"org.apache.commons:commons-lang3:3.13.0",
+			MavenGAV{GroupID: "org.apache.commons", ArtifactID: "commons-lang3"},
+			"3.13.0",
+		},
+		{
+			"com.google.guava:guava:bundle:32.1.2-jre",
+			MavenGAV{GroupID: "com.google.guava", ArtifactID: "guava", Packaging: "bundle"},
+			"32.1.2-jre",
+		},
+		{
+			"org.slf4j:slf4j-api:jar:sources:2.0.9",
+			MavenGAV{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Packaging: "jar", Classifier: "sources"},
+			"2.0.9",
+		},
+		{
+			"com.example:my_lib:1.0-SNAPSHOT",
+			MavenGAV{GroupID: "com.example", ArtifactID: "my_lib", Snapshot: true},
+			"1.0-SNAPSHOT",
+		},
+		{
+			"com.example:my-lib:jar:1.0-20230917.123456-3",
+			MavenGAV{GroupID: "com.example", ArtifactID: "my-lib", Packaging: "jar", Snapshot: true},
+			"1.0-20230917.123456-3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.coordinate, func(t *testing.T) {
+			gav, err := ParseMavenGAV(tt.coordinate)
+			require.NoError(t, err)
+
+			v, err := version.ParseMaven(tt.version)
+			require.NoError(t, err)
+			tt.expected.Version = v
+			assert.Equal(t, &tt.expected, gav)
+			assert.Equal(t, tt.coordinate, gav.Canonical())
+		})
+	}
+}
+
+// parseMavenGAVVersion parses a coordinate with ParseMavenGAV and returns its
+// version, so that coordinates can be checked with versiontest.
+func parseMavenGAVVersion(s string) (*version.Version, error) {
+	gav, err := ParseMavenGAV(s)
+	if err != nil {
+		return nil, err
+	}
+	return gav.Version, nil
+}
+
+func TestParseMavenGAVOrder(t *testing.T) {
+	versiontest.AssertOrdered(t, parseMavenGAVVersion, []string{
+		"com.example:lib:1.0-alpha-1",
+		"com.example:lib:1.0-beta-1",
+		"com.example:lib:1.0-RC1",
+		"com.example:lib:1.0-SNAPSHOT",
+		"com.example:lib:1.0",
+		"com.example:lib:1.0-sp1",
+		"com.example:lib:1.0.1",
+	})
+	versiontest.AssertAllEqual(t, parseMavenGAVVersion, []string{
+		"com.example:lib:1.0",
+		"com.example:lib:1.0.GA",
+		"com.example:lib:jar:1.0-Final",
+	})
+
+	v, err := parseMavenGAVVersion("com.example:lib:1.0-sp1")
+	require.NoError(t, err)
+	assert.Equal(t, version.Maven, v.ParsedAs)
+}
+
+func TestParseMavenGAVMetaVersions(t *testing.T) {
+	for _, meta := range []string{"LATEST", "RELEASE"} {
+		gav, err := ParseMavenGAV("junit:junit:" + meta)
+		require.NoError(t, err, meta)
+		assert.Nil(t, gav.Version, meta)
+		assert.Equal(t, meta, gav.MetaVersion)
+		assert.False(t, gav.Snapshot, meta)
+		assert.Equal(t, "junit:junit:"+meta, gav.Canonical())
+	}
+}
+
+func TestParseMavenGAVErrors(t *testing.T) {
+	for coordinate, expected := range map[string]string{
+		"junit:junit":                   "maven coordinate must have 3, 4 or 5 fields separated by colons, but has 2: junit:junit",
+		"a:b:c:d:e:1.0":                 "maven coordinate must have 3, 4 or 5 fields separated by colons, but has 6: a:b:c:d:e:1.0",
+		":junit:4.13.2":                 `invalid groupId in maven coordinate :junit:4.13.2: ""`,
+		"junit:ju nit:4.13.2":           `invalid artifactId in maven coordinate junit:ju nit:4.13.2: "ju nit"`,
+		"junit:junit:ja/r:4.13.2":       `invalid packaging in maven coordinate junit:junit:ja/r:4.13.2: "ja/r"`,
+		"junit:junit:jar::4.13.2":       `invalid classifier in maven coordinate junit:junit:jar::4.13.2: ""`,
+		"junit:junit:":                  `invalid version in maven coordinate junit:junit:: ""`,
+		"junit:junit:jar:sources:4.1 3": `invalid version in maven coordinate junit:junit:jar:sources:4.1 3: "4.1 3"`,
+	} {
+		_, err := ParseMavenGAV(coordinate)
+		if assert.Error(t, err, coordinate) {
+			assert.Contains(t, err.Error(), expected, coordinate)
+		}
+	}
+}
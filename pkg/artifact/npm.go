@@ -0,0 +1,244 @@
+package artifact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/name"
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+// NpmSpecType is the kind of thing an npm package spec refers to. The types
+// are the ones that npm-package-arg gives.
+type NpmSpecType int
+
+const (
+	// NpmVersion is an exact version from the registry, like "4.17.21".
+	NpmVersion NpmSpecType = iota + 1
+	// NpmRange is a semver range of versions from the registry, like
+	// "^4.17.21".
+	NpmRange
+	// NpmTag is a dist-tag in the registry, like "latest".
+	NpmTag
+	// NpmAlias is an alias for another registry package, like
+	// "npm:react@18.2.0".
+	NpmAlias
+	// NpmGit is a git repository, like "github:user/repo" or
+	// "git+https://example.com/repo.git#v1.0".
+	NpmGit
+	// NpmRemote is a tarball URL, like "https://example.com/foo.tgz".
+	NpmRemote
+	// NpmFile is a local tarball, like "file:../foo-1.0.0.tgz".
+	NpmFile
+	// NpmDirectory is a local directory, like "file:../foo".
+	NpmDirectory
+)
+
+var npmSpecTypeNames = map[NpmSpecType]string{
+	NpmVersion:   "version",
+	NpmRange:     "range",
+	NpmTag:       "tag",
+	NpmAlias:     "alias",
+	NpmGit:       "git",
+	NpmRemote:    "remote",
+	NpmFile:      "file",
+	NpmDirectory: "directory",
+}
+
+func (t NpmSpecType) String() string {
+	if n, ok := npmSpecTypeNames[t]; ok {
+		return n
+	}
+	return fmt.Sprintf("NpmSpecType(%d)", int(t))
+}
+
+// IsRegistry returns true if the spec type refers to packages in the npm
+// registry.
+func (t NpmSpecType) IsRegistry() bool {
+	return t == NpmVersion || t == NpmRange || t == NpmTag || t == NpmAlias
+}
+
+// NpmSpec is a parsed npm package spec, such as "lodash@^4.17.21".
+type NpmSpec struct {
+	// Name is the package name normalized with name.NormalizeNpm, including
+	// the scope of a scoped package. It is empty if the spec has no name,
+	// like a bare git URL.
+	Name string
+	// RawSpec is everything after the name, such as "^4.17.21". It is "*" if
+	// the spec is just a name.
+	RawSpec string
+	Type    NpmSpecType
+	// Version is the version parsed with version.ParseNpm for NpmVersion
+	// specs, and is nil otherwise.
+	Version *version.Version
+	// Alias is the spec that an NpmAlias spec is an alias for, such as
+	// "react@18.2.0" for "npm:react@18.2.0". It is nil for other types.
+	Alias *NpmSpec
+}
+
+var (
+	// npmHostedGitPrefix matches the shortcuts for repositories on git hosts.
+	npmHostedGitPrefix = regexp.MustCompile(`^(?:github|gitlab|bitbucket|gist):`)
+	// npmGitHubShortcut matches the "user/repo" shortcut for GitHub
+	// repositories, with an optional committish.
+	npmGitHubShortcut = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+(?:#.*)?$`)
+	// npmHostedGitURL matches the web URL of a repository on a git host.
+	npmHostedGitURL = regexp.MustCompile(`^(?:git\+)?https?://(?:www\.)?(?:github\.com|gitlab\.com|bitbucket\.org)/[^/]+/[^/#]+?(?:\.git)?/?(?:#.*)?$`)
+	// npmSCPGit matches an scp-style git URL, like
+	// "git@github.com:user/repo.git".
+	npmSCPGit = regexp.MustCompile(`^[^@]+@[^:.]+\.[^:]+:.+$`)
+	// npmURL matches a URL, as npm-package-arg tells them apart from other
+	// specs.
+	npmURL = regexp.MustCompile(`^(?:git\+)?[a-z]+:`)
+	// npmFilePath matches a path to a local file or directory.
+	npmFilePath = regexp.MustCompile(`^(?:file:|\.|~/|/|\\|[a-zA-Z]:)`)
+	// npmTarball matches the file name of a tarball.
+	npmTarball = regexp.MustCompile(`\.(?:tgz|tar\.gz|tar)$`)
+	// npmTag matches the characters that encodeURIComponent leaves alone,
+	// which are the only ones a tag can have.
+	npmTag = regexp.MustCompile(`^[A-Za-z0-9\-_.!~*'()]+$`)
+)
+
+// ParseNpmSpec parses an npm package spec, which is a name followed by an
+// optional "@" and spec, such as "lodash@^4.17.21", "@types/node@>=18" or
+// "react@npm:react@18.2.0". The name is split from the spec at the first "@",
+// or for scoped names at the first "@" after the scope, as npm-package-arg
+// does. Bare git and tarball URLs and file paths are also accepted, and have
+// no name.
+//
+// Specs that refer to git repositories, URLs and local files are returned
+// with the corresponding NpmSpecType rather than as errors. Ranges are
+// checked against the node-semver range grammar, but are not parsed, so
+// RawSpec holds the range.
+//
+// It returns an error if the name is not a valid npm package name, if an
+// alias is not for a named registry spec, or if a registry spec is not a
+// version, range or valid tag.
+func ParseNpmSpec(s string) (*NpmSpec, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("empty npm package spec")
+	}
+
+	nameEnd := strings.IndexByte(s, '@')
+	if s[0] == '@' {
+		nameEnd = strings.IndexByte(s[1:], '@') + 1
+	}
+	namePart := s
+	if nameEnd > 0 {
+		namePart = s[:nameEnd]
+	}
+
+	var n, spec string
+	switch {
+	case npmURL.MatchString(s), npmSCPGit.MatchString(s):
+		spec = s
+	case namePart[0] != '@' && (strings.Contains(namePart, "/") || npmTarball.MatchString(namePart)):
+		spec = s
+	case nameEnd > 0:
+		n, spec = namePart, s[nameEnd+1:]
+	default:
+		n = s
+	}
+
+	if n != "" {
+		n = name.NormalizeNpm(n)
+		if err := name.ValidateNpm(n); err != nil {
+			return nil, fmt.Errorf("invalid name in npm package spec %s: %s", s, err)
+		}
+	}
+	res, err := resolveNpmSpec(n, strings.TrimSpace(spec))
+	if err != nil {
+		return nil, fmt.Errorf("invalid npm package spec %s: %s", s, err)
+	}
+	return res, nil
+}
+
+// resolveNpmSpec works out the type of the spec that follows the name n, in
+// the same order as npm-package-arg.
+func resolveNpmSpec(n, spec string) (*NpmSpec, error) {
+	res := &NpmSpec{Name: n, RawSpec: spec}
+	switch {
+	case spec == "":
+		res.RawSpec = "*"
+		res.Type = NpmRange
+	case npmFilePath.MatchString(spec):
+		res.Type = fileOrDirectory(spec)
+	case strings.HasPrefix(spec, "npm:"):
+		alias, err := ParseNpmSpec(spec[len("npm:"):])
+		if err != nil {
+			return nil, err
+		}
+		if alias.Name == "" || !alias.Type.IsRegistry() || alias.Type == NpmAlias {
+			return nil, fmt.Errorf("aliases must be for a named package in the registry")
+		}
+		res.Type = NpmAlias
+		res.Alias = alias
+	case npmHostedGitPrefix.MatchString(spec), npmGitHubShortcut.MatchString(spec),
+		npmHostedGitURL.MatchString(spec), npmSCPGit.MatchString(spec):
+		res.Type = NpmGit
+	case npmURL.MatchString(spec):
+		if strings.HasPrefix(spec, "git+") || strings.HasPrefix(spec, "git:") {
+			res.Type = NpmGit
+		} else {
+			res.Type = NpmRemote
+		}
+	case strings.Contains(spec, "/") || npmTarball.MatchString(spec):
+		res.Type = fileOrDirectory(spec)
+	default:
+		if v, err := version.ParseNpm(spec); err == nil && npmFullVersion.MatchString(spec) {
+			res.Type = NpmVersion
+			res.Version = v
+		} else if isNpmRange(spec) {
+			res.Type = NpmRange
+		} else if npmTag.MatchString(spec) {
+			res.Type = NpmTag
+		} else {
+			return nil, fmt.Errorf("%q is not a version, range or tag", spec)
+		}
+	}
+	return res, nil
+}
+
+func fileOrDirectory(spec string) NpmSpecType {
+	if npmTarball.MatchString(spec) {
+		return NpmFile
+	}
+	return NpmDirectory
+}
+
+var (
+	npmPartial = `(?:[xX*]|[0-9]+)(?:\.(?:[xX*]|[0-9]+)(?:\.(?:[xX*]|[0-9]+)` +
+		`(?:-?[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?)?)?`
+	// npmComparator matches a single comparator in a range, like ">=1.2.3",
+	// "~1.2" or "1.x", with the loose prefixes node-semver allows.
+	npmComparator = regexp.MustCompile(`^(?:[<>]=?|=|~>?|\^)?[v=]*` + npmPartial + `$`)
+	// npmHyphenRange matches a hyphen range, like "1.2.3 - 2.3.4".
+	npmHyphenRange = regexp.MustCompile(`^[v=]*` + npmPartial + `\s+-\s+[v=]*` + npmPartial + `$`)
+	// npmOperatorSpace matches the space after an operator, which
+	// node-semver removes before splitting a range into comparators.
+	npmOperatorSpace = regexp.MustCompile(`([<>]=?|=|~>?|\^)\s+`)
+	// npmFullVersion matches the start of a version with a major, minor and
+	// patch version. version.ParseNpm also accepts versions like "1.2", but
+	// npm treats those as ranges.
+	npmFullVersion = regexp.MustCompile(`^[v=\s]*[0-9]+\.[0-9]+\.[0-9]+`)
+)
+
+// isNpmRange returns true if s is a valid node-semver range, which is one or
+// more sets of comparators separated by "||". An empty set means any
+// version.
+func isNpmRange(s string) bool {
+	for _, set := range strings.Split(s, "||") {
+		set = strings.TrimSpace(set)
+		if set == "" || npmHyphenRange.MatchString(set) {
+			continue
+		}
+		for _, c := range strings.Fields(npmOperatorSpace.ReplaceAllString(set, "$1")) {
+			if !npmComparator.MatchString(c) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package artifact
+
+import (
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNpmSpec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		name    string
+		rawSpec string
+		typ     NpmSpecType
+	}{
+		{"lodash", "lodash", "*", NpmRange},
+		{"lodash@", "lodash", "*", NpmRange},
+		{"lodash@^4.17.21", "lodash", "^4.17.21", NpmRange},
+		{"lodash@4.17.21", "lodash", "4.17.21", NpmVersion},
+		{"lodash@v4.17.21", "lodash", "v4.17.21", NpmVersion},
+		{"lodash@=4.17.21", "lodash", "=4.17.21", NpmVersion},
+		{"lodash@4.17.21beta", "lodash", "4.17.21beta", NpmVersion},
+		{"lodash@4.17", "lodash", "4.17", NpmRange},
+		{"lodash@latest", "lodash", "latest", NpmTag},
+		{"lodash@next-11", "lodash", "next-11", NpmTag},
+		{"@types/node", "@types/node", "*", NpmRange},
+		{"@types/node@>=18", "@types/node", ">=18", NpmRange},
+		{"@types/node@18.11.9", "@types/node", "18.11.9", NpmVersion},
+		{"@types/node@>= 18 < 20 || ^21.0.0-rc.1", "@types/node", ">= 18 < 20 || ^21.0.0-rc.1", NpmRange},
+		{"@babel/core@7.x", "@babel/core", "7.x", NpmRange},
+		{"@babel/core@1.0.0 - 2.3.x", "@babel/core", "1.0.0 - 2.3.x", NpmRange},
+		{"@babel/core@beta", "@babel/core", "beta", NpmTag},
+		{"JSONStream@~1.3.5", "JSONStream", "~1.3.5", NpmRange},
+		{"react@npm:react@18.2.0", "react", "npm:react@18.2.0", NpmAlias},
+		{"my-react@npm:@preact/compat@^17", "my-react", "npm:@preact/compat@^17", NpmAlias},
+		{"foo@github:user/repo#v1.0.0", "foo", "github:user/repo#v1.0.0", NpmGit},
+		{"foo@user/repo", "foo", "user/repo", NpmGit},
+		{"foo@git+https://git.example.com/foo.git#semver:^1.0", "foo", "git+https://git.example.com/foo.git#semver:^1.0", NpmGit},
+		{"foo@git://git.example.com/foo.git", "foo", "git://git.example.com/foo.git", NpmGit},
+		{"foo@https://github.com/user/foo", "foo", "https://github.com/user/foo", NpmGit},
+		{"foo@git@github.com:user/foo.git", "", "foo@git@github.com:user/foo.git", NpmGit},
+		{"git+ssh://git@github.com/user/foo.git", "", "git+ssh://git@github.com/user/foo.git", NpmGit},
+		{"foo@https://example.com/foo-1.0.0.tgz", "foo", "https://example.com/foo-1.0.0.tgz", NpmRemote},
+		{"https://example.com/foo-1.0.0.tgz", "", "https://example.com/foo-1.0.0.tgz", NpmRemote},
+		{"foo@file:../foo-1.0.0.tgz", "foo", "file:../foo-1.0.0.tgz", NpmFile},
+		{"foo@file:../foo", "foo", "file:../foo", NpmDirectory},
+		{"foo@./packages/foo", "foo", "./packages/foo", NpmDirectory},
+		{"./foo-1.0.0.tgz", "", "./foo-1.0.0.tgz", NpmFile},
+		{"user/repo", "", "user/repo", NpmGit},
+		{"packages/foo/bar", "", "packages/foo/bar", NpmDirectory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			spec, err := ParseNpmSpec(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, spec.Name)
+			assert.Equal(t, tt.rawSpec, spec.RawSpec)
+			assert.Equal(t, tt.typ, spec.Type, "got %s", spec.Type)
+			if tt.typ == NpmVersion {
+				require.NotNil(t, spec.Version)
+				assert.Equal(t, version.Npm, spec.Version.ParsedAs)
+			} else {
+				assert.Nil(t, spec.Version)
+			}
+			if tt.typ != NpmAlias {
+				assert.Nil(t, spec.Alias)
+			}
+		})
+	}
+}
+
+func TestParseNpmSpecAlias(t *testing.T) {
+	spec, err := ParseNpmSpec("react@npm:react@18.2.0")
+	require.NoError(t, err)
+	require.NotNil(t, spec.Alias)
+	assert.Equal(t, "react", spec.Alias.Name)
+	assert.Equal(t, NpmVersion, spec.Alias.Type)
+	v, err := version.ParseNpm("18.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, v, spec.Alias.Version)
+
+	spec, err = ParseNpmSpec("my-react@npm:@preact/compat@^17")
+	require.NoError(t, err)
+	require.NotNil(t, spec.Alias)
+	assert.Equal(t, &NpmSpec{Name: "@preact/compat", RawSpec: "^17", Type: NpmRange}, spec.Alias)
+}
+
+func TestParseNpmSpecErrors(t *testing.T) {
+	for s, expected := range map[string]string{
+		"":                         "empty npm package spec",
+		"_lodash@1.0.0":            `invalid name in npm package spec _lodash@1.0.0: "_lodash" is not a valid npm package name: it cannot start with '_'`,
+		"@types@1.0.0":             `invalid name in npm package spec @types@1.0.0: "@types" is not a valid npm package name: '@' at position 0 is not allowed`,
+		"lodash@not a tag":         `invalid npm package spec lodash@not a tag: "not a tag" is not a version, range or tag`,
+		"lodash@>=1.0.0 <=x.y.z.w": `invalid npm package spec lodash@>=1.0.0 <=x.y.z.w: ">=1.0.0 <=x.y.z.w" is not a version, range or tag`,
+		"foo@npm:github:user/foo":  "invalid npm package spec foo@npm:github:user/foo: aliases must be for a named package in the registry",
+		"foo@npm:bar@npm:baz@1":    "invalid npm package spec foo@npm:bar@npm:baz@1: aliases must be for a named package in the registry",
+		"foo@npm:bar@^^1":          `invalid npm package spec foo@npm:bar@^^1: invalid npm package spec bar@^^1: "^^1" is not a version, range or tag`,
+	} {
+		_, err := ParseNpmSpec(s)
+		if assert.Error(t, err, s) {
+			assert.Equal(t, expected, err.Error(), s)
+		}
+	}
+}
+
+func TestNpmSpecType(t *testing.T) {
+	assert.Equal(t, "alias", NpmAlias.String())
+	assert.Equal(t, "NpmSpecType(99)", NpmSpecType(99).String())
+	assert.True(t, NpmTag.IsRegistry())
+	assert.False(t, NpmGit.IsRegistry())
+}
@@ -0,0 +1,165 @@
+package artifact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/name"
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+// PythonRequirement is a PEP 508 requirement on a Python package, such as
+// `requests[socks]>=2.8.1,<3; python_version < "3.8"`.
+type PythonRequirement struct {
+	// Name is the package name normalized with name.NormalizePython.
+	Name string
+	// Extras are the names of the extras, normalized with
+	// name.NormalizePython, in the order they were given. It is nil if there
+	// are none.
+	Extras []string
+	// Specifiers is the version specifier set. It is nil if any version is
+	// allowed, or if the requirement has a URL.
+	Specifiers []PythonSpecifier
+	// URL is the URL after "@" in a direct reference, such as
+	// "https://example.com/pip-23.0.tar.gz". It is empty for requirements on
+	// versions from an index.
+	URL string
+	// Marker is the environment marker after ";", such as
+	// `python_version < "3.8"`. It is not parsed. It is empty if there is
+	// none.
+	Marker string
+}
+
+// PythonSpecifier is a single clause of a PEP 440 version specifier set, such
+// as ">=2.8.1".
+type PythonSpecifier struct {
+	// Operator is one of "~=", "==", "!=", "<=", ">=", "<", ">" or "===".
+	Operator string
+	// Version is the version as it was given, such as "2.8.1", or "2.*" for a
+	// prefix match.
+	Version string
+	// Parsed is Version parsed with version.ParsePython, without the ".*" of a
+	// prefix match. It is nil for the "===" operator, which compares versions
+	// as strings.
+	Parsed *version.Version
+}
+
+// pythonSpecifier matches a single clause of a version specifier set.
+var pythonSpecifier = regexp.MustCompile(`^(~=|===|==|!=|<=|>=|<|>)\s*([^\s,;()]+)$`)
+
+// ParsePythonRequirement parses a PEP 508 requirement, which is a package
+// name, optionally followed by extras in brackets, then either a version
+// specifier set or "@" and a URL, then optionally ";" and an environment
+// marker. The specifier set may be in parentheses, as in "name (>=1.0)".
+//
+// It returns an error if the name or an extra is not a valid Python package
+// name, or if a specifier does not have a valid operator and PEP 440 version.
+func ParsePythonRequirement(s string) (*PythonRequirement, error) {
+	rest := strings.TrimSpace(s)
+	end := 0
+	for end < len(rest) && isPythonNameByte(rest[end]) {
+		end++
+	}
+	if err := name.ValidatePython(rest[:end]); err != nil {
+		return nil, fmt.Errorf("invalid name in Python requirement %s: %s", s, err)
+	}
+	req := &PythonRequirement{Name: name.NormalizePython(rest[:end])}
+	rest = strings.TrimSpace(rest[end:])
+
+	if strings.HasPrefix(rest, "[") {
+		end = strings.IndexByte(rest, ']')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed extras in Python requirement %s", s)
+		}
+		if extras := strings.TrimSpace(rest[1:end]); extras != "" {
+			for _, extra := range strings.Split(extras, ",") {
+				extra = strings.TrimSpace(extra)
+				if err := name.ValidatePython(extra); err != nil {
+					return nil, fmt.Errorf("invalid extra in Python requirement %s: %s", s, err)
+				}
+				req.Extras = append(req.Extras, name.NormalizePython(extra))
+			}
+		}
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+
+	if strings.HasPrefix(rest, "@") {
+		rest = strings.TrimSpace(rest[1:])
+		end = strings.IndexAny(rest, " \t")
+		if end < 0 {
+			end = len(rest)
+		}
+		req.URL = rest[:end]
+		rest = strings.TrimSpace(rest[end:])
+		if req.URL == "" {
+			return nil, fmt.Errorf("empty URL in Python requirement %s", s)
+		}
+		if rest != "" && !strings.HasPrefix(rest, ";") {
+			return nil, fmt.Errorf("unexpected %q after URL in Python requirement %s", rest, s)
+		}
+	} else {
+		spec := rest
+		rest = ""
+		if i := strings.IndexByte(spec, ';'); i >= 0 {
+			spec, rest = spec[:i], spec[i:]
+		}
+		specifiers, err := parsePythonSpecifiers(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version specifier in Python requirement %s: %s", s, err)
+		}
+		req.Specifiers = specifiers
+	}
+
+	if rest != "" {
+		req.Marker = strings.TrimSpace(rest[1:])
+		if req.Marker == "" {
+			return nil, fmt.Errorf("empty environment marker in Python requirement %s", s)
+		}
+	}
+	return req, nil
+}
+
+func isPythonNameByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '.' || c == '_' || c == '-'
+}
+
+// parsePythonSpecifiers parses a comma separated version specifier set,
+// which may be in parentheses.
+func parsePythonSpecifiers(spec string) ([]PythonSpecifier, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "(") {
+		if !strings.HasSuffix(spec, ")") {
+			return nil, fmt.Errorf("unclosed parenthesis")
+		}
+		spec = strings.TrimSpace(spec[1 : len(spec)-1])
+	}
+	if spec == "" {
+		return nil, nil
+	}
+
+	var specifiers []PythonSpecifier
+	for _, clause := range strings.Split(spec, ",") {
+		m := pythonSpecifier.FindStringSubmatch(strings.TrimSpace(clause))
+		if m == nil {
+			return nil, fmt.Errorf("%q is not an operator followed by a version", strings.TrimSpace(clause))
+		}
+		sp := PythonSpecifier{Operator: m[1], Version: m[2]}
+		if sp.Operator != "===" {
+			v := sp.Version
+			if strings.HasSuffix(v, ".*") {
+				if sp.Operator != "==" && sp.Operator != "!=" {
+					return nil, fmt.Errorf("%s cannot be used with a prefix match: %s", sp.Operator, sp.Version)
+				}
+				v = v[:len(v)-len(".*")]
+			}
+			parsed, err := version.ParsePython(v)
+			if err != nil || parsed.ParsedAs != version.PythonPEP440 {
+				return nil, fmt.Errorf("%s is not a PEP 440 version", sp.Version)
+			}
+			sp.Parsed = parsed
+		}
+		specifiers = append(specifiers, sp)
+	}
+	return specifiers, nil
+}
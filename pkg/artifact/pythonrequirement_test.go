@@ -0,0 +1,78 @@
+package artifact
+
+import (
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePythonRequirement(t *testing.T) {
+	tests := []struct {
+		req        string
+		name       string
+		extras     []string
+		specifiers [][2]string
+		url        string
+		marker     string
+	}{
+		{"requests", "requests", nil, nil, "", ""},
+		{"Flask_SQLAlchemy==3.0.3", "flask-sqlalchemy", nil, [][2]string{{"==", "3.0.3"}}, "", ""},
+		{"requests[socks, Security]>=2.8.1,<3", "requests", []string{"socks", "security"}, [][2]string{{">=", "2.8.1"}, {"<", "3"}}, "", ""},
+		{"django ~= 4.2.0 ; python_version >= \"3.8\"", "django", nil, [][2]string{{"~=", "4.2.0"}}, "", "python_version >= \"3.8\""},
+		{"numpy (>=1.21, !=1.22.*)", "numpy", nil, [][2]string{{">=", "1.21"}, {"!=", "1.22.*"}}, "", ""},
+		{"pywin32; sys_platform == 'win32'", "pywin32", nil, nil, "", "sys_platform == 'win32'"},
+		{"foo[]", "foo", nil, nil, "", ""},
+		{"pip @ https://github.com/pypa/pip/archive/22.0.2.zip", "pip", nil, nil, "https://github.com/pypa/pip/archive/22.0.2.zip", ""},
+		{"name[quux]@ file:///tmp/name.whl ; os_name == 'posix'", "name", []string{"quux"}, nil, "file:///tmp/name.whl", "os_name == 'posix'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.req, func(t *testing.T) {
+			req, err := ParsePythonRequirement(tt.req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, req.Name)
+			assert.Equal(t, tt.extras, req.Extras)
+			assert.Equal(t, tt.url, req.URL)
+			assert.Equal(t, tt.marker, req.Marker)
+
+			require.Len(t, req.Specifiers, len(tt.specifiers))
+			for i, sp := range req.Specifiers {
+				assert.Equal(t, tt.specifiers[i][0], sp.Operator)
+				assert.Equal(t, tt.specifiers[i][1], sp.Version)
+				require.NotNil(t, sp.Parsed)
+				assert.Equal(t, version.PythonPEP440, sp.Parsed.ParsedAs)
+			}
+		})
+	}
+}
+
+func TestParsePythonRequirementArbitraryEquality(t *testing.T) {
+	req, err := ParsePythonRequirement("foobar===custom-build")
+	require.NoError(t, err)
+	assert.Equal(t, []PythonSpecifier{{Operator: "===", Version: "custom-build"}}, req.Specifiers)
+}
+
+func TestParsePythonRequirementErrors(t *testing.T) {
+	for s, expected := range map[string]string{
+		"":                            "invalid name in Python requirement : a Python package name cannot be empty",
+		">=1.0":                       "invalid name in Python requirement >=1.0: a Python package name cannot be empty",
+		"foo[bar":                     "unclosed extras in Python requirement foo[bar",
+		"foo[-bar]":                   "invalid extra in Python requirement foo[-bar]: \"-bar\" is not a valid Python package name: it cannot start with '-'",
+		"foo 1.0":                     "invalid version specifier in Python requirement foo 1.0: \"1.0\" is not an operator followed by a version",
+		"foo>=1.0,":                   "invalid version specifier in Python requirement foo>=1.0,: \"\" is not an operator followed by a version",
+		"foo>=1.*":                    "invalid version specifier in Python requirement foo>=1.*: >= cannot be used with a prefix match: 1.*",
+		"foo==1.0-custom":             "invalid version specifier in Python requirement foo==1.0-custom: 1.0-custom is not a PEP 440 version",
+		"foo (>=1.0":                  "invalid version specifier in Python requirement foo (>=1.0: unclosed parenthesis",
+		"foo @":                       "empty URL in Python requirement foo @",
+		"foo @ https://x.org/a b":     "unexpected \"b\" after URL in Python requirement foo @ https://x.org/a b",
+		"foo>=1.0;":                   "empty environment marker in Python requirement foo>=1.0;",
+		"foo @ https://x.org/a.whl ;": "empty environment marker in Python requirement foo @ https://x.org/a.whl ;",
+	} {
+		_, err := ParsePythonRequirement(s)
+		if assert.Error(t, err, s) {
+			assert.Equal(t, expected, err.Error(), s)
+		}
+	}
+}
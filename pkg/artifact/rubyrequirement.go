@@ -0,0 +1,68 @@
+package artifact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+// RubyRequirement is a RubyGems version requirement, such as
+// "~> 7.0, >= 7.0.4", which is a list of constraints that a version must
+// all meet.
+type RubyRequirement struct {
+	Constraints []RubyConstraint
+}
+
+// RubyConstraint is a single constraint in a RubyGems version requirement,
+// such as "~> 7.0".
+type RubyConstraint struct {
+	// Operator is one of "=", "!=", ">", "<", ">=", "<=" or "~>".
+	Operator string
+	// Version is parsed with version.ParseRuby.
+	Version *version.Version
+}
+
+// rubyConstraint matches a constraint as Gem::Requirement parses it.
+var rubyConstraint = regexp.MustCompile(`^\s*(=|!=|>=|<=|>|<|~>)?\s*(\S+)\s*$`)
+
+// ParseRubyRequirement parses a RubyGems version requirement, which is one or
+// more comma separated constraints, such as "~> 7.0, >= 7.0.4". A constraint
+// with no operator, like "1.2.3", means "= 1.2.3". An empty requirement means
+// ">= 0", as it does for RubyGems.
+//
+// It returns an error if a constraint has an invalid operator or version.
+func ParseRubyRequirement(s string) (*RubyRequirement, error) {
+	if strings.TrimSpace(s) == "" {
+		s = ">= 0"
+	}
+
+	req := &RubyRequirement{}
+	for _, c := range strings.Split(s, ",") {
+		m := rubyConstraint.FindStringSubmatch(c)
+		if m == nil {
+			return nil, fmt.Errorf("invalid constraint %q in ruby requirement %s", strings.TrimSpace(c), s)
+		}
+		v, err := version.ParseRuby(m[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %q in ruby requirement %s: %s", strings.TrimSpace(c), s, err)
+		}
+		op := m[1]
+		if op == "" {
+			op = "="
+		}
+		req.Constraints = append(req.Constraints, RubyConstraint{Operator: op, Version: v})
+	}
+	return req, nil
+}
+
+// String returns the requirement in the form RubyGems uses, such as
+// "~> 7.0, >= 7.0.4".
+func (r *RubyRequirement) String() string {
+	constraints := make([]string, len(r.Constraints))
+	for i, c := range r.Constraints {
+		constraints[i] = c.Operator + " " + c.Version.Original
+	}
+	return strings.Join(constraints, ", ")
+}
@@ -0,0 +1,40 @@
+package artifact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRubyRequirement(t *testing.T) {
+	for in, expected := range map[string]string{
+		"~> 7.0":            "~> 7.0",
+		"~> 7.0, >= 7.0.4":  "~> 7.0, >= 7.0.4",
+		">=1.13,<2":         ">= 1.13, < 2",
+		"1.2.3":             "= 1.2.3",
+		"= 1.0.0.beta1":     "= 1.0.0.beta1",
+		"!= 2.1.0":          "!= 2.1.0",
+		"":                  ">= 0",
+		"  > 0.9 , <= 1.1 ": "> 0.9, <= 1.1",
+		"~> 1.0.0-rc1":      "~> 1.0.0-rc1",
+	} {
+		req, err := ParseRubyRequirement(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, expected, req.String(), in)
+	}
+}
+
+func TestParseRubyRequirementErrors(t *testing.T) {
+	for in, expected := range map[string]string{
+		"=> 1.0":     `invalid constraint "=> 1.0" in ruby requirement => 1.0`,
+		"~> 1.0,":    `invalid constraint "" in ruby requirement ~> 1.0,`,
+		">= one":     `invalid constraint ">= one" in ruby requirement >= one: invalid ruby version: one`,
+		">= 1.0 2.0": `invalid constraint ">= 1.0 2.0" in ruby requirement >= 1.0 2.0`,
+	} {
+		_, err := ParseRubyRequirement(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, expected, err.Error(), in)
+		}
+	}
+}
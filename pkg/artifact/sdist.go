@@ -0,0 +1,68 @@
+package artifact
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/name"
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+// sdistExtensions are the archive extensions of Python source distributions.
+var sdistExtensions = []string{".tar.gz", ".tgz", ".zip", ".tar.bz2"}
+
+// ParseSdistFilename parses the file name of a Python source distribution,
+// such as "python-dateutil-2.8.2.tar.gz", without any directory. It returns
+// the name as it appears in the file name, the name normalized with
+// name.NormalizePython, and the version parsed with version.ParsePython.
+//
+// Both names and versions can contain hyphens, so the split between them is
+// ambiguous. After the archive extension is removed, each hyphen is tried in
+// turn from the right, and the first one where the part before it is a valid
+// Python package name and the part after it is a PEP440 version is used. If
+// there is no such hyphen, the rightmost one with a valid name before it and
+// a digit after it is used, and the version is a legacy one. So
+// "foo-1.0-1.tar.gz" is version "1" of "foo-1.0", even though "1.0-1" is also
+// a valid version.
+//
+// It returns an error if fn does not end with one of the extensions .tar.gz,
+// .tgz, .zip or .tar.bz2, or if there is no hyphen that splits it into a
+// valid name and a version.
+func ParseSdistFilename(fn string) (string, string, *version.Version, error) {
+	base := ""
+	for _, ext := range sdistExtensions {
+		if strings.HasSuffix(fn, ext) {
+			base = fn[:len(fn)-len(ext)]
+			break
+		}
+	}
+	if base == "" {
+		return "", "", nil, fmt.Errorf("not a source distribution file name: %s", fn)
+	}
+
+	var (
+		legacyName    string
+		legacyVersion *version.Version
+	)
+	for i := strings.LastIndexByte(base, '-'); i > 0; i = strings.LastIndexByte(base[:i], '-') {
+		n, ver := base[:i], base[i+1:]
+		if ver == "" || name.ValidatePython(n) != nil {
+			continue
+		}
+		v, err := version.ParsePython(ver)
+		if err != nil {
+			continue
+		}
+		if v.ParsedAs == version.PythonPEP440 {
+			return n, name.NormalizePython(n), v, nil
+		}
+		if legacyVersion == nil && ver[0] >= '0' && ver[0] <= '9' {
+			legacyName, legacyVersion = n, v
+		}
+	}
+
+	if legacyVersion == nil {
+		return "", "", nil, fmt.Errorf("cannot split source distribution file name into a name and version: %s", fn)
+	}
+	return legacyName, name.NormalizePython(legacyName), legacyVersion, nil
+}
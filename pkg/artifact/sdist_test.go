@@ -0,0 +1,63 @@
+package artifact
+
+import (
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSdistFilename(t *testing.T) {
+	tests := []struct {
+		filename, name, normalized, version string
+	}{
+		{"backports.ssl_match_hostname-3.7.0.1.tar.gz", "backports.ssl_match_hostname", "backports-ssl-match-hostname", "3.7.0.1"},
+		{"python-dateutil-2.8.2.zip", "python-dateutil", "python-dateutil", "2.8.2"},
+		{"zope.interface-5.4.0.tar.gz", "zope.interface", "zope-interface", "5.4.0"},
+		{"apache-airflow-2.7.1.tar.gz", "apache-airflow", "apache-airflow", "2.7.1"},
+		{"requests-2.31.0.tgz", "requests", "requests", "2.31.0"},
+		{"Django-4.2rc1.tar.bz2", "Django", "django", "4.2rc1"},
+		{"pywin32-306.zip", "pywin32", "pywin32", "306"},
+		{"py3-dns-3.2.1.tar.gz", "py3-dns", "py3-dns", "3.2.1"},
+		{"foo-1.0-1.tar.gz", "foo-1.0", "foo-1-0", "1"},
+		{"ansible-core-2.15.0.post1.tar.gz", "ansible-core", "ansible-core", "2.15.0.post1"},
+		{"foo-bar-1.0-custom.tar.gz", "foo-bar", "foo-bar", "1.0-custom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			n, normalized, v, err := ParseSdistFilename(tt.filename)
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, n)
+			assert.Equal(t, tt.normalized, normalized)
+
+			expected, err := version.ParsePython(tt.version)
+			require.NoError(t, err)
+			assert.Equal(t, expected, v)
+		})
+	}
+}
+
+func TestParseSdistFilenameLegacyVersion(t *testing.T) {
+	n, _, v, err := ParseSdistFilename("foo-bar-1.0-custom.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "foo-bar", n, "the rightmost split is used when no version is PEP440")
+	assert.Equal(t, version.PythonLegacy, v.ParsedAs)
+}
+
+func TestParseSdistFilenameErrors(t *testing.T) {
+	for _, fn := range []string{
+		"",
+		"requests-2.31.0.tar.xz",
+		"requests-2.31.0-py3-none-any.whl",
+		"requests.tar.gz",
+		"-2.31.0.tar.gz",
+		"requests-.tar.gz",
+		"requests-latest.tar.gz",
+		".tar.gz",
+	} {
+		_, _, _, err := ParseSdistFilename(fn)
+		assert.Error(t, err, fn)
+	}
+}
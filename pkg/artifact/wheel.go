@@ -0,0 +1,76 @@
+// Package artifact parses the names and versions of packages from the file
+// names of their archives, such as Python wheels and source distributions,
+// and Ruby gems, and from other references to them, such as Maven
+// coordinates.
+package artifact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/name"
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+// Wheel is the information in the file name of a Python wheel.
+type Wheel struct {
+	// Name is the distribution name as it appears in the file name, such as
+	// "Flask_SQLAlchemy".
+	Name string
+	// NormalizedName is Name normalized with name.NormalizePython, such as
+	// "flask-sqlalchemy".
+	NormalizedName string
+	// Version is the version parsed with version.ParsePython.
+	Version *version.Version
+	// BuildTag is the optional build tag, such as "1" or "2abc". It is empty
+	// if the file name has none.
+	BuildTag string
+	// PythonTags, ABITags and PlatformTags are the compatibility tags, such
+	// as []string{"py2", "py3"} for the compressed tag set "py2.py3".
+	PythonTags   []string
+	ABITags      []string
+	PlatformTags []string
+}
+
+// wheelFilename matches a wheel file name as described in PEP 427. This is
+// the same pattern that pip uses.
+var wheelFilename = regexp.MustCompile(`^([^\s-]+?)-([^\s-]+?)(?:-(\d[^-]*))?-([^\s-]+?)-([^\s-]+?)-([^\s-]+?)\.whl$`)
+
+// ParseWheelFilename parses the file name of a Python wheel, such as
+// "cryptography-41.0.3-cp39-abi3-manylinux_2_17_x86_64.whl", without any
+// directory. See https://peps.python.org/pep-0427/#file-name-convention for
+// the format.
+//
+// Runs of "-", "_" and "." in a distribution name are escaped as "_" in wheel
+// file names, and since name.NormalizePython treats all of these the same,
+// the escaped name normalizes to the same thing as the original one.
+//
+// It returns an error if fn does not match the file name convention, or if
+// the distribution name is not a valid Python package name.
+func ParseWheelFilename(fn string) (*Wheel, error) {
+	m := wheelFilename.FindStringSubmatch(fn)
+	if m == nil {
+		return nil, fmt.Errorf("not a wheel file name: %s", fn)
+	}
+	if err := name.ValidatePython(m[1]); err != nil {
+		return nil, fmt.Errorf("invalid distribution name in wheel file name %s: %s", fn, err)
+	}
+
+	// Hyphens in a version are escaped as underscores, which ParsePython
+	// treats the same way.
+	v, err := version.ParsePython(m[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid version in wheel file name %s: %s", fn, err)
+	}
+
+	return &Wheel{
+		Name:           m[1],
+		NormalizedName: name.NormalizePython(m[1]),
+		Version:        v,
+		BuildTag:       m[3],
+		PythonTags:     strings.Split(m[4], "."),
+		ABITags:        strings.Split(m[5], "."),
+		PlatformTags:   strings.Split(m[6], "."),
+	}, nil
+}
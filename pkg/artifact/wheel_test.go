@@ -0,0 +1,128 @@
+package artifact
+
+import (
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWheelFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected Wheel
+		version  string
+	}{
+		{
+			"cryptography-41.0.3-cp39-abi3-manylinux_2_17_x86_64.manylinux2014_x86_64.whl",
+			Wheel{
+				Name:           "cryptography",
+				NormalizedName: "cryptography",
+				PythonTags:     []string{"cp39"},
+				ABITags:        []string{"abi3"},
+				PlatformTags:   []string{"manylinux_2_17_x86_64", "manylinux2014_x86_64"},
+			},
+			"41.0.3",
+		},
+		{
+			"requests-2.31.0-py3-none-any.whl",
+			Wheel{Name: "requests", NormalizedName: "requests", PythonTags: []string{"py3"}, ABITags: []string{"none"}, PlatformTags: []string{"any"}},
+			"2.31.0",
+		},
+		{
+			"six-1.16.0-py2.py3-none-any.whl",
+			Wheel{Name: "six", NormalizedName: "six", PythonTags: []string{"py2", "py3"}, ABITags: []string{"none"}, PlatformTags: []string{"any"}},
+			"1.16.0",
+		},
+		{
+			"Flask_SQLAlchemy-3.0.5-py3-none-any.whl",
+			Wheel{Name: "Flask_SQLAlchemy", NormalizedName: "flask-sqlalchemy", PythonTags: []string{"py3"}, ABITags: []string{"none"}, PlatformTags: []string{"any"}},
+			"3.0.5",
+		},
+		{
+			"zope.interface-6.0-cp311-cp311-macosx_11_0_arm64.whl",
+			Wheel{Name: "zope.interface", NormalizedName: "zope-interface", PythonTags: []string{"cp311"}, ABITags: []string{"cp311"}, PlatformTags: []string{"macosx_11_0_arm64"}},
+			"6.0",
+		},
+		{
+			"typing_extensions-4.7.1-py3-none-any.whl",
+			Wheel{Name: "typing_extensions", NormalizedName: "typing-extensions", PythonTags: []string{"py3"}, ABITags: []string{"none"}, PlatformTags: []string{"any"}},
+			"4.7.1",
+		},
+		{
+			"numpy-1.25.2-cp310-cp310-win_amd64.whl",
+			Wheel{Name: "numpy", NormalizedName: "numpy", PythonTags: []string{"cp310"}, ABITags: []string{"cp310"}, PlatformTags: []string{"win_amd64"}},
+			"1.25.2",
+		},
+		{
+			"torch-2.0.1+cu118-cp311-cp311-linux_x86_64.whl",
+			Wheel{Name: "torch", NormalizedName: "torch", PythonTags: []string{"cp311"}, ABITags: []string{"cp311"}, PlatformTags: []string{"linux_x86_64"}},
+			"2.0.1+cu118",
+		},
+		{
+			"pip-23.2.1-1-py3-none-any.whl",
+			Wheel{Name: "pip", NormalizedName: "pip", BuildTag: "1", PythonTags: []string{"py3"}, ABITags: []string{"none"}, PlatformTags: []string{"any"}},
+			"23.2.1",
+		},
+		{
+			"tensorflow_gpu-2.10.0-2abc-cp39-cp39-manylinux2014_x86_64.whl",
+			Wheel{Name: "tensorflow_gpu", NormalizedName: "tensorflow-gpu", BuildTag: "2abc", PythonTags: []string{"cp39"}, ABITags: []string{"cp39"}, PlatformTags: []string{"manylinux2014_x86_64"}},
+			"2.10.0",
+		},
+		{
+			"Django-4.2rc1-py3-none-any.whl",
+			Wheel{Name: "Django", NormalizedName: "django", PythonTags: []string{"py3"}, ABITags: []string{"none"}, PlatformTags: []string{"any"}},
+			"4.2rc1",
+		},
+		{
+			"pywin32-306-cp312-cp312-win_arm64.whl",
+			Wheel{Name: "pywin32", NormalizedName: "pywin32", PythonTags: []string{"cp312"}, ABITags: []string{"cp312"}, PlatformTags: []string{"win_arm64"}},
+			"306",
+		},
+		{
+			"black-23.7.0.dev0+local_build-py3-none-any.whl",
+			Wheel{Name: "black", NormalizedName: "black", PythonTags: []string{"py3"}, ABITags: []string{"none"}, PlatformTags: []string{"any"}},
+			"23.7.0.dev0+local_build",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			actual, err := ParseWheelFilename(tt.filename)
+			require.NoError(t, err)
+
+			expected, err := version.ParsePython(tt.version)
+			require.NoError(t, err)
+			assert.Equal(t, version.PythonPEP440, actual.Version.ParsedAs)
+			assert.Equal(t, expected, actual.Version)
+
+			actual.Version = nil
+			assert.Equal(t, tt.expected, *actual)
+		})
+	}
+}
+
+func TestParseWheelFilenameEscapedVersion(t *testing.T) {
+	w, err := ParseWheelFilename("foo-1.0_rc1-py3-none-any.whl")
+	require.NoError(t, err)
+	rc, err := version.ParsePython("1.0-rc1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, version.Compare(rc, w.Version))
+}
+
+func TestParseWheelFilenameErrors(t *testing.T) {
+	for _, fn := range []string{
+		"",
+		"requests-2.31.0.tar.gz",
+		"requests-2.31.0-py3-none.whl",
+		"requests-2.31.0-py3-none-any-extra-tag.whl",
+		"requests-2.31.0-py3-none-any.WHL",
+		"requests-2.31.0-build-py3-none-any.whl",
+		"_requests-2.31.0-py3-none-any.whl",
+		"req uests-2.31.0-py3-none-any.whl",
+	} {
+		_, err := ParseWheelFilename(fn)
+		assert.Error(t, err, fn)
+	}
+}
@@ -0,0 +1,225 @@
+// Package cpe parses CPE 2.3 formatted strings
+// (https://nvlpubs.nist.gov/nistpubs/Legacy/IR/nistir7695.pdf), and matches
+// versions against the version ranges that vulnerability feeds such as the
+// NVD attach to them.
+package cpe
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+const (
+	// Any is the value of an attribute whose formatted string value is "*",
+	// which matches any value.
+	Any = "*"
+	// NA is the value of an attribute whose formatted string value is "-",
+	// which means that the attribute does not apply.
+	NA = "-"
+)
+
+// CPE is a parsed CPE 2.3 formatted string. The attribute values are
+// unquoted, so the value of "1\.2" is "1.2". Wildcards inside a value, as in
+// "1.*", are left as they are.
+type CPE struct {
+	// Part is "a" for applications, "o" for operating systems and "h" for
+	// hardware, or Any or NA.
+	Part      string
+	Vendor    string
+	Product   string
+	Version   string
+	Update    string
+	Edition   string
+	Language  string
+	SWEdition string
+	TargetSW  string
+	TargetHW  string
+	Other     string
+}
+
+// cpeAttributes are the names of the attributes of a CPE in the order they
+// appear in a formatted string, for error messages.
+var cpeAttributes = []string{
+	"part", "vendor", "product", "version", "update", "edition", "language",
+	"sw_edition", "target_sw", "target_hw", "other",
+}
+
+// ParseCPE23 parses a CPE 2.3 formatted string such as
+// "cpe:2.3:a:apache:log4j:2.14.1:*:*:*:*:*:*:*".
+func ParseCPE23(s string) (*CPE, error) {
+	if !strings.HasPrefix(s, "cpe:2.3:") {
+		return nil, fmt.Errorf("CPE does not start with cpe:2.3: %s", s)
+	}
+
+	fields, err := splitFields(s[len("cpe:2.3:"):])
+	if err != nil {
+		return nil, fmt.Errorf("invalid CPE %s: %s", s, err)
+	}
+	if len(fields) != len(cpeAttributes) {
+		return nil, fmt.Errorf("CPE must have %d attributes, but has %d: %s", len(cpeAttributes), len(fields), s)
+	}
+
+	values := make([]string, len(fields))
+	for i, f := range fields {
+		v, err := unquote(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in CPE %s: %s", cpeAttributes[i], s, err)
+		}
+		values[i] = v
+	}
+	switch values[0] {
+	case "a", "o", "h", Any, NA:
+	default:
+		return nil, fmt.Errorf("invalid part in CPE %s: %q is not one of a, o or h", s, values[0])
+	}
+
+	return &CPE{
+		Part:      values[0],
+		Vendor:    values[1],
+		Product:   values[2],
+		Version:   values[3],
+		Update:    values[4],
+		Edition:   values[5],
+		Language:  values[6],
+		SWEdition: values[7],
+		TargetSW:  values[8],
+		TargetHW:  values[9],
+		Other:     values[10],
+	}, nil
+}
+
+// splitFields splits s at each colon that is not quoted with a backslash.
+func splitFields(s string) ([]string, error) {
+	var fields []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i == len(s)-1 {
+				return nil, fmt.Errorf("it ends with an unquoted backslash")
+			}
+			i++
+		case ':':
+			fields = append(fields, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(fields, s[start:]), nil
+}
+
+// unquote returns the value of an attribute in a formatted string, with
+// quoting backslashes removed.
+func unquote(f string) (string, error) {
+	if f == "" {
+		return "", fmt.Errorf("attribute is empty")
+	}
+	if f == Any || f == NA {
+		return f, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(f); i++ {
+		c := f[i]
+		switch {
+		case c == '\\':
+			if i == len(f)-1 {
+				return "", fmt.Errorf("%q ends with an unquoted backslash", f)
+			}
+			i++
+			b.WriteByte(f[i])
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '_', c == '-', c == '.', c == '*', c == '?':
+			b.WriteByte(c)
+		default:
+			return "", fmt.Errorf("%q contains the unquoted character %q", f, c)
+		}
+	}
+	return b.String(), nil
+}
+
+// MatchesVersion returns true if the CPE's version matches v. A version of
+// Any matches every version, and a version of NA matches none. Otherwise the
+// CPE's version is parsed with parse, and matches if it is equal to v.
+func (c *CPE) MatchesVersion(v *version.Version, parse func(string) (*version.Version, error)) (bool, error) {
+	switch c.Version {
+	case Any:
+		return true, nil
+	case NA:
+		return false, nil
+	}
+	cv, err := parse(c.Version)
+	if err != nil {
+		return false, fmt.Errorf("error parsing CPE version %s: %s", c.Version, err)
+	}
+	cmp, err := version.CompareChecked(v, cv)
+	if err != nil {
+		return false, err
+	}
+	return cmp == 0, nil
+}
+
+// MatchCPEVersionRange returns true if v is in the range given by the NVD's
+// versionStartIncluding, versionStartExcluding, versionEndIncluding and
+// versionEndExcluding bounds, which are parsed with parse. An empty bound,
+// or one that is Any, does not limit the range.
+//
+// It returns an error if a bound cannot be parsed, if it is NA, if both the
+// including and excluding start or end bounds are given, or if a bound was
+// parsed as a type that cannot be compared with v.
+func MatchCPEVersionRange(v *version.Version, startInc, startExc, endInc, endExc string, parse func(string) (*version.Version, error)) (bool, error) {
+	if isBound(startInc) && isBound(startExc) {
+		return false, fmt.Errorf("range has both versionStartIncluding %s and versionStartExcluding %s", startInc, startExc)
+	}
+	if isBound(endInc) && isBound(endExc) {
+		return false, fmt.Errorf("range has both versionEndIncluding %s and versionEndExcluding %s", endInc, endExc)
+	}
+
+	type bound struct {
+		name, value string
+		// inRange returns true if the result of comparing a version with the
+		// bound means that the version is in the range.
+		inRange func(cmp int) bool
+		v       *version.Version
+	}
+	bounds := []*bound{
+		{name: "versionStartIncluding", value: startInc, inRange: func(cmp int) bool { return cmp >= 0 }},
+		{name: "versionStartExcluding", value: startExc, inRange: func(cmp int) bool { return cmp > 0 }},
+		{name: "versionEndIncluding", value: endInc, inRange: func(cmp int) bool { return cmp <= 0 }},
+		{name: "versionEndExcluding", value: endExc, inRange: func(cmp int) bool { return cmp < 0 }},
+	}
+
+	// All of the bounds are parsed before any are compared, so that an
+	// invalid bound is an error whatever v is.
+	for _, b := range bounds {
+		if !isBound(b.value) {
+			continue
+		}
+		if b.value == NA {
+			return false, fmt.Errorf("%s cannot be %s", b.name, NA)
+		}
+		var err error
+		if b.v, err = parse(b.value); err != nil {
+			return false, fmt.Errorf("error parsing %s %s: %s", b.name, b.value, err)
+		}
+	}
+
+	in := true
+	for _, b := range bounds {
+		if b.v == nil {
+			continue
+		}
+		cmp, err := version.CompareChecked(v, b.v)
+		if err != nil {
+			return false, fmt.Errorf("error comparing with %s %s: %s", b.name, b.value, err)
+		}
+		in = in && b.inRange(cmp)
+	}
+	return in, nil
+}
+
+// isBound returns true if a range bound limits the range.
+func isBound(bound string) bool {
+	return bound != "" && bound != Any
+}
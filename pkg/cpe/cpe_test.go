@@ -0,0 +1,159 @@
+package cpe
+
+import (
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCPE23(t *testing.T) {
+	tests := []struct {
+		cpe      string
+		expected CPE
+	}{
+		{
+			"cpe:2.3:a:apache:log4j:2.14.1:*:*:*:*:*:*:*",
+			CPE{Part: "a", Vendor: "apache", Product: "log4j", Version: "2.14.1", Update: Any, Edition: Any, Language: Any, SWEdition: Any, TargetSW: Any, TargetHW: Any, Other: Any},
+		},
+		{
+			"cpe:2.3:a:python:python:3.8.0:rc1:*:*:*:*:*:*",
+			CPE{Part: "a", Vendor: "python", Product: "python", Version: "3.8.0", Update: "rc1", Edition: Any, Language: Any, SWEdition: Any, TargetSW: Any, TargetHW: Any, Other: Any},
+		},
+		{
+			"cpe:2.3:a:nodejs:node.js:*:*:*:*:lts:*:*:*",
+			CPE{Part: "a", Vendor: "nodejs", Product: "node.js", Version: Any, Update: Any, Edition: Any, Language: Any, SWEdition: "lts", TargetSW: Any, TargetHW: Any, Other: Any},
+		},
+		{
+			"cpe:2.3:a:rack_project:rack:*:*:*:*:*:ruby:*:*",
+			CPE{Part: "a", Vendor: "rack_project", Product: "rack", Version: Any, Update: Any, Edition: Any, Language: Any, SWEdition: Any, TargetSW: "ruby", TargetHW: Any, Other: Any},
+		},
+		{
+			"cpe:2.3:o:linux:linux_kernel:-:*:*:*:*:*:*:*",
+			CPE{Part: "o", Vendor: "linux", Product: "linux_kernel", Version: NA, Update: Any, Edition: Any, Language: Any, SWEdition: Any, TargetSW: Any, TargetHW: Any, Other: Any},
+		},
+		{
+			`cpe:2.3:a:hp:insight_diagnostics:7.4.0.1570:-:*:*:online:win2003:x64:*`,
+			CPE{Part: "a", Vendor: "hp", Product: "insight_diagnostics", Version: "7.4.0.1570", Update: NA, Edition: Any, Language: Any, SWEdition: "online", TargetSW: "win2003", TargetHW: "x64", Other: Any},
+		},
+		{
+			`cpe:2.3:a:microsoft:internet_explorer:8.\*:sp?:*:*:*:*:*:*`,
+			CPE{Part: "a", Vendor: "microsoft", Product: "internet_explorer", Version: "8.*", Update: "sp?", Edition: Any, Language: Any, SWEdition: Any, TargetSW: Any, TargetHW: Any, Other: Any},
+		},
+		{
+			`cpe:2.3:a:foo\\bar:big\$money_2010:1\:0:*:*:*:*:*:*:*`,
+			CPE{Part: "a", Vendor: `foo\bar`, Product: "big$money_2010", Version: "1:0", Update: Any, Edition: Any, Language: Any, SWEdition: Any, TargetSW: Any, TargetHW: Any, Other: Any},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cpe, func(t *testing.T) {
+			c, err := ParseCPE23(tt.cpe)
+			require.NoError(t, err)
+			assert.Equal(t, &tt.expected, c)
+		})
+	}
+}
+
+func TestParseCPE23Errors(t *testing.T) {
+	for cpe, expected := range map[string]string{
+		"cpe:/a:apache:log4j:2.14.1":                    "CPE does not start with cpe:2.3: cpe:/a:apache:log4j:2.14.1",
+		"cpe:2.3:a:apache:log4j:2.14.1":                 "CPE must have 11 attributes, but has 4: cpe:2.3:a:apache:log4j:2.14.1",
+		"cpe:2.3:a:apache:log4j:2.14.1:*:*:*:*:*:*:*:*": "CPE must have 11 attributes, but has 12",
+		"cpe:2.3:x:apache:log4j:2.14.1:*:*:*:*:*:*:*":   `invalid part in CPE cpe:2.3:x:apache:log4j:2.14.1:*:*:*:*:*:*:*: "x" is not one of a, o or h`,
+		"cpe:2.3:a::log4j:2.14.1:*:*:*:*:*:*:*":         "invalid vendor in CPE cpe:2.3:a::log4j:2.14.1:*:*:*:*:*:*:*: attribute is empty",
+		"cpe:2.3:a:apache:log4j:2.14 1:*:*:*:*:*:*:*":   `invalid version in CPE cpe:2.3:a:apache:log4j:2.14 1:*:*:*:*:*:*:*: "2.14 1" contains the unquoted character ' '`,
+		`cpe:2.3:a:apache:log4j:2.14.1:*:*:*:*:*:*:*\`:  "ends with an unquoted backslash",
+	} {
+		_, err := ParseCPE23(cpe)
+		if assert.Error(t, err, cpe) {
+			assert.Contains(t, err.Error(), expected, cpe)
+		}
+	}
+}
+
+func TestMatchesVersion(t *testing.T) {
+	v := parse(t, "2.14.1")
+	for cpe, expected := range map[string]bool{
+		"cpe:2.3:a:apache:log4j:2.14.1:*:*:*:*:*:*:*":   true,
+		"cpe:2.3:a:apache:log4j:2.14.1.0:*:*:*:*:*:*:*": true,
+		"cpe:2.3:a:apache:log4j:2.14.0:*:*:*:*:*:*:*":   false,
+		"cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*":        true,
+		"cpe:2.3:a:apache:log4j:-:*:*:*:*:*:*:*":        false,
+	} {
+		c, err := ParseCPE23(cpe)
+		require.NoError(t, err, cpe)
+		matches, err := c.MatchesVersion(v, parseGeneric)
+		require.NoError(t, err, cpe)
+		assert.Equal(t, expected, matches, cpe)
+	}
+
+	c, err := ParseCPE23("cpe:2.3:a:apache:log4j:beta:*:*:*:*:*:*:*")
+	require.NoError(t, err)
+	_, err = c.MatchesVersion(v, semVer)
+	assert.Error(t, err)
+}
+
+func TestMatchCPEVersionRange(t *testing.T) {
+	tests := []struct {
+		version                            string
+		startInc, startExc, endInc, endExc string
+		expected                           bool
+	}{
+		// CVE-2021-44228, for log4j 2.0-beta9 up to but not including 2.15.0.
+		{"2.14.1", "2.0-beta9", "", "", "2.15.0", true},
+		{"2.0-beta9", "2.0-beta9", "", "", "2.15.0", true},
+		{"2.0-beta8", "2.0-beta9", "", "", "2.15.0", false},
+		{"2.15.0", "2.0-beta9", "", "", "2.15.0", false},
+		{"2.15.0", "", "", "2.15.0", "", true},
+		{"2.15.1", "", "", "2.15.0", "", false},
+		{"2.0", "", "2.0", "", "", false},
+		{"2.0.1", "", "2.0", "", "", true},
+		{"2.0.1", "", "2.0", "2.0.1", "", true},
+		{"2.0", "", "2.0", "2.0.1", "", false},
+		{"0.1", "", "", "", "", true},
+		{"0.1", Any, "", "", Any, true},
+	}
+
+	for _, tt := range tests {
+		matches, err := MatchCPEVersionRange(parse(t, tt.version), tt.startInc, tt.startExc, tt.endInc, tt.endExc, parseGeneric)
+		require.NoError(t, err, "%+v", tt)
+		assert.Equal(t, tt.expected, matches, "%+v", tt)
+	}
+}
+
+func TestMatchCPEVersionRangeErrors(t *testing.T) {
+	v := parse(t, "1.0.0")
+	for _, tt := range []struct {
+		startInc, startExc, endInc, endExc string
+		expected                           string
+	}{
+		{"1.0.0", "", "", "not a version", "error parsing versionEndExcluding not a version: "},
+		{"1.0.0", "0.9.0", "", "", "range has both versionStartIncluding 1.0.0 and versionStartExcluding 0.9.0"},
+		{"", "", "2.0.0", "2.0.0", "range has both versionEndIncluding 2.0.0 and versionEndExcluding 2.0.0"},
+		{NA, "", "", "", "versionStartIncluding cannot be -"},
+		// An invalid bound is an error even if v is outside a valid one.
+		{"2.0.0", "", "", "x", "error parsing versionEndExcluding x: "},
+	} {
+		_, err := MatchCPEVersionRange(v, tt.startInc, tt.startExc, tt.endInc, tt.endExc, semVer)
+		if assert.Error(t, err, "%+v", tt) {
+			assert.Contains(t, err.Error(), tt.expected, "%+v", tt)
+		}
+	}
+
+	_, err := MatchCPEVersionRange(v, "1.0", "", "", "", version.ParsePython)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "error comparing with versionStartIncluding 1.0: cannot compare")
+	}
+}
+
+func parse(t *testing.T, s string) *version.Version {
+	v, err := version.ParseGeneric(s)
+	require.NoError(t, err)
+	return v
+}
+
+func parseGeneric(s string) (*version.Version, error) { return version.ParseGeneric(s) }
+
+func semVer(s string) (*version.Version, error) { return version.ParseSemVer(s) }
@@ -0,0 +1,312 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/name"
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/pelletier/go-toml"
+)
+
+// CargoLockPackage is a package from a Cargo.lock file.
+type CargoLockPackage struct {
+	// Name is normalized with name.NormalizeCargo.
+	Name string
+	// Version is parsed with version.ParseCargo.
+	Version *version.Version
+	// Source is where the package comes from, such as
+	// "registry+https://github.com/rust-lang/crates.io-index" or a git URL
+	// with the commit after a "#". It is empty for the packages of the
+	// workspace and for path dependencies.
+	Source string
+	// Checksum is the SHA-256 of the package. It is empty for packages that
+	// are not from a registry, and for version 1 lock files, which keep
+	// checksums in their metadata table instead.
+	Checksum string
+}
+
+// CargoDependency is a dependency from a Cargo.toml file.
+type CargoDependency struct {
+	// Line is the line that the dependency is declared on.
+	Line int
+	// Name is the name of the crate, normalized with name.NormalizeCargo.
+	// For a renamed dependency, this is the name from its package key.
+	Name string
+	// Rename is the name the dependency is given in the manifest if it has a
+	// package key, and is empty otherwise.
+	Rename string
+	// Table is the table the dependency is in, which is "dependencies",
+	// "dev-dependencies" or "build-dependencies", or "workspace.dependencies"
+	// for the dependencies a workspace declares for its members.
+	Table string
+	// Target is the platform of a platform-specific dependency, such as
+	// "cfg(windows)" for one in [target.'cfg(windows)'.dependencies]. It is
+	// empty for other dependencies.
+	Target string
+	// Requirement is the version requirement as given, such as "1.0" or
+	// ">= 0.4.0-alpha.2, < 0.5". It is empty if there is none, as for some
+	// path and git dependencies.
+	Requirement string
+	Features    []string
+	Optional    bool
+	// Workspace is true if the dependency is inherited from the workspace,
+	// with "workspace = true".
+	Workspace bool
+	// Path and Git are the path or git URL of the dependency, if it has one.
+	Path string
+	Git  string
+	// Registry is true if the dependency is fetched from a registry. It is
+	// false for path, git and workspace dependencies, since those are not
+	// fetched from a registry when the manifest is built, or may not be.
+	Registry bool
+}
+
+// cargoDependencyTables are the tables that dependencies are declared in.
+var cargoDependencyTables = []string{"dependencies", "dev-dependencies", "build-dependencies"}
+
+// ParseCargoLock parses a Cargo.lock file, and returns each of its packages
+// in the order they appear. It returns an error if data is not valid TOML, or
+// with the line number if a package's name or version is invalid.
+func ParseCargoLock(data []byte) ([]CargoLockPackage, error) {
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Cargo.lock: %s", err)
+	}
+	raw, ok := tree.Get("package").([]*toml.Tree)
+	if !ok && tree.Has("package") {
+		return nil, fmt.Errorf("line %d of Cargo.lock: package must be an array of tables", tree.GetPosition("package").Line)
+	}
+
+	var packages []CargoLockPackage
+	for _, p := range raw {
+		var pkg CargoLockPackage
+		var n, v string
+		err = cargoStrings(p, map[string]*string{
+			"name":     &n,
+			"version":  &v,
+			"source":   &pkg.Source,
+			"checksum": &pkg.Checksum,
+		})
+		if err == nil {
+			err = name.ValidateCargo(n)
+		}
+		if err == nil {
+			pkg.Name = name.NormalizeCargo(n)
+			if pkg.Version, err = version.ParseCargo(v); err != nil {
+				err = fmt.Errorf("invalid version for %s: %s", n, err)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d of Cargo.lock: %s", p.Position().Line, err)
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+// ParseCargoDependencyTable parses a Cargo.toml file, and returns the
+// dependencies from its [dependencies], [dev-dependencies] and
+// [build-dependencies] tables, from the same tables under [target], and from
+// [workspace.dependencies], in the order they appear.
+//
+// Dependencies may be given as a requirement string, as in serde = "1.0", or
+// as a table with keys such as version, features, optional, package, path,
+// git and workspace. Other keys, such as branch and default-features, are
+// ignored. It returns an error if data is not valid TOML, or with the line
+// number if a dependency's name is invalid or one of its keys has the wrong
+// type.
+func ParseCargoDependencyTable(data []byte) ([]CargoDependency, error) {
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Cargo.toml: %s", err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	var deps []CargoDependency
+	add := func(parent *toml.Tree, key, table, target string) error {
+		d, depsErr := cargoDependencies(lines, parent, key, table, target)
+		deps = append(deps, d...)
+		return depsErr
+	}
+
+	for _, table := range cargoDependencyTables {
+		if err = add(tree, table, table, ""); err != nil {
+			return nil, err
+		}
+	}
+	if targets, ok := tree.Get("target").(*toml.Tree); ok {
+		for _, target := range targets.Keys() {
+			t, ok := targets.GetPath([]string{target}).(*toml.Tree)
+			if !ok {
+				return nil, fmt.Errorf("line %d of Cargo.toml: target %s must be a table", targets.GetPositionPath([]string{target}).Line, target)
+			}
+			for _, table := range cargoDependencyTables {
+				if err = add(t, table, table, target); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if workspace, ok := tree.Get("workspace").(*toml.Tree); ok {
+		if err = add(workspace, "dependencies", "workspace.dependencies", ""); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(deps, func(i, j int) bool { return deps[i].Line < deps[j].Line })
+	return deps, nil
+}
+
+// cargoDependencies returns the dependencies from the table at key in parent,
+// ordered by where they are declared.
+func cargoDependencies(lines []string, parent *toml.Tree, key, table, target string) ([]CargoDependency, error) {
+	if !parent.HasPath([]string{key}) {
+		return nil, nil
+	}
+	t, ok := parent.GetPath([]string{key}).(*toml.Tree)
+	if !ok {
+		return nil, fmt.Errorf("line %d of Cargo.toml: %s must be a table", parent.GetPositionPath([]string{key}).Line, table)
+	}
+
+	keys := t.Keys()
+	positions := make(map[string]toml.Position, len(keys))
+	for _, k := range keys {
+		positions[k] = cargoKeyPosition(lines, t, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		pi, pj := positions[keys[i]], positions[keys[j]]
+		return pi.Line < pj.Line || pi.Line == pj.Line && pi.Col < pj.Col
+	})
+
+	var deps []CargoDependency
+	for _, k := range keys {
+		dep := CargoDependency{
+			Line:   positions[k].Line,
+			Table:  table,
+			Target: target,
+		}
+		if err := dep.set(k, t.GetPath([]string{k})); err != nil {
+			return nil, fmt.Errorf("line %d of Cargo.toml: invalid dependency %s: %s", dep.Line, k, err)
+		}
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
+
+// cargoKeyPosition returns the position of key in t. go-toml does not record
+// the positions of the values of inline tables, so for those it looks for the
+// first place after the start of t where key is assigned to.
+func cargoKeyPosition(lines []string, t *toml.Tree, key string) toml.Position {
+	if pos := t.GetPositionPath([]string{key}); !pos.Invalid() && t.Position().Line != 0 {
+		return pos
+	}
+	assign := regexp.MustCompile(`(?:^|[\s{,])((?:` + regexp.QuoteMeta(key) + `|"` + regexp.QuoteMeta(key) + `")\s*[.=])`)
+	start := t.Position().Line - 1
+	if start < 0 {
+		start = 0
+	}
+	for i := start; i < len(lines); i++ {
+		if m := assign.FindStringSubmatchIndex(lines[i]); m != nil {
+			return toml.Position{Line: i + 1, Col: m[2] + 1}
+		}
+	}
+	return toml.Position{}
+}
+
+// set sets the fields of d from the key and value of its entry in a
+// dependency table.
+func (d *CargoDependency) set(key string, value interface{}) error {
+	n := key
+	switch v := value.(type) {
+	case string:
+		d.Requirement = v
+	case *toml.Tree:
+		var pkg string
+		err := cargoStrings(v, map[string]*string{
+			"version": &d.Requirement,
+			"package": &pkg,
+			"path":    &d.Path,
+			"git":     &d.Git,
+		})
+		if err == nil {
+			err = cargoBools(v, map[string]*bool{
+				"optional":  &d.Optional,
+				"workspace": &d.Workspace,
+			})
+		}
+		if err == nil {
+			d.Features, err = cargoStringArray(v, "features")
+		}
+		if err != nil {
+			return err
+		}
+		if pkg != "" {
+			n, d.Rename = pkg, key
+		}
+	default:
+		return fmt.Errorf("must be a string or a table")
+	}
+
+	if err := name.ValidateCargo(n); err != nil {
+		return err
+	}
+	d.Name = name.NormalizeCargo(n)
+	d.Registry = d.Path == "" && d.Git == "" && !d.Workspace
+	return nil
+}
+
+// cargoStrings sets each string in fields to the value of its key in t, if t
+// has the key. It returns an error if a value is not a string.
+func cargoStrings(t *toml.Tree, fields map[string]*string) error {
+	for key, field := range fields {
+		if !t.HasPath([]string{key}) {
+			continue
+		}
+		s, ok := t.GetPath([]string{key}).(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", key)
+		}
+		*field = s
+	}
+	return nil
+}
+
+// cargoBools is like cargoStrings, but for booleans.
+func cargoBools(t *toml.Tree, fields map[string]*bool) error {
+	for key, field := range fields {
+		if !t.HasPath([]string{key}) {
+			continue
+		}
+		b, ok := t.GetPath([]string{key}).(bool)
+		if !ok {
+			return fmt.Errorf("%s must be a boolean", key)
+		}
+		*field = b
+	}
+	return nil
+}
+
+// cargoStringArray returns the array of strings at key in t, or nil if t does
+// not have the key. It returns an error if the value is not an array of
+// strings.
+func cargoStringArray(t *toml.Tree, key string) ([]string, error) {
+	if !t.HasPath([]string{key}) {
+		return nil, nil
+	}
+	values, ok := t.GetPath([]string{key}).([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array of strings", key)
+	}
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array of strings", key)
+		}
+		strs = append(strs, s)
+	}
+	return strs, nil
+}
@@ -0,0 +1,119 @@
+package manifest
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCargoLock(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "mini-grep.Cargo.lock"))
+	require.NoError(t, err)
+	packages, err := ParseCargoLock(data)
+	require.NoError(t, err)
+
+	const registry = "registry+https://github.com/rust-lang/crates.io-index"
+	const git = "git+https://github.com/rust-lang/regex?branch=master#0d0023e4da4bd3d8e8e5d3b3e9b11e48d2b1f3a6"
+	tests := []struct {
+		name     string
+		version  string
+		source   string
+		checksum string
+	}{
+		{"aho_corasick", "1.1.2", registry, "b2969dcb958b36655471fc61f7e416fa76033bdd4bfed0678d8fee1e2d07a1f0"},
+		{"anyhow", "1.0.75", registry, "a4668cab20f66d8d020e1fbc0ebe47217433c1b6c8f2040faf858554e394ace6"},
+		{"grep_matcher", "0.1.7", "", ""},
+		{"memchr", "2.6.4", registry, "f665ee40bc4a3c5590afb1e9677db74a508659dfd71e126420da8274909a0167"},
+		{"mini_grep", "0.3.0", "", ""},
+		{"regex", "1.10.3-dev", git, ""},
+		{"regex_syntax", "0.8.2", git, ""},
+		{"toml_edit_fork", "0.21.0", registry, "d34d383cd00a163b4a5b85053df514d45bc330f6de7737edfe0a93311d1eaa03"},
+		{"winnow", "0.6.0-alpha.2", registry, "7e87b8dfbe3baffbe687eef2e164e32286eff31a5ee16463ce03d991643ec94d"},
+	}
+	require.Len(t, packages, len(tests))
+	for i, tt := range tests {
+		p := packages[i]
+		assert.Equal(t, tt.name, p.Name)
+		assert.Equal(t, tt.version, p.Version.Original, tt.name)
+		assert.Equal(t, tt.source, p.Source, tt.name)
+		assert.Equal(t, tt.checksum, p.Checksum, tt.name)
+	}
+}
+
+func TestParseCargoLockErrors(t *testing.T) {
+	for data, expected := range map[string]string{
+		"[[package]]\nname = \"serde\"\nversion = \"1.0\"\n":                                                                         "line 1 of Cargo.lock: invalid version for serde: invalid cargo version: 1.0",
+		"version = 3\n\n[[package]]\nname = \"a\"\nversion = \"1.0.0\"\n\n[[package]]\nname = \"serde json\"\nversion = \"1.0.0\"\n": `line 7 of Cargo.lock: "serde json" is not a valid Cargo package name: ' ' at position 5 is not allowed`,
+		"[[package]]\nname = 1\n":    "line 1 of Cargo.lock: name must be a string",
+		"package = \"serde\"\n":      "line 1 of Cargo.lock: package must be an array of tables",
+		"[[package]\nname = \"a\"\n": "invalid Cargo.lock: (1, 10): was expecting token [[, but got unclosed table array key instead",
+	} {
+		_, err := ParseCargoLock([]byte(data))
+		if assert.Error(t, err, data) {
+			assert.Equal(t, expected, err.Error(), data)
+		}
+	}
+}
+
+func TestParseCargoDependencyTable(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "mini-grep.Cargo.toml"))
+	require.NoError(t, err)
+	deps, err := ParseCargoDependencyTable(data)
+	require.NoError(t, err)
+
+	expected := []CargoDependency{
+		{Line: 11, Name: "log", Table: "workspace.dependencies", Requirement: "0.4.20", Registry: true},
+		{Line: 12, Name: "serde", Table: "workspace.dependencies", Requirement: "1.0.193", Features: []string{"derive"}, Registry: true},
+		{Line: 15, Name: "anyhow", Table: "dependencies", Requirement: "1.0", Registry: true},
+		{Line: 16, Name: "clap", Table: "dependencies", Requirement: "4.4.11", Features: []string{"derive", "wrap_help"}, Registry: true},
+		{Line: 17, Name: "grep_matcher", Table: "dependencies", Requirement: "0.1.7", Path: "crates/matcher"},
+		{Line: 18, Name: "grep_printer", Table: "dependencies", Path: "crates/printer"},
+		{Line: 19, Name: "log", Table: "dependencies", Workspace: true},
+		{Line: 20, Name: "memchr", Table: "dependencies", Requirement: "2.6", Optional: true, Registry: true},
+		{Line: 21, Name: "regex", Table: "dependencies", Git: "https://github.com/rust-lang/regex"},
+		{Line: 22, Name: "serde", Table: "dependencies", Workspace: true},
+		{Line: 23, Name: "serde_json", Table: "dependencies", Requirement: "1.0.108", Registry: true},
+		{Line: 24, Name: "toml_edit_fork", Rename: "toml_edit", Table: "dependencies", Requirement: "0.21", Registry: true},
+		{Line: 25, Name: "winnow", Table: "dependencies", Requirement: "=0.6.0-alpha.2", Registry: true},
+		{Line: 28, Name: "criterion", Table: "dev-dependencies", Requirement: "0.5.1", Registry: true},
+		{Line: 29, Name: "tempfile", Table: "dev-dependencies", Requirement: "3.8", Registry: true},
+		{Line: 32, Name: "cc", Table: "build-dependencies", Requirement: "1.0.83", Registry: true},
+		{Line: 35, Name: "winapi_util", Table: "dependencies", Target: "cfg(windows)", Requirement: "0.1.6", Registry: true},
+		{Line: 38, Name: "jemallocator", Table: "dev-dependencies", Target: "x86_64-unknown-linux-gnu", Requirement: "0.5", Registry: true},
+	}
+	assert.Equal(t, expected, deps)
+}
+
+func TestParseCargoDependencyTableInline(t *testing.T) {
+	deps, err := ParseCargoDependencyTable([]byte(`dependencies = { b = "2", a = "1" }`))
+	require.NoError(t, err)
+	require.Len(t, deps, 2)
+	assert.Equal(t, "b", deps[0].Name)
+	assert.Equal(t, "a", deps[1].Name)
+
+	deps, err = ParseCargoDependencyTable([]byte("[package]\nname = \"empty\"\n"))
+	require.NoError(t, err)
+	assert.Nil(t, deps)
+}
+
+func TestParseCargoDependencyTableErrors(t *testing.T) {
+	for data, expected := range map[string]string{
+		"[dependencies]\nserde = 1\n":                                   "line 2 of Cargo.toml: invalid dependency serde: must be a string or a table",
+		"[dependencies]\nserde = { version = 1 }\n":                     "line 2 of Cargo.toml: invalid dependency serde: version must be a string",
+		"[dependencies]\nserde = { optional = \"yes\" }\n":              "line 2 of Cargo.toml: invalid dependency serde: optional must be a boolean",
+		"[dependencies]\nserde = { features = \"derive\" }\n":           "line 2 of Cargo.toml: invalid dependency serde: features must be an array of strings",
+		"[dev-dependencies]\n\n_serde = \"1\"\n":                        `line 3 of Cargo.toml: invalid dependency _serde: "_serde" is not a valid Cargo package name: it cannot start with '_'`,
+		"[dependencies]\nfoo = { package = \"2d\", version = \"1\" }\n": `line 2 of Cargo.toml: invalid dependency foo: "2d" is not a valid Cargo package name: it cannot start with '2'`,
+		"dependencies = \"serde\"\n":                                    "line 1 of Cargo.toml: dependencies must be a table",
+		"[target]\nlinux = 1\n":                                         "line 2 of Cargo.toml: target linux must be a table",
+		"[dependencies]\nserde = \"1\"\nserde = \"2\"\n":                "invalid Cargo.toml: (3, 1): The following key was defined twice: dependencies.serde",
+	} {
+		_, err := ParseCargoDependencyTable([]byte(data))
+		if assert.Error(t, err, data) {
+			assert.Equal(t, expected, err.Error(), data)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/name"
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+// DebianDependency is one of the alternatives of a clause in a Debian
+// relationship field.
+type DebianDependency struct {
+	// Name is the package name, validated with name.ValidateDebian.
+	Name string
+	// Arch is the architecture qualifier after the ":" in the name, such as
+	// "any" in "python3:any". It is empty if there is none.
+	Arch string
+	// Relation is the version relation in parentheses. It is nil if there is
+	// none.
+	Relation *DebianRelation
+	// ArchRestrictions is the architecture restriction list, with its
+	// brackets, such as "[amd64 i386]" or "[!hurd-any]". It is empty if there
+	// is none.
+	ArchRestrictions string
+	// ProfileRestrictions are the build profile restriction formulas, with
+	// their angle brackets, such as "<!nocheck> <!cross>". It is empty if
+	// there are none.
+	ProfileRestrictions string
+}
+
+// DebianRelation is the version relation of a DebianDependency.
+type DebianRelation struct {
+	// Operator is one of "<<", "<=", "=", ">=" and ">>".
+	Operator string
+	// Version is parsed with version.ParseDebian.
+	Version *version.Version
+}
+
+// debianAlternative matches an alternative of a relationship field clause.
+// The obsolete "<" and ">" operators are not matched, so they are rejected.
+var debianAlternative = regexp.MustCompile(
+	`^([^\s:(\[<]+)(?::([a-z0-9-]+))?` +
+		`\s*(?:\(\s*(<<|<=|=|>=|>>)\s*([^\s)]+)\s*\))?` +
+		`\s*(\[[^\]]*\])?` +
+		`\s*((?:<[^>]*>\s*)*)$`)
+
+// ParseDebianDepends parses a Debian relationship field, such as the value of
+// a Depends, Build-Depends or Provides field in a control file. It returns a
+// group for each comma separated clause, holding the "|" separated
+// alternatives of the clause, so that "libssl3 | libssl1.1, tzdata" returns
+// two groups, the first with two alternatives.
+//
+// Continuation lines are allowed, as the field's value may span several
+// lines. Empty clauses, as left by a trailing comma, are skipped. Substitution
+// variables such as ${shlibs:Depends} are not expanded, and are rejected.
+//
+// It returns an error naming the clause if a clause is malformed, or if a
+// package name or version in it is invalid.
+func ParseDebianDepends(s string) ([][]DebianDependency, error) {
+	var groups [][]DebianDependency
+	for i, clause := range strings.Split(s, ",") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+		var group []DebianDependency
+		for _, alt := range strings.Split(clause, "|") {
+			dep, err := parseDebianAlternative(strings.TrimSpace(alt))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Debian relationship clause %d %q: %s", i+1, clause, err)
+			}
+			group = append(group, dep)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func parseDebianAlternative(alt string) (DebianDependency, error) {
+	m := debianAlternative.FindStringSubmatch(alt)
+	if m == nil {
+		return DebianDependency{}, fmt.Errorf("%q must be in the form \"name:arch (op version) [archs] <profiles>\"", alt)
+	}
+	if err := name.ValidateDebian(m[1]); err != nil {
+		return DebianDependency{}, err
+	}
+	dep := DebianDependency{
+		Name:                m[1],
+		Arch:                m[2],
+		ArchRestrictions:    m[5],
+		ProfileRestrictions: strings.TrimSpace(m[6]),
+	}
+	if m[3] != "" {
+		v, err := version.ParseDebian(m[4])
+		if err != nil {
+			return DebianDependency{}, err
+		}
+		dep.Relation = &DebianRelation{Operator: m[3], Version: v}
+	}
+	return dep, nil
+}
@@ -0,0 +1,130 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// debianGroups returns groups as strings like "libssl3 (>= 3.0.0) | libssl1.1",
+// with each alternative written back in the control file syntax.
+func debianGroups(groups [][]DebianDependency) []string {
+	var res []string
+	for _, group := range groups {
+		var alts []string
+		for _, d := range group {
+			s := d.Name
+			if d.Arch != "" {
+				s += ":" + d.Arch
+			}
+			if d.Relation != nil {
+				s += " (" + d.Relation.Operator + " " + d.Relation.Version.Original + ")"
+			}
+			for _, r := range []string{d.ArchRestrictions, d.ProfileRestrictions} {
+				if r != "" {
+					s += " " + r
+				}
+			}
+			alts = append(alts, s)
+		}
+		res = append(res, strings.Join(alts, " | "))
+	}
+	return res
+}
+
+func TestParseDebianDepends(t *testing.T) {
+	tests := map[string][]string{
+		// Depends of libc-bin from Ubuntu 22.04.
+		"libc6 (>> 2.35), libc6 (<< 2.36)": {"libc6 (>> 2.35)", "libc6 (<< 2.36)"},
+		// Depends of curl from Debian bookworm.
+		"libc6 (>= 2.34), libcurl4 (= 7.88.1-10+deb12u5), zlib1g (>= 1:1.1.4)": {
+			"libc6 (>= 2.34)",
+			"libcurl4 (= 7.88.1-10+deb12u5)",
+			"zlib1g (>= 1:1.1.4)",
+		},
+		// Depends of python3.11 from Debian bookworm.
+		"python3.11-minimal (= 3.11.2-6), libpython3.11-stdlib (= 3.11.2-6), media-types | mime-support, tzdata": {
+			"python3.11-minimal (= 3.11.2-6)",
+			"libpython3.11-stdlib (= 3.11.2-6)",
+			"media-types | mime-support",
+			"tzdata",
+		},
+		// Depends of openssh-client, with a relation on the alternative and
+		// a line break as in a control file.
+		"adduser (>= 3.10),\n dpkg (>= 1.7.0), passwd, libc6 (>= 2.36),\n libfido2-1 (>= 1.8.0), libssl3 (>= 3.0.0) | libssl1.1,": {
+			"adduser (>= 3.10)",
+			"dpkg (>= 1.7.0)",
+			"passwd",
+			"libc6 (>= 2.36)",
+			"libfido2-1 (>= 1.8.0)",
+			"libssl3 (>= 3.0.0) | libssl1.1",
+		},
+		// Build-Depends with architecture qualifiers, architecture
+		// restrictions and build profiles.
+		"debhelper-compat (= 13), dh-python, python3-all:any, python3-setuptools,\n python3-pytest <!nocheck>, libssl-dev [!hurd-any], gcc-multilib [amd64 i386] <!nocross> <!stage1>,\n libsystemd-dev [linux-any] | libelogind-dev": {
+			"debhelper-compat (= 13)",
+			"dh-python",
+			"python3-all:any",
+			"python3-setuptools",
+			"python3-pytest <!nocheck>",
+			"libssl-dev [!hurd-any]",
+			"gcc-multilib [amd64 i386] <!nocross> <!stage1>",
+			"libsystemd-dev [linux-any] | libelogind-dev",
+		},
+		// Versioned Provides of python3-numpy from Debian bookworm.
+		"python3-numpy-abi9, python3-numpy-dev (= 1:1.24.2-1), python3.11-numpy, python3-f2py": {
+			"python3-numpy-abi9",
+			"python3-numpy-dev (= 1:1.24.2-1)",
+			"python3.11-numpy",
+			"python3-f2py",
+		},
+		"libfoo1(>=1.0~rc1)": {"libfoo1 (>= 1.0~rc1)"},
+		"":                   nil,
+	}
+	for in, expected := range tests {
+		groups, err := ParseDebianDepends(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, expected, debianGroups(groups), in)
+	}
+}
+
+func TestParseDebianDependsFields(t *testing.T) {
+	groups, err := ParseDebianDepends("python3:any (>= 3.9~), libssl3 [amd64] <!nocheck> | libssl1.1")
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+
+	d := groups[0][0]
+	assert.Equal(t, "python3", d.Name)
+	assert.Equal(t, "any", d.Arch)
+	require.NotNil(t, d.Relation)
+	assert.Equal(t, ">=", d.Relation.Operator)
+	assert.Equal(t, "3.9~", d.Relation.Version.Original)
+
+	require.Len(t, groups[1], 2)
+	d = groups[1][0]
+	assert.Equal(t, "libssl3", d.Name)
+	assert.Equal(t, "", d.Arch)
+	assert.Nil(t, d.Relation)
+	assert.Equal(t, "[amd64]", d.ArchRestrictions)
+	assert.Equal(t, "<!nocheck>", d.ProfileRestrictions)
+	assert.Equal(t, DebianDependency{Name: "libssl1.1"}, groups[1][1])
+}
+
+func TestParseDebianDependsErrors(t *testing.T) {
+	for in, expected := range map[string]string{
+		"libc6 (>= 2.34), libssl3 (> 3.0)": `invalid Debian relationship clause 2 "libssl3 (> 3.0)": "libssl3 (> 3.0)" must be in the form "name:arch (op version) [archs] <profiles>"`,
+		"libc6 (>= 2.34":                   `invalid Debian relationship clause 1 "libc6 (>= 2.34": "libc6 (>= 2.34" must be in the form "name:arch (op version) [archs] <profiles>"`,
+		"tzdata, Libc6":                    `invalid Debian relationship clause 2 "Libc6": "Libc6" is not a valid Debian package name: 'L' at position 0 is not allowed`,
+		"a | b":                            `invalid Debian relationship clause 1 "a | b": "a" is not a valid Debian package name: it is shorter than 2 bytes`,
+		"libc6 (>= two)":                   `invalid Debian relationship clause 1 "libc6 (>= two)": invalid Debian version: two: upstream version does not start with a digit`,
+		"libc6 | , tzdata":                 `invalid Debian relationship clause 1 "libc6 |": "" must be in the form "name:arch (op version) [archs] <profiles>"`,
+		"${shlibs:Depends}, tzdata":        `invalid Debian relationship clause 1 "${shlibs:Depends}": "${shlibs:Depends}" must be in the form "name:arch (op version) [archs] <profiles>"`,
+	} {
+		_, err := ParseDebianDepends(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, expected, err.Error(), in)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package manifest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/artifact"
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+// GemfileLock is the information extracted from a Bundler Gemfile.lock file.
+type GemfileLock struct {
+	// Specs are the resolved gems from every source section, in the order
+	// they appear.
+	Specs []GemSpec
+	// Dependencies are the gems from the DEPENDENCIES section, which are the
+	// ones in the Gemfile.
+	Dependencies []GemDependency
+	// Platforms are the platforms from the PLATFORMS section, such as "ruby"
+	// and "x86_64-linux".
+	Platforms []string
+	// BundledWith is the version of Bundler from the BUNDLED WITH section,
+	// parsed with version.ParseRuby. It is nil if there is none.
+	BundledWith *version.Version
+}
+
+// GemSpec is a resolved gem from the specs of a GEM, GIT, PATH or PLUGIN
+// SOURCE section.
+type GemSpec struct {
+	Name string
+	// Version is parsed with version.ParseRuby.
+	Version *version.Version
+	// Platform is the platform of a platform-specific gem, such as
+	// "x86_64-linux". It is empty for pure Ruby gems.
+	Platform string
+	// Source is the name of the section the gem is in, such as "GEM" or
+	// "GIT".
+	Source string
+	// Remote is the remote of the section the gem is in, such as
+	// "https://rubygems.org/" or a git URL.
+	Remote string
+	// Dependencies are the gems this gem depends on, which are the lines
+	// indented under it.
+	Dependencies []GemDependency
+}
+
+// GemDependency is a dependency on a gem, from the DEPENDENCIES section or
+// from under a spec.
+type GemDependency struct {
+	Name        string
+	Requirement *artifact.RubyRequirement
+	// Pinned is true for gems in the DEPENDENCIES section that end in "!",
+	// which Bundler uses for gems from a GIT or PATH source.
+	Pinned bool
+}
+
+var (
+	// gemfileLockSpec matches a spec line, without its indentation. This is
+	// the pattern that Bundler uses, which takes everything after the first
+	// "-" in the parentheses as the platform.
+	gemfileLockSpec = regexp.MustCompile(`^(\S+) \(([^-)]*)(?:-([^)]*))?\)$`)
+	// gemfileLockDependency matches a dependency line, without its
+	// indentation.
+	gemfileLockDependency = regexp.MustCompile(`^([^\s(!]+)(?: \(([^)]*)\))?(!)?$`)
+)
+
+// gemfileLockSources are the sections that have specs.
+var gemfileLockSources = map[string]bool{
+	"GEM":           true,
+	"GIT":           true,
+	"PATH":          true,
+	"PLUGIN SOURCE": true,
+}
+
+// ParseGemfileLock parses a Bundler Gemfile.lock file. It extracts the specs
+// of the GEM, GIT, PATH and PLUGIN SOURCE sections with their dependencies,
+// and the contents of the DEPENDENCIES, PLATFORMS and BUNDLED WITH sections.
+// Other sections, such as RUBY VERSION and CHECKSUMS, are skipped.
+//
+// The sections are told apart by indentation, as Bundler does: section names
+// are not indented, the options of a section and its "specs:" line are
+// indented by two spaces, specs by four, and the dependencies of a spec by
+// six. It returns an error with the line number if a line in a section it
+// extracts from is malformed, or if a version or requirement is invalid.
+func ParseGemfileLock(r io.Reader) (*GemfileLock, error) {
+	lock := &GemfileLock{}
+	var section, remote string
+	var spec *GemSpec
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimRight(s.Text(), " \t\r")
+		if text == "" {
+			continue
+		}
+		content := strings.TrimLeft(text, " ")
+		indent := len(text) - len(content)
+		if indent == 0 {
+			section, remote, spec = content, "", nil
+			continue
+		}
+
+		var err error
+		switch {
+		case gemfileLockSources[section]:
+			switch indent {
+			case 2:
+				if strings.HasPrefix(content, "remote: ") {
+					remote = strings.TrimPrefix(content, "remote: ")
+				}
+			case 4:
+				spec, err = gemfileLockSpecLine(content, section, remote)
+				if err == nil {
+					lock.Specs = append(lock.Specs, *spec)
+					spec = &lock.Specs[len(lock.Specs)-1]
+				}
+			case 6:
+				if spec == nil {
+					err = fmt.Errorf("dependency %s is not under a spec", content)
+					break
+				}
+				var dep GemDependency
+				if dep, err = gemfileLockDependencyLine(content); err == nil {
+					spec.Dependencies = append(spec.Dependencies, dep)
+				}
+			default:
+				err = fmt.Errorf("unexpected indentation of %d spaces", indent)
+			}
+		case section == "DEPENDENCIES":
+			var dep GemDependency
+			if dep, err = gemfileLockDependencyLine(content); err == nil {
+				lock.Dependencies = append(lock.Dependencies, dep)
+			}
+		case section == "PLATFORMS":
+			lock.Platforms = append(lock.Platforms, content)
+		case section == "BUNDLED WITH":
+			if lock.BundledWith, err = version.ParseRuby(content); err != nil {
+				err = fmt.Errorf("invalid Bundler version: %s", err)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d of Gemfile.lock: %s", line, err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+func gemfileLockSpecLine(content, source, remote string) (*GemSpec, error) {
+	m := gemfileLockSpec.FindStringSubmatch(content)
+	if m == nil {
+		return nil, fmt.Errorf("spec must be in the form \"name (version)\": %s", content)
+	}
+	v, err := version.ParseRuby(m[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid version for %s: %s", m[1], err)
+	}
+	return &GemSpec{Name: m[1], Version: v, Platform: m[3], Source: source, Remote: remote}, nil
+}
+
+func gemfileLockDependencyLine(content string) (GemDependency, error) {
+	m := gemfileLockDependency.FindStringSubmatch(content)
+	if m == nil {
+		return GemDependency{}, fmt.Errorf("dependency must be in the form \"name (requirement)\": %s", content)
+	}
+	req, err := artifact.ParseRubyRequirement(m[2])
+	if err != nil {
+		return GemDependency{}, fmt.Errorf("invalid requirement for %s: %s", m[1], err)
+	}
+	return GemDependency{Name: m[1], Requirement: req, Pinned: m[3] != ""}, nil
+}
@@ -0,0 +1,142 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readGemfileLock(t *testing.T, file string) *GemfileLock {
+	in, err := os.Open(filepath.Join("testdata", file))
+	require.NoError(t, err)
+	defer in.Close()
+
+	lock, err := ParseGemfileLock(in)
+	require.NoError(t, err)
+	return lock
+}
+
+// gemDependencies returns deps as strings like "rails (~> 7.1.2)!".
+func gemDependencies(deps []GemDependency) []string {
+	var res []string
+	for _, d := range deps {
+		s := d.Name + " (" + d.Requirement.String() + ")"
+		if d.Pinned {
+			s += "!"
+		}
+		res = append(res, s)
+	}
+	return res
+}
+
+func TestParseGemfileLock(t *testing.T) {
+	lock := readGemfileLock(t, "rails.Gemfile.lock")
+
+	tests := []struct {
+		name     string
+		version  string
+		platform string
+		source   string
+		remote   string
+	}{
+		{"devise", "4.9.3", "", "GIT", "https://github.com/heartcombo/devise.git"},
+		{"billing", "0.1.0", "", "PATH", "engines/billing"},
+		{"actionpack", "7.1.2", "", "GEM", "https://rubygems.org/"},
+		{"bcrypt", "3.1.20", "", "GEM", "https://rubygems.org/"},
+		{"mini_portile2", "2.8.5", "", "GEM", "https://rubygems.org/"},
+		{"nokogiri", "1.15.5", "", "GEM", "https://rubygems.org/"},
+		{"nokogiri", "1.15.5", "arm64-darwin", "GEM", "https://rubygems.org/"},
+		{"nokogiri", "1.15.5", "x86_64-linux", "GEM", "https://rubygems.org/"},
+		{"orm_adapter", "0.5.0", "", "GEM", "https://rubygems.org/"},
+		{"pg", "1.5.4", "", "GEM", "https://rubygems.org/"},
+		{"puma", "6.4.0", "", "GEM", "https://rubygems.org/"},
+		{"nio4r", "2.7.0", "", "GEM", "https://rubygems.org/"},
+		{"rack", "3.0.8", "", "GEM", "https://rubygems.org/"},
+		{"racc", "1.7.3", "", "GEM", "https://rubygems.org/"},
+		{"rails", "7.1.2", "", "GEM", "https://rubygems.org/"},
+		{"railties", "7.1.2", "", "GEM", "https://rubygems.org/"},
+		{"responders", "3.1.1", "", "GEM", "https://rubygems.org/"},
+		{"sassc", "2.4.0", "", "GEM", "https://rubygems.org/"},
+		{"ffi", "1.16.3", "", "GEM", "https://rubygems.org/"},
+		{"ffi", "1.16.3", "x64-mingw-ucrt", "GEM", "https://rubygems.org/"},
+		{"tzinfo-data", "1.2023.3", "", "GEM", "https://rubygems.org/"},
+		{"tzinfo", "2.0.6", "", "GEM", "https://rubygems.org/"},
+		{"warden", "1.2.9", "", "GEM", "https://rubygems.org/"},
+	}
+	require.Len(t, lock.Specs, len(tests))
+	for i, tt := range tests {
+		spec := lock.Specs[i]
+		assert.Equal(t, tt.name, spec.Name)
+		assert.Equal(t, tt.version, spec.Version.Original, tt.name)
+		assert.Equal(t, tt.platform, spec.Platform, tt.name)
+		assert.Equal(t, tt.source, spec.Source, tt.name)
+		assert.Equal(t, tt.remote, spec.Remote, tt.name)
+	}
+
+	assert.Equal(t, []string{
+		"bcrypt (~> 3.0)",
+		"orm_adapter (~> 0.1)",
+		"railties (>= 4.1.0)",
+		"responders (>= 0)",
+		"warden (~> 1.2.3)",
+	}, gemDependencies(lock.Specs[0].Dependencies))
+	assert.Equal(t, []string{"mini_portile2 (~> 2.8.2)", "racc (~> 1.4)"}, gemDependencies(lock.Specs[5].Dependencies))
+	assert.Equal(t, []string{"racc (~> 1.4)"}, gemDependencies(lock.Specs[7].Dependencies))
+	assert.Nil(t, lock.Specs[3].Dependencies)
+
+	assert.Equal(t, []string{
+		"billing (>= 0)!",
+		"devise (>= 0)!",
+		"pg (~> 1.1)",
+		"puma (>= 5.0)",
+		"rails (~> 7.1.2)",
+		"sassc (>= 0)",
+		"tzinfo-data (>= 0)",
+	}, gemDependencies(lock.Dependencies))
+	assert.Equal(t, []string{"arm64-darwin-22", "ruby", "x64-mingw-ucrt", "x86_64-linux"}, lock.Platforms)
+	require.NotNil(t, lock.BundledWith)
+	assert.Equal(t, "2.4.22", lock.BundledWith.Original)
+}
+
+func TestParseGemfileLockWithUnknownSections(t *testing.T) {
+	lock := readGemfileLock(t, "jekyll.Gemfile.lock")
+
+	var specs []string
+	for _, spec := range lock.Specs {
+		specs = append(specs, spec.Name+" "+spec.Version.Original)
+	}
+	assert.Equal(t, []string{
+		"addressable 2.8.6",
+		"colorator 1.1.0",
+		"jekyll 4.3.3",
+		"jekyll-feed 0.17.0",
+		"public_suffix 5.0.4",
+		"terminal-table 3.0.2",
+		"unicode-display_width 2.5.0",
+	}, specs)
+	assert.Equal(t, []string{"public_suffix (>= 2.0.2, < 6.0)"}, gemDependencies(lock.Specs[0].Dependencies))
+	assert.Equal(t, []string{"jekyll (~> 4.3.3)", "jekyll-feed (~> 0.12)"}, gemDependencies(lock.Dependencies))
+	assert.Equal(t, []string{"ruby"}, lock.Platforms)
+	assert.Equal(t, "2.5.3", lock.BundledWith.Original)
+}
+
+func TestParseGemfileLockErrors(t *testing.T) {
+	for src, expected := range map[string]string{
+		"GEM\n  specs:\n    rails 7.1.2":              `line 3 of Gemfile.lock: spec must be in the form "name (version)": rails 7.1.2`,
+		"GEM\n  specs:\n    rails (seven)":            "line 3 of Gemfile.lock: invalid version for rails: invalid ruby version: seven",
+		"GEM\n  specs:\n      rack (>= 2.2)":          "line 3 of Gemfile.lock: dependency rack (>= 2.2) is not under a spec",
+		"GEM\n  specs:\n    rails (7.1.2)\n     rack": "line 4 of Gemfile.lock: unexpected indentation of 5 spaces",
+		"DEPENDENCIES\n  rails (=> 7.0)":              `line 2 of Gemfile.lock: invalid requirement for rails: invalid constraint "=> 7.0" in ruby requirement => 7.0`,
+		"DEPENDENCIES\n  rails ~> 7.0":                `line 2 of Gemfile.lock: dependency must be in the form "name (requirement)": rails ~> 7.0`,
+		"BUNDLED WITH\n   latest":                     "line 2 of Gemfile.lock: invalid Bundler version: invalid ruby version: latest",
+	} {
+		_, err := ParseGemfileLock(strings.NewReader(src))
+		if assert.Error(t, err, src) {
+			assert.Equal(t, expected, err.Error(), src)
+		}
+	}
+}
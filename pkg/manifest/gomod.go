@@ -0,0 +1,320 @@
+// Package manifest extracts the dependencies of a package, and their
+// versions, from the manifests and lock files of language package managers,
+// such as go.mod, requirements.txt, Gemfile.lock, package-lock.json, Cargo.toml
+// and Cargo.lock files, and Debian control file relationship fields.
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/name"
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+// GoMod is the information extracted from a go.mod file.
+type GoMod struct {
+	// Module is the path from the module directive. It is empty if there is
+	// none.
+	Module string
+	// Go is the version from the go directive. It is nil if there is none.
+	// See ParseGoMod for how it is ordered.
+	Go *version.Version
+	// Toolchain is the version from the toolchain directive, ordered like
+	// Go. It is nil if there is none.
+	Toolchain *version.Version
+	Require   []GoModRequire
+	Replace   []GoModReplace
+	Exclude   []GoModModule
+}
+
+// GoModModule is a module path and version.
+type GoModModule struct {
+	Path string
+	// Version is parsed with version.ParseGo. It is nil where a directive
+	// allows it to be left out, as in a replace directive for every version
+	// of a module.
+	Version *version.Version
+}
+
+// GoModRequire is an entry from a require directive.
+type GoModRequire struct {
+	GoModModule
+	// Indirect is true if the entry has an "// indirect" comment.
+	Indirect bool
+}
+
+// GoModReplace is an entry from a replace directive.
+type GoModReplace struct {
+	// Old is the module that is replaced. Its Version is nil if every
+	// version is replaced.
+	Old GoModModule
+	// New is the replacement. Its Version is nil if it is a local
+	// directory, like "../fork", in which case Path is the directory.
+	New GoModModule
+}
+
+// ParseGoMod extracts the module path, go and toolchain versions, and the
+// require, replace and exclude directives from the contents of a go.mod file.
+// Both the single line and block forms of directives are handled. Retract,
+// godebug and unknown directives are skipped, as they do not affect which
+// versions are used.
+//
+// The go and toolchain versions are ordered as the go command orders them,
+// where a version with no patch number, like "1.21", is the language version
+// and is less than every release of it, so "1.21" < "1.21rc1" < "1.21.0".
+// They are parsed as Generic, but their segments differ from what
+// version.ParseGeneric gives for the same string, so they should only be
+// compared with each other.
+//
+// This is not a full go.mod parser, so it does not check everything that the
+// go command does, but it returns an error with the line number if a
+// directive it extracts is malformed, or if a module path or version in one
+// is invalid.
+func ParseGoMod(src []byte) (*GoMod, error) {
+	mod := &GoMod{}
+	block := ""
+	s := bufio.NewScanner(bytes.NewReader(src))
+	for line := 1; s.Scan(); line++ {
+		tokens, comment, err := goModTokens(s.Text())
+		if err != nil {
+			return nil, fmt.Errorf("go.mod line %d: %s", line, err)
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+
+		if block != "" {
+			if tokens[0] == ")" && len(tokens) == 1 {
+				block = ""
+				continue
+			}
+			if err := mod.directive(block, tokens, comment); err != nil {
+				return nil, fmt.Errorf("go.mod line %d: %s", line, err)
+			}
+			continue
+		}
+
+		if len(tokens) == 2 && tokens[1] == "(" {
+			block = tokens[0]
+			continue
+		}
+		if len(tokens) == 3 && tokens[1] == "(" && tokens[2] == ")" {
+			continue
+		}
+		if err := mod.directive(tokens[0], tokens[1:], comment); err != nil {
+			return nil, fmt.Errorf("go.mod line %d: %s", line, err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if block != "" {
+		return nil, fmt.Errorf("go.mod has an unclosed %s block", block)
+	}
+	return mod, nil
+}
+
+// directive adds the directive verb with arguments args to mod. comment is
+// the text of the comment at the end of the line, if any.
+func (mod *GoMod) directive(verb string, args []string, comment string) error {
+	switch verb {
+	case "module":
+		if len(args) != 1 {
+			return fmt.Errorf("module directive must have one argument: the module path")
+		}
+		mod.Module = args[0]
+
+	case "go", "toolchain":
+		if len(args) != 1 {
+			return fmt.Errorf("%s directive must have one argument: the version", verb)
+		}
+		v, err := parseGoVersion(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid %s version: %s", verb, err)
+		}
+		if verb == "go" {
+			mod.Go = v
+		} else {
+			mod.Toolchain = v
+		}
+
+	case "require":
+		if len(args) != 2 {
+			return fmt.Errorf("require directive must have two arguments: the module path and version")
+		}
+		m, err := goModModule(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		mod.Require = append(mod.Require, GoModRequire{GoModModule: m, Indirect: isIndirect(comment)})
+
+	case "exclude":
+		if len(args) != 2 {
+			return fmt.Errorf("exclude directive must have two arguments: the module path and version")
+		}
+		m, err := goModModule(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		mod.Exclude = append(mod.Exclude, m)
+
+	case "replace":
+		r, err := goModReplace(args)
+		if err != nil {
+			return err
+		}
+		mod.Replace = append(mod.Replace, r)
+	}
+	return nil
+}
+
+func goModReplace(args []string) (GoModReplace, error) {
+	arrow := -1
+	for i, a := range args {
+		if a == "=>" {
+			arrow = i
+			break
+		}
+	}
+	if arrow < 1 || arrow > 2 || len(args)-arrow-1 < 1 || len(args)-arrow-1 > 2 {
+		return GoModReplace{}, fmt.Errorf("replace directive must be in the form \"path [version] => path [version]\"")
+	}
+
+	var r GoModReplace
+	var err error
+	old := args[:arrow]
+	if len(old) == 1 {
+		err = name.ValidateGoModule(old[0])
+		r.Old.Path = old[0]
+	} else {
+		r.Old, err = goModModule(old[0], old[1])
+	}
+	if err != nil {
+		return GoModReplace{}, err
+	}
+
+	replacement := args[arrow+1:]
+	if len(replacement) == 1 {
+		if !isLocalPath(replacement[0]) {
+			return GoModReplace{}, fmt.Errorf("replacement module %s has no version, but is not a local directory", replacement[0])
+		}
+		r.New.Path = replacement[0]
+		return r, nil
+	}
+	if r.New, err = goModModule(replacement[0], replacement[1]); err != nil {
+		return GoModReplace{}, err
+	}
+	return r, nil
+}
+
+func goModModule(path, ver string) (GoModModule, error) {
+	if err := name.ValidateGoModule(path); err != nil {
+		return GoModModule{}, err
+	}
+	v, err := version.ParseGo(ver)
+	if err != nil {
+		return GoModModule{}, fmt.Errorf("invalid version for %s: %s", path, err)
+	}
+	return GoModModule{Path: path, Version: v}, nil
+}
+
+// isLocalPath returns true if path is a directory rather than a module path,
+// which the go command decides by whether it starts with "./", "../" or
+// "/", or a Windows drive letter.
+func isLocalPath(path string) bool {
+	return strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../") || strings.HasPrefix(path, "/") ||
+		path == "." || path == ".." || strings.HasPrefix(path, `.\`) || strings.HasPrefix(path, `..\`) ||
+		len(path) >= 3 && path[1] == ':' && (path[2] == '\\' || path[2] == '/')
+}
+
+// isIndirect returns true if comment marks a require entry as indirect,
+// which is when it is "indirect", or starts with "indirect;".
+func isIndirect(comment string) bool {
+	comment = strings.TrimSpace(comment)
+	return comment == "indirect" || strings.HasPrefix(comment, "indirect;")
+}
+
+// goModTokens splits a line of a go.mod file into tokens, and returns them
+// with the text of the comment at the end of the line. Tokens are separated
+// by spaces, except in quoted strings, and "(", ")" and "=>" are always
+// tokens of their own.
+func goModTokens(line string) ([]string, string, error) {
+	var tokens []string
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(line[i:], "//"):
+			return tokens, line[i+2:], nil
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case strings.HasPrefix(line[i:], "=>"):
+			tokens = append(tokens, "=>")
+			i += 2
+		case c == '"' || c == '`':
+			end := i + 1
+			for end < len(line) && line[end] != c {
+				if c == '"' && line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				return nil, "", fmt.Errorf("unterminated quoted string")
+			}
+			tokens = append(tokens, strings.Replace(line[i+1:end], `\"`, `"`, -1))
+			i = end + 1
+		default:
+			end := i
+			for end < len(line) && !strings.ContainsRune(" \t\r()\"`", rune(line[end])) &&
+				!strings.HasPrefix(line[end:], "//") && !strings.HasPrefix(line[end:], "=>") {
+				end++
+			}
+			tokens = append(tokens, line[i:end])
+			i = end
+		}
+	}
+	return tokens, "", nil
+}
+
+// goVersionRegex matches the versions in go and toolchain directives, like
+// "1.21", "1.21rc2" or "go1.22.5".
+var goVersionRegex = regexp.MustCompile(`^(?:go)?(0|[1-9][0-9]*)(?:\.(0|[1-9][0-9]*)(?:\.(0|[1-9][0-9]*)|(alpha|beta|rc)(0|[1-9][0-9]*))?)?$`)
+
+// goVersionPreReleases maps the pre-release kinds of Go versions to the
+// segments which order them between the language version and the releases.
+var goVersionPreReleases = map[string]string{
+	"alpha": "-3",
+	"beta":  "-2",
+	"rc":    "-1",
+}
+
+// parseGoVersion parses the version from a go or toolchain directive, with
+// the ordering described in ParseGoMod.
+func parseGoVersion(s string) (*version.Version, error) {
+	m := goVersionRegex.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid go toolchain version: %s", s)
+	}
+
+	minor := m[2]
+	if minor == "" {
+		minor = "0"
+	}
+	var segments []string
+	switch {
+	case m[3] != "":
+		segments = []string{m[1], minor, m[3]}
+	case m[4] != "":
+		segments = []string{m[1], minor, goVersionPreReleases[m[4]], m[5]}
+	default:
+		segments = []string{m[1], minor, "-4"}
+	}
+	return version.FromSortable(s, segments, version.Generic)
+}
@@ -0,0 +1,139 @@
+package manifest
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/ActiveState/langtools/pkg/version/versiontest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readGoMod(t *testing.T, file string) *GoMod {
+	src, err := ioutil.ReadFile(filepath.Join("testdata", file))
+	require.NoError(t, err)
+	mod, err := ParseGoMod(src)
+	require.NoError(t, err)
+	return mod
+}
+
+func goModModuleFor(t *testing.T, path, ver string) GoModModule {
+	if ver == "" {
+		return GoModModule{Path: path}
+	}
+	v, err := version.ParseGo(ver)
+	require.NoError(t, err)
+	return GoModModule{Path: path, Version: v}
+}
+
+func goToolchainVersion(t *testing.T, ver string) *version.Version {
+	v, err := parseGoVersion(ver)
+	require.NoError(t, err)
+	return v
+}
+
+func TestGoVersionOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, parseGoVersion, []string{
+		"1.20",
+		"1.20.0",
+		"1.20.14",
+		"1.21",
+		"1.21alpha1",
+		"1.21beta2",
+		"1.21rc1",
+		"1.21rc2",
+		"1.21.0",
+		"go1.21.1",
+		"1.22",
+		"2",
+	})
+	versiontest.AssertAllEqual(t, parseGoVersion, []string{"1.21.0", "go1.21.0"})
+
+	for _, s := range []string{"", "go", "1.", "1.21.", "01.21", "1.21rc", "1.21.0rc1", "1.21-rc1", "v1.21"} {
+		_, err := parseGoVersion(s)
+		assert.Error(t, err, "%q is not a valid go version", s)
+	}
+}
+
+func TestParseGoMod(t *testing.T) {
+	mod := readGoMod(t, "hugo.go.mod")
+	assert.Equal(t, "github.com/gohugoio/hugo", mod.Module)
+	assert.Equal(t, goToolchainVersion(t, "1.22.0"), mod.Go)
+	assert.Equal(t, goToolchainVersion(t, "go1.22.5"), mod.Toolchain)
+
+	require.Len(t, mod.Require, 12)
+	assert.Equal(t, GoModRequire{GoModModule: goModModuleFor(t, "github.com/BurntSushi/locker", "v0.0.0-20171006230638-a6e239ea1c69")}, mod.Require[0])
+	assert.Equal(t, GoModRequire{GoModModule: goModModuleFor(t, "github.com/PuerkitoBio/goquery", "v1.9.2"), Indirect: true}, mod.Require[1])
+	assert.Equal(t, GoModRequire{GoModModule: goModModuleFor(t, "github.com/alecthomas/chroma/v2", "v2.14.0")}, mod.Require[2])
+	assert.Equal(t, GoModRequire{GoModModule: goModModuleFor(t, "gopkg.in/yaml.v2", "v2.4.0"), Indirect: true}, mod.Require[10])
+	assert.Equal(t, GoModRequire{GoModModule: goModModuleFor(t, "github.com/armon/go-radix", "v1.0.1-0.20221118154546-54df44f2176c"), Indirect: true}, mod.Require[11])
+
+	assert.Equal(t, []GoModModule{goModModuleFor(t, "github.com/pelletier/go-toml/v2", "v2.0.0")}, mod.Exclude)
+	assert.Equal(t, []GoModReplace{{
+		Old: goModModuleFor(t, "github.com/evanw/esbuild", ""),
+		New: goModModuleFor(t, "github.com/evanw/esbuild", "v0.21.3"),
+	}}, mod.Replace)
+}
+
+func TestParseGoModWithLocalReplaces(t *testing.T) {
+	mod := readGoMod(t, "kubernetes.go.mod")
+	assert.Equal(t, "k8s.io/kubernetes", mod.Module)
+	assert.Equal(t, goToolchainVersion(t, "1.21"), mod.Go)
+	assert.Nil(t, mod.Toolchain)
+	assert.Nil(t, mod.Exclude)
+
+	require.Len(t, mod.Require, 12)
+	assert.Equal(t, GoModRequire{GoModModule: goModModuleFor(t, "k8s.io/api", "v0.0.0")}, mod.Require[3])
+	assert.Equal(t, GoModRequire{GoModModule: goModModuleFor(t, "github.com/pkg/errors", "v0.9.1"), Indirect: true}, mod.Require[11])
+
+	assert.Equal(t, []GoModReplace{
+		{Old: goModModuleFor(t, "k8s.io/api", ""), New: GoModModule{Path: "./staging/src/k8s.io/api"}},
+		{Old: goModModuleFor(t, "k8s.io/apimachinery", ""), New: GoModModule{Path: "./staging/src/k8s.io/apimachinery"}},
+		{Old: goModModuleFor(t, "k8s.io/client-go", ""), New: GoModModule{Path: "./staging/src/k8s.io/client-go"}},
+		{Old: goModModuleFor(t, "github.com/gogo/protobuf", "v1.3.2"), New: goModModuleFor(t, "github.com/gogo/protobuf", "v1.3.1")},
+	}, mod.Replace)
+}
+
+func TestParseGoModSingleLine(t *testing.T) {
+	mod, err := ParseGoMod([]byte(`module "example.com/m" // quoted
+go 1.21rc2
+require example.com/a v1.0.0
+require example.com/b v2.0.0+incompatible // indirect
+require ()
+replace example.com/a v1.0.0 => ../a
+retract v0.9.0
+unknown directive
+`))
+	require.NoError(t, err)
+	assert.Equal(t, &GoMod{
+		Module: "example.com/m",
+		Go:     goToolchainVersion(t, "1.21rc2"),
+		Require: []GoModRequire{
+			{GoModModule: goModModuleFor(t, "example.com/a", "v1.0.0")},
+			{GoModModule: goModModuleFor(t, "example.com/b", "v2.0.0+incompatible"), Indirect: true},
+		},
+		Replace: []GoModReplace{
+			{Old: goModModuleFor(t, "example.com/a", "v1.0.0"), New: GoModModule{Path: "../a"}},
+		},
+	}, mod)
+}
+
+func TestParseGoModErrors(t *testing.T) {
+	for src, expected := range map[string]string{
+		"module example.com/m\ngo one":                         "go.mod line 2: invalid go version: invalid go toolchain version: one",
+		"require example.com/a 1.0.0":                          "go.mod line 1: invalid version for example.com/a: go module version does not start with v: 1.0.0",
+		"require example.com/a":                                "go.mod line 1: require directive must have two arguments: the module path and version",
+		"require (\n\texample.com/a v1.0.0\n":                  "go.mod has an unclosed require block",
+		"\nreplace example.com/a => example.com/b":             "go.mod line 2: replacement module example.com/b has no version, but is not a local directory",
+		"replace example.com/a v1.0.0 example.com/b v1.0.0":    "go.mod line 1: replace directive must be in the form \"path [version] => path [version]\"",
+		"module \"example.com/m":                               "go.mod line 1: unterminated quoted string",
+		"exclude (\n\texample.com/a v1.0.0\n\texample..com\n)": "go.mod line 3: exclude directive must have two arguments: the module path and version",
+	} {
+		_, err := ParseGoMod([]byte(src))
+		if assert.Error(t, err, src) {
+			assert.Equal(t, expected, err.Error(), src)
+		}
+	}
+}
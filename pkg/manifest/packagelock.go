@@ -0,0 +1,236 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/artifact"
+	"github.com/ActiveState/langtools/pkg/name"
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+// PackageLock is the information extracted from an npm package-lock.json
+// file.
+type PackageLock struct {
+	// LockfileVersion is 1, 2 or 3.
+	LockfileVersion int
+	// Packages are the installed packages, sorted by Path.
+	Packages []PackageLockEntry
+	// Dependencies, DevDependencies, OptionalDependencies and
+	// PeerDependencies map the names of the dependencies declared in the
+	// root package's package.json to their ranges. They are nil for
+	// version 1 lock files, which do not record them.
+	Dependencies         map[string]string
+	DevDependencies      map[string]string
+	OptionalDependencies map[string]string
+	PeerDependencies     map[string]string
+}
+
+// PackageLockEntry is an installed package from a package-lock.json file.
+type PackageLockEntry struct {
+	// Name is the name the package is installed under, normalized with
+	// name.NormalizeNpm. For an alias, this is the alias rather than the
+	// name of the package it is for.
+	Name string
+	// Path is where the package is installed, such as
+	// "node_modules/@babel/core/node_modules/semver".
+	Path string
+	// Version is the version parsed with version.ParseNpm. It is nil if the
+	// lock file has no version for the package, which is the case for links
+	// and for some packages that are not from the registry.
+	Version *version.Version
+	// Type is where the package comes from. It is artifact.NpmVersion for
+	// packages from the registry, artifact.NpmAlias for aliases of packages
+	// from the registry, and artifact.NpmGit, artifact.NpmRemote,
+	// artifact.NpmFile or artifact.NpmDirectory for packages from elsewhere.
+	// Links, such as workspace packages, are artifact.NpmDirectory.
+	Type artifact.NpmSpecType
+	// Resolved is where the package was fetched from, such as a tarball URL,
+	// or the target of a link. It is empty if the lock file does not say.
+	Resolved string
+	// Direct is true for the root package's own dependencies.
+	Direct bool
+	// Dev is true if the package is only needed by dev dependencies.
+	Dev bool
+}
+
+type packageLockJSON struct {
+	LockfileVersion int                                  `json:"lockfileVersion"`
+	Packages        map[string]packageLockPackageJSON    `json:"packages"`
+	Dependencies    map[string]packageLockDependencyJSON `json:"dependencies"`
+}
+
+// packageLockPackageJSON is an entry in the "packages" map of version 2 and
+// 3 lock files.
+type packageLockPackageJSON struct {
+	Version              string            `json:"version"`
+	Resolved             string            `json:"resolved"`
+	Link                 bool              `json:"link"`
+	Dev                  bool              `json:"dev"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+// packageLockDependencyJSON is an entry in the "dependencies" tree of
+// version 1 lock files.
+type packageLockDependencyJSON struct {
+	Version      string                               `json:"version"`
+	Resolved     string                               `json:"resolved"`
+	Dev          bool                                 `json:"dev"`
+	Requires     map[string]string                    `json:"requires"`
+	Dependencies map[string]packageLockDependencyJSON `json:"dependencies"`
+}
+
+// ParsePackageLock parses an npm package-lock.json file, and returns every
+// installed package in it, with the dependencies declared by the root
+// package. Version 2 and 3 lock files are read from their "packages" map,
+// and version 1 lock files from their "dependencies" tree.
+//
+// Version 1 lock files do not record the root package's dependencies, so for
+// them a package is taken to be direct if it is installed at the top level
+// and no other package requires it. This misses direct dependencies that
+// other packages also require.
+//
+// It returns an error if data is not a package-lock.json file, if its
+// lockfileVersion is not supported, or if a package's version is not valid.
+func ParsePackageLock(data []byte) (*PackageLock, error) {
+	var raw packageLockJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid package-lock.json: %s", err)
+	}
+
+	lock := &PackageLock{LockfileVersion: raw.LockfileVersion}
+	var err error
+	switch {
+	case raw.LockfileVersion == 1:
+		lock.Packages, err = packageLockV1Entries(raw.Dependencies)
+	case raw.LockfileVersion == 2 || raw.LockfileVersion == 3:
+		err = lock.addV2Entries(raw.Packages)
+	default:
+		return nil, fmt.Errorf("unsupported package-lock.json lockfileVersion: %d", raw.LockfileVersion)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid package-lock.json: %s", err)
+	}
+	sort.Slice(lock.Packages, func(i, j int) bool { return lock.Packages[i].Path < lock.Packages[j].Path })
+	return lock, nil
+}
+
+func (lock *PackageLock) addV2Entries(packages map[string]packageLockPackageJSON) error {
+	root := packages[""]
+	lock.Dependencies = root.Dependencies
+	lock.DevDependencies = root.DevDependencies
+	lock.OptionalDependencies = root.OptionalDependencies
+	lock.PeerDependencies = root.PeerDependencies
+
+	for path, p := range packages {
+		// Entries that are not under node_modules are the root package and
+		// workspace packages, which are installed by links.
+		i := strings.LastIndex(path, "node_modules/")
+		if i < 0 {
+			continue
+		}
+		n := name.NormalizeNpm(path[i+len("node_modules/"):])
+		if slashes := strings.Count(n, "/"); slashes > 1 || slashes == 1 && !strings.HasPrefix(n, "@") {
+			return fmt.Errorf("%s is not a package path", path)
+		}
+		entry := PackageLockEntry{
+			Name:     n,
+			Path:     path,
+			Resolved: p.Resolved,
+			Direct:   i == 0 && lock.declares(n),
+			Dev:      p.Dev,
+		}
+
+		if p.Link {
+			entry.Type = artifact.NpmDirectory
+		} else if err := entry.setVersion(p.Version); err != nil {
+			return err
+		}
+		lock.Packages = append(lock.Packages, entry)
+	}
+	return nil
+}
+
+// declares returns true if the root package declares a dependency on n.
+func (lock *PackageLock) declares(n string) bool {
+	for _, deps := range []map[string]string{lock.Dependencies, lock.DevDependencies, lock.OptionalDependencies, lock.PeerDependencies} {
+		if _, ok := deps[n]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func packageLockV1Entries(deps map[string]packageLockDependencyJSON) ([]PackageLockEntry, error) {
+	required := map[string]bool{}
+	var entries []PackageLockEntry
+	var walk func(prefix string, deps map[string]packageLockDependencyJSON) error
+	walk = func(prefix string, deps map[string]packageLockDependencyJSON) error {
+		for n, d := range deps {
+			entry := PackageLockEntry{
+				Name:     name.NormalizeNpm(n),
+				Path:     prefix + "node_modules/" + n,
+				Resolved: d.Resolved,
+				Dev:      d.Dev,
+			}
+			if err := entry.setVersion(d.Version); err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+			for r := range d.Requires {
+				required[name.NormalizeNpm(r)] = true
+			}
+			if err := walk(entry.Path+"/", d.Dependencies); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk("", deps); err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		entries[i].Direct = !strings.Contains(entries[i].Path[len("node_modules/"):], "node_modules/") && !required[entries[i].Name]
+	}
+	return entries, nil
+}
+
+// setVersion sets the version and type of e from the version in its lock
+// file entry, which is a semver version for packages from the registry, and
+// may be a spec, such as a git URL, for other packages. The resolved URL is
+// used to find the type of packages from git or local files that have a
+// version, as in version 2 and 3 lock files.
+func (e *PackageLockEntry) setVersion(raw string) error {
+	if v, err := version.ParseNpm(raw); err == nil {
+		e.Version = v
+		e.Type = artifact.NpmVersion
+		if e.Resolved != "" && !strings.HasPrefix(e.Resolved, "http://") && !strings.HasPrefix(e.Resolved, "https://") {
+			if spec, err := artifact.ParseNpmSpec(e.Resolved); err == nil {
+				e.Type = spec.Type
+			}
+		}
+		return nil
+	}
+
+	spec, err := artifact.ParseNpmSpec(e.Name + "@" + raw)
+	if err != nil {
+		return fmt.Errorf("invalid version for %s: %s", e.Path, err)
+	}
+	switch spec.Type {
+	case artifact.NpmAlias:
+		if spec.Alias.Version == nil {
+			return fmt.Errorf("invalid version for %s: alias %s is not for a version", e.Path, raw)
+		}
+		e.Version = spec.Alias.Version
+	case artifact.NpmVersion, artifact.NpmRange, artifact.NpmTag:
+		return fmt.Errorf("invalid version for %s: %s is not a semver version", e.Path, raw)
+	}
+	e.Type = spec.Type
+	return nil
+}
@@ -0,0 +1,117 @@
+package manifest
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/artifact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readPackageLock(t *testing.T, file string) *PackageLock {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", file))
+	require.NoError(t, err)
+	lock, err := ParsePackageLock(data)
+	require.NoError(t, err)
+	return lock
+}
+
+type packageLockTest struct {
+	path     string
+	name     string
+	version  string
+	typ      artifact.NpmSpecType
+	resolved string
+	direct   bool
+	dev      bool
+}
+
+func assertPackageLockEntries(t *testing.T, tests []packageLockTest, entries []PackageLockEntry) {
+	require.Len(t, entries, len(tests))
+	for i, tt := range tests {
+		e := entries[i]
+		assert.Equal(t, tt.path, e.Path)
+		assert.Equal(t, tt.name, e.Name, tt.path)
+		if tt.version == "" {
+			assert.Nil(t, e.Version, tt.path)
+		} else if assert.NotNil(t, e.Version, tt.path) {
+			assert.Equal(t, tt.version, e.Version.Original, tt.path)
+		}
+		assert.Equal(t, tt.typ, e.Type, tt.path)
+		assert.Equal(t, tt.resolved, e.Resolved, tt.path)
+		assert.Equal(t, tt.direct, e.Direct, tt.path)
+		assert.Equal(t, tt.dev, e.Dev, tt.path)
+	}
+}
+
+func TestParsePackageLockV1(t *testing.T) {
+	lock := readPackageLock(t, "package-lock-v1.json")
+	assert.Equal(t, 1, lock.LockfileVersion)
+	assert.Nil(t, lock.Dependencies)
+	assert.Nil(t, lock.DevDependencies)
+
+	const registry = "https://registry.npmjs.org/"
+	assertPackageLockEntries(t, []packageLockTest{
+		{"node_modules/@types/node", "@types/node", "14.18.63", artifact.NpmVersion, registry + "@types/node/-/node-14.18.63.tgz", true, true},
+		{"node_modules/accepts", "accepts", "1.3.8", artifact.NpmVersion, registry + "accepts/-/accepts-1.3.8.tgz", false, false},
+		{"node_modules/debug", "debug", "2.6.9", artifact.NpmVersion, registry + "debug/-/debug-2.6.9.tgz", false, false},
+		{"node_modules/express", "express", "4.18.2", artifact.NpmVersion, registry + "express/-/express-4.18.2.tgz", true, false},
+		{"node_modules/left-pad", "left-pad", "", artifact.NpmGit, "", true, false},
+		{"node_modules/local-utils", "local-utils", "", artifact.NpmDirectory, "", true, false},
+		{"node_modules/mime-db", "mime-db", "1.52.0", artifact.NpmVersion, registry + "mime-db/-/mime-db-1.52.0.tgz", false, false},
+		{"node_modules/mime-types", "mime-types", "2.1.35", artifact.NpmVersion, registry + "mime-types/-/mime-types-2.1.35.tgz", false, false},
+		{"node_modules/ms", "ms", "2.1.3", artifact.NpmVersion, registry + "ms/-/ms-2.1.3.tgz", false, false},
+		{"node_modules/negotiator", "negotiator", "0.6.3", artifact.NpmVersion, registry + "negotiator/-/negotiator-0.6.3.tgz", false, false},
+		{"node_modules/send", "send", "0.18.0", artifact.NpmVersion, registry + "send/-/send-0.18.0.tgz", false, false},
+		{"node_modules/send/node_modules/ms", "ms", "2.1.3", artifact.NpmVersion, registry + "ms/-/ms-2.1.3.tgz", false, false},
+		{"node_modules/string-width", "string-width", "4.2.3", artifact.NpmAlias, registry + "string-width/-/string-width-4.2.3.tgz", true, true},
+	}, lock.Packages)
+}
+
+func TestParsePackageLockV3(t *testing.T) {
+	lock := readPackageLock(t, "package-lock-v3.json")
+	assert.Equal(t, 3, lock.LockfileVersion)
+	assert.Equal(t, map[string]string{
+		"@babel/runtime": "^7.23.2",
+		"left-pad":       "github:stevemao/left-pad",
+		"react":          "^18.2.0",
+		"ui-kit":         "file:packages/ui-kit",
+	}, lock.Dependencies)
+	assert.Equal(t, map[string]string{"@babel/core": "^7.23.3", "typescript": "~5.2.2"}, lock.DevDependencies)
+	assert.Equal(t, map[string]string{"fsevents": "^2.3.3"}, lock.OptionalDependencies)
+	assert.Nil(t, lock.PeerDependencies)
+
+	const registry = "https://registry.npmjs.org/"
+	assertPackageLockEntries(t, []packageLockTest{
+		{"node_modules/@babel/core", "@babel/core", "7.23.3", artifact.NpmVersion, registry + "@babel/core/-/core-7.23.3.tgz", true, true},
+		{"node_modules/@babel/core/node_modules/semver", "semver", "6.3.1", artifact.NpmVersion, registry + "semver/-/semver-6.3.1.tgz", false, true},
+		{"node_modules/@babel/runtime", "@babel/runtime", "7.23.2", artifact.NpmVersion, registry + "@babel/runtime/-/runtime-7.23.2.tgz", true, false},
+		{"node_modules/fsevents", "fsevents", "2.3.3", artifact.NpmVersion, registry + "fsevents/-/fsevents-2.3.3.tgz", true, false},
+		{"node_modules/js-tokens", "js-tokens", "4.0.0", artifact.NpmVersion, registry + "js-tokens/-/js-tokens-4.0.0.tgz", false, false},
+		{"node_modules/left-pad", "left-pad", "1.3.0", artifact.NpmGit, "git+ssh://git@github.com/stevemao/left-pad.git#5ad7aa6e8d8a8ea06e8c2a5b5a9d8e3f9e8d6c4b", true, false},
+		{"node_modules/loose-envify", "loose-envify", "1.4.0", artifact.NpmVersion, registry + "loose-envify/-/loose-envify-1.4.0.tgz", false, false},
+		{"node_modules/react", "react", "18.2.0", artifact.NpmVersion, registry + "react/-/react-18.2.0.tgz", true, false},
+		{"node_modules/regenerator-runtime", "regenerator-runtime", "0.14.0", artifact.NpmVersion, registry + "regenerator-runtime/-/regenerator-runtime-0.14.0.tgz", false, false},
+		{"node_modules/typescript", "typescript", "5.2.2", artifact.NpmVersion, registry + "typescript/-/typescript-5.2.2.tgz", true, true},
+		{"node_modules/ui-kit", "ui-kit", "", artifact.NpmDirectory, "packages/ui-kit", true, false},
+	}, lock.Packages)
+}
+
+func TestParsePackageLockErrors(t *testing.T) {
+	for data, expected := range map[string]string{
+		`[]`:                     "invalid package-lock.json: json: cannot unmarshal array into Go value of type manifest.packageLockJSON",
+		`{"lockfileVersion": 4}`: "unsupported package-lock.json lockfileVersion: 4",
+		`{}`:                     "unsupported package-lock.json lockfileVersion: 0",
+		`{"lockfileVersion": 3, "packages": {"node_modules/a/b": {"version": "1.0.0"}}}`: "invalid package-lock.json: node_modules/a/b is not a package path",
+		`{"lockfileVersion": 3, "packages": {"node_modules/a": {"version": "^1.0.0"}}}`:  "invalid package-lock.json: invalid version for node_modules/a: ^1.0.0 is not a semver version",
+		`{"lockfileVersion": 1, "dependencies": {"a": {"version": "npm:b@^1.0.0"}}}`:     "invalid package-lock.json: invalid version for node_modules/a: alias npm:b@^1.0.0 is not for a version",
+		`{"lockfileVersion": 1, "dependencies": {"a": {"version": "latest"}}}`:           "invalid package-lock.json: invalid version for node_modules/a: latest is not a semver version",
+	} {
+		_, err := ParsePackageLock([]byte(data))
+		if assert.Error(t, err, data) {
+			assert.Equal(t, expected, err.Error(), data)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package manifest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/artifact"
+	"github.com/ActiveState/langtools/pkg/name"
+)
+
+// RequirementsFile is the information extracted from a pip requirements
+// file.
+type RequirementsFile struct {
+	Requirements []RequirementsEntry
+	// Includes are the other requirements and constraints files that the file
+	// refers to with -r and -c. They are not read.
+	Includes []RequirementsInclude
+	// Options are the options that apply to the whole file, such as
+	// --index-url, which are not used here but are kept for callers that
+	// need them.
+	Options []RequirementsOption
+	// Errors has an error for each line that could not be parsed. It is nil
+	// if every line was parsed.
+	Errors []*LineError
+}
+
+// RequirementsEntry is a requirement from a requirements file.
+type RequirementsEntry struct {
+	// Line is the line number that the requirement starts on.
+	Line int
+	// PythonRequirement is the requirement. For editable installs, and
+	// requirements that are just a URL or path, Name is the name from the
+	// "#egg=" fragment of the URL, or empty if there is none.
+	artifact.PythonRequirement
+	// Editable is true for editable installs, which are given with -e.
+	Editable bool
+	// Hashes are the values of the --hash options, such as "sha256:abc...".
+	Hashes []string
+	// Options are the other options given on the line of the requirement,
+	// such as --config-settings.
+	Options []RequirementsOption
+}
+
+// RequirementsInclude is a reference to another requirements or constraints
+// file.
+type RequirementsInclude struct {
+	Line int
+	// Path is the path or URL of the file, as given.
+	Path string
+	// Constraint is true for constraints files, which are given with -c,
+	// rather than requirements files, which are given with -r.
+	Constraint bool
+}
+
+// RequirementsOption is an option from a requirements file.
+type RequirementsOption struct {
+	Line int
+	// Name is the option as given, such as "--index-url" or "-i".
+	Name string
+	// Value is empty for options that take no value, such as --pre.
+	Value string
+}
+
+// LineError is an error in a line of a file.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+var (
+	// requirementsComment matches a comment, which pip recognizes at the
+	// start of a line or after white space.
+	requirementsComment = regexp.MustCompile(`(?:^|\s+)#.*$`)
+	// requirementsURL matches the start of a URL.
+	requirementsURL = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*://`)
+	// requirementsEgg matches the "#egg=" fragment that gives the name of
+	// the package at a URL.
+	requirementsEgg = regexp.MustCompile(`#egg=([^&]+)`)
+)
+
+// requirementsOptions lists the options that pip allows in requirements
+// files, and whether they take a value. perRequirement is true for the ones
+// that are given after a requirement on the same line.
+var requirementsOptions = map[string]struct {
+	takesValue, perRequirement bool
+}{
+	"-i":                {takesValue: true},
+	"--index-url":       {takesValue: true},
+	"--extra-index-url": {takesValue: true},
+	"--no-index":        {},
+	"-f":                {takesValue: true},
+	"--find-links":      {takesValue: true},
+	"--trusted-host":    {takesValue: true},
+	"--no-binary":       {takesValue: true},
+	"--only-binary":     {takesValue: true},
+	"--prefer-binary":   {},
+	"--pre":             {},
+	"--require-hashes":  {},
+	"--use-feature":     {takesValue: true},
+	"-r":                {takesValue: true},
+	"--requirement":     {takesValue: true},
+	"-c":                {takesValue: true},
+	"--constraint":      {takesValue: true},
+	"-e":                {takesValue: true},
+	"--editable":        {takesValue: true},
+	"--hash":            {takesValue: true, perRequirement: true},
+	"-C":                {takesValue: true, perRequirement: true},
+	"--config-settings": {takesValue: true, perRequirement: true},
+	"--global-option":   {takesValue: true, perRequirement: true},
+}
+
+// ParseRequirementsFile parses a pip requirements file. Lines ending in a
+// backslash are joined to the next line, and comments are removed, as pip
+// does. Each remaining line is either a PEP 508 requirement, parsed with
+// artifact.ParsePythonRequirement and optionally followed by options like
+// --hash, or an option. Editable installs given with -e are returned as
+// requirements, and other requirements files given with -r or -c are
+// returned as includes without being read.
+//
+// A line that cannot be parsed does not stop the rest of the file from being
+// parsed. Instead there is a *LineError for it in the Errors of the result.
+// The returned error is only for errors reading from r.
+func ParseRequirementsFile(r io.Reader) (*RequirementsFile, error) {
+	f := &RequirementsFile{}
+	s := bufio.NewScanner(r)
+	logical, start := "", 0
+	for line := 1; s.Scan(); line++ {
+		text := s.Text()
+		if logical == "" {
+			start = line
+		}
+		if strings.HasSuffix(text, `\`) {
+			logical += text[:len(text)-1]
+			continue
+		}
+		f.parseLine(start, logical+text)
+		logical = ""
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if logical != "" {
+		f.parseLine(start, logical)
+	}
+	return f, nil
+}
+
+// parseLine adds the contents of the logical line text, which starts on
+// line number line, to f.
+func (f *RequirementsFile) parseLine(line int, text string) {
+	text = strings.TrimSpace(requirementsComment.ReplaceAllString(text, ""))
+	if text == "" {
+		return
+	}
+
+	var err error
+	if strings.HasPrefix(text, "-") {
+		err = f.parseOptionLine(line, strings.Fields(text))
+	} else {
+		err = f.parseRequirementLine(line, text)
+	}
+	if err != nil {
+		f.Errors = append(f.Errors, &LineError{Line: line, Err: err})
+	}
+}
+
+func (f *RequirementsFile) parseOptionLine(line int, fields []string) error {
+	opts, err := parseRequirementsOptions(line, fields)
+	if err != nil {
+		return err
+	}
+	opt := opts[0]
+	if requirementsOptions[opt.Name].perRequirement {
+		return fmt.Errorf("option %s must follow a requirement", opt.Name)
+	}
+	if len(opts) > 1 && opt.Name != "-e" && opt.Name != "--editable" {
+		return fmt.Errorf("option %s must be on a line of its own", opt.Name)
+	}
+
+	switch opt.Name {
+	case "-r", "--requirement":
+		f.Includes = append(f.Includes, RequirementsInclude{Line: line, Path: opt.Value})
+	case "-c", "--constraint":
+		f.Includes = append(f.Includes, RequirementsInclude{Line: line, Path: opt.Value, Constraint: true})
+	case "-e", "--editable":
+		entry, err := requirementsURLEntry(line, opt.Value)
+		if err != nil {
+			return err
+		}
+		entry.Editable = true
+		return f.addRequirement(entry, opts[1:])
+	default:
+		f.Options = append(f.Options, opt)
+	}
+	return nil
+}
+
+func (f *RequirementsFile) parseRequirementLine(line int, text string) error {
+	// pip splits the requirement from its options at the first field that
+	// starts with "-".
+	fields := strings.Fields(text)
+	end := 0
+	for end < len(fields) && !strings.HasPrefix(fields[end], "-") {
+		end++
+	}
+	req := strings.Join(fields[:end], " ")
+	opts, err := parseRequirementsOptions(line, fields[end:])
+	if err != nil {
+		return err
+	}
+
+	var entry RequirementsEntry
+	if requirementsURL.MatchString(req) || strings.HasPrefix(req, ".") || strings.HasPrefix(req, "/") {
+		entry, err = requirementsURLEntry(line, req)
+	} else {
+		var parsed *artifact.PythonRequirement
+		parsed, err = artifact.ParsePythonRequirement(req)
+		if parsed != nil {
+			entry = RequirementsEntry{Line: line, PythonRequirement: *parsed}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return f.addRequirement(entry, opts)
+}
+
+// addRequirement adds entry to f with the per-requirement options opts.
+func (f *RequirementsFile) addRequirement(entry RequirementsEntry, opts []RequirementsOption) error {
+	for _, opt := range opts {
+		if !requirementsOptions[opt.Name].perRequirement {
+			return fmt.Errorf("option %s cannot follow a requirement", opt.Name)
+		}
+		if opt.Name == "--hash" {
+			entry.Hashes = append(entry.Hashes, opt.Value)
+		} else {
+			entry.Options = append(entry.Options, opt)
+		}
+	}
+	f.Requirements = append(f.Requirements, entry)
+	return nil
+}
+
+// requirementsURLEntry returns the entry for a requirement that is a URL or
+// path, with the name from its "#egg=" fragment.
+func requirementsURLEntry(line int, url string) (RequirementsEntry, error) {
+	entry := RequirementsEntry{Line: line}
+	entry.URL = url
+	if m := requirementsEgg.FindStringSubmatch(url); m != nil {
+		if err := name.ValidatePython(m[1]); err != nil {
+			return RequirementsEntry{}, fmt.Errorf("invalid egg name in %s: %s", url, err)
+		}
+		entry.Name = name.NormalizePython(m[1])
+	}
+	return entry, nil
+}
+
+// parseRequirementsOptions parses fields as options, which are either
+// "--name=value" or "--name value" for options that take a value.
+func parseRequirementsOptions(line int, fields []string) ([]RequirementsOption, error) {
+	var opts []RequirementsOption
+	for i := 0; i < len(fields); i++ {
+		opt := RequirementsOption{Line: line, Name: fields[i]}
+		hasValue := false
+		if eq := strings.IndexByte(opt.Name, '='); eq >= 0 {
+			opt.Name, opt.Value = opt.Name[:eq], opt.Name[eq+1:]
+			hasValue = true
+		}
+		o, ok := requirementsOptions[opt.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown option %s", opt.Name)
+		}
+		switch {
+		case o.takesValue && !hasValue:
+			if i+1 == len(fields) {
+				return nil, fmt.Errorf("option %s needs a value", opt.Name)
+			}
+			i++
+			opt.Value = fields[i]
+		case !o.takesValue && hasValue:
+			return nil, fmt.Errorf("option %s does not take a value", opt.Name)
+		}
+		opts = append(opts, opt)
+	}
+	return opts, nil
+}
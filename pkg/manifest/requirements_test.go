@@ -0,0 +1,104 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequirementsFile(t *testing.T) {
+	in, err := os.Open(filepath.Join("testdata", "requirements.txt"))
+	require.NoError(t, err)
+	defer in.Close()
+
+	f, err := ParseRequirementsFile(in)
+	require.NoError(t, err)
+
+	assert.Equal(t, []RequirementsOption{
+		{Line: 5, Name: "--index-url", Value: "https://pypi.org/simple"},
+		{Line: 6, Name: "--extra-index-url", Value: "https://packages.example.com/simple"},
+		{Line: 7, Name: "--trusted-host", Value: "packages.example.com"},
+	}, f.Options)
+	assert.Equal(t, []RequirementsInclude{
+		{Line: 9, Path: "base.txt"},
+		{Line: 10, Path: "constraints.txt", Constraint: true},
+	}, f.Includes)
+
+	tests := []struct {
+		line       int
+		name       string
+		extras     []string
+		specifiers []string
+		marker     string
+		url        string
+		editable   bool
+	}{
+		{12, "django", nil, []string{"==4.2.7"}, "", "", false},
+		{15, "celery", []string{"redis", "sqs"}, []string{">=5.3", "<6.0"}, "", "", false},
+		{16, "psycopg2-binary", nil, []string{"~=2.9.9"}, `platform_python_implementation == "CPython"`, "", false},
+		{17, "typing-extensions", nil, []string{">=4.8"}, `python_version < "3.11"`, "", false},
+		{18, "gunicorn", nil, nil, "", "", false},
+		{19, "uvloop", nil, []string{">=0.17"}, `sys_platform != "win32"`, "", false},
+		{20, "zope-interface", nil, []string{"!=6.0.*"}, "", "", false},
+		{21, "requests", []string{"security"}, []string{"==2.31.0"}, "", "", false},
+		{24, "", nil, nil, "", "./libs/shared", true},
+		{25, "example-toolkit", nil, nil, "", "git+https://github.com/example/toolkit.git@v1.2.0#egg=Example_Toolkit", true},
+		{26, "internal-lib", nil, nil, "", "https://files.example.com/wheels/internal_lib-1.0-py3-none-any.whl#egg=internal-lib", false},
+	}
+	require.Len(t, f.Requirements, len(tests))
+	for i, tt := range tests {
+		req := f.Requirements[i]
+		assert.Equal(t, tt.line, req.Line, tt.name)
+		assert.Equal(t, tt.name, req.Name, tt.line)
+		assert.Equal(t, tt.extras, req.Extras, tt.line)
+		assert.Equal(t, tt.marker, req.Marker, tt.line)
+		assert.Equal(t, tt.url, req.URL, tt.line)
+		assert.Equal(t, tt.editable, req.Editable, tt.line)
+
+		var specifiers []string
+		for _, sp := range req.Specifiers {
+			specifiers = append(specifiers, sp.Operator+sp.Version)
+			assert.NotNil(t, sp.Parsed, tt.line)
+		}
+		assert.Equal(t, tt.specifiers, specifiers, tt.line)
+	}
+
+	assert.Equal(t, []string{
+		"sha256:8e0f1c2c2786b5c0e39fe1afce24c926040fad47c8ea8ad30aaf1188df29fc41",
+		"sha256:e1d37c51ad26186de355cbcec16613ebdabfa9689bbade9c538835205a8abbe9",
+	}, f.Requirements[0].Hashes)
+	assert.Equal(t, []RequirementsOption{{Line: 21, Name: "--config-settings", Value: "--build-option=--no-cython"}}, f.Requirements[7].Options)
+
+	var errs []string
+	for _, e := range f.Errors {
+		errs = append(errs, e.Error())
+	}
+	assert.Equal(t, []string{
+		`line 29: invalid version specifier in Python requirement numpy = 1.26.2: "= 1.26.2" is not an operator followed by a version`,
+		"line 30: unknown option --not-an-option",
+		"line 31: option --index-url cannot follow a requirement",
+	}, errs)
+}
+
+func TestParseRequirementsFileErrors(t *testing.T) {
+	for src, expected := range map[string]string{
+		"-r":                        "line 1: option -r needs a value",
+		"--pre=yes":                 "line 1: option --pre does not take a value",
+		"--hash=sha256:abc":         "line 1: option --hash must follow a requirement",
+		"--pre --no-index":          "line 1: option --pre must be on a line of its own",
+		"\n-e ./foo#egg=-foo":       `line 2: invalid egg name in ./foo#egg=-foo: "-foo" is not a valid Python package name: it cannot start with '-'`,
+		"foo>=1.0 \\\n\\\n  bar":    "line 1: invalid version specifier in Python requirement foo>=1.0 bar: \">=1.0 bar\" is not an operator followed by a version",
+		"foo==1.0 --hash":           "line 1: option --hash needs a value",
+		"foo[bar\nbaz==1.0 # ok \\": "line 1: unclosed extras in Python requirement foo[bar",
+	} {
+		f, err := ParseRequirementsFile(strings.NewReader(src))
+		require.NoError(t, err, src)
+		if assert.Len(t, f.Errors, 1, src) {
+			assert.Equal(t, expected, f.Errors[0].Error(), src)
+		}
+	}
+}
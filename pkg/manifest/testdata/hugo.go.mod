@@ -0,0 +1,36 @@
+module github.com/gohugoio/hugo
+
+go 1.22.0
+
+toolchain go1.22.5
+
+godebug (
+	default=go1.21
+	panicnil=1
+)
+
+require (
+	github.com/BurntSushi/locker v0.0.0-20171006230638-a6e239ea1c69
+	github.com/PuerkitoBio/goquery v1.9.2 // indirect
+	github.com/alecthomas/chroma/v2 v2.14.0
+	github.com/bep/godartsass/v2 v2.0.0
+	github.com/evanw/esbuild v0.21.4
+	github.com/fsnotify/fsnotify v1.7.0
+	github.com/gohugoio/go-i18n/v2 v2.1.3-0.20230805085216-e63c13218d0e
+	github.com/spf13/cobra v1.8.1
+	golang.org/x/net v0.26.0
+	golang.org/x/text v0.16.0
+	gopkg.in/yaml.v2 v2.4.0 // indirect; used by tests
+)
+
+require github.com/armon/go-radix v1.0.1-0.20221118154546-54df44f2176c // indirect
+
+// go-toml v2.0.0 broke the decoding of dates.
+exclude github.com/pelletier/go-toml/v2 v2.0.0
+
+retract (
+	v0.123.0 // Published accidentally.
+	[v0.110.0, v0.110.2] // Broken module path.
+)
+
+replace github.com/evanw/esbuild => github.com/evanw/esbuild v0.21.3
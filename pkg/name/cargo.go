@@ -0,0 +1,43 @@
+package name
+
+import (
+	"strings"
+)
+
+// cargoMaxLength is the greatest length that crates.io allows for a crate
+// name.
+const cargoMaxLength = 64
+
+// ValidateCargo returns a *NameError if name is not a valid Cargo crate name.
+// Valid names start with an ASCII letter, followed by any number of ASCII
+// letters, digits, hyphens and underscores, and are at most 64 bytes long, as
+// crates.io requires.
+func ValidateCargo(name string) error {
+	if name == "" {
+		return newNameError("cargo", name, ErrEmptyName, -1)
+	}
+	for i, r := range name {
+		switch {
+		case ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z'):
+		case isASCIIAlnum(r) || r == '-' || r == '_':
+			if i == 0 {
+				return newNameError("cargo", name, ErrBadStart, i)
+			}
+		default:
+			return newNameError("cargo", name, ErrInvalidRune, i)
+		}
+	}
+	if len(name) > cargoMaxLength {
+		return newNameError("cargo", name, ErrTooLong, cargoMaxLength)
+	}
+	return nil
+}
+
+// NormalizeCargo takes a Cargo crate name and returns it in normalized form.
+// crates.io does not allow two crates whose names differ only in case, or in
+// hyphens and underscores, so the normalized form is lower case with hyphens
+// replaced by underscores, as crates.io uses to compare names. This is also
+// how the crate is named in Rust code, so "Serde-JSON" becomes "serde_json".
+func NormalizeCargo(name string) string {
+	return strings.ToLower(strings.Replace(name, "-", "_", -1))
+}
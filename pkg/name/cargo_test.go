@@ -0,0 +1,48 @@
+package name
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCargo(t *testing.T) {
+	valid := []string{"serde", "serde_json", "proc-macro2", "Inflector", "x", "tokio-1", strings.Repeat("a", 64)}
+	for _, n := range valid {
+		assert.NoError(t, ValidateCargo(n), "%q is valid", n)
+	}
+
+	tests := map[string]struct {
+		reason Kind
+		pos    int
+	}{
+		"":                      {ErrEmptyName, -1},
+		"2d":                    {ErrBadStart, 0},
+		"_serde":                {ErrBadStart, 0},
+		"-serde":                {ErrBadStart, 0},
+		"serde.json":            {ErrInvalidRune, 5},
+		"serde json":            {ErrInvalidRune, 5},
+		"sérde":                 {ErrInvalidRune, 1},
+		strings.Repeat("a", 65): {ErrTooLong, 64},
+	}
+	for n, tt := range tests {
+		err := ValidateCargo(n)
+		require.IsType(t, &NameError{}, err, "%q", n)
+		assert.Equal(t, &NameError{Ecosystem: "cargo", Input: n, Reason: tt.reason, Pos: tt.pos}, err)
+	}
+}
+
+func TestNormalizeCargo(t *testing.T) {
+	tests := map[string]string{
+		"serde":       "serde",
+		"Serde-JSON":  "serde_json",
+		"proc-macro2": "proc_macro2",
+		"Inflector":   "inflector",
+		"a--b__c":     "a__b__c",
+	}
+	for from, to := range tests {
+		assert.Equal(t, to, NormalizeCargo(from), from)
+	}
+}
@@ -0,0 +1,67 @@
+package name
+
+import (
+	"strings"
+)
+
+// ValidateComposer returns a *NameError if name is not a valid Composer
+// package name. Valid names are a vendor and a package name separated by a
+// slash, like "symfony/console". Each part is made of ASCII letters and
+// digits, separated by single periods, underscores or hyphens, although the
+// package name can also contain "--". This is the pattern in Composer's
+// schema, except that upper case letters are allowed, since Composer compares
+// names case-insensitively. See
+// https://getcomposer.org/doc/04-schema.md#name for details.
+//
+// Platform packages like "php" and "ext-json" have no vendor, so they are not
+// valid.
+func ValidateComposer(name string) error {
+	if name == "" {
+		return newNameError("composer", name, ErrEmptyName, -1)
+	}
+	slash := strings.IndexByte(name, '/')
+	if slash < 0 {
+		return newNameError("composer", name, ErrNoVendor, -1)
+	}
+	if err := validateComposerPart(name, 0, slash, false); err != nil {
+		return err
+	}
+	return validateComposerPart(name, slash+1, len(name), true)
+}
+
+// validateComposerPart validates the vendor or package name in
+// name[start:end]. If isPackage is true, two hyphens in a row are allowed.
+func validateComposerPart(name string, start, end int, isPackage bool) error {
+	if start == end {
+		return newNameError("composer", name, ErrBadStart, start)
+	}
+	for i := start; i < end; i++ {
+		r := rune(name[i])
+		switch {
+		case isASCIIAlnum(r):
+		case r == '.' || r == '_' || r == '-':
+			if i == start {
+				return newNameError("composer", name, ErrBadStart, i)
+			}
+			if i == end-1 {
+				return newNameError("composer", name, ErrBadEnd, i)
+			}
+			prev := name[i-1]
+			if prev == '.' || prev == '_' || prev == '-' {
+				if !isPackage || r != '-' || prev != '-' || i >= start+2 && name[i-2] == '-' {
+					return newNameError("composer", name, ErrInvalidRune, i)
+				}
+			}
+		default:
+			return newNameError("composer", name, ErrInvalidRune, i)
+		}
+	}
+	return nil
+}
+
+// NormalizeComposer takes a Composer package name and returns it in
+// normalized form, which is lower case with leading and trailing space
+// removed.
+func NormalizeComposer(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
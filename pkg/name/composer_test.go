@@ -0,0 +1,54 @@
+package name
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateComposer(t *testing.T) {
+	valid := []string{
+		"symfony/console", "laravel/framework", "phpunit/php-code-coverage",
+		"doctrine/dbal", "friendsofphp/php-cs-fixer", "a/b", "Monolog/Monolog",
+		"zendframework/zend-db", "vendor/pkg--name", "league/oauth2.client", "my_vendor/my_package",
+	}
+	for _, n := range valid {
+		assert.NoError(t, ValidateComposer(n), "%q is valid", n)
+	}
+
+	tests := map[string]struct {
+		reason Kind
+		pos    int
+	}{
+		"":                   {ErrEmptyName, -1},
+		"console":            {ErrNoVendor, -1},
+		"ext-json":           {ErrNoVendor, -1},
+		"/console":           {ErrBadStart, 0},
+		"symfony/":           {ErrBadStart, 8},
+		"-symfony/console":   {ErrBadStart, 0},
+		"symfony/console.":   {ErrBadEnd, 15},
+		"symfony_/console":   {ErrBadEnd, 7},
+		"sym--fony/console":  {ErrInvalidRune, 4},
+		"symfony/con---sole": {ErrInvalidRune, 13},
+		"symfony/con-_sole":  {ErrInvalidRune, 12},
+		"symfony/con/sole":   {ErrInvalidRune, 11},
+		"symfony/cönsole":    {ErrInvalidRune, 9},
+		"symfony/con sole":   {ErrInvalidRune, 11},
+	}
+	for n, tt := range tests {
+		err := ValidateComposer(n)
+		require.IsType(t, &NameError{}, err, "%q", n)
+		assert.Equal(t, &NameError{Ecosystem: "composer", Input: n, Reason: tt.reason, Pos: tt.pos}, err)
+	}
+}
+
+func TestNormalizeComposer(t *testing.T) {
+	for from, to := range map[string]string{
+		"symfony/console":    "symfony/console",
+		"Monolog/Monolog":    "monolog/monolog",
+		" Laravel/Framework": "laravel/framework",
+	} {
+		assert.Equal(t, to, NormalizeComposer(from), from)
+	}
+}
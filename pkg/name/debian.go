@@ -0,0 +1,30 @@
+package name
+
+// debianMinLength is the least number of characters that Debian policy allows
+// in a package name.
+const debianMinLength = 2
+
+// ValidateDebian returns a *NameError if name is not a valid Debian package
+// name. Debian policy says that valid names have at least two characters,
+// which are lower case ASCII letters, digits, "+", "-" and ".", and start
+// with a letter or digit.
+func ValidateDebian(name string) error {
+	if name == "" {
+		return newNameError("debian", name, ErrEmptyName, -1)
+	}
+	for i, r := range name {
+		switch {
+		case 'a' <= r && r <= 'z' || '0' <= r && r <= '9':
+		case r == '+' || r == '-' || r == '.':
+			if i == 0 {
+				return newNameError("debian", name, ErrBadStart, i)
+			}
+		default:
+			return newNameError("debian", name, ErrInvalidRune, i)
+		}
+	}
+	if len(name) < debianMinLength {
+		return newNameError("debian", name, ErrTooShort, debianMinLength)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package name
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDebian(t *testing.T) {
+	valid := []string{"libc6", "libstdc++6", "python3.11", "g++", "0ad", "lib32z1-dev", "xz-utils"}
+	for _, n := range valid {
+		assert.NoError(t, ValidateDebian(n), "%q is valid", n)
+	}
+
+	tests := map[string]struct {
+		reason Kind
+		pos    int
+	}{
+		"":          {ErrEmptyName, -1},
+		"a":         {ErrTooShort, 2},
+		"-libc6":    {ErrBadStart, 0},
+		".hidden":   {ErrBadStart, 0},
+		"+x":        {ErrBadStart, 0},
+		"LibC6":     {ErrInvalidRune, 0},
+		"libc_6":    {ErrInvalidRune, 4},
+		"python3:":  {ErrInvalidRune, 7},
+		"libc6 dev": {ErrInvalidRune, 5},
+	}
+	for n, tt := range tests {
+		err := ValidateDebian(n)
+		require.IsType(t, &NameError{}, err, "%q", n)
+		assert.Equal(t, &NameError{Ecosystem: "debian", Input: n, Reason: tt.reason, Pos: tt.pos}, err)
+	}
+}
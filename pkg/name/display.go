@@ -0,0 +1,115 @@
+package name
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// DisplayRegistry remembers an original name to display for each normalized
+// name, since normalization loses the case and punctuation a package was
+// registered with. For example, after recording "backports.SSL" as a Python
+// name, looking up "backports-ssl" returns "backports.SSL". It is safe for
+// concurrent use.
+type DisplayRegistry struct {
+	mu sync.RWMutex
+	// names maps each ecosystem to a map from normalized names to the names
+	// to display.
+	names map[string]map[string]string
+}
+
+// NewDisplayRegistry returns an empty DisplayRegistry.
+func NewDisplayRegistry() *DisplayRegistry {
+	return &DisplayRegistry{names: map[string]map[string]string{}}
+}
+
+// Record normalizes original with Normalize and returns the normalized name.
+// If no name to display has been recorded for the normalized name yet,
+// original becomes the name to display. Otherwise the first one recorded is
+// kept. It returns an error if Normalize does.
+func (r *DisplayRegistry) Record(eco, original string) (string, error) {
+	return r.record(eco, original, false)
+}
+
+// SetCanonical works like Record, but original always becomes the name to
+// display, replacing any that was recorded before. Use it when the name a
+// package was registered with is known.
+func (r *DisplayRegistry) SetCanonical(eco, original string) (string, error) {
+	return r.record(eco, original, true)
+}
+
+func (r *DisplayRegistry) record(eco, original string, replace bool) (string, error) {
+	normalized, err := Normalize(eco, original)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names, ok := r.names[eco]
+	if !ok {
+		names = map[string]string{}
+		r.names[eco] = names
+	}
+	if _, ok := names[normalized]; !ok || replace {
+		names[normalized] = original
+	}
+	return normalized, nil
+}
+
+// Lookup returns the name to display for a normalized name in the given
+// ecosystem, and false if none has been recorded.
+func (r *DisplayRegistry) Lookup(eco, normalized string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	display, ok := r.names[eco][normalized]
+	return display, ok
+}
+
+// Export returns the contents of the registry as JSON, for loading later with
+// Import. The JSON is an object mapping each ecosystem to an object that maps
+// normalized names to the names to display.
+func (r *DisplayRegistry) Export() ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return json.Marshal(r.names)
+}
+
+// Import adds the names in data, which is JSON as returned by Export, to the
+// registry. Imported names replace any already recorded for the same
+// normalized name. It returns an error, and changes nothing, if data is not
+// valid, or if a name to display does not normalize to the name it is
+// recorded for.
+func (r *DisplayRegistry) Import(data []byte) error {
+	var imported map[string]map[string]string
+	if err := json.Unmarshal(data, &imported); err != nil {
+		return fmt.Errorf("error decoding display names: %s", err)
+	}
+	for eco, names := range imported {
+		for normalized, display := range names {
+			n, err := Normalize(eco, display)
+			if err != nil {
+				return err
+			}
+			if n != normalized {
+				return fmt.Errorf("the %s display name %q normalizes to %q, not %q", eco, display, n, normalized)
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for eco, names := range imported {
+		if r.names[eco] == nil {
+			r.names[eco] = map[string]string{}
+		}
+		for normalized, display := range names {
+			r.names[eco][normalized] = display
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package name
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplayRegistry(t *testing.T) {
+	r := NewDisplayRegistry()
+
+	n, err := r.Record("python", "backports.SSL")
+	require.NoError(t, err)
+	assert.Equal(t, "backports-ssl", n)
+
+	n, err = r.Record("python", "Backports_ssl")
+	require.NoError(t, err)
+	assert.Equal(t, "backports-ssl", n)
+
+	display, ok := r.Lookup("python", "backports-ssl")
+	assert.True(t, ok)
+	assert.Equal(t, "backports.SSL", display, "the first original recorded is kept")
+
+	_, err = r.SetCanonical("python", "backports.ssl")
+	require.NoError(t, err)
+	display, _ = r.Lookup("python", "backports-ssl")
+	assert.Equal(t, "backports.ssl", display, "a canonical name replaces the first")
+
+	_, err = r.Record("python", "BACKPORTS.SSL")
+	require.NoError(t, err)
+	display, _ = r.Lookup("python", "backports-ssl")
+	assert.Equal(t, "backports.ssl", display)
+
+	_, ok = r.Lookup("python", "flask")
+	assert.False(t, ok)
+	_, ok = r.Lookup("julia", "backports-ssl")
+	assert.False(t, ok, "names are recorded per ecosystem")
+
+	_, err = r.Record("cobol", "Flask")
+	assert.Error(t, err)
+	_, err = r.Record("hex", "Phoenix")
+	assert.Error(t, err, "names that the ecosystem's normalizer rejects are not recorded")
+}
+
+func TestDisplayRegistryExportImport(t *testing.T) {
+	r := NewDisplayRegistry()
+	for _, n := range []string{"backports.SSL", "Flask", "zope.interface"} {
+		_, err := r.Record("python", n)
+		require.NoError(t, err)
+	}
+	_, err := r.Record("julia", "DataFrames")
+	require.NoError(t, err)
+
+	data, err := r.Export()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"julia": {"DataFrames": "DataFrames"},
+		"python": {"backports-ssl": "backports.SSL", "flask": "Flask", "zope-interface": "zope.interface"}
+	}`, string(data))
+
+	imported := NewDisplayRegistry()
+	_, err = imported.Record("python", "FLASK")
+	require.NoError(t, err)
+	require.NoError(t, imported.Import(data))
+
+	again, err := imported.Export()
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(again))
+
+	display, ok := imported.Lookup("python", "flask")
+	assert.True(t, ok)
+	assert.Equal(t, "Flask", display, "imported names replace recorded ones")
+}
+
+func TestDisplayRegistryImportErrors(t *testing.T) {
+	r := NewDisplayRegistry()
+	for _, data := range []string{
+		`[]`,
+		`{"python": {"flask": 1}}`,
+		`{"cobol": {"flask": "Flask"}}`,
+		`{"python": {"flask": "Django"}}`,
+		`{"python": {"flask": "Flask", "django": "Flask"}}`,
+	} {
+		assert.Error(t, r.Import([]byte(data)), data)
+	}
+
+	data, err := r.Export()
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(data), "nothing is imported if there is an error")
+}
+
+func TestDisplayRegistryConcurrentUse(t *testing.T) {
+	r := NewDisplayRegistry()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, err := r.Record("python", fmt.Sprintf("Package.%d", j))
+				assert.NoError(t, err)
+				r.Lookup("python", fmt.Sprintf("package-%d", j))
+				if j%10 == 0 {
+					_, err = r.Export()
+					assert.NoError(t, err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	display, ok := r.Lookup("python", "package-42")
+	assert.True(t, ok)
+	assert.Equal(t, "Package.42", display)
+}
@@ -0,0 +1,107 @@
+package name
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Kind is the reason that a NameError was returned.
+type Kind int
+
+// The kinds of NameError.
+const (
+	// ErrEmptyName means that the name is empty.
+	ErrEmptyName Kind = iota + 1
+	// ErrInvalidRune means that the name contains a character that is never
+	// allowed in the ecosystem's names.
+	ErrInvalidRune
+	// ErrBadStart means that the name starts with a character that is only
+	// allowed later in the name.
+	ErrBadStart
+	// ErrBadEnd means that the name ends with a character that is only
+	// allowed earlier in the name.
+	ErrBadEnd
+	// ErrTooLong means that the name is longer than the ecosystem allows.
+	ErrTooLong
+	// ErrUnknownEcosystem means that the ecosystem is not one of those
+	// returned by Ecosystems.
+	ErrUnknownEcosystem
+	// ErrReservedWord means that the name is a word the ecosystem reserves,
+	// such as a language keyword.
+	ErrReservedWord
+	// ErrNoVendor means that the name has no vendor prefix, which the
+	// ecosystem requires.
+	ErrNoVendor
+	// ErrTooShort means that the name is shorter than the ecosystem allows.
+	ErrTooShort
+)
+
+var kindNames = map[Kind]string{
+	ErrEmptyName:        "empty name",
+	ErrInvalidRune:      "invalid character",
+	ErrBadStart:         "bad start",
+	ErrBadEnd:           "bad end",
+	ErrTooLong:          "too long",
+	ErrUnknownEcosystem: "unknown ecosystem",
+	ErrReservedWord:     "reserved word",
+	ErrNoVendor:         "no vendor",
+	ErrTooShort:         "too short",
+}
+
+func (k Kind) String() string {
+	if n, ok := kindNames[k]; ok {
+		return n
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// NameError is returned by the funcs that validate package names, and by
+// Normalize and Validate for an unknown ecosystem. Pos is the byte offset in
+// Input of the character that caused the error. For ErrTooLong it is the
+// greatest length allowed, for ErrTooShort it is the least length allowed,
+// and for ErrEmptyName, ErrUnknownEcosystem, ErrReservedWord and ErrNoVendor
+// it is -1.
+type NameError struct {
+	Ecosystem string
+	Input     string
+	Reason    Kind
+	Pos       int
+}
+
+func (e *NameError) Error() string {
+	eco := e.Ecosystem
+	if ecosystem, ok := ecosystems[eco]; ok {
+		eco = ecosystem.title
+	}
+
+	switch e.Reason {
+	case ErrUnknownEcosystem:
+		return fmt.Sprintf("unknown ecosystem %q", e.Ecosystem)
+	case ErrEmptyName:
+		return fmt.Sprintf("a %s package name cannot be empty", eco)
+	case ErrTooLong:
+		return fmt.Sprintf("%q is not a valid %s package name: it is longer than %d bytes", e.Input, eco, e.Pos)
+	case ErrTooShort:
+		return fmt.Sprintf("%q is not a valid %s package name: it is shorter than %d bytes", e.Input, eco, e.Pos)
+	case ErrReservedWord:
+		return fmt.Sprintf("%q is not a valid %s package name: it is a reserved word", e.Input, eco)
+	case ErrNoVendor:
+		return fmt.Sprintf("%q is not a valid %s package name: it has no vendor", e.Input, eco)
+	}
+
+	r, _ := utf8.DecodeRuneInString(e.Input[e.Pos:])
+	var problem string
+	switch e.Reason {
+	case ErrBadStart:
+		problem = fmt.Sprintf("it cannot start with %q", r)
+	case ErrBadEnd:
+		problem = fmt.Sprintf("it cannot end with %q", r)
+	default:
+		problem = fmt.Sprintf("%q at position %d is not allowed", r, e.Pos)
+	}
+	return fmt.Sprintf("%q is not a valid %s package name: %s", e.Input, eco, problem)
+}
+
+func newNameError(eco, input string, reason Kind, pos int) *NameError {
+	return &NameError{Ecosystem: eco, Input: input, Reason: reason, Pos: pos}
+}
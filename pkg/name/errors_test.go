@@ -0,0 +1,26 @@
+package name
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameErrorMessages(t *testing.T) {
+	tests := map[string]*NameError{
+		`a Python package name cannot be empty`:                                        {"python", "", ErrEmptyName, -1},
+		`"-flask" is not a valid Python package name: it cannot start with '-'`:        {"python", "-flask", ErrBadStart, 0},
+		`"flask_" is not a valid Python package name: it cannot end with '_'`:          {"python", "flask_", ErrBadEnd, 5},
+		`"flåsk" is not a valid Python package name: 'å' at position 2 is not allowed`: {"python", "flåsk", ErrInvalidRune, 2},
+		`"flask" is not a valid cobol package name: it is longer than 3 bytes`:         {"cobol", "flask", ErrTooLong, 3},
+		`"x" is not a valid cobol package name: it is shorter than 2 bytes`:            {"cobol", "x", ErrTooShort, 2},
+		`unknown ecosystem "cobol"`:                                                    {"cobol", "flask", ErrUnknownEcosystem, -1},
+		`"console" is not a valid Composer package name: it has no vendor`:             {"composer", "console", ErrNoVendor, -1},
+	}
+	for expected, err := range tests {
+		assert.Equal(t, expected, err.Error())
+	}
+
+	assert.Equal(t, "invalid character", ErrInvalidRune.String())
+	assert.Equal(t, "Kind(99)", Kind(99).String())
+}
@@ -0,0 +1,76 @@
+package name
+
+import (
+	"strings"
+)
+
+// ValidateGoModule returns a *NameError if path is not a valid Go module
+// path. Valid paths are made of elements separated by single slashes. Each
+// element is made of ASCII letters, digits and "-._~", and does not start or
+// end with a period. These are the rules that golang.org/x/mod/module uses
+// for import paths. Module paths that can be fetched also need a domain name
+// as their first element, but paths like "example/hello" are allowed in
+// go.mod files, so they are valid here.
+func ValidateGoModule(path string) error {
+	if path == "" {
+		return newNameError("go", path, ErrEmptyName, -1)
+	}
+	start := 0
+	for i := 0; i <= len(path); i++ {
+		if i < len(path) && path[i] != '/' {
+			r := rune(path[i])
+			if !isASCIIAlnum(r) && !strings.ContainsRune("-._~", r) {
+				return newNameError("go", path, ErrInvalidRune, i)
+			}
+			continue
+		}
+
+		// i is the end of an element.
+		switch {
+		case i == start && i == len(path):
+			return newNameError("go", path, ErrBadEnd, i-1)
+		case i == start:
+			return newNameError("go", path, ErrBadStart, i)
+		case path[start] == '.':
+			return newNameError("go", path, ErrBadStart, start)
+		case path[i-1] == '.':
+			return newNameError("go", path, ErrBadEnd, i-1)
+		}
+		start = i + 1
+	}
+	return nil
+}
+
+// NormalizeGoModule returns path if it is a valid Go module path, and a
+// *NameError otherwise. Go module paths are case sensitive, so nothing is
+// changed. Use EscapeGoModule to get the form that module proxies and the
+// module cache use.
+func NormalizeGoModule(path string) (string, error) {
+	if err := ValidateGoModule(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// EscapeGoModule returns path with each upper case letter replaced by an
+// exclamation mark followed by the lower case letter, as module proxies and
+// the module cache do, so "github.com/Azure/azure-sdk-for-go" becomes
+// "github.com/!azure/azure-sdk-for-go". This keeps paths that differ only by
+// case apart on case-insensitive file systems. It returns a *NameError if
+// path is not a valid Go module path.
+func EscapeGoModule(path string) (string, error) {
+	if err := ValidateGoModule(path); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if 'A' <= c && c <= 'Z' {
+			b.WriteByte('!')
+			c += 'a' - 'A'
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,64 @@
+package name
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateGoModule(t *testing.T) {
+	valid := []string{
+		"golang.org/x/crypto", "github.com/Hashicorp/terraform", "gopkg.in/yaml.v3",
+		"github.com/go-kit/kit/v2", "example/hello", "m", "example.com/~user/mod_1",
+	}
+	for _, p := range valid {
+		assert.NoError(t, ValidateGoModule(p), "%q is valid", p)
+	}
+
+	tests := map[string]struct {
+		reason Kind
+		pos    int
+	}{
+		"":                      {ErrEmptyName, -1},
+		"/golang.org/x/crypto":  {ErrBadStart, 0},
+		"golang.org/x/crypto/":  {ErrBadEnd, 19},
+		"golang.org//crypto":    {ErrBadStart, 11},
+		".golang.org/x/crypto":  {ErrBadStart, 0},
+		"golang.org/x./crypto":  {ErrBadEnd, 12},
+		"golang.org/x/.crypto":  {ErrBadStart, 13},
+		"golang.org/x/cry pto":  {ErrInvalidRune, 16},
+		"golang.org/x/cr!ypto":  {ErrInvalidRune, 15},
+		"golang.org/x/crypto@1": {ErrInvalidRune, 19},
+		"golang.org/x/crÿpto":   {ErrInvalidRune, 15},
+	}
+	for p, tt := range tests {
+		err := ValidateGoModule(p)
+		require.IsType(t, &NameError{}, err, "%q", p)
+		assert.Equal(t, &NameError{Ecosystem: "go", Input: p, Reason: tt.reason, Pos: tt.pos}, err)
+	}
+}
+
+func TestNormalizeGoModule(t *testing.T) {
+	p, err := NormalizeGoModule("github.com/Hashicorp/terraform")
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/Hashicorp/terraform", p, "module paths are case sensitive")
+
+	_, err = NormalizeGoModule("github.com//terraform")
+	assert.Error(t, err)
+}
+
+func TestEscapeGoModule(t *testing.T) {
+	for from, to := range map[string]string{
+		"golang.org/x/crypto":               "golang.org/x/crypto",
+		"github.com/Azure/azure-sdk-for-go": "github.com/!azure/azure-sdk-for-go",
+		"github.com/BurntSushi/toml":        "github.com/!burnt!sushi/toml",
+	} {
+		escaped, err := EscapeGoModule(from)
+		require.NoError(t, err, from)
+		assert.Equal(t, to, escaped, from)
+	}
+
+	_, err := EscapeGoModule("github.com/!azure/azure-sdk-for-go")
+	assert.Error(t, err, "escaped paths are not valid module paths")
+}
@@ -0,0 +1,44 @@
+package name
+
+import (
+	"strings"
+)
+
+// ValidateHex returns a *NameError if name is not a valid Hex package name.
+// Valid names start with a lower case ASCII letter, followed by any number of
+// ASCII letters, digits and underscores, matching the regex ^[a-z]\w*$ that
+// hex.pm uses.
+func ValidateHex(name string) error {
+	if name == "" {
+		return newNameError("hex", name, ErrEmptyName, -1)
+	}
+	for i, r := range name {
+		switch {
+		case 'a' <= r && r <= 'z':
+		case isASCIIAlnum(r) || r == '_':
+			if i == 0 {
+				return newNameError("hex", name, ErrBadStart, i)
+			}
+		default:
+			return newNameError("hex", name, ErrInvalidRune, i)
+		}
+	}
+	return nil
+}
+
+// NormalizeHex returns name if it is a valid Hex package name, and a
+// *NameError otherwise. Hex compares names exactly, and hyphens and
+// underscores are not interchangeable, so by default nothing is changed.
+//
+// With WithLenient, name is lower cased and its hyphens are replaced with
+// underscores before it is validated, so "My-App" becomes "my_app". This is
+// useful for names taken from GitHub repositories, but it is a guess.
+func NormalizeHex(name string, opts ...Option) (string, error) {
+	if applyOptions(opts).lenient {
+		name = strings.ToLower(strings.Replace(name, "-", "_", -1))
+	}
+	if err := ValidateHex(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
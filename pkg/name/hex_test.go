@@ -0,0 +1,60 @@
+package name
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateHex(t *testing.T) {
+	valid := []string{"phoenix", "ecto_sql", "a", "plug_cowboy2", "phoenix_HTML", "x__"}
+	for _, n := range valid {
+		assert.NoError(t, ValidateHex(n), "%q is valid", n)
+	}
+
+	tests := map[string]struct {
+		reason Kind
+		pos    int
+	}{
+		"":         {ErrEmptyName, -1},
+		"Phoenix":  {ErrBadStart, 0},
+		"_phoenix": {ErrBadStart, 0},
+		"2fa":      {ErrBadStart, 0},
+		"my-app":   {ErrInvalidRune, 2},
+		"-app":     {ErrInvalidRune, 0},
+		"my.app":   {ErrInvalidRune, 2},
+		"café":     {ErrInvalidRune, 3},
+	}
+	for n, tt := range tests {
+		err := ValidateHex(n)
+		require.IsType(t, &NameError{}, err, "%q", n)
+		assert.Equal(t, &NameError{Ecosystem: "hex", Input: n, Reason: tt.reason, Pos: tt.pos}, err)
+	}
+}
+
+func TestNormalizeHex(t *testing.T) {
+	n, err := NormalizeHex("ecto_sql")
+	require.NoError(t, err)
+	assert.Equal(t, "ecto_sql", n)
+
+	for _, invalid := range []string{"Phoenix", "my-app", "My-App"} {
+		_, err = NormalizeHex(invalid)
+		assert.Error(t, err, "%q is rejected by default", invalid)
+	}
+
+	lenient := map[string]string{
+		"Phoenix":  "phoenix",
+		"my-app":   "my_app",
+		"My-App":   "my_app",
+		"ecto_sql": "ecto_sql",
+	}
+	for from, to := range lenient {
+		n, err = NormalizeHex(from, WithLenient())
+		require.NoError(t, err, from)
+		assert.Equal(t, to, n, from)
+	}
+
+	_, err = NormalizeHex("1-app", WithLenient())
+	assert.Equal(t, &NameError{Ecosystem: "hex", Input: "1_app", Reason: ErrBadStart, Pos: 0}, err, "names that cannot be fixed are still rejected")
+}
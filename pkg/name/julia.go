@@ -0,0 +1,91 @@
+package name
+
+import (
+	"strings"
+)
+
+// ValidateJulia returns a *NameError if name is not a valid Julia package
+// name. Valid names start with an upper case ASCII letter, followed by any
+// number of ASCII letters, digits and underscores. The General registry has
+// further rules for new packages, such as a minimum length, that are
+// guidelines rather than structural rules and are not checked here.
+func ValidateJulia(name string) error {
+	if name == "" {
+		return newNameError("julia", name, ErrEmptyName, -1)
+	}
+	for i, r := range name {
+		switch {
+		case 'A' <= r && r <= 'Z':
+		case isASCIIAlnum(r) || r == '_':
+			if i == 0 {
+				return newNameError("julia", name, ErrBadStart, i)
+			}
+		default:
+			return newNameError("julia", name, ErrInvalidRune, i)
+		}
+	}
+	return nil
+}
+
+// NormalizeJulia takes a Julia package name and returns it in normalized
+// form. Julia names are case sensitive, so this only removes leading and
+// trailing space.
+func NormalizeJulia(name string) string {
+	return strings.TrimSpace(name)
+}
+
+// JuliaCollisionKey returns a key for finding Julia package names that differ
+// only by case, which the General registry does not allow. It is the
+// normalized name in lower case.
+func JuliaCollisionKey(name string) string {
+	return strings.ToLower(NormalizeJulia(name))
+}
+
+// TooSimilar returns true if the General registry would reject one of the
+// Julia package names a and b because of the other, which is the case if
+// their collision keys are equal or are one edit apart. An edit is inserting,
+// deleting or substituting a character, or swapping two adjacent characters.
+func TooSimilar(a, b string) bool {
+	return editDistance(JuliaCollisionKey(a), JuliaCollisionKey(b)) <= 1
+}
+
+// editDistance returns the optimal string alignment distance between a and b,
+// which is the Levenshtein distance with the swapping of two adjacent
+// characters counted as one edit.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// d[i][j] is the distance between the first i runes of a and the first j
+	// runes of b.
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func minInt(first int, rest ...int) int {
+	m := first
+	for _, n := range rest {
+		if n < m {
+			m = n
+		}
+	}
+	return m
+}
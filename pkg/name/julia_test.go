@@ -0,0 +1,87 @@
+package name
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateJulia(t *testing.T) {
+	valid := []string{"DataFrames", "JSON", "CUDA", "Plots", "HTTP2", "Flux_Extras", "A"}
+	for _, n := range valid {
+		assert.NoError(t, ValidateJulia(n), "%q is valid", n)
+	}
+
+	tests := map[string]struct {
+		reason Kind
+		pos    int
+	}{
+		"":              {ErrEmptyName, -1},
+		"dataFrames":    {ErrBadStart, 0},
+		"_Private":      {ErrBadStart, 0},
+		"3DPlots":       {ErrBadStart, 0},
+		"DataFrames.jl": {ErrInvalidRune, 10},
+		"Data-Frames":   {ErrInvalidRune, 4},
+		"Ünicode":       {ErrInvalidRune, 0},
+	}
+	for n, tt := range tests {
+		err := ValidateJulia(n)
+		require.IsType(t, &NameError{}, err, "%q", n)
+		assert.Equal(t, &NameError{Ecosystem: "julia", Input: n, Reason: tt.reason, Pos: tt.pos}, err)
+	}
+}
+
+func TestNormalizeJulia(t *testing.T) {
+	assert.Equal(t, "DataFrames", NormalizeJulia(" DataFrames\n"))
+	assert.Equal(t, "JSON", NormalizeJulia("JSON"))
+	assert.Equal(t, "dataframes", JuliaCollisionKey(" DataFrames"))
+	assert.Equal(t, JuliaCollisionKey("Json"), JuliaCollisionKey("JSON"))
+}
+
+// These follow the General registry's AutoMerge rules, which reject a new
+// name that differs from an existing one only by case or by one edit.
+func TestTooSimilar(t *testing.T) {
+	similar := [][2]string{
+		{"DataFrames", "DataFrames"},
+		{"Json", "JSON"},
+		{"DataFrames", "DataFrame"},
+		{"DataFrames", "DataFramesX"},
+		{"Plots", "Plats"},
+		{"Plots", "Polts"},
+		{"CSV", "CVS"},
+	}
+	for _, pair := range similar {
+		assert.True(t, TooSimilar(pair[0], pair[1]), "%s and %s", pair[0], pair[1])
+		assert.True(t, TooSimilar(pair[1], pair[0]), "%s and %s", pair[1], pair[0])
+	}
+
+	distinct := [][2]string{
+		{"DataFrames", "DataFrame2s2"},
+		{"Plots", "Plotly"},
+		{"CSV", "TSV2"},
+		{"Flux", "Zygote"},
+		{"Plots", "Pstol"},
+	}
+	for _, pair := range distinct {
+		assert.False(t, TooSimilar(pair[0], pair[1]), "%s and %s", pair[0], pair[1])
+		assert.False(t, TooSimilar(pair[1], pair[0]), "%s and %s", pair[1], pair[0])
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"ab", "ba", 1},
+		{"ca", "abc", 3},
+		{"flåw", "flaw", 1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, editDistance(tt.a, tt.b), "%s and %s", tt.a, tt.b)
+	}
+}
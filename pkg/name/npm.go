@@ -0,0 +1,81 @@
+package name
+
+import (
+	"strings"
+)
+
+// npmMaxLength is the longest name the npm registry allows.
+const npmMaxLength = 214
+
+// npmReservedNames are names that npm does not allow.
+var npmReservedNames = map[string]bool{
+	"node_modules": true,
+	"favicon.ico":  true,
+}
+
+// ValidateNpm returns a *NameError if name is not a valid npm package name.
+// Valid names are at most 214 bytes long, do not start with a period or
+// underscore, and contain only the characters that JavaScript's
+// encodeURIComponent leaves alone, which are ASCII letters and digits and
+// "-_.!~*'()". Scoped names like "@types/node" are also valid if both the
+// scope and the name are. See
+// https://github.com/npm/validate-npm-package-name for details.
+//
+// These are the rules for existing packages. New packages must also have
+// lower case names without "~'!()*", but older ones like "JSONStream" do not.
+func ValidateNpm(name string) error {
+	if name == "" {
+		return newNameError("npm", name, ErrEmptyName, -1)
+	}
+	if len(name) > npmMaxLength {
+		return newNameError("npm", name, ErrTooLong, npmMaxLength)
+	}
+	if name[0] == '.' || name[0] == '_' {
+		return newNameError("npm", name, ErrBadStart, 0)
+	}
+	if npmReservedNames[strings.ToLower(name)] {
+		return newNameError("npm", name, ErrReservedWord, -1)
+	}
+
+	start := 0
+	if name[0] == '@' {
+		slash := strings.IndexByte(name, '/')
+		if slash < 0 {
+			return newNameError("npm", name, ErrInvalidRune, 0)
+		}
+		if slash == 1 {
+			return newNameError("npm", name, ErrBadStart, 1)
+		}
+		if slash == len(name)-1 {
+			return newNameError("npm", name, ErrBadEnd, slash)
+		}
+		if err := validateNpmChars(name, 1, slash); err != nil {
+			return err
+		}
+		start = slash + 1
+		if name[start] == '.' || name[start] == '_' {
+			return newNameError("npm", name, ErrBadStart, start)
+		}
+	}
+	return validateNpmChars(name, start, len(name))
+}
+
+// validateNpmChars checks that name[start:end] only contains characters that
+// encodeURIComponent does not encode.
+func validateNpmChars(name string, start, end int) error {
+	for i := start; i < end; i++ {
+		r := rune(name[i])
+		if !isASCIIAlnum(r) && !strings.ContainsRune("-_.!~*'()", r) {
+			return newNameError("npm", name, ErrInvalidRune, i)
+		}
+	}
+	return nil
+}
+
+// NormalizeNpm takes an npm package name and returns it in normalized form,
+// which only has leading and trailing space removed. The npm registry
+// compares names exactly, and some older packages have upper case letters, so
+// names are not lower cased.
+func NormalizeNpm(name string) string {
+	return strings.TrimSpace(name)
+}
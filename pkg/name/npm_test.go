@@ -0,0 +1,58 @@
+package name
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNpm(t *testing.T) {
+	valid := []string{
+		"lodash", "react-dom", "@types/node", "@babel/core", "JSONStream",
+		"left-pad", "a", "lodash.merge", "@angular/animations", "socket.io",
+		"really-cool~package", "x" + strings.Repeat("y", 213),
+	}
+	for _, n := range valid {
+		assert.NoError(t, ValidateNpm(n), "%q is valid", n)
+	}
+
+	tests := map[string]struct {
+		reason Kind
+		pos    int
+	}{
+		"":                             {ErrEmptyName, -1},
+		".bin":                         {ErrBadStart, 0},
+		"_private":                     {ErrBadStart, 0},
+		"node_modules":                 {ErrReservedWord, -1},
+		"favicon.ico":                  {ErrReservedWord, -1},
+		" lodash":                      {ErrInvalidRune, 0},
+		"lodash ":                      {ErrInvalidRune, 6},
+		"lo/dash":                      {ErrInvalidRune, 2},
+		"lodash@4":                     {ErrInvalidRune, 6},
+		"@types":                       {ErrInvalidRune, 0},
+		"@/node":                       {ErrBadStart, 1},
+		"@types/":                      {ErrBadEnd, 6},
+		"@types/_node":                 {ErrBadStart, 7},
+		"@ty pes/node":                 {ErrInvalidRune, 3},
+		"@types/no/de":                 {ErrInvalidRune, 9},
+		"café":                         {ErrInvalidRune, 3},
+		"x" + strings.Repeat("y", 214): {ErrTooLong, 214},
+	}
+	for n, tt := range tests {
+		err := ValidateNpm(n)
+		require.IsType(t, &NameError{}, err, "%q", n)
+		assert.Equal(t, &NameError{Ecosystem: "npm", Input: n, Reason: tt.reason, Pos: tt.pos}, err)
+	}
+}
+
+func TestNormalizeNpm(t *testing.T) {
+	for from, to := range map[string]string{
+		"lodash":        "lodash",
+		" @types/node ": "@types/node",
+		"JSONStream":    "JSONStream",
+	} {
+		assert.Equal(t, to, NormalizeNpm(from), from)
+	}
+}
@@ -0,0 +1,26 @@
+package name
+
+// Option configures optional normalization behavior. Each normalization func
+// documents the options it honors; options that do not apply to a given func
+// are ignored by it.
+type Option func(*options)
+
+type options struct {
+	lenient bool
+}
+
+func applyOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLenient makes normalization funcs that would otherwise reject a name
+// that is not valid guess at the valid name that was meant instead.
+func WithLenient() Option {
+	return func(o *options) {
+		o.lenient = true
+	}
+}
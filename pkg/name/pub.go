@@ -0,0 +1,65 @@
+package name
+
+import (
+	"strings"
+)
+
+// pubReservedWords are the Dart keywords, which pub does not allow as package
+// names. Keywords that are not all lower case, like "Function", are left out
+// because they are not valid names anyway.
+var pubReservedWords = map[string]bool{
+	"abstract": true, "as": true, "assert": true, "async": true, "await": true,
+	"break": true, "case": true, "catch": true, "class": true, "const": true,
+	"continue": true, "covariant": true, "default": true, "deferred": true,
+	"do": true, "dynamic": true, "else": true, "enum": true, "export": true,
+	"extends": true, "extension": true, "external": true, "factory": true,
+	"false": true, "final": true, "finally": true, "for": true, "get": true,
+	"hide": true, "if": true, "implements": true, "import": true, "in": true,
+	"interface": true, "is": true, "late": true, "library": true, "mixin": true,
+	"new": true, "null": true, "of": true, "on": true, "operator": true,
+	"part": true, "required": true, "rethrow": true, "return": true, "set": true,
+	"show": true, "static": true, "super": true, "switch": true, "sync": true,
+	"this": true, "throw": true, "true": true, "try": true, "typedef": true,
+	"var": true, "void": true, "while": true, "with": true, "yield": true,
+}
+
+// ValidatePub returns a *NameError if name is not a valid Dart package name.
+// Valid names are Dart identifiers made of lower case ASCII letters, digits
+// and underscores, which do not start with a digit and are not reserved
+// words. See https://dart.dev/tools/pub/pubspec#name for details.
+func ValidatePub(name string) error {
+	if name == "" {
+		return newNameError("pub", name, ErrEmptyName, -1)
+	}
+	for i, r := range name {
+		switch {
+		case ('a' <= r && r <= 'z') || r == '_':
+		case '0' <= r && r <= '9':
+			if i == 0 {
+				return newNameError("pub", name, ErrBadStart, i)
+			}
+		default:
+			return newNameError("pub", name, ErrInvalidRune, i)
+		}
+	}
+	if pubReservedWords[name] {
+		return newNameError("pub", name, ErrReservedWord, -1)
+	}
+	return nil
+}
+
+// NormalizePub takes a Dart package name and returns it in normalized form,
+// which is lower case with leading and trailing space removed. Pub names are
+// otherwise compared exactly.
+func NormalizePub(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// PubCollisionKey returns a key for matching a Dart package name against
+// names from elsewhere, such as repository names, which often use hyphens
+// where the package name uses underscores. It is the normalized name with
+// hyphens replaced by underscores, so "My-Package" and "my_package" have the
+// same key. The key is not necessarily a valid package name.
+func PubCollisionKey(name string) string {
+	return strings.Replace(NormalizePub(name), "-", "_", -1)
+}
@@ -0,0 +1,55 @@
+package name
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePub(t *testing.T) {
+	valid := []string{"http", "flutter_bloc", "_private", "a1", "path_provider2", "fortune"}
+	for _, n := range valid {
+		assert.NoError(t, ValidatePub(n), "%q is valid", n)
+	}
+
+	tests := map[string]struct {
+		reason Kind
+		pos    int
+	}{
+		"":           {ErrEmptyName, -1},
+		"1password":  {ErrBadStart, 0},
+		"for":        {ErrReservedWord, -1},
+		"null":       {ErrReservedWord, -1},
+		"My-Package": {ErrInvalidRune, 0},
+		"my-package": {ErrInvalidRune, 2},
+		"my.package": {ErrInvalidRune, 2},
+		" http":      {ErrInvalidRune, 0},
+	}
+	for n, tt := range tests {
+		err := ValidatePub(n)
+		require.IsType(t, &NameError{}, err, "%q", n)
+		assert.Equal(t, &NameError{Ecosystem: "pub", Input: n, Reason: tt.reason, Pos: tt.pos}, err)
+	}
+
+	assert.Equal(t, `"for" is not a valid Dart package name: it is a reserved word`, ValidatePub("for").Error())
+}
+
+func TestNormalizePub(t *testing.T) {
+	cases := map[string]string{
+		"http":          "http",
+		"HTTP":          "http",
+		" flutter_bloc": "flutter_bloc",
+		"My-Package\n":  "my-package",
+	}
+	for from, norm := range cases {
+		assert.Equal(t, norm, NormalizePub(from), "normalization of %q", from)
+	}
+}
+
+func TestPubCollisionKey(t *testing.T) {
+	assert.Equal(t, PubCollisionKey("my_package"), PubCollisionKey("my-package"))
+	assert.Equal(t, PubCollisionKey("my_package"), PubCollisionKey("My-Package"))
+	assert.Equal(t, "my_package", PubCollisionKey("my-package"))
+	assert.NotEqual(t, PubCollisionKey("mypackage"), PubCollisionKey("my_package"))
+}
@@ -16,3 +16,32 @@ var replacement = regexp.MustCompile(`[\.\_-]+`)
 func NormalizePython(name string) string {
 	return strings.ToLower(replacement.ReplaceAllString(name, "-"))
 }
+
+// ValidatePython returns a *NameError if name is not a valid Python package
+// name. Valid names contain only ASCII letters, digits, periods, underscores
+// and hyphens, and start and end with a letter or digit. See
+// https://www.python.org/dev/peps/pep-0508/#names for details.
+func ValidatePython(name string) error {
+	if name == "" {
+		return newNameError("python", name, ErrEmptyName, -1)
+	}
+	for i, r := range name {
+		switch {
+		case isASCIIAlnum(r):
+		case r == '.' || r == '_' || r == '-':
+			if i == 0 {
+				return newNameError("python", name, ErrBadStart, i)
+			}
+			if i == len(name)-1 {
+				return newNameError("python", name, ErrBadEnd, i)
+			}
+		default:
+			return newNameError("python", name, ErrInvalidRune, i)
+		}
+	}
+	return nil
+}
+
+func isASCIIAlnum(r rune) bool {
+	return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')
+}
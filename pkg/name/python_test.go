@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNormalizePython(t *testing.T) {
@@ -25,3 +26,41 @@ func TestNormalizePython(t *testing.T) {
 		assert.Equal(t, norm, NormalizePython(from), `normalization of "%s" is "%s"`, from, norm)
 	}
 }
+
+func TestValidatePython(t *testing.T) {
+	valid := []string{"flask", "Flask", "backports.SSL", "zope.interface", "a", "9", "Django_REST-framework"}
+	for _, n := range valid {
+		assert.NoError(t, ValidatePython(n), "%q is valid", n)
+	}
+
+	invalid := []string{"", "-flask", "flask-", ".flask", "flask_", "flask!", "fl ask", "flåsk"}
+	for _, n := range invalid {
+		assert.Error(t, ValidatePython(n), "%q is invalid", n)
+	}
+}
+
+func TestValidatePythonErrors(t *testing.T) {
+	tests := map[string]struct {
+		reason Kind
+		pos    int
+	}{
+		"":         {ErrEmptyName, -1},
+		"-flask":   {ErrBadStart, 0},
+		".":        {ErrBadStart, 0},
+		"flask-":   {ErrBadEnd, 5},
+		"flask_":   {ErrBadEnd, 5},
+		"flask!":   {ErrInvalidRune, 5},
+		"fl ask":   {ErrInvalidRune, 2},
+		"flåsk":    {ErrInvalidRune, 2},
+		"zope.i/o": {ErrInvalidRune, 6},
+	}
+	for n, tt := range tests {
+		err := ValidatePython(n)
+		require.IsType(t, &NameError{}, err, "%q", n)
+		nerr := err.(*NameError)
+		assert.Equal(t, "python", nerr.Ecosystem, "%q", n)
+		assert.Equal(t, n, nerr.Input, "%q", n)
+		assert.Equal(t, tt.reason, nerr.Reason, "%q", n)
+		assert.Equal(t, tt.pos, nerr.Pos, "%q", n)
+	}
+}
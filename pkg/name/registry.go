@@ -0,0 +1,66 @@
+package name
+
+import (
+	"sort"
+)
+
+type ecosystem struct {
+	// title is the name of the ecosystem used in error messages.
+	title     string
+	normalize func(string) (string, error)
+	validate  func(string) error
+}
+
+// ecosystems maps the name of each ecosystem to the funcs that normalize and
+// validate its package names. Debian package names have only one form, so
+// they are returned as they are by Normalize.
+var ecosystems = map[string]ecosystem{
+	"cargo":    {"Cargo", func(n string) (string, error) { return NormalizeCargo(n), nil }, ValidateCargo},
+	"composer": {"Composer", func(n string) (string, error) { return NormalizeComposer(n), nil }, ValidateComposer},
+	"debian":   {"Debian", func(n string) (string, error) { return n, nil }, ValidateDebian},
+	"go":       {"Go", NormalizeGoModule, ValidateGoModule},
+	"hex":      {"Hex", func(n string) (string, error) { return NormalizeHex(n) }, ValidateHex},
+	"julia":    {"Julia", func(n string) (string, error) { return NormalizeJulia(n), nil }, ValidateJulia},
+	"npm":      {"npm", func(n string) (string, error) { return NormalizeNpm(n), nil }, ValidateNpm},
+	"pub":      {"Dart", func(n string) (string, error) { return NormalizePub(n), nil }, ValidatePub},
+	"python":   {"Python", func(n string) (string, error) { return NormalizePython(n), nil }, ValidatePython},
+}
+
+// Ecosystems returns the names of the ecosystems that Normalize and Validate
+// support, sorted alphabetically.
+func Ecosystems() []string {
+	names := make([]string, 0, len(ecosystems))
+	for n := range ecosystems {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Normalize returns name in the normalized form for the given ecosystem, such
+// as "python". It returns a *NameError if the ecosystem is not one of those
+// returned by Ecosystems. Normalize does not check that name is valid, unless
+// the ecosystem's normalization func does, as NormalizeHex does. Use Validate
+// for that.
+func Normalize(eco, name string) (string, error) {
+	e, ok := ecosystems[eco]
+	if !ok {
+		return "", unknownEcosystemError(eco, name)
+	}
+	return e.normalize(name)
+}
+
+// Validate returns a *NameError if name is not a valid package name in the
+// given ecosystem, or if the ecosystem is not one of those returned by
+// Ecosystems.
+func Validate(eco, name string) error {
+	e, ok := ecosystems[eco]
+	if !ok {
+		return unknownEcosystemError(eco, name)
+	}
+	return e.validate(name)
+}
+
+func unknownEcosystemError(eco, name string) error {
+	return newNameError(eco, name, ErrUnknownEcosystem, -1)
+}
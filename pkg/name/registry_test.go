@@ -0,0 +1,42 @@
+package name
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEcosystems(t *testing.T) {
+	assert.Equal(t, []string{"cargo", "composer", "debian", "go", "hex", "julia", "npm", "pub", "python"}, Ecosystems())
+}
+
+func TestNormalize(t *testing.T) {
+	n, err := Normalize("python", "backports.SSL")
+	require.NoError(t, err)
+	assert.Equal(t, "backports-ssl", n)
+
+	n, err = Normalize("hex", "ecto_sql")
+	require.NoError(t, err)
+	assert.Equal(t, "ecto_sql", n)
+
+	_, err = Normalize("hex", "Phoenix")
+	assert.Error(t, err, "hex names are validated by default")
+
+	n, err = Normalize("pub", " Flutter_Bloc")
+	require.NoError(t, err)
+	assert.Equal(t, "flutter_bloc", n)
+
+	_, err = Normalize("cobol", "backports.SSL")
+	require.Error(t, err)
+	assert.Equal(t, `unknown ecosystem "cobol"`, err.Error())
+	assert.Equal(t, &NameError{Ecosystem: "cobol", Input: "backports.SSL", Reason: ErrUnknownEcosystem, Pos: -1}, err)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("python", "Flask"))
+	assert.Error(t, Validate("python", "-flask"))
+	assert.NoError(t, Validate("debian", "libssl3"))
+	assert.Error(t, Validate("debian", "libSSL3"))
+	assert.Equal(t, &NameError{Ecosystem: "cobol", Input: "Flask", Reason: ErrUnknownEcosystem, Pos: -1}, Validate("cobol", "Flask"))
+}
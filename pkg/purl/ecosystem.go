@@ -0,0 +1,125 @@
+package purl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/name"
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+// Ecosystem is how the names and versions of the packages of one package URL
+// type are parsed, as returned by LookupEcosystem.
+type Ecosystem struct {
+	// ParsedAs is the type that the ecosystem's versions are parsed as with
+	// version.ParseComparable. It is version.Generic for ecosystems whose
+	// versions have no parser of their own.
+	ParsedAs  version.ParsedAs
+	normalize func(string) (string, error)
+	// separator joins the namespace and name into a single name. Types with
+	// no separator do not include the namespace in the name, since it is
+	// something like the name of a Linux distribution.
+	separator string
+}
+
+// ecosystems maps package URL types to their ecosystems. See
+// https://github.com/package-url/purl-spec for the types.
+var ecosystems = map[string]Ecosystem{
+	"cargo":    {ParsedAs: version.SemVer},
+	"composer": {ParsedAs: version.PHP, normalize: func(n string) (string, error) { return name.NormalizeComposer(n), nil }, separator: "/"},
+	"cpan":     {ParsedAs: version.PerlDecimal},
+	"deb":      {ParsedAs: version.Debian},
+	"gem":      {ParsedAs: version.Ruby},
+	"golang":   {ParsedAs: version.Go, separator: "/"},
+	"hex":      {ParsedAs: version.Hex, normalize: func(n string) (string, error) { return name.NormalizeHex(n) }},
+	"maven":    {ParsedAs: version.Maven, separator: ":"},
+	"npm":      {ParsedAs: version.Npm, separator: "/"},
+	"nuget":    {ParsedAs: version.NuGet, normalize: lowerCase},
+	"pub":      {ParsedAs: version.SemVer, normalize: func(n string) (string, error) { return name.NormalizePub(n), nil }},
+	"pypi":     {ParsedAs: version.PythonPEP440, normalize: func(n string) (string, error) { return name.NormalizePython(n), nil }},
+	"rpm":      {ParsedAs: version.Generic},
+}
+
+func lowerCase(n string) (string, error) {
+	return strings.ToLower(n), nil
+}
+
+// LookupEcosystem returns the ecosystem for the package URL type typ, such as
+// "pypi". Types are case insensitive. It returns false if the type is not one
+// of those listed in this package.
+func LookupEcosystem(typ string) (Ecosystem, bool) {
+	e, ok := ecosystems[strings.ToLower(typ)]
+	return e, ok
+}
+
+// ParseVersion parses a version from the ecosystem with the version package's
+// dispatcher, accepting any type comparable with e.ParsedAs, such as a legacy
+// Python version for "pypi".
+func (e Ecosystem) ParseVersion(s string) (*version.Version, error) {
+	return version.ParseComparable(e.ParsedAs, s)
+}
+
+// NormalizeName returns n normalized for the ecosystem, or n as it is if the
+// ecosystem has no normalization. It returns an error if the ecosystem's
+// normalization rejects invalid names, as Hex's does, and n is not valid.
+func (e Ecosystem) NormalizeName(n string) (string, error) {
+	if e.normalize == nil {
+		return n, nil
+	}
+	return e.normalize(n)
+}
+
+// FromPURL parses a package URL, and returns the package's name normalized
+// for its ecosystem and its version parsed with the ecosystem's parser. For
+// types where the namespace is part of the package's name, such as npm
+// scopes, Composer vendors and Go module paths, the name includes it, as in
+// "@angular/core" or "github.com/gorilla/mux". Maven names are
+// "groupId:artifactId".
+//
+// The version is nil if the package URL has none. It returns an error if the
+// package URL is invalid, if its type is not one of those listed in this
+// package, or if its name or version are invalid for its ecosystem.
+func FromPURL(s string) (string, *version.Version, error) {
+	p, err := ParsePURL(s)
+	if err != nil {
+		return "", nil, err
+	}
+	e, ok := ecosystems[p.Type]
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported package URL type %s: %s", p.Type, s)
+	}
+
+	n := p.Name
+	if e.separator != "" && p.Namespace != "" {
+		n = p.Namespace + e.separator + n
+	}
+	if n, err = e.NormalizeName(n); err != nil {
+		return "", nil, fmt.Errorf("invalid name in package URL %s: %s", s, err)
+	}
+
+	if p.Version == "" {
+		return n, nil, nil
+	}
+	v, err := e.ParseVersion(p.Version)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid version in package URL %s: %s", s, err)
+	}
+	return n, v, nil
+}
+
+// ToPURL returns the package URL for a package in the ecosystem named by the
+// package URL type eco, with a name in the form that FromPURL returns. The
+// namespace is split off the name for the types that FromPURL includes it
+// in. The version is left out if v is nil.
+func ToPURL(eco, n string, v *version.Version) string {
+	p := &PURL{Type: strings.ToLower(eco), Name: n}
+	if sep := ecosystems[p.Type].separator; sep != "" {
+		if i := strings.LastIndex(n, sep); i >= 0 {
+			p.Namespace, p.Name = n[:i], n[i+len(sep):]
+		}
+	}
+	if v != nil {
+		p.Version = v.Original
+	}
+	return p.String()
+}
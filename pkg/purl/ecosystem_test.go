@@ -0,0 +1,125 @@
+package purl
+
+import (
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/ActiveState/langtools/pkg/version/versiontest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromPURL(t *testing.T) {
+	tests := []struct {
+		purl      string
+		name      string
+		version   string
+		parsedAs  version.ParsedAs
+		canonical string
+	}{
+		{"pkg:pypi/Django_package@1.11.1.dev1", "django-package", "1.11.1.dev1", version.PythonPEP440, "pkg:pypi/django-package@1.11.1.dev1"},
+		{"pkg:npm/%40angular/animation@12.3.1", "@angular/animation", "12.3.1", version.Npm, ""},
+		{"pkg:npm/foobar@12.3.1", "foobar", "12.3.1", version.Npm, ""},
+		{"pkg:gem/ruby-advisory-db-check@0.12.4", "ruby-advisory-db-check", "0.12.4", version.Ruby, ""},
+		{"pkg:golang/golang.org/x/text@v0.3.2", "golang.org/x/text", "v0.3.2", version.Go, ""},
+		{"pkg:composer/Laravel/Framework@10.0.0", "laravel/framework", "10.0.0", version.PHP, "pkg:composer/laravel/framework@10.0.0"},
+		{"pkg:cargo/rand@0.7.2", "rand", "0.7.2", version.SemVer, ""},
+		{"pkg:cpan/Perl-Version@1.013", "Perl-Version", "1.013", version.PerlDecimal, ""},
+		{"pkg:deb/debian/curl@7.50.3-1?arch=i386&distro=jessie", "curl", "7.50.3-1", version.Debian, "pkg:deb/curl@7.50.3-1"},
+		{"pkg:rpm/fedora/curl@7.50.3-1.fc25?arch=i386", "curl", "7.50.3-1.fc25", version.Generic, "pkg:rpm/curl@7.50.3-1.fc25"},
+		{"pkg:nuget/EnterpriseLibrary.Common@6.0.1304", "enterpriselibrary.common", "6.0.1304", version.NuGet, "pkg:nuget/enterpriselibrary.common@6.0.1304"},
+		{"pkg:nuget/Newtonsoft.Json@13.0.3-beta1", "newtonsoft.json", "13.0.3-beta1", version.NuGet, "pkg:nuget/newtonsoft.json@13.0.3-beta1"},
+		{"pkg:maven/org.apache.xmlgraphics/batik-anim@1.9.1?classifier=sources", "org.apache.xmlgraphics:batik-anim", "1.9.1", version.Maven, "pkg:maven/org.apache.xmlgraphics/batik-anim@1.9.1"},
+		{"pkg:hex/phoenix_live_view@0.20.1", "phoenix_live_view", "0.20.1", version.Hex, ""},
+		{"pkg:pub/Http@1.1.0", "http", "1.1.0", version.SemVer, "pkg:pub/http@1.1.0"},
+		{"pkg:npm/%40babel/core@7.22.9%2Bbuild.1", "@babel/core", "7.22.9+build.1", version.Npm, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.purl, func(t *testing.T) {
+			n, v, err := FromPURL(tt.purl)
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, n)
+			require.NotNil(t, v)
+			assert.Equal(t, tt.version, v.Original)
+			assert.Equal(t, tt.parsedAs, v.ParsedAs)
+
+			p, err := ParsePURL(tt.purl)
+			require.NoError(t, err)
+			expected := tt.canonical
+			if expected == "" {
+				expected = tt.purl
+			}
+			assert.Equal(t, expected, ToPURL(p.Type, n, v), "ToPURL drops qualifiers, subpaths and namespaces that are not part of the name")
+		})
+	}
+}
+
+// fromPURLVersion returns the version of a package URL, so that package
+// URLs can be checked with versiontest.
+func fromPURLVersion(s string) (*version.Version, error) {
+	_, v, err := FromPURL(s)
+	return v, err
+}
+
+func TestFromPURLOrder(t *testing.T) {
+	versiontest.AssertOrdered(t, fromPURLVersion, []string{
+		"pkg:nuget/Newtonsoft.Json@2.5.1-beta",
+		"pkg:nuget/Newtonsoft.Json@2.5.1",
+		"pkg:nuget/Newtonsoft.Json@2.5.1.6205-beta",
+		"pkg:nuget/Newtonsoft.Json@2.5.1.6205",
+	})
+}
+
+func TestFromPURLBuildMetadata(t *testing.T) {
+	_, v, err := FromPURL("pkg:hex/jason@1.4.1%2Bbuild.7")
+	require.NoError(t, err)
+	assert.Equal(t, version.Hex, v.ParsedAs)
+	assert.Equal(t, "build.7", v.BuildMetadata)
+}
+
+func TestLookupEcosystem(t *testing.T) {
+	e, ok := LookupEcosystem("PyPI")
+	require.True(t, ok)
+	assert.Equal(t, version.PythonPEP440, e.ParsedAs)
+
+	v, err := e.ParseVersion("0.13-dev-r1")
+	require.NoError(t, err)
+	assert.Equal(t, version.PythonLegacy, v.ParsedAs, "types comparable with the ecosystem's are accepted")
+
+	n, err := e.NormalizeName("Flask_SQLAlchemy")
+	require.NoError(t, err)
+	assert.Equal(t, "flask-sqlalchemy", n)
+
+	e, ok = LookupEcosystem("rpm")
+	require.True(t, ok)
+	n, err = e.NormalizeName("Curl")
+	require.NoError(t, err)
+	assert.Equal(t, "Curl", n, "names are returned as they are without a normalizer")
+
+	_, ok = LookupEcosystem("docker")
+	assert.False(t, ok)
+}
+
+func TestFromPURLWithoutVersion(t *testing.T) {
+	n, v, err := FromPURL("pkg:pypi/Flask_SQLAlchemy")
+	require.NoError(t, err)
+	assert.Equal(t, "flask-sqlalchemy", n)
+	assert.Nil(t, v)
+	assert.Equal(t, "pkg:pypi/flask-sqlalchemy", ToPURL("pypi", n, nil))
+}
+
+func TestFromPURLErrors(t *testing.T) {
+	for purl, expected := range map[string]string{
+		"npm/foobar@12.3.1":                  "package URL does not start with pkg: npm/foobar@12.3.1",
+		"pkg:docker/nginx@1.25":              "unsupported package URL type docker: pkg:docker/nginx@1.25",
+		"pkg:golang/golang.org/x/text@0.3.2": "invalid version in package URL pkg:golang/golang.org/x/text@0.3.2: go module version does not start with v: 0.3.2",
+		"pkg:npm/foobar@1.0.x":               "invalid version in package URL pkg:npm/foobar@1.0.x: invalid npm version: 1.0.x",
+		"pkg:hex/Not%20Valid@1.0.0":          "invalid name in package URL pkg:hex/Not%20Valid@1.0.0: ",
+	} {
+		_, _, err := FromPURL(purl)
+		if assert.Error(t, err, purl) {
+			assert.Contains(t, err.Error(), expected, purl)
+		}
+	}
+}
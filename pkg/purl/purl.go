@@ -0,0 +1,245 @@
+// Package purl parses and builds package URLs
+// (https://github.com/package-url/purl-spec), and maps them to the name
+// normalization and version parsing of each package's ecosystem.
+package purl
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PURL is a parsed package URL. Every field is decoded, so it holds the
+// values as they are rather than as they appear in the URL.
+type PURL struct {
+	// Type is the package type, such as "pypi" or "npm", in lower case.
+	Type string
+	// Namespace is the optional namespace, such as the scope of an npm
+	// package or the group of a Maven artifact. Its segments are separated by
+	// "/".
+	Namespace string
+	Name      string
+	// Version is empty if the package URL has no version.
+	Version string
+	// Qualifiers maps the qualifier keys, in lower case, to their values.
+	// Qualifiers with empty values are dropped. It is nil if there are none.
+	Qualifiers map[string]string
+	// Subpath is the optional path within the package. Its segments are
+	// separated by "/".
+	Subpath string
+}
+
+var (
+	purlType         = regexp.MustCompile(`^[a-zA-Z.+-][a-zA-Z0-9.+-]*$`)
+	purlQualifierKey = regexp.MustCompile(`^[a-zA-Z.\-_][a-zA-Z0-9.\-_]*$`)
+)
+
+// ParsePURL parses a package URL such as
+// "pkg:npm/%40angular/animation@12.3.1?arch=x86#lib/core", following the
+// parsing rules in the purl spec. The type is lower cased, and the namespace
+// and name are normalized as the spec requires for some types, such as pypi,
+// where names are lower cased and "_" is replaced by "-".
+func ParsePURL(s string) (*PURL, error) {
+	p := &PURL{}
+	rest := s
+
+	if i := strings.LastIndexByte(rest, '#'); i >= 0 {
+		subpath, err := decodeSegments(rest[i+1:], true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subpath in package URL %s: %s", s, err)
+		}
+		p.Subpath = subpath
+		rest = rest[:i]
+	}
+
+	if i := strings.LastIndexByte(rest, '?'); i >= 0 {
+		q, err := parseQualifiers(rest[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid qualifiers in package URL %s: %s", s, err)
+		}
+		p.Qualifiers = q
+		rest = rest[:i]
+	}
+
+	i := strings.IndexByte(rest, ':')
+	if i < 0 || !strings.EqualFold(rest[:i], "pkg") {
+		return nil, fmt.Errorf("package URL does not start with pkg: %s", s)
+	}
+	rest = strings.TrimLeft(rest[i+1:], "/")
+
+	i = strings.IndexByte(rest, '/')
+	if i < 0 {
+		return nil, fmt.Errorf("package URL has no name: %s", s)
+	}
+	if !purlType.MatchString(rest[:i]) {
+		return nil, fmt.Errorf("invalid type in package URL %s: %q", s, rest[:i])
+	}
+	p.Type = strings.ToLower(rest[:i])
+	rest = rest[i+1:]
+
+	if i := strings.LastIndexByte(rest, '@'); i >= 0 {
+		v, err := url.PathUnescape(rest[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version in package URL %s: %s", s, err)
+		}
+		p.Version = v
+		rest = rest[:i]
+	}
+
+	rest = strings.TrimRight(rest, "/")
+	i = strings.LastIndexByte(rest, '/')
+	n, err := url.PathUnescape(rest[i+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid name in package URL %s: %s", s, err)
+	}
+	if n == "" {
+		return nil, fmt.Errorf("package URL has no name: %s", s)
+	}
+	p.Name = n
+
+	if i >= 0 {
+		ns, err := decodeSegments(rest[:i], false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace in package URL %s: %s", s, err)
+		}
+		p.Namespace = ns
+	}
+
+	p.normalize()
+	return p, nil
+}
+
+// decodeSegments percent-decodes each "/" separated segment of s, dropping
+// empty segments, and also "." and ".." if isSubpath is true.
+func decodeSegments(s string, isSubpath bool) (string, error) {
+	var segments []string
+	for _, seg := range strings.Split(s, "/") {
+		if seg == "" || isSubpath && (seg == "." || seg == "..") {
+			continue
+		}
+		decoded, err := url.PathUnescape(seg)
+		if err != nil {
+			return "", err
+		}
+		segments = append(segments, decoded)
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+func parseQualifiers(s string) (map[string]string, error) {
+	var q map[string]string
+	for _, pair := range strings.Split(s, "&") {
+		if pair == "" {
+			continue
+		}
+		i := strings.IndexByte(pair, '=')
+		if i < 0 {
+			return nil, fmt.Errorf("qualifier has no value: %s", pair)
+		}
+		key := strings.ToLower(pair[:i])
+		if !purlQualifierKey.MatchString(key) {
+			return nil, fmt.Errorf("invalid qualifier key: %q", key)
+		}
+		value, err := url.PathUnescape(pair[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		if value == "" {
+			continue
+		}
+		if _, ok := q[key]; ok {
+			return nil, fmt.Errorf("duplicate qualifier key: %s", key)
+		}
+		if q == nil {
+			q = map[string]string{}
+		}
+		q[key] = value
+	}
+	return q, nil
+}
+
+// normalize applies the normalization that the purl spec requires for some
+// types.
+func (p *PURL) normalize() {
+	switch p.Type {
+	case "bitbucket", "github":
+		p.Namespace = strings.ToLower(p.Namespace)
+		p.Name = strings.ToLower(p.Name)
+	case "pypi":
+		p.Name = strings.Replace(strings.ToLower(p.Name), "_", "-", -1)
+	}
+}
+
+// String returns the package URL in canonical form, with the qualifiers
+// sorted by key, and everything percent-encoded as the purl spec requires.
+func (p *PURL) String() string {
+	var b strings.Builder
+	b.WriteString("pkg:")
+	b.WriteString(p.Type)
+	b.WriteByte('/')
+	if p.Namespace != "" {
+		b.WriteString(encodeSegments(p.Namespace))
+		b.WriteByte('/')
+	}
+	b.WriteString(encode(p.Name))
+	if p.Version != "" {
+		b.WriteByte('@')
+		b.WriteString(encode(p.Version))
+	}
+
+	if len(p.Qualifiers) > 0 {
+		keys := make([]string, 0, len(p.Qualifiers))
+		for k, v := range p.Qualifiers {
+			if v != "" {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i == 0 {
+				b.WriteByte('?')
+			} else {
+				b.WriteByte('&')
+			}
+			b.WriteString(strings.ToLower(k))
+			b.WriteByte('=')
+			b.WriteString(encode(p.Qualifiers[k]))
+		}
+	}
+
+	if p.Subpath != "" {
+		b.WriteByte('#')
+		b.WriteString(encodeSegments(p.Subpath))
+	}
+	return b.String()
+}
+
+func encodeSegments(s string) string {
+	segments := strings.Split(strings.Trim(s, "/"), "/")
+	for i, seg := range segments {
+		segments[i] = encode(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+// encode percent-encodes every byte of s except for ASCII letters and digits,
+// ".", "-", "_", "~" and ":".
+func encode(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '.', c == '-', c == '_', c == '~', c == ':':
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&15])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,135 @@
+package purl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Most of these come from the test suite in the purl-spec repository.
+func TestParsePURL(t *testing.T) {
+	tests := []struct {
+		purl      string
+		expected  PURL
+		canonical string
+	}{
+		{
+			"pkg:pypi/django@1.11.1",
+			PURL{Type: "pypi", Name: "django", Version: "1.11.1"},
+			"pkg:pypi/django@1.11.1",
+		},
+		{
+			"pkg:pypi/Django_package@1.11.1.dev1",
+			PURL{Type: "pypi", Name: "django-package", Version: "1.11.1.dev1"},
+			"pkg:pypi/django-package@1.11.1.dev1",
+		},
+		{
+			"pkg:npm/%40angular/animation@12.3.1",
+			PURL{Type: "npm", Namespace: "@angular", Name: "animation", Version: "12.3.1"},
+			"pkg:npm/%40angular/animation@12.3.1",
+		},
+		{
+			"pkg:npm/foobar@12.3.1",
+			PURL{Type: "npm", Name: "foobar", Version: "12.3.1"},
+			"pkg:npm/foobar@12.3.1",
+		},
+		{
+			"pkg:gem/ruby-advisory-db-check@0.12.4",
+			PURL{Type: "gem", Name: "ruby-advisory-db-check", Version: "0.12.4"},
+			"pkg:gem/ruby-advisory-db-check@0.12.4",
+		},
+		{
+			"pkg:golang/github.com/gorilla/context@234fd47e07d1004f0aed9c#api",
+			PURL{Type: "golang", Namespace: "github.com/gorilla", Name: "context", Version: "234fd47e07d1004f0aed9c", Subpath: "api"},
+			"pkg:golang/github.com/gorilla/context@234fd47e07d1004f0aed9c#api",
+		},
+		{
+			"pkg:GOLANG/google.golang.org/genproto@abcdedf#/googleapis/api/annotations/",
+			PURL{Type: "golang", Namespace: "google.golang.org", Name: "genproto", Version: "abcdedf", Subpath: "googleapis/api/annotations"},
+			"pkg:golang/google.golang.org/genproto@abcdedf#googleapis/api/annotations",
+		},
+		{
+			"pkg:cargo/rand@0.7.2",
+			PURL{Type: "cargo", Name: "rand", Version: "0.7.2"},
+			"pkg:cargo/rand@0.7.2",
+		},
+		{
+			"pkg:nuget/EnterpriseLibrary.Common@6.0.1304",
+			PURL{Type: "nuget", Name: "EnterpriseLibrary.Common", Version: "6.0.1304"},
+			"pkg:nuget/EnterpriseLibrary.Common@6.0.1304",
+		},
+		{
+			"pkg:deb/debian/curl@7.50.3-1?arch=i386&distro=jessie",
+			PURL{Type: "deb", Namespace: "debian", Name: "curl", Version: "7.50.3-1", Qualifiers: map[string]string{"arch": "i386", "distro": "jessie"}},
+			"pkg:deb/debian/curl@7.50.3-1?arch=i386&distro=jessie",
+		},
+		{
+			"pkg:rpm/fedora/curl@7.50.3-1.fc25?distro=fedora-25&Arch=i386",
+			PURL{Type: "rpm", Namespace: "fedora", Name: "curl", Version: "7.50.3-1.fc25", Qualifiers: map[string]string{"arch": "i386", "distro": "fedora-25"}},
+			"pkg:rpm/fedora/curl@7.50.3-1.fc25?arch=i386&distro=fedora-25",
+		},
+		{
+			"pkg:maven/org.apache.xmlgraphics/batik-anim@1.9.1?classifier=sources&repository_url=repo.spring.io/release",
+			PURL{Type: "maven", Namespace: "org.apache.xmlgraphics", Name: "batik-anim", Version: "1.9.1", Qualifiers: map[string]string{"classifier": "sources", "repository_url": "repo.spring.io/release"}},
+			"pkg:maven/org.apache.xmlgraphics/batik-anim@1.9.1?classifier=sources&repository_url=repo.spring.io%2Frelease",
+		},
+		{
+			"pkg:github/Package-url/purl-Spec@244fd47e07d1004f0aed9c",
+			PURL{Type: "github", Namespace: "package-url", Name: "purl-spec", Version: "244fd47e07d1004f0aed9c"},
+			"pkg:github/package-url/purl-spec@244fd47e07d1004f0aed9c",
+		},
+		{
+			"pkg:docker/customer/dockerimage@sha256%3A244fd47e07d1004f0aed9c?repository_url=gcr.io",
+			PURL{Type: "docker", Namespace: "customer", Name: "dockerimage", Version: "sha256:244fd47e07d1004f0aed9c", Qualifiers: map[string]string{"repository_url": "gcr.io"}},
+			"pkg:docker/customer/dockerimage@sha256:244fd47e07d1004f0aed9c?repository_url=gcr.io",
+		},
+		{
+			"pkg://npm//@babel//core@7.22.9?checksum=&vcs_url=",
+			PURL{Type: "npm", Namespace: "@babel", Name: "core", Version: "7.22.9"},
+			"pkg:npm/%40babel/core@7.22.9",
+		},
+		{
+			"pkg:generic/openssl@1.1.10g%2Bbuild%201?download_url=https://openssl.org/source/openssl-1.1.0g.tar.gz#./a/../b",
+			PURL{Type: "generic", Name: "openssl", Version: "1.1.10g+build 1", Qualifiers: map[string]string{"download_url": "https://openssl.org/source/openssl-1.1.0g.tar.gz"}, Subpath: "a/b"},
+			"pkg:generic/openssl@1.1.10g%2Bbuild%201?download_url=https:%2F%2Fopenssl.org%2Fsource%2Fopenssl-1.1.0g.tar.gz#a/b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.purl, func(t *testing.T) {
+			p, err := ParsePURL(tt.purl)
+			require.NoError(t, err)
+			assert.Equal(t, &tt.expected, p)
+			assert.Equal(t, tt.canonical, p.String())
+
+			reparsed, err := ParsePURL(p.String())
+			require.NoError(t, err)
+			assert.Equal(t, p, reparsed, "the canonical form parses to the same thing")
+		})
+	}
+}
+
+func TestParsePURLErrors(t *testing.T) {
+	for purl, expected := range map[string]string{
+		"npm/foobar@12.3.1":            "package URL does not start with pkg: npm/foobar@12.3.1",
+		"http://example.com/foobar":    "package URL does not start with pkg: http://example.com/foobar",
+		"pkg:npm":                      "package URL has no name: pkg:npm",
+		"pkg:maven/@1.3.4":             "package URL has no name: pkg:maven/@1.3.4",
+		"pkg:n&g/nginx@0.8.9":          "invalid type in package URL pkg:n&g/nginx@0.8.9: \"n&g\"",
+		"pkg:3rd/foobar":               "invalid type in package URL pkg:3rd/foobar: \"3rd\"",
+		"pkg:npm/foobar?arch":          "invalid qualifiers in package URL pkg:npm/foobar?arch: qualifier has no value: arch",
+		"pkg:npm/foobar?a%20b=c":       "invalid qualifiers in package URL pkg:npm/foobar?a%20b=c: invalid qualifier key: \"a%20b\"",
+		"pkg:npm/foobar?arch=x&arch=y": "invalid qualifiers in package URL pkg:npm/foobar?arch=x&arch=y: duplicate qualifier key: arch",
+		"pkg:npm/foo%zzbar":            "invalid name in package URL pkg:npm/foo%zzbar: ",
+		"pkg:npm/foobar@1.0%":          "invalid version in package URL pkg:npm/foobar@1.0%: ",
+		"pkg:npm/%4/foobar":            "invalid namespace in package URL pkg:npm/%4/foobar: ",
+		"pkg:npm/foobar#a/%xx":         "invalid subpath in package URL pkg:npm/foobar#a/%xx: ",
+		"pkg:npm/foobar?arch=%":        "invalid qualifiers in package URL pkg:npm/foobar?arch=%: ",
+	} {
+		_, err := ParsePURL(purl)
+		if assert.Error(t, err, purl) {
+			assert.Contains(t, err.Error(), expected, purl)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Package sbom parses the names and versions of the components listed in
+// CycloneDX and SPDX software bills of materials, using the version scheme and
+// name normalization of each component's ecosystem, as given by
+// purl.LookupEcosystem.
+package sbom
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/purl"
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+// Component is a parsed SBOM component.
+type Component struct {
+	// Ecosystem is the package URL type of the component, such as "pypi" or
+	// "npm", in lower case. It is empty if the component has no package URL.
+	Ecosystem string
+	// Name is the component's name, normalized for its ecosystem where this
+	// repo knows how to do so.
+	Name string
+	// Version is the parsed version. It is nil if the version could not be
+	// parsed.
+	Version *version.Version
+	// Generic is true if the version was parsed with version.ParseGeneric
+	// because there is no parser for the component's ecosystem.
+	Generic bool
+	// NameError is set if the component has no name, or if its ecosystem
+	// rejects invalid names when normalizing them, as Hex does, and the name
+	// is not valid.
+	NameError error
+	// VersionError is set if the component has no version or the version
+	// could not be parsed.
+	VersionError error
+}
+
+// SPDXExternalRef is an entry in the externalRefs list of an SPDX package.
+// The field tags match SPDX JSON documents, so a package's externalRefs can be
+// decoded straight into a []SPDXExternalRef.
+type SPDXExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// ParseCycloneDXComponent parses the name and version of a CycloneDX
+// component. purlOrType is either the component's package URL, such as
+// "pkg:pypi/requests@2.31.0", or just its type, such as "pypi". Components
+// from ecosystems without a parser have their version parsed with
+// version.ParseGeneric, and their Generic field set.
+func ParseCycloneDXComponent(purlOrType, componentName, componentVersion string) Component {
+	typ := purlOrType
+	if strings.HasPrefix(purlOrType, "pkg:") {
+		typ = purlType(purlOrType)
+	}
+	return parseComponent(strings.ToLower(typ), componentName, componentVersion)
+}
+
+// ParseSPDXPackage parses the name and version of an SPDX package. The
+// ecosystem is taken from the first package URL in refs, which is the
+// package manager reference with type "purl". Packages without one have
+// their version parsed with version.ParseGeneric, as in
+// ParseCycloneDXComponent.
+func ParseSPDXPackage(packageName, packageVersion string, refs []SPDXExternalRef) Component {
+	typ := ""
+	for _, ref := range refs {
+		category := strings.ReplaceAll(ref.ReferenceCategory, "_", "-")
+		if category == "PACKAGE-MANAGER" && ref.ReferenceType == "purl" {
+			typ = purlType(ref.ReferenceLocator)
+			break
+		}
+	}
+	return parseComponent(strings.ToLower(typ), packageName, packageVersion)
+}
+
+// purlType returns the type of a package URL like "pkg:type/namespace/name",
+// or the empty string if s is not a package URL.
+func purlType(s string) string {
+	if !strings.HasPrefix(s, "pkg:") {
+		return ""
+	}
+	rest := strings.TrimLeft(s[len("pkg:"):], "/")
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		return rest[:i]
+	}
+	return ""
+}
+
+func parseComponent(typ, componentName, componentVersion string) Component {
+	c := Component{Ecosystem: typ, Name: componentName}
+
+	e, ok := purl.LookupEcosystem(typ)
+	if !ok {
+		e = purl.Ecosystem{ParsedAs: version.Generic}
+	}
+	c.Generic = e.ParsedAs == version.Generic
+
+	if componentName == "" {
+		c.NameError = errors.New("component has no name")
+	} else if n, err := e.NormalizeName(componentName); err != nil {
+		c.NameError = err
+	} else {
+		c.Name = n
+	}
+
+	if componentVersion == "" {
+		c.VersionError = errors.New("component has no version")
+	} else {
+		c.Version, c.VersionError = e.ParseVersion(componentVersion)
+	}
+
+	return c
+}
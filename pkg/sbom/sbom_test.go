@@ -0,0 +1,154 @@
+package sbom
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cycloneDXComponent struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	PURL    string `json:"purl"`
+}
+
+func TestParseCycloneDXComponent(t *testing.T) {
+	tests := []struct {
+		component string
+		ecosystem string
+		name      string
+		parsedAs  version.ParsedAs
+		generic   bool
+	}{
+		{
+			`{"type": "library", "bom-ref": "pkg:pypi/Flask-SQLAlchemy@3.0.5", "name": "Flask-SQLAlchemy", "version": "3.0.5", "purl": "pkg:pypi/Flask-SQLAlchemy@3.0.5"}`,
+			"pypi", "flask-sqlalchemy", version.PythonPEP440, false,
+		},
+		{
+			`{"type": "library", "bom-ref": "pkg:npm/%40babel/core@7.22.9", "group": "@babel", "name": "core", "version": "7.22.9", "purl": "pkg:npm/%40babel/core@7.22.9"}`,
+			"npm", "core", version.Npm, false,
+		},
+		{
+			`{"type": "library", "name": "rack", "version": "2.2.7", "purl": "pkg:gem/rack@2.2.7"}`,
+			"gem", "rack", version.Ruby, false,
+		},
+		{
+			`{"type": "library", "name": "golang.org/x/text", "version": "v0.3.2", "purl": "pkg:golang/golang.org/x/text@v0.3.2"}`,
+			"golang", "golang.org/x/text", version.Go, false,
+		},
+		{
+			`{"type": "library", "name": "libssl3", "version": "3.0.9-1", "purl": "pkg:deb/debian/libssl3@3.0.9-1?arch=amd64&distro=debian-12"}`,
+			"deb", "libssl3", version.Debian, false,
+		},
+		{
+			`{"type": "library", "name": "curl", "version": "7.76.1-26.el9", "purl": "pkg:rpm/redhat/curl@7.76.1-26.el9?arch=x86_64"}`,
+			"rpm", "curl", version.Generic, true,
+		},
+		{
+			`{"type": "library", "group": "org.apache.commons", "name": "commons-lang3", "version": "3.13.0", "purl": "pkg:maven/org.apache.commons/commons-lang3@3.13.0"}`,
+			"maven", "commons-lang3", version.Maven, false,
+		},
+		{
+			`{"type": "library", "name": "Newtonsoft.Json", "version": "13.0.3", "purl": "pkg:nuget/Newtonsoft.Json@13.0.3"}`,
+			"nuget", "newtonsoft.json", version.NuGet, false,
+		},
+		{
+			`{"type": "library", "name": "pyOpenSSL", "version": "0.13-dev-r1", "purl": "pkg:pypi/pyopenssl@0.13-dev-r1"}`,
+			"pypi", "pyopenssl", version.PythonLegacy, false,
+		},
+	}
+
+	for _, tt := range tests {
+		var component cycloneDXComponent
+		require.NoError(t, json.Unmarshal([]byte(tt.component), &component))
+
+		c := ParseCycloneDXComponent(component.PURL, component.Name, component.Version)
+		assert.Equal(t, tt.ecosystem, c.Ecosystem, tt.component)
+		assert.Equal(t, tt.name, c.Name, tt.component)
+		assert.Equal(t, tt.generic, c.Generic, tt.component)
+		assert.NoError(t, c.NameError, tt.component)
+		if assert.NoError(t, c.VersionError, tt.component) {
+			assert.Equal(t, component.Version, c.Version.Original, tt.component)
+			assert.Equal(t, tt.parsedAs, c.Version.ParsedAs, tt.component)
+		}
+	}
+}
+
+func TestParseCycloneDXComponentWithType(t *testing.T) {
+	c := ParseCycloneDXComponent("PyPI", "backports.SSL", "1.0")
+	assert.Equal(t, "pypi", c.Ecosystem)
+	assert.Equal(t, "backports-ssl", c.Name)
+	assert.False(t, c.Generic)
+	require.NoError(t, c.VersionError)
+	assert.Equal(t, version.PythonPEP440, c.Version.ParsedAs)
+}
+
+func TestParseCycloneDXComponentErrors(t *testing.T) {
+	c := ParseCycloneDXComponent("pkg:npm/left-pad@1.3.x", "", "1.3.x")
+	assert.Error(t, c.NameError)
+	assert.Error(t, c.VersionError, "npm versions cannot be ranges")
+	assert.Nil(t, c.Version)
+
+	c = ParseCycloneDXComponent("pkg:golang/example.com/mod", "example.com/mod", "1.2.3")
+	assert.Error(t, c.VersionError, "go module versions start with v")
+
+	c = ParseCycloneDXComponent("pkg:hex/Phoenix@1.7.10", "Phoenix", "1.7.10")
+	assert.Error(t, c.NameError, "hex names are validated when they are normalized")
+	assert.Equal(t, "Phoenix", c.Name)
+	assert.NoError(t, c.VersionError)
+
+	c = ParseCycloneDXComponent("", "thing", "")
+	assert.Equal(t, "", c.Ecosystem)
+	assert.True(t, c.Generic)
+	assert.Error(t, c.VersionError)
+}
+
+func TestParseSPDXPackage(t *testing.T) {
+	pkg := `{
+		"SPDXID": "SPDXRef-Package-python-requests",
+		"name": "requests",
+		"versionInfo": "2.31.0",
+		"externalRefs": [
+			{
+				"referenceCategory": "SECURITY",
+				"referenceType": "cpe23Type",
+				"referenceLocator": "cpe:2.3:a:python-requests:requests:2.31.0:*:*:*:*:*:*:*"
+			},
+			{
+				"referenceCategory": "PACKAGE-MANAGER",
+				"referenceType": "purl",
+				"referenceLocator": "pkg:pypi/requests@2.31.0"
+			}
+		]
+	}`
+
+	var spdx struct {
+		Name         string            `json:"name"`
+		VersionInfo  string            `json:"versionInfo"`
+		ExternalRefs []SPDXExternalRef `json:"externalRefs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(pkg), &spdx))
+
+	c := ParseSPDXPackage(spdx.Name, spdx.VersionInfo, spdx.ExternalRefs)
+	assert.Equal(t, "pypi", c.Ecosystem)
+	assert.Equal(t, "requests", c.Name)
+	assert.False(t, c.Generic)
+	require.NoError(t, c.VersionError)
+	assert.Equal(t, version.PythonPEP440, c.Version.ParsedAs)
+
+	refs := []SPDXExternalRef{{ReferenceCategory: "PACKAGE_MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:gem/rails@7.0.4"}}
+	c = ParseSPDXPackage("rails", "7.0.4", refs)
+	assert.Equal(t, "gem", c.Ecosystem)
+	require.NoError(t, c.VersionError)
+	assert.Equal(t, version.Ruby, c.Version.ParsedAs)
+
+	c = ParseSPDXPackage("zlib", "1.2.13", nil)
+	assert.Equal(t, "", c.Ecosystem)
+	assert.True(t, c.Generic)
+	require.NoError(t, c.VersionError)
+	assert.Equal(t, version.Generic, c.Version.ParsedAs)
+}
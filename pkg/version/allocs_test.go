@@ -0,0 +1,63 @@
+package version
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// allocationBudgetRelease is the Go release the allocation budgets were
+// measured with. Allocation counts change between releases as the compiler's
+// escape analysis and the standard library change, so the budgets are only
+// checked with this release. Update it when the budgets are measured again.
+const allocationBudgetRelease = "go1.27"
+
+// TestParseAllocationBudgets fails if parsing a corpus takes more allocations
+// than it did when the budget was last set. When an optimization brings a
+// parser's count down, lower its budget to the new count in the same change so
+// the improvement can't quietly regress.
+//
+// The budgets are exact counts from allocationBudgetRelease, so the test is
+// skipped with any other release, including the older ones CI runs.
+func TestParseAllocationBudgets(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not meaningful with the race detector")
+	}
+	if v := runtime.Version(); v != allocationBudgetRelease && !strings.HasPrefix(v, allocationBudgetRelease+".") {
+		t.Skipf("allocation budgets were measured with %s, not %s", allocationBudgetRelease, v)
+	}
+
+	tests := []struct {
+		name   string
+		parse  func(string) (*Version, error)
+		inputs []string
+		// budget is the most allocations allowed for parsing every input
+		// once.
+		budget float64
+	}{
+		{"Generic", func(s string) (*Version, error) { return ParseGeneric(s) }, genericBenchmarkStrings, 94},
+		{"SemVer", ParseSemVer, testParseSemVerOrderInputs, 767},
+		{"Python", ParsePython, pythonTestStrings, 2134},
+		{"PerlDecimal", ParsePerl, perlDecimalBenchmarkStrings, 42},
+		{"PerlVString", ParsePerl, perlVStringBenchmarkStrings, 25},
+		{"PHP", func(s string) (*Version, error) { return ParsePHP(s) }, testParsePHPOrderInputs, 2507},
+		{"Ruby", ParseRuby, rubyTestStrings, 973},
+		{"Go", func(s string) (*Version, error) { return ParseGo(s) }, goModuleVersions, 94},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocs := testing.AllocsPerRun(10, func() {
+				for _, s := range tt.inputs {
+					if _, err := tt.parse(s); err != nil {
+						t.Fatal(err)
+					}
+				}
+			})
+			assert.True(t, allocs <= tt.budget,
+				"parsing %d %s versions allocates at most %v times (got %v)", len(tt.inputs), tt.name, tt.budget, allocs)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package version
+
+import (
+	"bytes"
+)
+
+// Argsort returns the permutation that sorts vs, without changing vs. The
+// i-th element of the result is the index in vs of the i-th version in sorted
+// order, so vs[Argsort(vs)[0]] is the least version. The order is the same as
+// Sort's, so equal versions keep their original relative order.
+//
+// Like SortByKey, it computes each version's CompareKey up front, which makes
+// it fast for large slices. It does not check StrictCompare.
+func Argsort(vs []*Version) []int {
+	keyed := sortedKeyedVersions(vs)
+	indexes := make([]int, len(keyed))
+	for i, k := range keyed {
+		indexes[i] = k.index
+	}
+	return indexes
+}
+
+// Rank returns the rank of each version in vs, in the same order as vs. The
+// least version has a rank of 0, equal versions share a rank, and each
+// version that is greater than the one before it in sorted order has a rank
+// one greater than that version's. For example, the ranks of 2.0, 1.0, 1.0.0
+// and 3.0 are 1, 0, 0 and 2.
+//
+// It does not check StrictCompare.
+func Rank(vs []*Version) []int {
+	keyed := sortedKeyedVersions(vs)
+	ranks := make([]int, len(keyed))
+	rank := 0
+	for i, k := range keyed {
+		if i > 0 && !bytes.Equal(k.key, keyed[i-1].key) {
+			rank++
+		}
+		ranks[k.index] = rank
+	}
+	return ranks
+}
+
+// CompareAll returns the result of comparing every pair of versions in vs.
+// The result at [i][j] is -1, 0 or 1 as vs[i] is less than, equal to, or
+// greater than vs[j]. The matrix has len(vs)*len(vs) elements, so this is
+// only suitable for small slices. Use Rank for large ones.
+func CompareAll(vs []*Version) [][]int {
+	ranks := Rank(vs)
+	matrix := make([][]int, len(vs))
+	cells := make([]int, len(vs)*len(vs))
+	for i := range matrix {
+		matrix[i] = cells[i*len(vs) : (i+1)*len(vs) : (i+1)*len(vs)]
+		for j := range matrix[i] {
+			switch {
+			case ranks[i] < ranks[j]:
+				matrix[i][j] = -1
+			case ranks[i] > ranks[j]:
+				matrix[i][j] = 1
+			}
+		}
+	}
+	return matrix
+}
@@ -0,0 +1,120 @@
+package version
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rankTestVersions returns the versions in the Ruby equality groups in a
+// shuffled order, along with the index of the group each one came from.
+func rankTestVersions(t *testing.T) ([]*Version, []int) {
+	var (
+		vs     []*Version
+		groups []int
+	)
+	for i, group := range equalRubyVersions {
+		for _, s := range group {
+			vs = append(vs, parseRubyOrFatal(t, s))
+			groups = append(groups, i)
+		}
+	}
+
+	r := rand.New(rand.NewSource(42))
+	r.Shuffle(len(vs), func(i, j int) {
+		vs[i], vs[j] = vs[j], vs[i]
+		groups[i], groups[j] = groups[j], groups[i]
+	})
+	return vs, groups
+}
+
+func TestArgsort(t *testing.T) {
+	vs, _ := rankTestVersions(t)
+	original := append([]*Version{}, vs...)
+
+	indexes := Argsort(vs)
+	assert.Equal(t, original, vs, "vs is not changed")
+
+	sorted := append([]*Version{}, vs...)
+	require.NoError(t, Sort(sorted))
+
+	require.Len(t, indexes, len(vs))
+	for i, index := range indexes {
+		assert.True(t, vs[index] == sorted[i], "%d: %s is %s", i, vs[index], sorted[i])
+	}
+
+	assert.Equal(t, []int{}, Argsort(nil))
+	assert.Equal(t, []int{1, 2, 0}, Argsort([]*Version{
+		parseRubyOrFatal(t, "2.0"),
+		parseRubyOrFatal(t, "1.0"),
+		parseRubyOrFatal(t, "1.0.0"),
+	}), "equal versions keep their original order")
+}
+
+func TestRank(t *testing.T) {
+	vs, groups := rankTestVersions(t)
+	ranks := Rank(vs)
+	require.Len(t, ranks, len(vs))
+
+	for i := range vs {
+		for j := range vs {
+			if groups[i] == groups[j] {
+				assert.Equal(t, ranks[i], ranks[j], "%s and %s are equal", vs[i], vs[j])
+				continue
+			}
+			cmp := Compare(vs[i], vs[j])
+			require.NotEqual(t, 0, cmp, "%s and %s are in different groups", vs[i], vs[j])
+			assert.Equal(t, cmp < 0, ranks[i] < ranks[j], "%s and %s", vs[i], vs[j])
+		}
+	}
+
+	seen := map[int]bool{}
+	for _, r := range ranks {
+		seen[r] = true
+	}
+	for r := 0; r < len(equalRubyVersions); r++ {
+		assert.True(t, seen[r], "ranks are dense, and %d is used", r)
+	}
+
+	assert.Equal(t, []int{1, 0, 0, 2}, Rank([]*Version{
+		parseOrFatalGeneric(t, "2.0"),
+		parseOrFatalGeneric(t, "1.0"),
+		parseOrFatalGeneric(t, "1.0.0"),
+		parseOrFatalGeneric(t, "3.0"),
+	}))
+	assert.Equal(t, []int{}, Rank(nil))
+}
+
+func TestCompareAll(t *testing.T) {
+	vs, _ := rankTestVersions(t)
+	matrix := CompareAll(vs)
+	require.Len(t, matrix, len(vs))
+	for i := range vs {
+		require.Len(t, matrix[i], len(vs))
+		for j := range vs {
+			assert.Equal(t, Compare(vs[i], vs[j]), matrix[i][j], "%s and %s", vs[i], vs[j])
+		}
+	}
+
+	assert.Equal(t, [][]int{}, CompareAll(nil))
+}
+
+func BenchmarkArgsort100k(b *testing.B) {
+	vs := millionSyntheticVersions()[:100000]
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Argsort(vs)
+	}
+}
+
+func BenchmarkRank100k(b *testing.B) {
+	vs := millionSyntheticVersions()[:100000]
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Rank(vs)
+	}
+}
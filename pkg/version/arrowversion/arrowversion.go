@@ -0,0 +1,255 @@
+// Package arrowversion converts Versions from the version package to and from
+// Apache Arrow records, for exporting parsed versions to Arrow and Parquet
+// based analytics tools. It is a separate package so that programs which only
+// import the version package don't depend on Arrow.
+package arrowversion
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/decimal128"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/ericlagergren/decimal"
+)
+
+// EncodingVersion is written to the encoding_version column of every row. It
+// will be increased if the layout of the record changes.
+const EncodingVersion = 1
+
+// The maximum number of digits in an Arrow decimal128 value.
+const maxPrecision = 38
+
+// The names of the columns in a record, in order.
+const (
+	originalColumn        = "original"
+	parsedAsColumn        = "parsed_as"
+	segmentsColumn        = "segments"
+	fallbackColumn        = "segments_fallback"
+	encodingVersionColumn = "encoding_version"
+)
+
+var (
+	two64  = new(big.Int).Lsh(big.NewInt(1), 64)
+	two128 = new(big.Int).Lsh(big.NewInt(1), 128)
+	ten    = big.NewInt(10)
+)
+
+// BuildArrowRecord returns a record with a row for each version in vs, with
+// these columns:
+//
+//   - original (utf8): the original version.
+//   - parsed_as (utf8): the name of the type the version was parsed as. This
+//     is not dictionary encoded, since the Arrow release we use supports Go
+//     1.12 but not dictionary arrays.
+//   - segments (list of decimal128): the version's segments.
+//   - segments_fallback (list of utf8): for each segment that does not fit
+//     in the segments column, the segment as a string. The two lists always
+//     have the same length, and for each segment exactly one of them is
+//     null.
+//   - encoding_version (int32): EncodingVersion.
+//
+// Arrow decimal128 columns have a single scale, so the scale of the segments
+// column is the largest scale of any segment that fits in 38 digits, and a
+// segment goes in segments_fallback if it needs more than 38 digits at that
+// scale. Integer segments always fit, but the segments that ParseGeneric and
+// others make from words usually do not.
+//
+// The caller must call Release on the returned record.
+func BuildArrowRecord(vs []*version.Version) (array.Record, error) {
+	scale := int32(0)
+	for _, v := range vs {
+		if v == nil {
+			return nil, fmt.Errorf("cannot build an Arrow record from a nil version")
+		}
+		for _, d := range v.Decimal {
+			if s := d.Scale(); s > int(scale) && integerDigits(d)+s <= maxPrecision {
+				scale = int32(s)
+			}
+		}
+	}
+
+	decimalType := &arrow.Decimal128Type{Precision: maxPrecision, Scale: scale}
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: originalColumn, Type: arrow.BinaryTypes.String},
+		{Name: parsedAsColumn, Type: arrow.BinaryTypes.String},
+		{Name: segmentsColumn, Type: arrow.ListOf(decimalType)},
+		{Name: fallbackColumn, Type: arrow.ListOf(arrow.BinaryTypes.String)},
+		{Name: encodingVersionColumn, Type: arrow.PrimitiveTypes.Int32},
+	}, nil)
+
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+
+	originals := b.Field(0).(*array.StringBuilder)
+	parsedAs := b.Field(1).(*array.StringBuilder)
+	segments := b.Field(2).(*array.ListBuilder)
+	decimals := segments.ValueBuilder().(*array.Decimal128Builder)
+	fallback := b.Field(3).(*array.ListBuilder)
+	strs := fallback.ValueBuilder().(*array.StringBuilder)
+	encodingVersions := b.Field(4).(*array.Int32Builder)
+
+	for _, v := range vs {
+		originals.Append(v.Original)
+		parsedAs.Append(v.ParsedAs.String())
+		encodingVersions.Append(EncodingVersion)
+
+		segments.Append(true)
+		fallback.Append(true)
+		for _, d := range v.Decimal {
+			if n, ok := toDecimal128(d, int(scale)); ok {
+				decimals.Append(n)
+				strs.AppendNull()
+			} else {
+				decimals.AppendNull()
+				strs.Append(d.String())
+			}
+		}
+	}
+
+	return b.NewRecord(), nil
+}
+
+// VersionsFromArrowRecord returns the versions in a record made by
+// BuildArrowRecord. The segments are taken from the record rather than by
+// parsing the original versions again.
+func VersionsFromArrowRecord(rec array.Record) ([]*version.Version, error) {
+	if err := checkSchema(rec.Schema()); err != nil {
+		return nil, err
+	}
+
+	originals := rec.Column(0).(*array.String)
+	parsedAs := rec.Column(1).(*array.String)
+	segments := rec.Column(2).(*array.List)
+	decimals := segments.ListValues().(*array.Decimal128)
+	scale := int(segments.DataType().(*arrow.ListType).Elem().(*arrow.Decimal128Type).Scale)
+	fallback := rec.Column(3).(*array.List)
+	strs := fallback.ListValues().(*array.String)
+	encodingVersions := rec.Column(4).(*array.Int32)
+
+	vs := make([]*version.Version, rec.NumRows())
+	for i := range vs {
+		if encodingVersions.Value(i) != EncodingVersion {
+			return nil, fmt.Errorf("row %d has encoding version %d, but only version %d is supported", i, encodingVersions.Value(i), EncodingVersion)
+		}
+
+		pa, err := version.ParsedAsString(parsedAs.Value(i))
+		if err != nil || pa == version.Unknown {
+			return nil, fmt.Errorf("row %d has an unknown version type: %s", i, parsedAs.Value(i))
+		}
+
+		start, end := segments.Offsets()[i], segments.Offsets()[i+1]
+		if fallback.Offsets()[i] != start || fallback.Offsets()[i+1] != end {
+			return nil, fmt.Errorf("row %d has a different number of segments and fallback segments", i)
+		}
+		if start == end {
+			return nil, fmt.Errorf("row %d has no segments", i)
+		}
+
+		v := &version.Version{
+			Original: originals.Value(i),
+			Decimal:  make([]*decimal.Big, 0, end-start),
+			ParsedAs: pa,
+		}
+		for j := int(start); j < int(end); j++ {
+			switch {
+			case decimals.IsValid(j) && strs.IsNull(j):
+				v.Decimal = append(v.Decimal, fromDecimal128(decimals.Value(j), scale))
+			case decimals.IsNull(j) && strs.IsValid(j):
+				d, ok := new(decimal.Big).SetString(strs.Value(j))
+				if !ok {
+					return nil, fmt.Errorf("row %d has an invalid fallback segment: %s", i, strs.Value(j))
+				}
+				v.Decimal = append(v.Decimal, d)
+			default:
+				return nil, fmt.Errorf("row %d segment %d must be set in exactly one of %s and %s", i, j-int(start), segmentsColumn, fallbackColumn)
+			}
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+func checkSchema(schema *arrow.Schema) error {
+	expected := []struct {
+		name string
+		typ  arrow.Type
+		elem arrow.Type
+	}{
+		{originalColumn, arrow.STRING, 0},
+		{parsedAsColumn, arrow.STRING, 0},
+		{segmentsColumn, arrow.LIST, arrow.DECIMAL},
+		{fallbackColumn, arrow.LIST, arrow.STRING},
+		{encodingVersionColumn, arrow.INT32, 0},
+	}
+	if len(schema.Fields()) != len(expected) {
+		return fmt.Errorf("record has %d columns, not %d", len(schema.Fields()), len(expected))
+	}
+	for i, e := range expected {
+		f := schema.Field(i)
+		if f.Name != e.name || f.Type.ID() != e.typ {
+			return fmt.Errorf("column %d of record is %s %s, not %s %s", i, f.Name, f.Type, e.name, e.typ)
+		}
+		if e.typ == arrow.LIST {
+			if elem := f.Type.(*arrow.ListType).Elem(); elem.ID() != e.elem {
+				return fmt.Errorf("column %s of record is a list of %s, not %s", f.Name, elem, e.elem)
+			}
+		}
+	}
+	return nil
+}
+
+// integerDigits returns the number of digits before the decimal point of d.
+func integerDigits(d *decimal.Big) int {
+	if n := d.Precision() - d.Scale(); n > 0 {
+		return n
+	}
+	return 0
+}
+
+// toDecimal128 returns d as a decimal128 with the given scale, or false if d
+// does not fit.
+func toDecimal128(d *decimal.Big, scale int) (decimal128.Num, bool) {
+	if d.Scale() > scale || integerDigits(d)+scale > maxPrecision {
+		return decimal128.Num{}, false
+	}
+
+	// d is its mantissa × 10^-d.Scale(), so the value at the new scale is
+	// the mantissa × 10^(scale-d.Scale()).
+	mantissa := new(decimal.Big).Copy(d)
+	mantissa.SetScale(0)
+	unscaled := mantissa.Int(nil)
+	unscaled.Mul(unscaled, new(big.Int).Exp(ten, big.NewInt(int64(scale-d.Scale())), nil))
+
+	if unscaled.Sign() < 0 {
+		unscaled.Add(unscaled, two128)
+	}
+	hi := new(big.Int).Rsh(unscaled, 64)
+	lo := new(big.Int).Mod(unscaled, two64)
+	return decimal128.New(int64(hi.Uint64()), lo.Uint64()), true
+}
+
+// fromDecimal128 returns the decimal with the given unscaled value and scale,
+// without trailing zeros after the decimal point.
+func fromDecimal128(n decimal128.Num, scale int) *decimal.Big {
+	unscaled := new(big.Int).SetInt64(n.HighBits())
+	unscaled.Mul(unscaled, two64)
+	unscaled.Add(unscaled, new(big.Int).SetUint64(n.LowBits()))
+
+	q, r := new(big.Int), new(big.Int)
+	for scale > 0 && unscaled.Sign() != 0 {
+		q.QuoRem(unscaled, ten, r)
+		if r.Sign() != 0 {
+			break
+		}
+		unscaled.Set(q)
+		scale--
+	}
+	if unscaled.Sign() == 0 {
+		scale = 0
+	}
+	return new(decimal.Big).SetBigMantScale(unscaled, scale)
+}
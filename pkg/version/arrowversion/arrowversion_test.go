@@ -0,0 +1,216 @@
+package arrowversion
+
+import (
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// This is a copy of pythonTestStrings from the version package's tests.
+var pythonTestStrings = []string{
+	// Legacy version tests, implicit epoch of -1
+	"  hmm",
+	"a cat is fine too",
+	"a",
+	"b",
+	"foobar",
+	"lolwut",
+	"0000000011g",
+	"1.13++",
+	"000000011g",
+	"2.0b1pl0",
+	"2e6",
+	"2g6",
+	"2.6.0-0.1pre6",
+	"2.6.0-0.1-pre7",
+	"2.6.0-0.1",
+	"2.6.0-0.2",
+	"2.6.0-0.92",
+	"2.7.0-0.92",
+	"2.16.0-0.92",
+	"3.2pl0",
+	"3.4j",
+	"5.5.kw",
+	"11g",
+	"012g",
+
+	// Implicit epoch of 0
+	"1.0.dev0",
+	"1.0.dev456",
+	"1.0a0",
+	"1.0a1",
+	"1.0a2.dev456",
+	"1.0a12.dev456",
+	"1.0a12",
+	"1.0b1.dev456",
+	"1.0b2",
+	"1.0b2.post345.dev456",
+	"1.0b2.post345",
+	"1.0b2-346",
+	"1.0rc1.dev456",
+	"1.0rc1",
+	"1.0rc2",
+	"1.0c3",
+	"1.0",
+	"1.0+abc.5",
+	"1.0+abc.7",
+	"1.0+5",
+	"1.0.post456.dev34",
+	"1.0.post456",
+	"1.0.1.2.3.4.5.6.7.8.9.1.2.3.4",
+	"1.1.dev1",
+	"1.2",
+	"1.2+123abc",
+	"1.2+123abc456",
+	"1.2+abc",
+	"1.2+abc123",
+	"1.2+abc123def",
+	"1.2+abcd",
+	"1.2+def",
+	"1.2+1",
+	"1.2+05",
+	"1.2+12",
+	"1.2+25",
+	"1.2+123",
+	"1.2+123.abc",
+	"1.2+123-def",
+	"1.2+123_gg",
+	"1.2+0124",
+	"1.2+1234.abc",
+	"1.2+123456",
+	"1.2.r32+123456",
+	"1.2.rev33+123456",
+
+	// Explicit epoch of 1
+	"1!1.0.dev456",
+	"1!1.0a1",
+	"1!1.0a2.dev456",
+	"1!1.0a12.dev456",
+	"1!1.0a12",
+	"1!1.0b1.dev456",
+	"1!1.0b2",
+	"1!1.0b2.post345.dev456",
+	"1!1.0b2.post345",
+	"1!1.0b2-346",
+	"1!1.0c1.dev456",
+	"1!1.0c1",
+	"1!1.0rc2",
+	"1!1.0c3",
+	"1!1.0",
+	"1!1.0.post456.dev34",
+	"1!1.0.post456",
+	"1!1.1.dev1",
+	"1!1.2+123abc",
+	"1!1.2+123abc456",
+	"1!1.2+abc",
+	"1!1.2+abc123",
+	"1!1.2+abc123def",
+	"1!1.2+1234.abc",
+	"1!1.2+123456",
+	"1!1.2.r32+123456",
+	"1!1.2.rev33+123456",
+}
+
+func assertSameVersions(t *testing.T, expected, actual []*version.Version) {
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		assert.Equal(t, expected[i].Original, actual[i].Original)
+		assert.Equal(t, expected[i].ParsedAs, actual[i].ParsedAs, expected[i].Original)
+		if assert.Len(t, actual[i].Decimal, len(expected[i].Decimal), expected[i].Original) {
+			for j := range expected[i].Decimal {
+				assert.Equal(t, 0, expected[i].Decimal[j].Cmp(actual[i].Decimal[j]),
+					"segment %d of %s is %s, not %s", j, expected[i].Original, actual[i].Decimal[j], expected[i].Decimal[j])
+			}
+		}
+	}
+}
+
+func TestRoundTripPythonCorpus(t *testing.T) {
+	var vs []*version.Version
+	for _, s := range pythonTestStrings {
+		v, err := version.ParsePython(s)
+		require.NoError(t, err, s)
+		vs = append(vs, v)
+	}
+
+	rec, err := BuildArrowRecord(vs)
+	require.NoError(t, err)
+	defer rec.Release()
+
+	assert.Equal(t, int64(len(vs)), rec.NumRows())
+	fallback := rec.Column(3).(*array.List).ListValues()
+	assert.True(t, fallback.NullN() < fallback.Len(), "some word segments need the fallback column")
+	assert.True(t, fallback.NullN() > 0, "other segments fit in the decimal column")
+
+	decoded, err := VersionsFromArrowRecord(rec)
+	require.NoError(t, err)
+	assertSameVersions(t, vs, decoded)
+}
+
+func TestRecordColumns(t *testing.T) {
+	vs := []*version.Version{}
+	for _, s := range []string{"1.2.3", "1.0.0-alpha"} {
+		v, err := version.ParseSemVer(s)
+		require.NoError(t, err)
+		vs = append(vs, v)
+	}
+	perl, err := version.ParsePerl("0.000000001")
+	require.NoError(t, err)
+	vs = append(vs, perl)
+
+	rec, err := BuildArrowRecord(vs)
+	require.NoError(t, err)
+	defer rec.Release()
+
+	names := []string{}
+	for _, f := range rec.Schema().Fields() {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"original", "parsed_as", "segments", "segments_fallback", "encoding_version"}, names)
+
+	assert.Equal(t, "1.0.0-alpha", rec.Column(0).(*array.String).Value(1))
+	assert.Equal(t, "SemVer", rec.Column(1).(*array.String).Value(1))
+	assert.Equal(t, "PerlDecimal", rec.Column(1).(*array.String).Value(2))
+	assert.Equal(t, int32(EncodingVersion), rec.Column(4).(*array.Int32).Value(0))
+
+	segments := rec.Column(2).(*array.List)
+	assert.Equal(t, []int32{0, 3, 9, 13}, segments.Offsets())
+	decimalType := segments.DataType().(*arrow.ListType).Elem().(*arrow.Decimal128Type)
+	assert.Equal(t, int32(38), decimalType.Precision)
+
+	decoded, err := VersionsFromArrowRecord(rec)
+	require.NoError(t, err)
+	assertSameVersions(t, vs, decoded)
+	assert.Equal(t, []string{"1", "0", "0", "-1", "97.108112104097", "-1"}, decoded[1].Segments())
+}
+
+func TestBuildArrowRecordNilVersion(t *testing.T) {
+	_, err := BuildArrowRecord([]*version.Version{nil})
+	assert.Error(t, err)
+}
+
+func TestVersionsFromArrowRecordErrors(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "original", Type: arrow.BinaryTypes.String}}, nil)
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	b.Field(0).(*array.StringBuilder).Append("1.0")
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	_, err := VersionsFromArrowRecord(rec)
+	assert.Error(t, err)
+
+	v, err := version.ParseSemVer("1.2.3")
+	require.NoError(t, err)
+	v.ParsedAs = version.Unknown
+	rec, err = BuildArrowRecord([]*version.Version{v})
+	require.NoError(t, err)
+	defer rec.Release()
+	_, err = VersionsFromArrowRecord(rec)
+	assert.Error(t, err, "unknown version types are rejected")
+}
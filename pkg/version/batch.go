@@ -0,0 +1,149 @@
+package version
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// BatchOption configures ParseAll and ParseStream.
+type BatchOption func(*batchOptions)
+
+type batchOptions struct {
+	workers      int
+	parseOptions []Option
+}
+
+func applyBatchOptions(opts []BatchOption) batchOptions {
+	o := batchOptions{workers: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.workers < 1 {
+		o.workers = 1
+	}
+	return o
+}
+
+// WithWorkers sets the number of goroutines used to parse versions. The
+// default is runtime.GOMAXPROCS(0). Values less than one are treated as one.
+func WithWorkers(n int) BatchOption {
+	return func(o *batchOptions) {
+		o.workers = n
+	}
+}
+
+// WithParseOptions sets the options passed to the parsing func for each
+// version.
+func WithParseOptions(opts ...Option) BatchOption {
+	return func(o *batchOptions) {
+		o.parseOptions = opts
+	}
+}
+
+// ParseAll parses every string in versions as the given type (see Parse),
+// using multiple goroutines. It returns a slice of Versions and a slice of
+// errors, both the same length as versions. For each index, exactly one of
+// the two slices has a non-nil value at that index.
+func ParseAll(pa ParsedAs, versions []string, opts ...BatchOption) ([]*Version, []error) {
+	o := applyBatchOptions(opts)
+
+	parsed := make([]*Version, len(versions))
+	errs := make([]error, len(versions))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < o.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				parsed[i], errs[i] = Parse(pa, versions[i], o.parseOptions...)
+			}
+		}()
+	}
+
+	for i := range versions {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return parsed, errs
+}
+
+// Result is a single result from ParseStream.
+type Result struct {
+	// Index is the position of the input in the stream, starting at zero.
+	Index int
+	// Input is the string that was parsed.
+	Input string
+	// Version is the parsed version. This is nil if Err is not nil.
+	Version *Version
+	// Err is the error from parsing the input, if any.
+	Err error
+}
+
+// ParseStream parses each string received from in as the given type (see
+// Parse), using multiple goroutines, and sends a Result for each one to the
+// returned channel. Results are sent as soon as they are ready, so they may
+// not be in the same order as the input. Use Result.Index to restore the
+// original order if needed.
+//
+// The returned channel is closed once in has been closed and every result has
+// been sent, or once ctx is done. In the latter case some inputs may not have
+// a result.
+func ParseStream(ctx context.Context, pa ParsedAs, in <-chan string, opts ...BatchOption) <-chan Result {
+	o := applyBatchOptions(opts)
+
+	type input struct {
+		index int
+		s     string
+	}
+	inputs := make(chan input)
+	out := make(chan Result, o.workers)
+
+	go func() {
+		defer close(inputs)
+		i := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case s, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case inputs <- input{index: i, s: s}:
+					i++
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < o.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range inputs {
+				v, err := Parse(pa, item.s, o.parseOptions...)
+				select {
+				case out <- Result{Index: item.index, Input: item.s, Version: v, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
@@ -0,0 +1,102 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAll(t *testing.T) {
+	inputs := []string{"1.2.3", "not a version", "1.0.0-rc.1", "1.0", "2.0.0"}
+
+	for _, workers := range []int{0, 1, 2, 8} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			parsed, errs := ParseAll(SemVer, inputs, WithWorkers(workers))
+			require.Len(t, parsed, len(inputs))
+			require.Len(t, errs, len(inputs))
+
+			for i, input := range inputs {
+				expected, expectedErr := ParseSemVer(input)
+				assert.Equal(t, expected, parsed[i], "result %d is in input order", i)
+				assert.Equal(t, expectedErr, errs[i], "error %d is in input order", i)
+			}
+		})
+	}
+}
+
+func TestParseAllWithParseOptions(t *testing.T) {
+	parsed, errs := ParseAll(PHP, []string{"1.2.3.4.5"}, WithParseOptions(WithExtraSegments(5)))
+	assert.NoError(t, errs[0])
+	assert.Equal(t, "1.2.3.4.5", parsed[0].Original)
+}
+
+func TestParseAllEmpty(t *testing.T) {
+	parsed, errs := ParseAll(Generic, nil)
+	assert.Empty(t, parsed)
+	assert.Empty(t, errs)
+}
+
+func TestParseStream(t *testing.T) {
+	in := make(chan string)
+	go func() {
+		for _, s := range pythonTestStrings {
+			in <- s
+		}
+		close(in)
+	}()
+
+	results := make([]Result, len(pythonTestStrings))
+	count := 0
+	for r := range ParseStream(context.Background(), PythonPEP440, in, WithWorkers(4)) {
+		results[r.Index] = r
+		count++
+	}
+	require.Equal(t, len(pythonTestStrings), count, "got a result for each input")
+
+	for i, s := range pythonTestStrings {
+		r := results[i]
+		assert.Equal(t, s, r.Input)
+
+		expected, err := ParsePython(s)
+		require.NoError(t, err)
+		if expected.ParsedAs == PythonPEP440 {
+			assert.NoError(t, r.Err)
+			assert.Equal(t, expected, r.Version)
+		} else {
+			assert.Error(t, r.Err, "%s is not a PEP440 version", s)
+			assert.Nil(t, r.Version)
+		}
+	}
+}
+
+func TestParseStreamCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// The input channel is never closed, so the output channel can only be
+	// closed by cancelling the context.
+	in := make(chan string)
+	out := ParseStream(ctx, Generic, in, WithWorkers(2))
+
+	in <- "1.0"
+	r := <-out
+	assert.Equal(t, "1.0", r.Input)
+
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for range out {
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("output channel was not closed after the context was cancelled")
+	}
+}
@@ -1,7 +1,15 @@
 package version
 
 import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/ericlagergren/decimal"
 )
 
 func BenchmarkCompare(b *testing.B) {
@@ -22,3 +30,643 @@ func BenchmarkCompare(b *testing.B) {
 		}
 	}
 }
+
+var genericBenchmarkStrings = []string{
+	"0",
+	"1.0",
+	"1.2.3",
+	"1.1.0-pre1",
+	"1.1.0c",
+	"1.0.0-alpha.beta",
+	"2021.04.15",
+	"10 Generic 142910-17",
+	"4.8.23abd",
+	"v2.7.18-rc1+build.5",
+	"小寸-1.1",
+	"1 2 3  4",
+}
+
+// BenchmarkCompareMixed compares versions from every parser with each other,
+// which covers many more combinations of decimal sizes and scales than a
+// single parser does.
+func BenchmarkCompareMixed(b *testing.B) {
+	var versions []*Version
+	add := func(parse func(string) (*Version, error), inputs []string) {
+		for _, s := range inputs {
+			v, err := parse(s)
+			if err != nil {
+				b.Fatal(err)
+			}
+			versions = append(versions, v)
+		}
+	}
+	add(func(s string) (*Version, error) { return ParseGeneric(s) }, genericBenchmarkStrings)
+	add(ParseSemVer, testParseSemVerOrderInputs[:10])
+	add(ParsePython, pythonTestStrings[:10])
+	add(ParsePerl, perlDecimalBenchmarkStrings[:5])
+	add(ParsePerl, perlVStringBenchmarkStrings[:5])
+	add(func(s string) (*Version, error) { return ParsePHP(s) }, testParsePHPOrderInputs[:10])
+	add(ParseRuby, rubyTestStrings[:10])
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, v1 := range versions {
+			for _, v2 := range versions {
+				Compare(v1, v2)
+			}
+		}
+	}
+}
+
+// Corpora for the parsers that don't have one elsewhere in the tests.
+var (
+	perlDecimalBenchmarkStrings = []string{
+		"1", "1.", ".2", "1.2", "1.02", "1.002", "1.0023", "1.00203",
+		"1.002003", "1.00200304", "1.00200", "1.002_003", "0.001_001",
+	}
+	perlVStringBenchmarkStrings = []string{
+		"v1", "v1.2", "v1.2.3", "v1.2.3_4", "1.2.3", "v1.02.003",
+		"v10.20.30.40", "1.2.3.4.5",
+	}
+)
+
+func benchmarkParser(b *testing.B, parse func(string) (*Version, error), inputs []string) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, s := range inputs {
+			if _, err := parse(s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkParseGeneric(b *testing.B) {
+	benchmarkParser(b, func(s string) (*Version, error) { return ParseGeneric(s) }, genericBenchmarkStrings)
+}
+
+func BenchmarkParseSemVer(b *testing.B) {
+	benchmarkParser(b, ParseSemVer, testParseSemVerOrderInputs)
+}
+
+func BenchmarkParsePython(b *testing.B) {
+	benchmarkParser(b, ParsePython, pythonTestStrings)
+}
+
+func BenchmarkParsePerlDecimal(b *testing.B) {
+	benchmarkParser(b, ParsePerl, perlDecimalBenchmarkStrings)
+}
+
+func BenchmarkParsePerlVString(b *testing.B) {
+	benchmarkParser(b, ParsePerl, perlVStringBenchmarkStrings)
+}
+
+func BenchmarkParsePHP(b *testing.B) {
+	benchmarkParser(b, func(s string) (*Version, error) { return ParsePHP(s) }, testParsePHPOrderInputs)
+}
+
+func BenchmarkParseRuby(b *testing.B) {
+	benchmarkParser(b, ParseRuby, rubyTestStrings)
+}
+
+func BenchmarkParseGo(b *testing.B) {
+	benchmarkParser(b, func(s string) (*Version, error) { return ParseGo(s) }, goModuleVersions)
+}
+
+func BenchmarkParsePythonPEP440(b *testing.B) {
+	var pep440 []string
+	for _, s := range pythonTestStrings {
+		if _, err := parsePEP440Fast(s); err == nil {
+			pep440 = append(pep440, s)
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range pep440 {
+			if _, err := ParsePython(s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkMatchPEP440(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, s := range pythonTestStrings {
+			_, _ = matchPEP440(s)
+		}
+	}
+}
+
+func BenchmarkMatchPEP440Regex(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, s := range pythonTestStrings {
+			findPEP440Matches(s)
+		}
+	}
+}
+
+// legacyPythonBenchmarkStrings returns the lowercased members of
+// pythonTestStrings that are not PEP440 versions, which is what
+// parseLegacyPython sees in practice.
+func legacyPythonBenchmarkStrings(b *testing.B) []string {
+	var legacy []string
+	for _, s := range pythonTestStrings {
+		if _, err := parsePEP440Fast(s); err != nil {
+			legacy = append(legacy, strings.ToLower(s))
+		}
+	}
+	if len(legacy) == 0 {
+		b.Fatal("pythonTestStrings has no legacy versions")
+	}
+	return legacy
+}
+
+func BenchmarkSplitLegacyPythonSegments(b *testing.B) {
+	legacy := legacyPythonBenchmarkStrings(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range legacy {
+			splitLegacyPythonSegments(s)
+		}
+	}
+}
+
+func BenchmarkParseLegacyPython(b *testing.B) {
+	legacy := legacyPythonBenchmarkStrings(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range legacy {
+			if _, err := parseLegacyPython(s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkParseAll(b *testing.B) {
+	var inputs []string
+	for len(inputs) < 10000 {
+		inputs = append(inputs, pythonTestStrings...)
+	}
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ParseAll(PythonPEP440, inputs, WithWorkers(workers))
+			}
+		})
+	}
+}
+
+// A 20 rune word, as found in the wordier generic versions.
+const benchmarkWord = "snapshotbuildrelease"
+
+func BenchmarkToDecimalString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		toDecimalString(benchmarkWord)
+	}
+}
+
+func BenchmarkASCIIToDecimalString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		asciiToDecimalString(benchmarkWord)
+	}
+}
+
+// normalizeDecimalBenchmarkStrings are the kinds of strings normalizeDecimal
+// is given: plain numbers, numbers with leading and trailing zeros, and
+// converted words.
+var normalizeDecimalBenchmarkStrings = []string{
+	"1", "20", "0", "000", "007", "1.5", "1.50", "01.0", ".5", "-26",
+	"115.0000000110000000097000000011600000001040000000111",
+}
+
+func BenchmarkNormalizeDecimal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, s := range normalizeDecimalBenchmarkStrings {
+			normalizeDecimal(s)
+		}
+	}
+}
+
+func BenchmarkSplitRubySegments(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, s := range rubyTestStrings {
+			splitSegments(s)
+		}
+	}
+}
+
+var (
+	syntheticVersionsOnce sync.Once
+	syntheticVersions     []*Version
+)
+
+// millionSyntheticVersions returns 1M versions with 2 to 5 segments, some of
+// which have a negative pre-release segment. The slice is shared and must be
+// copied before sorting.
+func millionSyntheticVersions() []*Version {
+	syntheticVersionsOnce.Do(func() {
+		r := rand.New(rand.NewSource(1))
+		syntheticVersions = make([]*Version, 1000000)
+		for i := range syntheticVersions {
+			segments := make([]*decimal.Big, 2+r.Intn(4))
+			for j := range segments {
+				segments[j] = decimal.New(int64(r.Intn(30)), 0)
+			}
+			if r.Intn(4) == 0 {
+				segments[len(segments)-1] = decimal.New(-int64(1+r.Intn(4)), 0)
+			}
+			syntheticVersions[i] = &Version{Decimal: segments}
+		}
+	})
+	return syntheticVersions
+}
+
+func BenchmarkSortMillion(b *testing.B) {
+	versions := millionSyntheticVersions()
+	vs := make([]*Version, len(versions))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(vs, versions)
+		if err := Sort(vs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSortByKeyMillion(b *testing.B) {
+	versions := millionSyntheticVersions()
+	vs := make([]*Version, len(versions))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(vs, versions)
+		if err := SortByKey(vs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+var (
+	fourMillionVersionsOnce sync.Once
+	fourMillionVersions     []*Version
+)
+
+// fourMillionSyntheticVersions returns 4M versions made in the same way as
+// millionSyntheticVersions. To keep the memory used down, all of the versions
+// share a small set of decimals. The slice is shared and must be copied before
+// sorting.
+func fourMillionSyntheticVersions() []*Version {
+	fourMillionVersionsOnce.Do(func() {
+		decimals := make([]*decimal.Big, 30)
+		for i := range decimals {
+			decimals[i] = decimal.New(int64(i), 0)
+		}
+		negatives := make([]*decimal.Big, 4)
+		for i := range negatives {
+			negatives[i] = decimal.New(-int64(i+1), 0)
+		}
+
+		r := rand.New(rand.NewSource(1))
+		fourMillionVersions = make([]*Version, 4000000)
+		for i := range fourMillionVersions {
+			segments := make([]*decimal.Big, 2+r.Intn(4))
+			for j := range segments {
+				segments[j] = decimals[r.Intn(len(decimals))]
+			}
+			if r.Intn(4) == 0 {
+				segments[len(segments)-1] = negatives[r.Intn(len(negatives))]
+			}
+			fourMillionVersions[i] = &Version{Decimal: segments}
+		}
+	})
+	return fourMillionVersions
+}
+
+func BenchmarkSortParallelFourMillion(b *testing.B) {
+	versions := fourMillionSyntheticVersions()
+	vs := make([]*Version, len(versions))
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				copy(vs, versions)
+				if err := SortParallel(vs, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// zipfianVersions returns 10000 versions drawn from pythonTestStrings with a
+// Zipfian distribution, so a few versions are very common, as they are in
+// dependency graphs.
+func zipfianVersions() []string {
+	r := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(r, 1.1, 1, uint64(len(pythonTestStrings)-1))
+	versions := make([]string, 10000)
+	for i := range versions {
+		versions[i] = pythonTestStrings[zipf.Uint64()]
+	}
+	return versions
+}
+
+func BenchmarkParseZipfian(b *testing.B) {
+	versions := zipfianVersions()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range versions {
+			if _, err := ParsePython(s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkCacheParseZipfian(b *testing.B) {
+	versions := zipfianVersions()
+	types := make([]ParsedAs, len(versions))
+	for i, s := range versions {
+		v, err := ParsePython(s)
+		if err != nil {
+			b.Fatal(err)
+		}
+		types[i] = v.ParsedAs
+	}
+	c := NewCache(32)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, s := range versions {
+			if _, err := c.Parse(types[j], s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.StopTimer()
+	stats := c.Stats()
+	b.ReportMetric(float64(stats.Hits)/float64(stats.Hits+stats.Misses), "hit-rate")
+}
+
+// peakHeapWriter discards what is written to it, sampling how far the heap
+// has grown beyond its size when the writer was created every so often.
+type peakHeapWriter struct {
+	writes   int
+	baseline uint64
+	peak     uint64
+}
+
+func newPeakHeapWriter() *peakHeapWriter {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return &peakHeapWriter{baseline: stats.HeapAlloc}
+}
+
+func (w *peakHeapWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes%64 == 0 {
+		w.sample()
+	}
+	return len(p), nil
+}
+
+func (w *peakHeapWriter) sample() {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	if stats.HeapAlloc > w.baseline && stats.HeapAlloc-w.baseline > w.peak {
+		w.peak = stats.HeapAlloc - w.baseline
+	}
+}
+
+// BenchmarkEncodeJSON reports the peak heap growth seen while encoding. The
+// slices reuse a few Versions so that the heap is small and garbage doesn't
+// hide the memory that is actually held on to. The growth stays flat for
+// EncodeJSONStream as the number of versions goes up, while json.Marshal has
+// to hold the whole output in memory.
+func BenchmarkEncodeJSON(b *testing.B) {
+	var base []*Version
+	for _, s := range pythonTestStrings[:16] {
+		v, err := ParsePython(s)
+		if err != nil {
+			b.Fatal(err)
+		}
+		base = append(base, v)
+	}
+
+	for _, n := range []int{10000, 100000, 1000000} {
+		versions := make([]*Version, n)
+		for i := range versions {
+			versions[i] = base[i%len(base)]
+		}
+
+		b.Run(fmt.Sprintf("Marshal/%d", n), func(b *testing.B) {
+			w := newPeakHeapWriter()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				out, err := json.Marshal(versions)
+				if err != nil {
+					b.Fatal(err)
+				}
+				w.sample()
+				if _, err := w.Write(out); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(w.peak)/(1<<20), "peak-heap-growth-MiB")
+		})
+
+		b.Run(fmt.Sprintf("Stream/%d", n), func(b *testing.B) {
+			w := newPeakHeapWriter()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := EncodeJSONStream(w, versions); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(w.peak)/(1<<20), "peak-heap-growth-MiB")
+		})
+	}
+}
+
+// heapInUse returns the size of the live heap after a GC.
+func heapInUse() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+// BenchmarkVersionMemory reports the bytes held per version for 100k generic
+// versions, not counting their Original strings, as Versions and as a
+// CompactSet.
+func BenchmarkVersionMemory(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	inputs := make([]string, 100000)
+	for i := range inputs {
+		inputs[i] = fmt.Sprintf("%d.%d.%d", r.Intn(20), r.Intn(100), r.Intn(2000))
+	}
+
+	b.Run("Version", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			before := heapInUse()
+			versions := make([]*Version, len(inputs))
+			for j, s := range inputs {
+				v, err := ParseGeneric(s)
+				if err != nil {
+					b.Fatal(err)
+				}
+				versions[j] = v
+			}
+			after := heapInUse()
+			b.ReportMetric(float64(after-before)/float64(len(inputs)), "bytes/version")
+			runtime.KeepAlive(versions)
+		}
+	})
+
+	b.Run("CompactSet", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			before := heapInUse()
+			var set CompactSet
+			for _, s := range inputs {
+				v, err := ParseGeneric(s)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if err := set.Add(v); err != nil {
+					b.Fatal(err)
+				}
+			}
+			after := heapInUse()
+			b.ReportMetric(float64(after-before)/float64(len(inputs)), "bytes/version")
+			runtime.KeepAlive(&set)
+		}
+	})
+}
+
+// BenchmarkDetectScheme checks which of semver, perl and php each python and
+// ruby version matches, as auto-detection does, with and without the
+// prefilters in front of the regexes.
+func BenchmarkDetectScheme(b *testing.B) {
+	var inputs []string
+	inputs = append(inputs, pythonTestStrings...)
+	inputs = append(inputs, rubyTestStrings...)
+
+	regexOnly := []func(string) bool{
+		semVerRegEx.MatchString,
+		func(s string) bool { return decimalRegex.MatchString(s) || dottedDecimalRegex.MatchString(s) },
+		func(s string) bool {
+			_, err := normalizePHPWithRegexes(s, phpClassicalSegments)
+			return err == nil
+		},
+	}
+	prefiltered := []func(string) bool{
+		func(s string) bool { return semVerMayMatch(s) && semVerRegEx.MatchString(s) },
+		func(s string) bool {
+			return perlMayMatch(s) && (decimalRegex.MatchString(s) || dottedDecimalRegex.MatchString(s))
+		},
+		func(s string) bool {
+			_, err := normalizePHPMaxSegments(s, phpClassicalSegments)
+			return err == nil
+		},
+	}
+
+	for _, bm := range []struct {
+		name     string
+		matchers []func(string) bool
+	}{
+		{"regex", regexOnly},
+		{"prefilter", prefiltered},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, s := range inputs {
+					for _, match := range bm.matchers {
+						match(s)
+					}
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkParseMillion parses 1M generic versions in batches of 10k, keeping
+// each batch until the next one is parsed, as a pipeline does. Parse uses
+// ParseGeneric and leaves each batch to the garbage collector, and Parser
+// releases each Version back to a Parser before parsing into it again. Along
+// with allocations, it reports the number of GCs and the total GC pause time
+// for each op.
+func BenchmarkParseMillion(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	inputs := make([]string, 1000000)
+	for i := range inputs {
+		inputs[i] = fmt.Sprintf("%d.%d.%d", r.Intn(20), r.Intn(100), r.Intn(2000))
+	}
+	const batchSize = 10000
+
+	b.Run("Parse", func(b *testing.B) {
+		batch := make([]*Version, batchSize)
+		benchmarkWithGCStats(b, func() {
+			for start := 0; start < len(inputs); start += batchSize {
+				for j := range batch {
+					v, err := ParseGeneric(inputs[start+j])
+					if err != nil {
+						b.Fatal(err)
+					}
+					batch[j] = v
+				}
+			}
+		})
+	})
+
+	b.Run("Parser", func(b *testing.B) {
+		var p Parser
+		batch := make([]*Version, batchSize)
+		benchmarkWithGCStats(b, func() {
+			for start := 0; start < len(inputs); start += batchSize {
+				for j := range batch {
+					p.ReleaseVersion(batch[j])
+					v, err := p.Parse(Generic, inputs[start+j])
+					if err != nil {
+						b.Fatal(err)
+					}
+					batch[j] = v
+				}
+			}
+		})
+	})
+}
+
+func benchmarkWithGCStats(b *testing.B, f func()) {
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f()
+	}
+	b.StopTimer()
+	runtime.ReadMemStats(&after)
+	b.ReportMetric(float64(after.NumGC-before.NumGC)/float64(b.N), "gcs/op")
+	b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/float64(b.N)/1e6, "gc-pause-ms/op")
+}
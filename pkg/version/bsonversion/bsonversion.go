@@ -0,0 +1,129 @@
+// Package bsonversion stores Versions from the version package in MongoDB.
+// It is a separate package so that programs which only import the version
+// package don't depend on the MongoDB driver.
+package bsonversion
+
+import (
+	"fmt"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/ericlagergren/decimal"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Version wraps a *version.Version so that it implements bson.Marshaler and
+// bson.Unmarshaler. It is stored as a document like this:
+//
+//	{
+//		"original": "1.2.3",
+//		"parsed_as": "SemVer",
+//		"segments": [NumberDecimal("1"), NumberDecimal("2"), NumberDecimal("3")]
+//	}
+//
+// Each segment is stored as a Decimal128 value so that MongoDB compares
+// segments numerically. Decimal128 has 34 digits of precision, which is not
+// enough for some segments, such as the ones that ParseGeneric makes from
+// long words. Those segments are stored as strings instead, and the document
+// has a "string_segments" field set to true, since MongoDB does not order
+// strings and numbers the same way as version.Compare.
+//
+// When sorting on an array field MongoDB uses the lowest element of each
+// array, not the order of the elements, so to sort on a version either
+// project the segments into separate fields or sort on "segments.0",
+// "segments.1" and so on.
+//
+// Build metadata is stored in a "build_metadata" field when there is any.
+type Version struct {
+	*version.Version
+}
+
+type document struct {
+	Original       string        `bson:"original"`
+	ParsedAs       string        `bson:"parsed_as"`
+	Segments       []interface{} `bson:"segments"`
+	StringSegments bool          `bson:"string_segments,omitempty"`
+	BuildMetadata  string        `bson:"build_metadata,omitempty"`
+}
+
+type rawDocument struct {
+	Original      string          `bson:"original"`
+	ParsedAs      string          `bson:"parsed_as"`
+	Segments      []bson.RawValue `bson:"segments"`
+	BuildMetadata string          `bson:"build_metadata"`
+}
+
+// MarshalBSON implements bson.Marshaler.
+func (v Version) MarshalBSON() ([]byte, error) {
+	if v.Version == nil {
+		return nil, fmt.Errorf("cannot marshal a nil version to BSON")
+	}
+
+	doc := document{
+		Original:      v.Original,
+		ParsedAs:      v.ParsedAs.String(),
+		Segments:      make([]interface{}, len(v.Decimal)),
+		BuildMetadata: v.BuildMetadata,
+	}
+	for i, d := range v.Decimal {
+		s := d.String()
+		d128, err := primitive.ParseDecimal128(s)
+		if err != nil {
+			// The segment has too many digits, or an exponent that is too
+			// large or small, to be stored as a Decimal128.
+			doc.Segments[i] = s
+			doc.StringSegments = true
+			continue
+		}
+		doc.Segments[i] = d128
+	}
+
+	return bson.Marshal(doc)
+}
+
+// UnmarshalBSON implements bson.Unmarshaler. The version's segments are taken
+// from the document rather than by parsing the original version again, so
+// they are the same as when the version was stored even if this module's
+// parsers have changed since then.
+func (v *Version) UnmarshalBSON(data []byte) error {
+	var doc rawDocument
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	pa, err := version.ParsedAsString(doc.ParsedAs)
+	if err != nil || pa == version.Unknown {
+		return fmt.Errorf("unknown version type in BSON document for version %s: %s", doc.Original, doc.ParsedAs)
+	}
+	if len(doc.Segments) == 0 {
+		return fmt.Errorf("BSON document for version %s has no segments", doc.Original)
+	}
+
+	segments := make([]*decimal.Big, len(doc.Segments))
+	for i, rv := range doc.Segments {
+		var s string
+		switch rv.Type {
+		case bsontype.Decimal128:
+			s = rv.Decimal128().String()
+		case bsontype.String:
+			s = rv.StringValue()
+		default:
+			return fmt.Errorf("segment %d in BSON document for version %s is a %s, not a decimal or string", i, doc.Original, rv.Type)
+		}
+
+		d, ok := new(decimal.Big).SetString(s)
+		if !ok {
+			return fmt.Errorf("segment %d in BSON document for version %s is not a number: %s", i, doc.Original, s)
+		}
+		segments[i] = d
+	}
+
+	v.Version = &version.Version{
+		Original:      doc.Original,
+		Decimal:       segments,
+		ParsedAs:      pa,
+		BuildMetadata: doc.BuildMetadata,
+	}
+	return nil
+}
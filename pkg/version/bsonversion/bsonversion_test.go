@@ -0,0 +1,174 @@
+package bsonversion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func parseOrFatal(t *testing.T, parse func(string) (*version.Version, error), s string) *version.Version {
+	v, err := parse(s)
+	require.NoError(t, err, "no error parsing %s", s)
+	return v
+}
+
+func testVersions(t *testing.T) []*version.Version {
+	var vs []*version.Version
+	for _, s := range []string{"1.2.3", "1.0.0-alpha.1", "1.2.3-4-foo", "1.2.3-r100+build.5"} {
+		vs = append(vs, parseOrFatal(t, version.ParseSemVer, s))
+	}
+	for _, s := range []string{"1", "1.2.3", "v1.2.3", "1.2_3", "0.000001"} {
+		vs = append(vs, parseOrFatal(t, version.ParsePerl, s))
+	}
+	for _, s := range []string{"1.0.0", "1.2.3-beta2", "v2.0.0-RC1", "1.0.0-patch.1", "1.0.0.0-dev"} {
+		vs = append(vs, parseOrFatal(t, func(s string) (*version.Version, error) { return version.ParsePHP(s) }, s))
+	}
+	for _, s := range []string{"1.0", "1!1.0rc1.post2.dev3", "1.0+local.7", "1.0-foo"} {
+		vs = append(vs, parseOrFatal(t, version.ParsePython, s))
+	}
+	for _, s := range []string{"1.2.3", "1.2.3.pre.1", "1.2.a"} {
+		vs = append(vs, parseOrFatal(t, version.ParseRuby, s))
+	}
+	for _, s := range []string{"1.2.3", "2020-10-01", "1.0-alpha", "1.0-beta.2"} {
+		vs = append(vs, parseOrFatal(t, func(s string) (*version.Version, error) { return version.ParseGeneric(s) }, s))
+	}
+
+	v, err := version.ParseGeneric("1.2.3+20230917.abcdef", version.WithIgnoreBuildMetadata())
+	require.NoError(t, err)
+	return append(vs, v)
+}
+
+func assertSameVersion(t *testing.T, expected, actual *version.Version) {
+	assert.Equal(t, expected.Original, actual.Original)
+	assert.Equal(t, expected.ParsedAs, actual.ParsedAs, expected.Original)
+	assert.Equal(t, expected.BuildMetadata, actual.BuildMetadata, expected.Original)
+	if assert.Len(t, actual.Decimal, len(expected.Decimal), expected.Original) {
+		for i := range expected.Decimal {
+			assert.Equal(t, 0, expected.Decimal[i].Cmp(actual.Decimal[i]),
+				"segment %d of %s is %s, not %s", i, expected.Original, actual.Decimal[i], expected.Decimal[i])
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, v := range testVersions(t) {
+		data, err := bson.Marshal(Version{v})
+		require.NoError(t, err, v.Original)
+
+		var decoded Version
+		require.NoError(t, bson.Unmarshal(data, &decoded), v.Original)
+		assertSameVersion(t, v, decoded.Version)
+	}
+}
+
+func TestRoundTripAsField(t *testing.T) {
+	type pkg struct {
+		Name    string  `bson:"name"`
+		Version Version `bson:"version"`
+	}
+
+	v := parseOrFatal(t, version.ParseSemVer, "1.2.3-rc.1")
+	data, err := bson.Marshal(pkg{Name: "left-pad", Version: Version{v}})
+	require.NoError(t, err)
+
+	var decoded pkg
+	require.NoError(t, bson.Unmarshal(data, &decoded))
+	assert.Equal(t, "left-pad", decoded.Name)
+	assertSameVersion(t, v, decoded.Version.Version)
+}
+
+func TestDocument(t *testing.T) {
+	v := parseOrFatal(t, version.ParsePerl, "1.002003")
+	data, err := bson.Marshal(Version{v})
+	require.NoError(t, err)
+
+	raw := bson.Raw(data)
+	assert.Equal(t, "1.002003", raw.Lookup("original").StringValue())
+	assert.Equal(t, "PerlDecimal", raw.Lookup("parsed_as").StringValue())
+	_, err = raw.LookupErr("string_segments")
+	assert.Error(t, err, "string_segments is omitted when all segments are decimals")
+	_, err = raw.LookupErr("build_metadata")
+	assert.Error(t, err, "build_metadata is omitted when there is none")
+
+	values, err := raw.Lookup("segments").Array().Values()
+	require.NoError(t, err)
+	var segments []string
+	for _, value := range values {
+		d, ok := value.Decimal128OK()
+		require.True(t, ok, "segment is a decimal")
+		segments = append(segments, d.String())
+	}
+	assert.Equal(t, []string{"1", "2", "3"}, segments)
+}
+
+// Decimal128 values have 34 digits of precision. ParseGeneric turns each
+// letter of a word into 10 digits or fewer, so a long word makes a segment
+// that is too precise to store as a Decimal128.
+func TestPrecisionLimits(t *testing.T) {
+	short := parseOrFatal(t, func(s string) (*version.Version, error) { return version.ParseGeneric(s) }, "1.0-abc")
+	data, err := bson.Marshal(Version{short})
+	require.NoError(t, err)
+	raw := bson.Raw(data)
+	_, err = raw.LookupErr("string_segments")
+	assert.Error(t, err, "a short word fits in a Decimal128")
+
+	word := strings.Repeat("supercalifragilisticexpialidocious", 3)
+	huge := parseOrFatal(t, func(s string) (*version.Version, error) { return version.ParseGeneric(s) }, "1.0-"+word)
+	var hugeSegment string
+	for _, d := range huge.Decimal {
+		if s := d.String(); len(s) > len(hugeSegment) {
+			hugeSegment = s
+		}
+	}
+	_, err = primitive.ParseDecimal128(hugeSegment)
+	require.Error(t, err, "%s does not fit in a Decimal128", hugeSegment)
+
+	data, err = bson.Marshal(Version{huge})
+	require.NoError(t, err)
+	raw = bson.Raw(data)
+	assert.True(t, raw.Lookup("string_segments").Boolean(), "string_segments is set")
+
+	values, err := raw.Lookup("segments").Array().Values()
+	require.NoError(t, err)
+	var strs []string
+	for _, value := range values {
+		if s, ok := value.StringValueOK(); ok {
+			strs = append(strs, s)
+		} else {
+			_, ok := value.Decimal128OK()
+			assert.True(t, ok, "other segments are still decimals")
+		}
+	}
+	assert.Equal(t, []string{hugeSegment}, strs, "only the word segment is a string")
+
+	var decoded Version
+	require.NoError(t, bson.Unmarshal(data, &decoded))
+	assertSameVersion(t, huge, decoded.Version)
+}
+
+func TestMarshalNil(t *testing.T) {
+	_, err := bson.Marshal(Version{})
+	assert.Error(t, err)
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	docs := []bson.D{
+		{{Key: "original", Value: "1.0"}, {Key: "parsed_as", Value: "Nonsense"}, {Key: "segments", Value: bson.A{"1", "0"}}},
+		{{Key: "original", Value: "1.0"}, {Key: "parsed_as", Value: "Unknown"}, {Key: "segments", Value: bson.A{"1", "0"}}},
+		{{Key: "original", Value: "1.0"}, {Key: "parsed_as", Value: "Generic"}, {Key: "segments", Value: bson.A{}}},
+		{{Key: "original", Value: "1.0"}, {Key: "parsed_as", Value: "Generic"}, {Key: "segments", Value: bson.A{int32(1), int32(0)}}},
+		{{Key: "original", Value: "1.0"}, {Key: "parsed_as", Value: "Generic"}, {Key: "segments", Value: bson.A{"one", "0"}}},
+	}
+	for _, doc := range docs {
+		data, err := bson.Marshal(doc)
+		require.NoError(t, err)
+
+		var v Version
+		assert.Error(t, bson.Unmarshal(data, &v), "%v", doc)
+	}
+}
@@ -0,0 +1,127 @@
+package version
+
+import "fmt"
+
+// BumpType describes the change from one release to the next, as returned by
+// BumpTypeBetween. The names follow the release types of npm's semver
+// package.
+type BumpType int
+
+const (
+	// BumpUnknown means the change cannot be classified, because the
+	// versions are not of a type that ClassifyUpgrade supports or do not
+	// start with a major and minor version.
+	BumpUnknown BumpType = iota
+	// BumpNone means the versions are equal, as when a release is published
+	// again under an equivalent version.
+	BumpNone
+	// BumpMajor means the major version changed, as in 1.4.0 to 2.0.0.
+	BumpMajor
+	// BumpPreMajor is a pre-release of a new major version, as in 1.4.0 to
+	// 2.0.0-alpha.
+	BumpPreMajor
+	// BumpMinor means the minor version changed, as in 1.4.0 to 1.5.0.
+	BumpMinor
+	// BumpPreMinor is a pre-release of a new minor version, as in 1.4.0 to
+	// 1.5.0-rc.1.
+	BumpPreMinor
+	// BumpPatch means the patch version or a later segment changed, as in
+	// 1.4.0 to 1.4.1.
+	BumpPatch
+	// BumpPrePatch is a pre-release of a new patch version, as in 1.4.0 to
+	// 1.4.1-rc.1.
+	BumpPrePatch
+	// BumpPreRelease is a change from one pre-release to another of the same
+	// release, as in 2.0.0-alpha to 2.0.0-beta.
+	BumpPreRelease
+	// BumpRelease is the release that a pre-release led up to, as in
+	// 2.0.0-rc.1 to 2.0.0.
+	BumpRelease
+)
+
+var bumpTypeNames = map[BumpType]string{
+	BumpUnknown:    "unknown",
+	BumpNone:       "none",
+	BumpMajor:      "major",
+	BumpPreMajor:   "premajor",
+	BumpMinor:      "minor",
+	BumpPreMinor:   "preminor",
+	BumpPatch:      "patch",
+	BumpPrePatch:   "prepatch",
+	BumpPreRelease: "prerelease",
+	BumpRelease:    "release",
+}
+
+func (b BumpType) String() string {
+	if n, ok := bumpTypeNames[b]; ok {
+		return n
+	}
+	return fmt.Sprintf("BumpType(%d)", int(b))
+}
+
+// BumpTypeBetween returns the kind of change from prev to next, where next is
+// the release that followed prev. It uses the same rules as ClassifyUpgrade,
+// but returns BumpNone rather than an error for equal versions, and
+// BumpUnknown rather than an error for versions it cannot classify.
+//
+// It returns an error if the versions were parsed as types that are not
+// comparable, or if next is less than prev.
+func BumpTypeBetween(prev, next *Version) (BumpType, error) {
+	if family(prev.ParsedAs) != family(next.ParsedAs) {
+		return BumpUnknown, &IncomparableError{ParsedAs1: prev.ParsedAs, ParsedAs2: next.ParsedAs}
+	}
+	switch cmp := Compare(prev, next); {
+	case cmp == 0:
+		return BumpNone, nil
+	case cmp > 0:
+		return BumpUnknown, fmt.Errorf("%s to %s is a downgrade", prev.Original, next.Original)
+	}
+	if !canClassifyUpgrade(prev.ParsedAs) || !hasMajorMinor(prev) || !hasMajorMinor(next) {
+		return BumpUnknown, nil
+	}
+
+	kind, err := ClassifyUpgrade(prev, next)
+	if err != nil {
+		return BumpUnknown, err
+	}
+	pre := next.IsPreRelease()
+	switch kind {
+	case UpgradeMajor:
+		if pre {
+			return BumpPreMajor, nil
+		}
+		return BumpMajor, nil
+	case UpgradeMinor:
+		if pre {
+			return BumpPreMinor, nil
+		}
+		return BumpMinor, nil
+	case UpgradePatch:
+		if pre {
+			return BumpPrePatch, nil
+		}
+		return BumpPatch, nil
+	case UpgradePreReleaseOnly:
+		if pre {
+			return BumpPreRelease, nil
+		}
+		return BumpRelease, nil
+	}
+	return BumpUnknown, nil
+}
+
+// BumpTypes returns the BumpTypeBetween each consecutive pair of versions in
+// sorted, which must be in ascending order, so the result has one fewer
+// element than sorted. A pair that BumpTypeBetween returns an error for is
+// BumpUnknown.
+func BumpTypes(sorted []*Version) []BumpType {
+	if len(sorted) < 2 {
+		return nil
+	}
+	bumps := make([]BumpType, len(sorted)-1)
+	for i := range bumps {
+		// BumpTypeBetween returns BumpUnknown along with any error.
+		bumps[i], _ = BumpTypeBetween(sorted[i], sorted[i+1])
+	}
+	return bumps
+}
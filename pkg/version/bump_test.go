@@ -0,0 +1,103 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBumpTypes(t *testing.T) {
+	expected := []BumpType{
+		BumpRelease,    // 0.0.0-foo -> 0.0.0
+		BumpPatch,      // 0.0.0 -> 0.0.1
+		BumpMinor,      // 0.0.1 -> 0.1.2
+		BumpMinor,      // 0.1.2 -> 0.9.0
+		BumpPatch,      // 0.9.0 -> 0.9.9
+		BumpMinor,      // 0.9.9 -> 0.10.0
+		BumpMinor,      // 0.10.0 -> 0.99.0
+		BumpPreMajor,   // 0.99.0 -> 1.0.0-alpha
+		BumpPreRelease, // 1.0.0-alpha -> 1.0.0-alpha.0
+		BumpPreRelease, // 1.0.0-alpha.0 -> 1.0.0-alpha.1
+		BumpPreRelease, // 1.0.0-alpha.1 -> 1.0.0-alpha.100
+		BumpPreRelease, // 1.0.0-alpha.100 -> 1.0.0-alpha.100.0
+		BumpPreRelease, // 1.0.0-alpha.100.0 -> 1.0.0-alpha.100.a
+		BumpPreRelease, // 1.0.0-alpha.100.a -> 1.0.0-alpha.beta
+		BumpPreRelease, // 1.0.0-alpha.beta -> 1.0.0-beta
+		BumpPreRelease, // 1.0.0-beta -> 1.0.0-beta.2
+		BumpPreRelease, // 1.0.0-beta.2 -> 1.0.0-beta.11
+		BumpPreRelease, // 1.0.0-beta.11 -> 1.0.0-rc.1
+		BumpRelease,    // 1.0.0-rc.1 -> 1.0.0
+		BumpPatch,      // 1.0.0 -> 1.0.1
+		BumpMinor,      // 1.0.1 -> 1.2.2
+		BumpPrePatch,   // 1.2.2 -> 1.2.3-4
+		BumpPreRelease, // 1.2.3-4 -> 1.2.3-5
+		BumpPreRelease, // 1.2.3-5 -> 1.2.3-4-foo
+		BumpPreRelease, // 1.2.3-4-foo -> 1.2.3-5-Foo
+		BumpPreRelease, // 1.2.3-5-Foo -> 1.2.3-5-foo
+		BumpPreRelease, // 1.2.3-5-foo -> 1.2.3-R2
+		BumpPreRelease, // 1.2.3-R2 -> 1.2.3-a
+		BumpPreRelease, // 1.2.3-a -> 1.2.3-a.0
+		BumpPreRelease, // 1.2.3-a.0 -> 1.2.3-a.5
+		BumpPreRelease, // 1.2.3-a.5 -> 1.2.3-a.10
+		BumpPreRelease, // 1.2.3-a.10 -> 1.2.3-a.100
+		BumpPreRelease, // 1.2.3-a.100 -> 1.2.3-a.b
+		BumpPreRelease, // 1.2.3-a.b -> 1.2.3-a.b.c.5.d.100
+		BumpPreRelease, // 1.2.3-a.b.c.5.d.100 -> 1.2.3-a.b.c.10.d.5
+		BumpPreRelease, // 1.2.3-a.b.c.10.d.5 -> 1.2.3-alpha.0.2
+		BumpPreRelease, // 1.2.3-alpha.0.2 -> 1.2.3-alpha.0.pr.1
+		BumpPreRelease, // 1.2.3-alpha.0.pr.1 -> 1.2.3-alpha.0.pr.2
+		BumpPreRelease, // 1.2.3-alpha.0.pr.2 -> 1.2.3-asdf
+		BumpPreRelease, // 1.2.3-asdf -> 1.2.3-pre
+		BumpPreRelease, // 1.2.3-pre -> 1.2.3-r100
+		BumpPreRelease, // 1.2.3-r100 -> 1.2.3-r2
+		BumpRelease,    // 1.2.3-r2 -> 1.2.3
+		BumpPrePatch,   // 1.2.3 -> 1.2.4-1
+		BumpRelease,    // 1.2.4-1 -> 1.2.4
+		BumpMajor,      // 1.2.4 -> 2.0.0
+		BumpMinor,      // 2.0.0 -> 2.3.4
+		BumpMinor,      // 2.3.4 -> 2.7.2+asdf
+		BumpMajor,      // 2.7.2+asdf -> 3.0.0
+		BumpPreMajor,   // 3.0.0 -> 9.9.9-alpha.0.pr.1
+	}
+
+	var vs []*Version
+	for _, s := range testParseSemVerOrderInputs {
+		vs = append(vs, parseOrFatalSemVer(t, s))
+	}
+	actual := BumpTypes(vs)
+	require.Len(t, actual, len(vs)-1)
+	for i := range actual {
+		assert.Equal(t, expected[i].String(), actual[i].String(), "%s -> %s", vs[i].Original, vs[i+1].Original)
+	}
+
+	assert.Nil(t, BumpTypes(vs[:1]))
+}
+
+func TestBumpTypeBetween(t *testing.T) {
+	tests := []struct {
+		prev, next *Version
+		expected   BumpType
+	}{
+		{parseOrFatalSemVer(t, "1.0.0"), parseOrFatalSemVer(t, "1.0.0+build"), BumpNone},
+		{parseOrFatalGeneric(t, "1.2.0"), parseOrFatalGeneric(t, "1.2"), BumpNone},
+		{parseOrFatalGeneric(t, "1.2.3.4"), parseOrFatalGeneric(t, "1.2.3.5"), BumpPatch},
+		{parseOrFatalGeneric(t, "1.2"), parseOrFatalGeneric(t, "1.3-rc1"), BumpPreMinor},
+		{parseOrFatalGeneric(t, "1.0"), parseOrFatalGeneric(t, "release"), BumpUnknown},
+		{parsePythonOrFatal(t, "1.0"), parsePythonOrFatal(t, "2.0"), BumpUnknown},
+	}
+	for _, tt := range tests {
+		actual, err := BumpTypeBetween(tt.prev, tt.next)
+		require.NoError(t, err, "%s -> %s", tt.prev, tt.next)
+		assert.Equal(t, tt.expected, actual, "%s -> %s", tt.prev, tt.next)
+	}
+
+	_, err := BumpTypeBetween(parseOrFatalSemVer(t, "1.0.0"), parseOrFatalGeneric(t, "1.0.1"))
+	assert.IsType(t, &IncomparableError{}, err)
+
+	_, err = BumpTypeBetween(parseOrFatalSemVer(t, "1.0.1"), parseOrFatalSemVer(t, "1.0.0"))
+	assert.Error(t, err)
+
+	bumps := BumpTypes([]*Version{parseOrFatalSemVer(t, "1.0.0"), parseOrFatalGeneric(t, "1.0.1"), parseOrFatalGeneric(t, "1.0.2")})
+	assert.Equal(t, []BumpType{BumpUnknown, BumpPatch}, bumps)
+}
@@ -0,0 +1,123 @@
+package version
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// Cache memoizes parsing. It holds the results of the most recently used
+// parses, up to a fixed number, and is safe for concurrent use.
+type Cache struct {
+	size    int
+	options []Option
+
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	// lru holds *cacheEntry values, with the most recently used at the front.
+	lru    *list.List
+	hits   uint64
+	misses uint64
+}
+
+type cacheKey struct {
+	parsedAs ParsedAs
+	version  string
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	version *Version
+	err     error
+}
+
+// CacheStats holds counters describing how well a Cache is working.
+type CacheStats struct {
+	// Hits is the number of calls to Parse that were answered from the
+	// cache.
+	Hits uint64
+	// Misses is the number of calls to Parse that had to parse the version.
+	Misses uint64
+	// Len is the number of results currently held in the cache.
+	Len int
+}
+
+// NewCache returns a Cache that holds up to size results. Values less than one
+// are treated as one. Any options are passed along to the parsing func for
+// every version parsed through the cache.
+func NewCache(size int, opts ...Option) *Cache {
+	if size < 1 {
+		size = 1
+	}
+	return &Cache{
+		size:    size,
+		options: opts,
+		entries: make(map[cacheKey]*list.Element, size),
+		lru:     list.New(),
+	}
+}
+
+// Parse parses version as the given type in the same way as Parse, returning
+// a cached result if the same version has been parsed as the same type
+// recently. Errors are cached as well as Versions.
+//
+// Each call returns a new *Version with its own Decimal slice, so callers may
+// modify the Version as usual. As with all Versions, the *decimal.Big values
+// in the slice are shared and must never be modified in place.
+func (c *Cache) Parse(pa ParsedAs, version string) (*Version, error) {
+	key := cacheKey{parsedAs: pa, version: version}
+
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(e)
+		c.hits++
+		entry := e.Value.(*cacheEntry)
+		c.mu.Unlock()
+		return entry.result()
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// Parse without holding the lock so that other goroutines are not held
+	// up. If another goroutine parses the same version at the same time,
+	// both results are identical and whichever is stored last wins.
+	v, err := Parse(pa, version, c.options...)
+	entry := &cacheEntry{key: key, version: v, err: err}
+
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(e)
+		e.Value = entry
+	} else {
+		c.entries[key] = c.lru.PushFront(entry)
+		if c.lru.Len() > c.size {
+			oldest := c.lru.Back()
+			c.lru.Remove(oldest)
+			delete(c.entries, oldest.Value.(*cacheEntry).key)
+		}
+	}
+	c.mu.Unlock()
+
+	return entry.result()
+}
+
+// Stats returns the cache's hit and miss counters and its current length.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Len: c.lru.Len()}
+}
+
+// result returns a copy of the cached Version that shares its decimals, or
+// the cached error.
+func (e *cacheEntry) result() (*Version, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	v := *e.version
+	v.Decimal = make([]*decimal.Big, len(e.version.Decimal))
+	copy(v.Decimal, e.version.Decimal)
+	return &v, nil
+}
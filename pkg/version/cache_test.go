@@ -0,0 +1,113 @@
+package version
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	c := NewCache(2)
+
+	v, err := c.Parse(SemVer, "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", v.Original)
+	assert.Equal(t, SemVer, v.ParsedAs)
+	assert.Equal(t, CacheStats{Misses: 1, Len: 1}, c.Stats())
+
+	again, err := c.Parse(SemVer, "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, 0, Compare(v, again))
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1, Len: 1}, c.Stats())
+
+	_, err = c.Parse(Generic, "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 2, Len: 2}, c.Stats(), "the type is part of the key")
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewCache(2)
+
+	for _, s := range []string{"1", "2", "1", "3"} {
+		_, err := c.Parse(Generic, s)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 3, Len: 2}, c.Stats())
+
+	_, err := c.Parse(Generic, "1")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), c.Stats().Hits, "1 was used more recently than 2")
+
+	_, err = c.Parse(Generic, "2")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), c.Stats().Misses, "2 was evicted")
+}
+
+func TestCacheCachesErrors(t *testing.T) {
+	c := NewCache(10)
+
+	_, err := c.Parse(SemVer, "not a version")
+	require.Error(t, err)
+	_, again := c.Parse(SemVer, "not a version")
+	assert.Equal(t, err, again)
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1, Len: 1}, c.Stats())
+}
+
+func TestCacheReturnsCopies(t *testing.T) {
+	c := NewCache(10)
+
+	v, err := c.Parse(Generic, "1.2.3")
+	require.NoError(t, err)
+	v.Original = "changed"
+	v.Decimal[0] = v.Decimal[2]
+
+	again, err := c.Parse(Generic, "1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", again.Original)
+	assertDecimalEqualString(t, []string{"1", "2", "3"}, again.Decimal)
+}
+
+func TestCacheWithOptions(t *testing.T) {
+	c := NewCache(10, WithIgnoreBuildMetadata())
+
+	v, err := c.Parse(Generic, "1.2.3+build")
+	require.NoError(t, err)
+	assert.Equal(t, "build", v.BuildMetadata)
+}
+
+func TestCacheSizeLessThanOne(t *testing.T) {
+	c := NewCache(0)
+
+	for _, s := range []string{"1", "2"} {
+		_, err := c.Parse(Generic, s)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, c.Stats().Len)
+}
+
+func TestCacheConcurrentParse(t *testing.T) {
+	c := NewCache(4)
+	inputs := []string{"1.0.0", "1.0.0", "1.0.0", "2.0.0", "3.0.0", "4.0.0", "5.0.0"}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				s := inputs[i%len(inputs)]
+				v, err := c.Parse(SemVer, s)
+				if assert.NoError(t, err) {
+					assert.Equal(t, s, v.Original)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(8*200), stats.Hits+stats.Misses)
+	assert.True(t, stats.Len <= 4, "cache holds at most 4 results (got %d)", stats.Len)
+}
@@ -0,0 +1,193 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// calVerField is a field of a CalVer layout.
+type calVerField int
+
+const (
+	calVerYear calVerField = iota
+	calVerMonth
+	calVerWeek
+	calVerDay
+	calVerNumber
+)
+
+// calVerTokens are the tokens of a CalVer layout, from calver.org, with the
+// name and kind of the field they set and the regex for their values. Longer
+// tokens come first so that "YYYY" is not read as "YY" twice.
+var calVerTokens = []struct {
+	token string
+	name  string
+	field calVerField
+	regex string
+}{
+	{"YYYY", "year", calVerYear, `[0-9]{4}`},
+	{"YY", "year", calVerYear, `[1-9][0-9]{0,2}|0`},
+	{"0Y", "year", calVerYear, `[0-9]{2,3}`},
+	{"MM", "month", calVerMonth, `[0-9]{1,2}`},
+	{"0M", "month", calVerMonth, `[0-9]{2}`},
+	{"WW", "week", calVerWeek, `[0-9]{1,2}`},
+	{"0W", "week", calVerWeek, `[0-9]{2}`},
+	{"DD", "day", calVerDay, `[0-9]{1,2}`},
+	{"0D", "day", calVerDay, `[0-9]{2}`},
+	{"MAJOR", "major", calVerNumber, `[0-9]+`},
+	{"MINOR", "minor", calVerNumber, `[0-9]+`},
+	{"MICRO", "micro", calVerNumber, `[0-9]+`},
+}
+
+// calVerLayout is a parsed CalVer layout.
+type calVerLayout struct {
+	regex *regexp.Regexp
+	// fields holds the field of each group of regex.
+	fields []calVerField
+	// shortYear is true if the year is a short year, which is the year
+	// minus 2000.
+	shortYear bool
+}
+
+// calVerLayouts caches the parsed layouts, as a package usually has the same
+// layout for all of its versions.
+var calVerLayouts sync.Map
+
+// WithCalVerLayout sets the layout that Parse and ParseVersionString use to
+// parse CalVer versions, as with the layout argument of ParseCalVer.
+func WithCalVerLayout(layout string) Option {
+	return func(o *options) {
+		o.calVerLayout = layout
+	}
+}
+
+// ParseCalVer parses a calendar version, such as "2024.04.1", "24.04" or
+// "20240115", with a layout made of the tokens from calver.org:
+//
+//   - YYYY is the full year, as in 2024.
+//   - YY and 0Y are the short year, which is the year minus 2000, as in 24.
+//     0Y is zero padded, as in 06.
+//   - MM and 0M are the month, WW and 0W the week, and DD and 0D the day. The
+//     zero padded forms must have two digits.
+//   - MAJOR, MINOR and MICRO are numbers.
+//
+// Any ".", "-" and "_" in the layout must be in the version as well, so the
+// layout of "2024.04.1" is "YYYY.0M.MICRO". A layout must have a year, and
+// may not have any field twice, or both a month and a week. It returns an
+// error if the layout is invalid, if the version does not match it, or if a
+// month, week or day in the version is out of range.
+//
+// The segments of the returned version are the year, month, week and day,
+// with 0 for the ones that the layout does not have, and then the numbers in
+// the order they are in the layout. Short years are stored as full years, so
+// versions with different layouts can be compared if their other fields are
+// the same.
+func ParseCalVer(version, layout string) (*Version, error) {
+	l, err := parseCalVerLayout(layout)
+	if err != nil {
+		return nil, err
+	}
+	matches := l.regex.FindStringSubmatch(version)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid calver version %s: does not match layout %s", version, layout)
+	}
+
+	date := []string{"0", "0", "0", "0"}
+	var numbers []string
+	for i, field := range l.fields {
+		value := matches[i+1]
+		switch field {
+		case calVerYear:
+			if l.shortYear {
+				n, _ := strconv.Atoi(value)
+				value = strconv.Itoa(2000 + n)
+			}
+		case calVerMonth:
+			err = checkCalVerRange(version, "month", value, 12)
+		case calVerWeek:
+			err = checkCalVerRange(version, "week", value, 53)
+		case calVerDay:
+			err = checkCalVerRange(version, "day", value, 31)
+		case calVerNumber:
+			numbers = append(numbers, value)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		date[field] = value
+	}
+	return fromStringSlice(CalVer, version, append(date, numbers...))
+}
+
+// parseCalVerOption is the parsing func for CalVer in parsers, which takes
+// the layout from WithCalVerLayout.
+func parseCalVerOption(version string, opts ...Option) (*Version, error) {
+	o := applyOptions(opts)
+	if o.calVerLayout == "" {
+		return nil, fmt.Errorf("cannot parse %s as a CalVer version without a layout; use WithCalVerLayout", version)
+	}
+	return ParseCalVer(version, o.calVerLayout)
+}
+
+func checkCalVerRange(version, name, value string, last int) error {
+	if n, _ := strconv.Atoi(value); n < 1 || n > last {
+		return fmt.Errorf("invalid calver version %s: %s %s is not between 1 and %d", version, name, value, last)
+	}
+	return nil
+}
+
+func parseCalVerLayout(layout string) (*calVerLayout, error) {
+	if l, ok := calVerLayouts.Load(layout); ok {
+		return l.(*calVerLayout), nil
+	}
+
+	l := &calVerLayout{}
+	seen := map[string]bool{}
+	var regex strings.Builder
+	regex.WriteString("^")
+	for rest := layout; rest != ""; {
+		if c := rest[0]; c == '.' || c == '-' || c == '_' {
+			regex.WriteString(regexp.QuoteMeta(rest[:1]))
+			rest = rest[1:]
+			continue
+		}
+
+		found := false
+		for _, t := range calVerTokens {
+			if !strings.HasPrefix(rest, t.token) {
+				continue
+			}
+			if seen[t.name] {
+				return nil, fmt.Errorf("invalid calver layout %s: it has more than one %s", layout, t.name)
+			}
+			seen[t.name] = true
+			l.fields = append(l.fields, t.field)
+			if t.field == calVerYear {
+				l.shortYear = t.token != "YYYY"
+			}
+			regex.WriteString("(" + t.regex + ")")
+			rest = rest[len(t.token):]
+			found = true
+			break
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid calver layout %s: unknown token at %q", layout, rest)
+		}
+	}
+	regex.WriteString("$")
+
+	if !seen["year"] {
+		return nil, fmt.Errorf("invalid calver layout %s: it has no year", layout)
+	}
+	if seen["month"] && seen["week"] {
+		return nil, fmt.Errorf("invalid calver layout %s: it has both a month and a week", layout)
+	}
+
+	l.regex = regexp.MustCompile(regex.String())
+	calVerLayouts.Store(layout, l)
+	return l, nil
+}
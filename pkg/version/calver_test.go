@@ -0,0 +1,93 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseCalVerOrFatal(t *testing.T, v, layout string) *Version {
+	ver, err := ParseCalVer(v, layout)
+	require.NoError(t, err, "no error parsing %v as a CalVer version with layout %s", v, layout)
+	return ver
+}
+
+func TestParseCalVer(t *testing.T) {
+	tests := []struct {
+		in, layout string
+		expected   []string
+	}{
+		{"2024.04.1", "YYYY.0M.MICRO", []string{"2024", "4", "0", "0", "1"}},
+		{"24.04", "YY.0M", []string{"2024", "4"}},
+		{"24.4", "YY.MM", []string{"2024", "4"}},
+		{"20240115", "YYYYMMDD", []string{"2024", "1", "0", "15"}},
+		{"20240115", "YYYY0M0D", []string{"2024", "1", "0", "15"}},
+		{"2023.3", "YYYY.MINOR", []string{"2023", "0", "0", "0", "3"}},
+		{"06.52", "0Y.WW", []string{"2006", "0", "52"}},
+		{"2024-01-02_3", "YYYY-0M-0D_MICRO", []string{"2024", "1", "0", "2", "3"}},
+		{"1.2024.5", "MAJOR.YYYY.MINOR", []string{"2024", "0", "0", "0", "1", "5"}},
+	}
+	for _, tt := range tests {
+		v := parseCalVerOrFatal(t, tt.in, tt.layout)
+		assert.Equal(t, tt.in, v.Original)
+		assert.Equal(t, CalVer, v.ParsedAs, tt.in)
+		assert.Equal(t, mustStringsToDecimal(t, tt.expected), v.Decimal, "%s with layout %s", tt.in, tt.layout)
+	}
+}
+
+// Each of these is a version and the layout to parse it with, separated by a
+// space.
+var testParseCalVerOrderInputs = []string{
+	"23.10 YY.MM",
+	"2023.12.0 YYYY.0M.MICRO",
+	"2023.12.1 YYYY.0M.MICRO",
+	"2023.12.10 YYYY.0M.MICRO",
+	"2024.01.0 YYYY.0M.MICRO",
+	"2024.02.0 YYYY.0M.MICRO",
+	"24.04 YY.0M",
+	"2024.10.0 YYYY.0M.MICRO",
+	"2025.01.0 YYYY.0M.MICRO",
+}
+
+var testParseCalVerEqualInputs = [][]string{
+	{"24.04 YY.0M", "2024.4 YYYY.MM"},
+}
+
+func TestParseCalVerErrors(t *testing.T) {
+	tests := []struct {
+		in, layout, err string
+	}{
+		{"2024.4", "YYYY.0M", "invalid calver version 2024.4: does not match layout YYYY.0M"},
+		{"24.04.1", "YY.0M", "invalid calver version 24.04.1: does not match layout YY.0M"},
+		{"2024.13", "YYYY.MM", "invalid calver version 2024.13: month 13 is not between 1 and 12"},
+		{"2024.00", "YYYY.0M", "invalid calver version 2024.00: month 00 is not between 1 and 12"},
+		{"20240132", "YYYYMMDD", "invalid calver version 20240132: day 32 is not between 1 and 31"},
+		{"2024.54", "YYYY.WW", "invalid calver version 2024.54: week 54 is not between 1 and 53"},
+		{"06.1", "YY.MM", "invalid calver version 06.1: does not match layout YY.MM"},
+		{"2024.1", "YYYY.YY", "invalid calver layout YYYY.YY: it has more than one year"},
+		{"24.2024", "YY.YYYY", "invalid calver layout YY.YYYY: it has more than one year"},
+		{"2024.1.1", "YYYY.MICRO.MICRO", "invalid calver layout YYYY.MICRO.MICRO: it has more than one micro"},
+		{"2024.1.1", "YYYY.MM.WW", "invalid calver layout YYYY.MM.WW: it has both a month and a week"},
+		{"1.2", "MAJOR.MINOR", "invalid calver layout MAJOR.MINOR: it has no year"},
+		{"2024+1", "YYYY+MICRO", `invalid calver layout YYYY+MICRO: unknown token at "+MICRO"`},
+		{"2024.1", "yyyy.MM", `invalid calver layout yyyy.MM: unknown token at "yyyy.MM"`},
+	}
+	for _, tt := range tests {
+		_, err := ParseCalVer(tt.in, tt.layout)
+		if assert.Error(t, err, tt.in) {
+			assert.Equal(t, tt.err, err.Error())
+		}
+	}
+}
+
+func TestParseCalVerWithParse(t *testing.T) {
+	v, err := Parse(CalVer, "2024.04.1", WithCalVerLayout("YYYY.0M.MICRO"))
+	require.NoError(t, err)
+	assert.Equal(t, parseCalVerOrFatal(t, "2024.04.1", "YYYY.0M.MICRO"), v)
+
+	_, err = Parse(CalVer, "2024.04.1")
+	if assert.Error(t, err) {
+		assert.Equal(t, "cannot parse 2024.04.1 as a CalVer version without a layout; use WithCalVerLayout", err.Error())
+	}
+}
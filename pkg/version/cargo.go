@@ -0,0 +1,18 @@
+package version
+
+import (
+	"fmt"
+)
+
+// ParseCargo parses a Cargo crate version, such as "1.0.197" or
+// "0.4.0-alpha.2". Cargo requires crate versions to be complete semver 2.0
+// versions, so the Version is parsed as SemVer, and shorthands like "1.0" or
+// a leading "v" are rejected. Build metadata is allowed, and like all build
+// metadata it is ignored when versions are compared.
+func ParseCargo(version string) (*Version, error) {
+	v, err := ParseSemVer(version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cargo version: %s", version)
+	}
+	return v, nil
+}
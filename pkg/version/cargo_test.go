@@ -0,0 +1,31 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCargo(t *testing.T) {
+	for _, in := range []string{
+		"1.0.197",
+		"0.4.0-alpha.2",
+		"1.0.0-rc.1",
+		"0.2.153+wasi-snapshot-preview1",
+		"0.0.0",
+	} {
+		v, err := ParseCargo(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, SemVer, v.ParsedAs, in)
+		assert.Equal(t, parseOrFatalSemVer(t, in).Decimal, v.Decimal, in)
+	}
+
+	for _, in := range []string{"", "1.0", "v1.0.0", "1.0.0.0", "01.0.0", "1.0.0-"} {
+		_, err := ParseCargo(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid cargo version: "+in, err.Error(), in)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package version
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// CompactVersion is a Version stored in much less memory, for holding very
+// large numbers of versions. Instead of a slice of *decimal.Big values, the
+// segments are encoded into a single string. A small integer segment takes
+// one to nine bytes.
+//
+// Segments that are not integers, have a scale other than zero, or are
+// outside of ±2^62 are stored as their decimal text, so they take more room
+// and are slower to compare, but are still exact. Converting a Version to a
+// CompactVersion and back gives an equal Version, with the same scale for
+// every segment.
+//
+// The zero value is not a valid CompactVersion. Create them with
+// NewCompactVersion.
+type CompactVersion struct {
+	// Original is the string that was passed to the parsing func.
+	Original string
+	// ParsedAs indicates which type the version was parsed as.
+	ParsedAs ParsedAs
+	// BuildMetadata contains any build metadata that was split off the
+	// version before parsing. See Version.BuildMetadata.
+	BuildMetadata string
+
+	segments string
+}
+
+// Each segment is encoded as a uvarint header. If the low bit of the header
+// is 0, the rest of it is the zigzag encoded value of an integer segment.
+// Otherwise, the rest of it is the length of the segment's decimal text,
+// which follows the header.
+const (
+	compactTextFlag = 1
+
+	compactMinInt = -1 << 62
+	compactMaxInt = 1<<62 - 1
+)
+
+// NewCompactVersion returns the compact form of v. It returns an error if any
+// segment of v is not a finite number. No parser in this package creates such
+// segments.
+func NewCompactVersion(v *Version) (CompactVersion, error) {
+	var b []byte
+	for _, d := range v.Decimal {
+		if !d.IsFinite() {
+			return CompactVersion{}, fmt.Errorf("cannot make a compact version from %s: segment %s is not finite", v, d)
+		}
+
+		if n, ok := d.Int64(); ok && d.Scale() == 0 && n >= compactMinInt && n <= compactMaxInt && !(n == 0 && d.Signbit()) {
+			b = appendUvarint(b, uint64((n<<1)^(n>>63))<<1)
+			continue
+		}
+
+		text := d.String()
+		b = appendUvarint(b, uint64(len(text))<<1|compactTextFlag)
+		b = append(b, text...)
+	}
+
+	return CompactVersion{
+		Original:      v.Original,
+		ParsedAs:      v.ParsedAs,
+		BuildMetadata: v.BuildMetadata,
+		segments:      string(b),
+	}, nil
+}
+
+// Version returns c as a Version. Small integer segments share the
+// *decimal.Big values used by the parsers.
+func (c CompactVersion) Version() *Version {
+	var decimals []*decimal.Big
+	for rest := c.segments; rest != ""; {
+		var seg compactSegment
+		seg, rest = nextCompactSegment(rest)
+		decimals = append(decimals, seg.decimal())
+	}
+
+	return &Version{
+		Original:      c.Original,
+		Decimal:       decimals,
+		ParsedAs:      c.ParsedAs,
+		BuildMetadata: c.BuildMetadata,
+	}
+}
+
+// Clone returns a copy of c. CompactVersions are immutable, so this is the same
+// as copying the value, and is only here for symmetry with Version.Clone.
+func (c CompactVersion) Clone() CompactVersion {
+	return c
+}
+
+// Compare compares c with other in the same way as Compare compares their
+// Versions. Integer segments are compared without decoding them to decimals,
+// so this does not allocate unless a segment is stored as text.
+func (c CompactVersion) Compare(other CompactVersion) int {
+	a, b := c.segments, other.segments
+	for a != "" || b != "" {
+		var cmp int
+		switch {
+		case b == "":
+			var seg compactSegment
+			seg, a = nextCompactSegment(a)
+			cmp = seg.sign()
+		case a == "":
+			var seg compactSegment
+			seg, b = nextCompactSegment(b)
+			cmp = -seg.sign()
+		default:
+			var segA, segB compactSegment
+			segA, a = nextCompactSegment(a)
+			segB, b = nextCompactSegment(b)
+			cmp = segA.compare(segB)
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
+
+// compactSegment is a decoded segment. If text is empty the segment is the
+// integer n.
+type compactSegment struct {
+	n    int64
+	text string
+}
+
+func nextCompactSegment(s string) (compactSegment, string) {
+	header, s := readUvarint(s)
+	if header&compactTextFlag == 0 {
+		u := header >> 1
+		return compactSegment{n: int64(u>>1) ^ -int64(u&1)}, s
+	}
+
+	length := int(header >> 1)
+	return compactSegment{text: s[:length]}, s[length:]
+}
+
+func (seg compactSegment) decimal() *decimal.Big {
+	if seg.text == "" {
+		if seg.n >= 0 && seg.n <= maxInternedDecimal {
+			return internedDecimals[seg.n]
+		}
+		return decimal.New(seg.n, 0)
+	}
+
+	d, ok := new(decimal.Big).SetString(seg.text)
+	if !ok {
+		// NewCompactVersion only stores text produced by decimal.Big.
+		panic("invalid decimal text in CompactVersion: " + seg.text)
+	}
+	return d
+}
+
+func (seg compactSegment) sign() int {
+	switch {
+	case seg.text != "":
+		return seg.decimal().Sign()
+	case seg.n < 0:
+		return -1
+	case seg.n > 0:
+		return 1
+	}
+	return 0
+}
+
+func (seg compactSegment) compare(other compactSegment) int {
+	if seg.text == "" && other.text == "" {
+		switch {
+		case seg.n < other.n:
+			return -1
+		case seg.n > other.n:
+			return 1
+		}
+		return 0
+	}
+	return compareDecimals(seg.decimal(), other.decimal())
+}
+
+func appendUvarint(b []byte, u uint64) []byte {
+	for u >= 0x80 {
+		b = append(b, byte(u)|0x80)
+		u >>= 7
+	}
+	return append(b, byte(u))
+}
+
+// readUvarint is binary.Uvarint for strings, which saves converting the
+// segments to a []byte. The input is always produced by appendUvarint.
+func readUvarint(s string) (uint64, string) {
+	var u uint64
+	var shift uint
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x80 {
+			return u | uint64(c)<<shift, s[i+1:]
+		}
+		u |= uint64(c&0x7f) << shift
+		shift += 7
+	}
+	panic("truncated uvarint in CompactVersion")
+}
+
+// CompactSet holds a large number of versions as CompactVersions.
+type CompactSet struct {
+	versions []CompactVersion
+}
+
+// Add adds the compact form of each of vs to the set. If any of them cannot be
+// made compact, Add returns an error and adds none of them.
+func (s *CompactSet) Add(vs ...*Version) error {
+	compact := make([]CompactVersion, len(vs))
+	for i, v := range vs {
+		c, err := NewCompactVersion(v)
+		if err != nil {
+			return err
+		}
+		compact[i] = c
+	}
+
+	if s.versions == nil {
+		s.versions = compact
+	} else {
+		s.versions = append(s.versions, compact...)
+	}
+	return nil
+}
+
+// Len returns the number of versions in the set.
+func (s *CompactSet) Len() int {
+	return len(s.versions)
+}
+
+// At returns the version at index i, which must be in the range [0, Len()).
+func (s *CompactSet) At(i int) CompactVersion {
+	return s.versions[i]
+}
+
+// Sort sorts the set in ascending order. As with Sort, the sort is stable.
+func (s *CompactSet) Sort() {
+	sort.SliceStable(s.versions, func(i, j int) bool {
+		return s.versions[i].Compare(s.versions[j]) < 0
+	})
+}
+
+// Search returns the index of the first version in the set that is not less
+// than c, or Len() if there is none. The set must be sorted.
+func (s *CompactSet) Search(c CompactVersion) int {
+	return sort.Search(len(s.versions), func(i int) bool {
+		return s.versions[i].Compare(c) >= 0
+	})
+}
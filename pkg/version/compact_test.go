@@ -0,0 +1,142 @@
+package version
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustCompact(t *testing.T, v *Version) CompactVersion {
+	c, err := NewCompactVersion(v)
+	require.NoError(t, err, "no error making %s compact", v)
+	return c
+}
+
+func assertSameDecimals(t *testing.T, expected, actual []*decimal.Big) {
+	require.Equal(t, len(expected), len(actual))
+	for i := range expected {
+		assert.Equal(t, expected[i].String(), actual[i].String(), "segment %d", i)
+		assert.Equal(t, expected[i].Scale(), actual[i].Scale(), "scale of segment %d", i)
+	}
+}
+
+func TestCompactVersionRoundTrip(t *testing.T) {
+	for name, versions := range keyTestCorpora(t) {
+		t.Run(name, func(t *testing.T) {
+			for _, v := range versions {
+				c := mustCompact(t, v)
+				back := c.Version()
+				assert.Equal(t, v.Original, back.Original)
+				assert.Equal(t, v.ParsedAs, back.ParsedAs)
+				assertSameDecimals(t, v.Decimal, back.Decimal)
+			}
+		})
+	}
+
+	v, err := ParseGeneric("1.2.3+build.7", WithIgnoreBuildMetadata())
+	require.NoError(t, err)
+	assert.Equal(t, "build.7", mustCompact(t, v).Version().BuildMetadata)
+}
+
+var compactEdgeCaseDecimals = [][]string{
+	{"0"},
+	{"-0"},
+	{"1", "0", "-1"},
+	{"1", "-1"},
+	{"1.50"},
+	{"1.5"},
+	{"1E+5"},
+	{"100000"},
+	{"-3"},
+	{"1024"},
+	{"1025"},
+	{"4611686018427387903"},
+	{"4611686018427387904"},
+	{"-4611686018427387904"},
+	{"-4611686018427387905"},
+	{"123456789012345678901234567890"},
+	{"97.0000000108000000011200000001040000000097"},
+}
+
+func TestCompactVersionRoundTripEdgeCases(t *testing.T) {
+	for _, d := range compactEdgeCaseDecimals {
+		v := &Version{Original: d[0], Decimal: mustStringsToDecimal(t, d)}
+		assertSameDecimals(t, v.Decimal, mustCompact(t, v).Version().Decimal)
+	}
+}
+
+func TestCompactVersionSmallIntegersAreCompact(t *testing.T) {
+	c := mustCompact(t, parseOrFatalGeneric(t, "1.22.333"))
+	assert.Equal(t, 4, len(c.segments))
+}
+
+func TestCompactVersionCompare(t *testing.T) {
+	var versions []*Version
+	for _, d := range compactEdgeCaseDecimals {
+		versions = append(versions, &Version{Decimal: mustStringsToDecimal(t, d)})
+	}
+	corpora := keyTestCorpora(t)
+	corpora["edge cases"] = versions
+
+	for name, versions := range corpora {
+		t.Run(name, func(t *testing.T) {
+			compact := make([]CompactVersion, len(versions))
+			for i, v := range versions {
+				compact[i] = mustCompact(t, v)
+			}
+
+			for i, v1 := range versions {
+				for j, v2 := range versions {
+					assert.Equal(t, sign(Compare(v1, v2)), sign(compact[i].Compare(compact[j])),
+						"comparing %s with %s", v1, v2)
+				}
+			}
+		})
+	}
+}
+
+func TestCompactVersionRejectsNonFiniteSegments(t *testing.T) {
+	v := &Version{Original: "inf", Decimal: []*decimal.Big{new(decimal.Big).SetInf(false)}}
+	_, err := NewCompactVersion(v)
+	assert.Error(t, err)
+
+	var s CompactSet
+	assert.Error(t, s.Add(parseOrFatalGeneric(t, "1"), v))
+	assert.Equal(t, 0, s.Len(), "nothing is added")
+}
+
+func TestCompactSet(t *testing.T) {
+	versions := keyTestCorpora(t)["python"]
+	shuffled := make([]*Version, len(versions))
+	copy(shuffled, versions)
+	rand.New(rand.NewSource(1)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	var s CompactSet
+	require.NoError(t, s.Add(shuffled[:10]...))
+	require.NoError(t, s.Add(shuffled[10:]...))
+	require.Equal(t, len(versions), s.Len())
+	s.Sort()
+
+	expected := make([]*Version, len(shuffled))
+	copy(expected, shuffled)
+	require.NoError(t, Sort(expected))
+	for i, v := range expected {
+		assert.Equal(t, v.Original, s.At(i).Original, "position %d", i)
+	}
+
+	for i, v := range expected {
+		found := s.Search(mustCompact(t, v))
+		assert.True(t, found <= i, "%s is found at or before %d", v, i)
+		assert.Equal(t, 0, s.At(found).Compare(mustCompact(t, v)), "%s is found", v)
+	}
+
+	above := &Version{Decimal: mustStringsToDecimal(t, []string{"1000000"})}
+	assert.Equal(t, s.Len(), s.Search(mustCompact(t, above)))
+	below := &Version{Decimal: mustStringsToDecimal(t, []string{"-1000000"})}
+	assert.Equal(t, 0, s.Search(mustCompact(t, below)))
+}
@@ -0,0 +1,116 @@
+package version
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// compareDecimals returns the same result as x.Cmp(y) without allocating.
+//
+// decimal.Big.Cmp allocates when x and y have different scales, the same
+// number of integral digits, and one of them does not fit in a uint64 once
+// they are rescaled to match. That is common for the values toDecimalString
+// produces, such as "97.0000000108000000011200000001040000000097" for
+// "alpha". Those comparisons are done here using reusable scratch space
+// instead.
+func compareDecimals(x, y *decimal.Big) int {
+	if x.Scale() == y.Scale() || x.Sign() != y.Sign() || x.Sign() == 0 {
+		return x.Cmp(y)
+	}
+
+	// Cmp compares the number of integral digits first.
+	if x.Precision()-x.Scale() != y.Precision()-y.Scale() {
+		return x.Cmp(y)
+	}
+
+	shift := x.Scale() - y.Scale()
+	if shift < 0 {
+		shift = -shift
+	}
+	if x.Precision() <= maxUint64Digits && y.Precision() <= maxUint64Digits && shift < maxUint64Digits {
+		return x.Cmp(y)
+	}
+
+	return compareDecimalsWithScratch(x, y)
+}
+
+// maxUint64Digits is the number of decimal digits that always fit in a
+// uint64.
+const maxUint64Digits = 19
+
+// powersOfTen holds 10^0 through 10^19.
+var powersOfTen = func() [maxUint64Digits + 1]uint64 {
+	var p [maxUint64Digits + 1]uint64
+	p[0] = 1
+	for i := 1; i < len(p); i++ {
+		p[i] = p[i-1] * 10
+	}
+	return p
+}()
+
+// compareScratch holds the values used by compareDecimalsWithScratch. They
+// keep their capacity between uses, so once a scratch value has compared
+// numbers of a given size it can compare them again without allocating.
+type compareScratch struct {
+	x, y   decimal.Big
+	xi, yi big.Int
+	a, b   big.Int
+	power  big.Int
+}
+
+var compareScratchPool = sync.Pool{
+	New: func() interface{} { return new(compareScratch) },
+}
+
+// compareDecimalsWithScratch compares x and y as integers by moving both to
+// a scale of zero and multiplying the one that had the smaller scale by the
+// difference between the scales.
+func compareDecimalsWithScratch(x, y *decimal.Big) int {
+	s := compareScratchPool.Get().(*compareScratch)
+
+	s.x.Copy(x)
+	xScale := s.x.Scale()
+	s.x.SetScale(0)
+	s.x.Int(&s.xi)
+
+	s.y.Copy(y)
+	yScale := s.y.Scale()
+	s.y.SetScale(0)
+	s.y.Int(&s.yi)
+
+	var result int
+	if xScale < yScale {
+		result = s.mulPow10(&s.xi, yScale-xScale).Cmp(&s.yi)
+	} else {
+		result = s.xi.Cmp(s.mulPow10(&s.yi, xScale-yScale))
+	}
+
+	compareScratchPool.Put(s)
+	return result
+}
+
+// mulPow10 returns n × 10^exp, which is stored in one of the scratch values.
+// Multiplying in steps of at most 10^19 and alternating between two
+// destinations that don't alias their inputs lets math/big reuse their
+// storage.
+func (s *compareScratch) mulPow10(n *big.Int, exp int) *big.Int {
+	src, dst := n, &s.a
+	for exp > 0 {
+		step := exp
+		if step > maxUint64Digits {
+			step = maxUint64Digits
+		}
+		s.power.SetUint64(powersOfTen[step])
+		dst.Mul(src, &s.power)
+
+		if dst == &s.a {
+			src, dst = &s.a, &s.b
+		} else {
+			src, dst = &s.b, &s.a
+		}
+		exp -= step
+	}
+	return src
+}
@@ -0,0 +1,61 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareDecimalsMatchesCmp(t *testing.T) {
+	values := []string{
+		"0", "1", "-1", "9", "10", "10.5", "10.50", "-10.5",
+		"123456789012345678901234567890",
+		"123456789012345678901234567890.1",
+		"-123456789012345678901234567890.000000000000000000001",
+		"18446744073709551615", "18446744073709551616", "1.8446744073709551616",
+		"0.00000000000000000000000000001", "0.0000000000000000000000000001",
+		"1E+40", "1.0000000000000000000000000000000000000001E+40",
+		toDecimalString("alpha"), toDecimalString("alphb"), toDecimalString("alph"),
+		toDecimalString("beta"), toDecimalString("a"),
+		asciiToDecimalString("alpha"), asciiToDecimalString("beta"),
+		"97.0000000108", "97.00000001080000000112", "97.0000000109",
+	}
+
+	decimals := mustStringsToDecimal(t, values)
+	for i, x := range decimals {
+		for j, y := range decimals {
+			assert.Equal(t, x.Cmp(y), compareDecimals(x, y), "comparing %s with %s", values[i], values[j])
+		}
+	}
+}
+
+func TestCompareDoesNotAllocate(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not meaningful with the race detector")
+	}
+
+	pairs := [][2]*Version{
+		{parseOrFatalGeneric(t, "1.2.3"), parseOrFatalGeneric(t, "1.2.4")},
+		{parseOrFatalGeneric(t, "1.0.0-alpha"), parseOrFatalGeneric(t, "1.0.0-beta")},
+		{parseOrFatalGeneric(t, "1.0.snapshot"), parseOrFatalGeneric(t, "1.0.release")},
+		{parseOrFatalSemVer(t, "1.0.0-alpha.1"), parseOrFatalSemVer(t, "1.0.0-alpha.beta")},
+		{parseOrFatalSemVer(t, "1.0.0-rc.1"), parseOrFatalSemVer(t, "1.0.0")},
+		{parsePerlOrFatal(t, "1.002003"), parsePerlOrFatal(t, "v1.2.4")},
+		{parsePHPOrFatal(t, "5.3.0-dev"), parsePHPOrFatal(t, "5.3.0RC1")},
+		{parsePythonOrFatal(t, "1.0a1"), parsePythonOrFatal(t, "1.0.post1")},
+		{parsePythonOrFatal(t, "1.0+ubuntu.1"), parsePythonOrFatal(t, "1.0+ubuntu.2")},
+		{parsePythonOrFatal(t, "1.0+abc"), parsePythonOrFatal(t, "1.0+abcd")},
+		{parsePythonOrFatal(t, "0.9-legacy_form"), parsePythonOrFatal(t, "0.9-legacy_other")},
+		{parseRubyOrFatal(t, "1.0.a"), parseRubyOrFatal(t, "1.0.b10")},
+		{parseRubyOrFatal(t, "1.0.beta"), parseRubyOrFatal(t, "1.0.alpha")},
+	}
+
+	for _, pair := range pairs {
+		v1, v2 := pair[0], pair[1]
+		allocs := testing.AllocsPerRun(100, func() {
+			Compare(v1, v2)
+			Compare(v2, v1)
+		})
+		assert.Equal(t, float64(0), allocs, "comparing %s with %s does not allocate", v1, v2)
+	}
+}
@@ -0,0 +1,80 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// conanItemsRegEx matches the dot separated items of a Conan version or
+// pre-release.
+var conanItemsRegEx = regexp.MustCompile(`^[A-Za-z0-9_-]+(?:\.[A-Za-z0-9_-]+)*$`)
+
+// ParseConan parses a Conan 2 package version, such as "1.2.3.4-alpha.1",
+// "cci.20230101" or "1.0.0+build.1", ordering it as Conan's Version does. The
+// version is split into a build after the last "+", then a pre-release after
+// the last "-", and then into items at each ".".
+//
+// Items are compared in order, and a version with fewer items is less, after
+// any trailing zero items are removed, so "1" equals "1.0.0" but "1" <
+// "1.a". Items that are numbers are compared as numbers, and other items are
+// compared lexically and are greater than numbers, so "1.0.0" <
+// "cci.20210101" < "cci.20230101". Conan compares a number and an item that
+// starts with a digit but is not a number, like "1a", as strings, which is not
+// a consistent order. ParseConan orders these as other items that are not
+// numbers.
+//
+// A version with a pre-release is less than the same version without one,
+// and pre-releases are compared in the same way as versions. The build is
+// ignored.
+func ParseConan(version string) (*Version, error) {
+	release := version
+	if i := strings.LastIndexByte(release, '+'); i >= 0 {
+		if !conanItemsRegEx.MatchString(release[i+1:]) {
+			return nil, fmt.Errorf("invalid conan version: %s", version)
+		}
+		release = release[:i]
+	}
+	pre := ""
+	if i := strings.LastIndexByte(release, '-'); i >= 0 {
+		release, pre = release[:i], release[i+1:]
+		if !conanItemsRegEx.MatchString(pre) {
+			return nil, fmt.Errorf("invalid conan version: %s", version)
+		}
+	}
+	if !conanItemsRegEx.MatchString(release) {
+		return nil, fmt.Errorf("invalid conan version: %s", version)
+	}
+
+	// The release is 0 after the items, and a pre-release is -1 and then its
+	// items.
+	segments := conanItemSegments(release)
+	if pre == "" {
+		segments = append(segments, "0")
+	} else {
+		segments = append(segments, "-1")
+		segments = append(segments, conanItemSegments(pre)...)
+	}
+	return fromStringSlice(Conan, version, segments)
+}
+
+// conanItemSegments returns two segments for each item in s, after removing
+// trailing zero items, and then two zeros for the end of the items, which
+// makes fewer items less. A number is 1 and then the number, and other items
+// are 2 and then the item as a decimal.
+func conanItemSegments(s string) []string {
+	items := strings.Split(s, ".")
+	for len(items) > 0 && strings.Trim(items[len(items)-1], "0") == "" {
+		items = items[:len(items)-1]
+	}
+
+	segments := make([]string, 0, len(items)*2+2)
+	for _, item := range items {
+		if strings.Trim(item, asciiDigits) == "" {
+			segments = append(segments, "1", item)
+		} else {
+			segments = append(segments, "2", asciiToDecimalString(item))
+		}
+	}
+	return append(segments, "0", "0")
+}
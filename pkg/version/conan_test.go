@@ -0,0 +1,72 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseConanOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseConan(v)
+	require.NoError(t, err, "no error parsing %v as a Conan version", v)
+	return ver
+}
+
+func TestParseConan(t *testing.T) {
+	tests := map[string][]string{
+		"1.0":           {"1", "1"},
+		"1.2.3.4":       {"1", "1", "1", "2", "1", "3", "1", "4"},
+		"1.0-alpha.1":   {"1", "1", "0", "0", "-1", "2", "97.108112104097", "1", "1"},
+		"1.0-0":         {"1", "1", "0", "0", "-1"},
+		"cci.20230101":  {"2", "99.099105", "1", "20230101"},
+		"1.0.1+build.5": {"1", "1", "1", "0", "1", "1"},
+		"1.0-a-b":       {"1", "1", "2", "48.045097", "0", "0", "-1", "2", "98"},
+		"007.0.0+a":     {"1", "7"},
+	}
+	for in, expected := range tests {
+		v := parseConanOrFatal(t, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, Conan, v.ParsedAs, in)
+		assert.Equal(t, mustStringsToDecimal(t, expected), v.Decimal, in)
+	}
+}
+
+var testParseConanOrderInputs = []string{
+	"0.9",
+	"1-0",
+	"1-1",
+	"1-alpha",
+	"1-alpha.1",
+	"1-beta",
+	"1",
+	"1.0.1-rc",
+	"1.0.1",
+	"1.1",
+	"1.2",
+	"1.10",
+	"1.10.0.1",
+	"1.a",
+	"2",
+	"cci.20210101",
+	"cci.20230101",
+	"cci.20230101.1",
+}
+
+var testParseConanEqualInputs = [][]string{
+	{"1.0", "1"},
+	{"1.0", "1.0.0"},
+	{"1.0", "01.00"},
+	{"1.0-alpha", "1-alpha.0"},
+	{"1.0", "1.0+build.1"},
+	{"1.0+a", "1.0+b"},
+}
+
+func TestParseConanErrors(t *testing.T) {
+	for _, in := range []string{"", ".", ".1", "1.", "1..2", "1-", "-1", "1+", "1.0+a+b", "1.*", "1 0"} {
+		_, err := ParseConan(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid conan version: "+in, err.Error(), in)
+		}
+	}
+}
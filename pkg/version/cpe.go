@@ -0,0 +1,129 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cpePreReleaseWords are the words in a CPE version or update that make it
+// a pre-release, and their order.
+var cpePreReleaseWords = map[string]string{
+	"dev":       "-6",
+	"alpha":     "-5",
+	"beta":      "-4",
+	"milestone": "-3",
+	"pre":       "-2",
+	"preview":   "-2",
+	"rc":        "-1",
+}
+
+// ParseCPE parses the version and update attributes of a CPE 2.3 name, such
+// as "1.1.1" and "k" from "cpe:2.3:a:openssl:openssl:1.1.1:k:*:*:*:*:*:*",
+// into a single version. The attributes are in the formatted string form, so
+// other characters than letters, digits, ".", "_", "-" and "~" must be
+// escaped with a "\". The Original of the returned version is the version and
+// update joined with a ":", or just the version if the update is "".
+//
+// The version is compared first and then the update. Each of them is split
+// into numbers and words at other characters and where a letter follows a
+// digit or a digit follows a letter, and the numbers and words are compared
+// in order. Numbers are compared as numbers and missing numbers are 0, so
+// "1.0" equals "1". The words dev, alpha, beta, milestone, pre, preview and rc
+// are pre-releases, in that order, and are less than a release, so "3.10.0:rc1"
+// < "3.10.0:-". Other words, such as the letters of OpenSSL releases and p,
+// sp and update, are compared lexically and are greater than a release, so
+// "1.1.1:-" < "1.1.1:k" < "1.1.1:l" and "7.4:-" < "7.4:p1".
+//
+// An update of "-", which means there is none, is the same as "". The version
+// may not be "-" or "", and an error is returned if either attribute is the
+// "*" or "?" wildcard, or contains one.
+func ParseCPE(version, update string) (*Version, error) {
+	original := version
+	if update != "" {
+		original += ":" + update
+	}
+
+	if version == "-" || version == "" {
+		return nil, fmt.Errorf("invalid cpe version: %s", original)
+	}
+	if update == "-" {
+		update = ""
+	}
+	var segments []string
+	for _, attr := range []string{version, update} {
+		if err := checkCPEAttribute(original, attr); err != nil {
+			return nil, err
+		}
+		segments = appendCPESegments(segments, attr)
+	}
+	return fromStringSlice(CPE, original, segments)
+}
+
+// parseCPEString is the parsing func for CPE in parsers, which splits the
+// Original of a CPE version at the first ":" that is not escaped.
+func parseCPEString(s string) (*Version, error) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ':':
+			return ParseCPE(s[:i], s[i+1:])
+		}
+	}
+	return ParseCPE(s, "")
+}
+
+func checkCPEAttribute(original, attr string) error {
+	for i := 0; i < len(attr); i++ {
+		switch c := attr[i]; {
+		case isASCIIDigit(c) || isASCIILetter(c) || c == '.' || c == '_' || c == '-' || c == '~':
+		case c == '*' || c == '?':
+			return fmt.Errorf("invalid cpe version %s: wildcards are not allowed", original)
+		case c == '\\' && i+1 < len(attr) && attr[i+1] > ' ' && attr[i+1] < 0x7f:
+			i++
+		default:
+			return fmt.Errorf("invalid cpe version: %s", original)
+		}
+	}
+	return nil
+}
+
+// appendCPESegments appends two segments for each number and word in attr,
+// and then two zeros for the end of attr, which makes fewer numbers and words
+// less. A number is 2 and then the number, a pre-release word is -1 and then
+// its order, and other words are 1 and then the word as a decimal. Zero
+// numbers before a pre-release word or the end are left out, so that "1.0rc1"
+// equals "1rc1".
+func appendCPESegments(segments []string, attr string) []string {
+	trimZeros := func() {
+		for len(segments) >= 2 && segments[len(segments)-2] == "2" && segments[len(segments)-1] == "0" {
+			segments = segments[:len(segments)-2]
+		}
+	}
+
+	for i := 0; i < len(attr); {
+		start := i
+		switch {
+		case isASCIIDigit(attr[i]):
+			for i < len(attr) && isASCIIDigit(attr[i]) {
+				i++
+			}
+			segments = append(segments, "2", trimLeadingZeros(attr[start:i]))
+		case isASCIILetter(attr[i]):
+			for i < len(attr) && isASCIILetter(attr[i]) {
+				i++
+			}
+			word := strings.ToLower(attr[start:i])
+			if order, ok := cpePreReleaseWords[word]; ok {
+				trimZeros()
+				segments = append(segments, "-1", order)
+			} else {
+				segments = append(segments, "1", asciiToDecimalString(word))
+			}
+		default:
+			i++
+		}
+	}
+	trimZeros()
+	return append(segments, "0", "0")
+}
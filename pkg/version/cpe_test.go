@@ -0,0 +1,94 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseCPEOrFatal(t *testing.T, v, update string) *Version {
+	ver, err := ParseCPE(v, update)
+	require.NoError(t, err, "no error parsing %v:%v as a CPE version", v, update)
+	return ver
+}
+
+func TestParseCPE(t *testing.T) {
+	tests := map[[2]string]struct {
+		original string
+		segments []string
+	}{
+		{"1.1.1", "k"}:          {"1.1.1:k", []string{"2", "1", "2", "1", "2", "1", "0", "0", "1", "107"}},
+		{"1.1.1", "-"}:          {"1.1.1:-", []string{"2", "1", "2", "1", "2", "1"}},
+		{"1.1.1", ""}:           {"1.1.1", []string{"2", "1", "2", "1", "2", "1"}},
+		{"7.4", "p1"}:           {"7.4:p1", []string{"2", "7", "2", "4", "0", "0", "1", "112", "2", "1"}},
+		{"3.10.0", "rc1"}:       {"3.10.0:rc1", []string{"2", "3", "2", "10", "0", "0", "-1", "-1", "2", "1"}},
+		{"1.8.0", "update_291"}: {"1.8.0:update_291", []string{"2", "1", "2", "8", "0", "0", "1", "117.112100097116101", "2", "291"}},
+		{"9.0.0", "M1"}:         {"9.0.0:M1", []string{"2", "9", "0", "0", "1", "109", "2", "1"}},
+		{"5.0\\(1\\)", ""}:      {"5.0\\(1\\)", []string{"2", "5", "2", "0", "2", "1"}},
+		{"2.0.0-beta1", "-"}:    {"2.0.0-beta1:-", []string{"2", "2", "-1", "-4", "2", "1"}},
+	}
+	for in, expected := range tests {
+		v := parseCPEOrFatal(t, in[0], in[1])
+		assert.Equal(t, expected.original, v.Original, "%v", in)
+		assert.Equal(t, CPE, v.ParsedAs, "%v", in)
+		assert.Equal(t, mustStringsToDecimal(t, expected.segments), v.Decimal, "%v", in)
+	}
+}
+
+// These are the version and update attributes of CPE names from the NVD,
+// joined with a colon.
+var testParseCPEOrderInputs = []string{
+	"1.0.2:-",
+	"1.0.2:a",
+	"1.0.2:u",
+	"1.1.0:beta1",
+	"1.1.0:pre1",
+	"1.1.1:-",
+	"1.1.1:a",
+	"1.1.1:k",
+	"1.1.1:l",
+	"1.1.1k:-",
+	"3.10.0:alpha1",
+	"3.10.0:beta4",
+	"3.10.0:rc1",
+	"3.10.0:rc2",
+	"3.10.0:-",
+	"3.10.1:-",
+	"7.4:-",
+	"7.4:p1",
+	"7.4:sp1",
+	"7.5:-",
+	"9.0.0:milestone1",
+	"9.0.0:milestone9",
+	"9.0.0:-",
+	"9.0.0:update_1",
+}
+
+var testParseCPEEqualInputs = [][]string{
+	{"1.0:-", "1:"},
+	{"1.0:-", "1.0.0:-"},
+	{"1.1.1:K", "1.1.1:k"},
+	{"3.10.0:rc1", "3.10:rc.1"},
+	{"7.4:p01", "7.4:p1"},
+}
+
+func TestParseCPEErrors(t *testing.T) {
+	for _, in := range [][2]string{{"", ""}, {"-", ""}, {"-", "k"}, {"1.0 beta", "-"}, {"1.0", "p 1"}, {"1.0:1", "-"}, {"1.0", "k\\"}} {
+		original := in[0]
+		if in[1] != "" {
+			original += ":" + in[1]
+		}
+		_, err := ParseCPE(in[0], in[1])
+		if assert.Error(t, err, original) {
+			assert.Equal(t, "invalid cpe version: "+original, err.Error(), original)
+		}
+	}
+
+	for _, in := range [][2]string{{"*", "-"}, {"1.1.1", "*"}, {"1.1.*", ""}, {"1.1.1", "?"}} {
+		_, err := ParseCPE(in[0], in[1])
+		if assert.Error(t, err, "%v", in) {
+			assert.Contains(t, err.Error(), "wildcards are not allowed", "%v", in)
+		}
+	}
+}
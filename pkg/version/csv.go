@@ -0,0 +1,140 @@
+package version
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// CSVOption configures EncodeCSV.
+type CSVOption func(*csvOptions)
+
+type csvOptions struct {
+	segmentColumns int
+}
+
+// WithSegmentColumns makes EncodeCSV write each segment in its own column,
+// for databases that do not support arrays. Every row has n segment
+// columns, and the columns after a version's last segment are left empty.
+// Compare treats missing segments as zero, so these columns should be loaded
+// as zero, or sorted with COALESCE, when ordering by them. EncodeCSV returns
+// an error if a version has more than n segments. Values of n less than one
+// have no effect.
+func WithSegmentColumns(n int) CSVOption {
+	return func(o *csvOptions) {
+		o.segmentColumns = n
+	}
+}
+
+// EncodeCSV writes vs to w as CSV, with one row for each version. Each row
+// has the original version, the name of the type it was parsed as, and the
+// version's segments. By default the segments are written in a single column
+// as a Postgres array literal, such as "{1,2,3}", which can be loaded into a
+// numeric[] column with COPY. See WithSegmentColumns for writing each
+// segment in its own column instead.
+//
+// Segments are written the same way as in JSON. Build metadata is not
+// written.
+func EncodeCSV(w io.Writer, vs []*Version, opts ...CSVOption) error {
+	o := csvOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	cw := csv.NewWriter(w)
+	var record []string
+	for _, v := range vs {
+		record = append(record[:0], v.Original, v.ParsedAs.String())
+		if o.segmentColumns > 0 {
+			if len(v.Decimal) > o.segmentColumns {
+				return fmt.Errorf("version %s has %d segments, which is more than the %d segment columns", v.Original, len(v.Decimal), o.segmentColumns)
+			}
+			for _, d := range v.Decimal {
+				record = append(record, d.String())
+			}
+			for i := len(v.Decimal); i < o.segmentColumns; i++ {
+				record = append(record, "")
+			}
+		} else {
+			var b strings.Builder
+			b.WriteByte('{')
+			for i, d := range v.Decimal {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				b.WriteString(d.String())
+			}
+			b.WriteByte('}')
+			record = append(record, b.String())
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// DecodeCSV reads versions written by EncodeCSV from r. The segments are
+// taken from the CSV rather than by parsing the original version again, as
+// with JSON. Rows whose third column starts with "{" are read as having an
+// array column, and other rows as having one column per segment, where empty
+// columns at the end of the row are ignored.
+func DecodeCSV(r io.Reader) ([]*Version, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+
+	var vs []*Version
+	for row := 1; ; row++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return vs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 3 {
+			return nil, fmt.Errorf("row %d of CSV has %d columns, but versions have at least 3", row, len(record))
+		}
+
+		pa, err := ParsedAsString(record[1])
+		if err != nil || pa == Unknown {
+			return nil, fmt.Errorf("row %d of CSV has an unknown version type: %s", row, record[1])
+		}
+
+		segments := record[2:]
+		if strings.HasPrefix(record[2], "{") {
+			if len(record) > 3 || !strings.HasSuffix(record[2], "}") {
+				return nil, fmt.Errorf("row %d of CSV has an invalid array of segments: %s", row, record[2])
+			}
+			segments = strings.Split(record[2][1:len(record[2])-1], ",")
+		} else {
+			n := len(segments)
+			for n > 0 && segments[n-1] == "" {
+				n--
+			}
+			segments = segments[:n]
+		}
+
+		v := &Version{
+			Original: record[0],
+			Decimal:  make([]*decimal.Big, len(segments)),
+			ParsedAs: pa,
+		}
+		for i, s := range segments {
+			d := new(decimal.Big)
+			if err := d.UnmarshalText([]byte(s)); err != nil {
+				return nil, fmt.Errorf("row %d of CSV has an invalid segment %q: %s", row, s, err)
+			}
+			v.Decimal[i] = d
+		}
+		if len(v.Decimal) == 0 {
+			return nil, fmt.Errorf("row %d of CSV has no segments for version %s", row, v.Original)
+		}
+		vs = append(vs, v)
+	}
+}
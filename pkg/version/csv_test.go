@@ -0,0 +1,142 @@
+package version
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func csvTestVersions(t *testing.T) []*Version {
+	return []*Version{
+		parseOrFatalSemVer(t, "1.0.0-alpha.1"),
+		parsePerlOrFatal(t, "1.002003"),
+		parsePerlOrFatal(t, "0.000000001"),
+		parsePythonOrFatal(t, "1!2.0rc1.post2.dev3"),
+		parseRubyOrFatal(t, "2.0.b1"),
+		parsePHPOrFatal(t, "1.2.3.4-beta2"),
+		parseOrFatalGeneric(t, "1.0-supercalifragilistic"),
+		parseOrFatalGeneric(t, `1.0 "quoted", with commas`),
+	}
+}
+
+func assertSameCSVVersions(t *testing.T, expected, actual []*Version) {
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		assert.Equal(t, expected[i].Original, actual[i].Original)
+		assert.Equal(t, expected[i].ParsedAs, actual[i].ParsedAs, expected[i].Original)
+		if assert.Len(t, actual[i].Decimal, len(expected[i].Decimal), expected[i].Original) {
+			for j := range expected[i].Decimal {
+				assert.Equal(t, 0, expected[i].Decimal[j].Cmp(actual[i].Decimal[j]),
+					"segment %d of %s is %s, not %s", j, expected[i].Original, actual[i].Decimal[j], expected[i].Decimal[j])
+			}
+		}
+	}
+}
+
+func TestEncodeCSV(t *testing.T) {
+	vs := []*Version{
+		parseOrFatalSemVer(t, "1.0.0-alpha.1"),
+		parseOrFatalGeneric(t, "1.0-alpha"),
+		parsePerlOrFatal(t, "1.002003"),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, EncodeCSV(&buf, vs))
+	assert.Equal(t,
+		"1.0.0-alpha.1,SemVer,\"{1,0,0,-1,97.108112104097,0,1,-1}\"\n"+
+			"1.0-alpha,Generic,\"{1,0,-26}\"\n"+
+			"1.002003,PerlDecimal,\"{1,2,3}\"\n",
+		buf.String())
+
+	buf.Reset()
+	require.NoError(t, EncodeCSV(&buf, vs, WithSegmentColumns(8)))
+	assert.Equal(t,
+		"1.0.0-alpha.1,SemVer,1,0,0,-1,97.108112104097,0,1,-1\n"+
+			"1.0-alpha,Generic,1,0,-26,,,,,\n"+
+			"1.002003,PerlDecimal,1,2,3,,,,,\n",
+		buf.String())
+}
+
+func TestEncodeCSVQuoting(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, EncodeCSV(&buf, []*Version{parseOrFatalGeneric(t, `1.0 "quoted", with commas`)}))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, `1.0 "quoted", with commas`, records[0][0])
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	vs := csvTestVersions(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, EncodeCSV(&buf, vs))
+	decoded, err := DecodeCSV(&buf)
+	require.NoError(t, err)
+	assertSameCSVVersions(t, vs, decoded)
+
+	buf.Reset()
+	require.NoError(t, EncodeCSV(&buf, vs, WithSegmentColumns(30)))
+	records, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+	require.NoError(t, err)
+	for _, record := range records {
+		assert.Len(t, record, 32, "every row has the same number of columns")
+	}
+	decoded, err = DecodeCSV(&buf)
+	require.NoError(t, err)
+	assertSameCSVVersions(t, vs, decoded)
+}
+
+func TestCSVLongFractionsAndNegativeSegments(t *testing.T) {
+	word := parseOrFatalGeneric(t, "1.0-supercalifragilistic")
+	tiny := parsePerlOrFatal(t, "0.000000001")
+	alpha := parseOrFatalSemVer(t, "1.0.0-alpha")
+
+	long := false
+	for _, d := range word.Decimal {
+		s := d.String()
+		if i := strings.IndexByte(s, '.'); i >= 0 && len(s)-i > 50 {
+			long = true
+		}
+	}
+	require.True(t, long, "a long word makes a segment with a long fraction")
+	require.Equal(t, -1, alpha.Decimal[3].Sign(), "alpha is a negative segment")
+
+	vs := []*Version{word, tiny, alpha}
+	for _, opts := range [][]CSVOption{nil, {WithSegmentColumns(8)}} {
+		var buf bytes.Buffer
+		require.NoError(t, EncodeCSV(&buf, vs, opts...))
+		decoded, err := DecodeCSV(&buf)
+		require.NoError(t, err)
+		assertSameCSVVersions(t, vs, decoded)
+	}
+}
+
+func TestEncodeCSVTooManySegments(t *testing.T) {
+	var buf bytes.Buffer
+	err := EncodeCSV(&buf, []*Version{parseOrFatalSemVer(t, "1.0.0-alpha.1")}, WithSegmentColumns(3))
+	assert.Error(t, err)
+}
+
+func TestDecodeCSVErrors(t *testing.T) {
+	for _, input := range []string{
+		"1.0,Generic\n",
+		"1.0,Nonsense,\"{1,0}\"\n",
+		"1.0,Unknown,\"{1,0}\"\n",
+		"1.0,Generic,\"{1,0\"\n",
+		"1.0,Generic,\"{1,0}\",2\n",
+		"1.0,Generic,\"{1,x}\"\n",
+		"1.0,Generic,\"{}\"\n",
+		"1.0,Generic,1,,0\n",
+		"1.0,Generic,,,\n",
+		"1.0,Generic,\"{1,0}\"\n1.0,Generic\n",
+	} {
+		_, err := DecodeCSV(strings.NewReader(input))
+		assert.Error(t, err, input)
+	}
+}
@@ -0,0 +1,245 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// debianRevisionBytes holds the bytes allowed in a Debian revision.
+	debianRevisionBytes = newByteSet(asciiDigits + "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ+.~")
+	// debianUpstreamBytes holds the bytes allowed in the upstream part of a
+	// Debian version.
+	debianUpstreamBytes = newByteSet(asciiDigits + "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ+.~-:")
+)
+
+// ParseDebian parses a Debian package version
+// (https://www.debian.org/doc/debian-policy/ch-controlfields.html#version),
+// which is an optional epoch followed by ":", the upstream version, and an
+// optional revision after the last "-", as in "1:2.34-0ubuntu3". The upstream
+// version must start with a digit, and may only contain a ":" if there is an
+// epoch, and a "-" if there is a revision.
+//
+// The segments are chosen so that Compare orders versions as dpkg does. The
+// epoch is the first segment. The upstream version and revision are each
+// split into alternating runs of non-digits and digits. Each character of a
+// non-digit run becomes a segment, with "~" lower than the end of the run,
+// letters higher, and all other characters higher still, followed by a zero
+// segment for the end of the run. Each digit run becomes a segment holding
+// its value. A zero segment separates the upstream version from the
+// revision.
+func ParseDebian(version string) (*Version, error) {
+	v := strings.TrimSpace(version)
+	epoch, upstream, revision := "0", v, ""
+	if i := strings.IndexByte(upstream, ':'); i >= 0 {
+		epoch, upstream = upstream[:i], upstream[i+1:]
+		if epoch == "" || strings.Trim(epoch, asciiDigits) != "" {
+			return nil, fmt.Errorf("invalid Debian version: %s: epoch is not a number", version)
+		}
+	}
+	if i := strings.LastIndexByte(upstream, '-'); i >= 0 {
+		upstream, revision = upstream[:i], upstream[i+1:]
+		if revision == "" || !debianRevisionBytes.containsAll(revision) {
+			return nil, fmt.Errorf("invalid Debian version: %s: invalid revision", version)
+		}
+	}
+	if upstream == "" || !isASCIIDigit(upstream[0]) {
+		return nil, fmt.Errorf("invalid Debian version: %s: upstream version does not start with a digit", version)
+	}
+	if !debianUpstreamBytes.containsAll(upstream) {
+		return nil, fmt.Errorf("invalid Debian version: %s: invalid upstream version", version)
+	}
+
+	segments := []string{epoch}
+	segments = appendDebianSegments(segments, upstream)
+	segments = append(segments, "0")
+	segments = appendDebianSegments(segments, revision)
+	return fromStringSlice(Debian, version, segments)
+}
+
+// appendDebianSegments appends the segments for the runs of non-digits and
+// digits in s to segments, as described for ParseDebian.
+func appendDebianSegments(segments []string, s string) []string {
+	for i := 0; i < len(s); {
+		for ; i < len(s) && !isASCIIDigit(s[i]); i++ {
+			segments = append(segments, strconv.Itoa(debianOrder(s[i])))
+		}
+		segments = append(segments, "0")
+
+		start := i
+		for i < len(s) && isASCIIDigit(s[i]) {
+			i++
+		}
+		if start == i {
+			segments = append(segments, "0")
+		} else {
+			segments = append(segments, s[start:i])
+		}
+	}
+	return segments
+}
+
+// debianOrder is the order of the non-digit c in dpkg's version comparison.
+func debianOrder(c byte) int {
+	switch {
+	case c == '~':
+		return -1
+	case isASCIILower(c) || ('A' <= c && c <= 'Z'):
+		return int(c)
+	default:
+		return int(c) + 256
+	}
+}
+
+// ToDebianString returns v as a Debian version string
+// (https://www.debian.org/doc/debian-policy/ch-controlfields.html#version)
+// with the given Debian revision, such as "1". If revision is empty the
+// result has no revision.
+//
+// The result is chosen so that dpkg orders the Debian versions of a set of
+// versions from the same scheme as Compare orders the versions themselves:
+//
+//   - SemVer and Go versions keep their release without the "v" of a Go
+//     version, and a pre-release becomes a "~" suffix, so "1.2.3-rc.1" and
+//     "v1.2.3-rc.1" become "1.2.3~rc.1". Build metadata is dropped.
+//     Identifiers are copied as they are, so dpkg orders two pre-releases
+//     differently from semver if the first identifiers that differ mix digits
+//     with other characters, like "rc1" and "rc10", or if one is a word that
+//     starts with the other, like "a" and "alpha".
+//   - PythonPEP440 versions keep their epoch. Trailing zeros are removed from
+//     the release, since dpkg would otherwise order "1.0.0~a1" after "1.0~b1".
+//     Pre-releases become "~a1", "~b1" or "~rc1", post-releases "+post1",
+//     and dev releases "~dev1", or "~~dev1" when there is no pre-release or
+//     post-release. "1!1.0rc1.post2.dev3" becomes "1:1~rc1+post2~dev3".
+//   - Debian versions are returned as they are if revision is empty, and
+//     cannot be given a revision if they already have one.
+//   - Versions from any other scheme are converted only if all of their
+//     segments are non-negative integers, which are joined with ".".
+//
+// Hyphens are only valid in the upstream part of a Debian version when there
+// is a revision, so when revision is empty they are replaced with "+". An
+// error is returned if revision has characters that are not valid in a
+// Debian revision, or if v cannot be converted.
+func (v *Version) ToDebianString(revision string) (string, error) {
+	if !debianRevisionBytes.containsAll(revision) {
+		return "", fmt.Errorf("invalid Debian revision: %s", revision)
+	}
+
+	var upstream string
+	var err error
+	switch v.ParsedAs {
+	case SemVer, Go:
+		upstream, err = semVerToDebian(strings.TrimPrefix(v.Original, "v"))
+	case PythonPEP440:
+		upstream, err = pep440ToDebian(v.Original)
+	case Debian:
+		return debianWithRevision(v.Original, revision)
+	default:
+		upstream, err = integerSegmentsToDebian(v)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if revision == "" {
+		return strings.ReplaceAll(upstream, "-", "+"), nil
+	}
+	return upstream + "-" + revision, nil
+}
+
+func debianWithRevision(original, revision string) (string, error) {
+	original = strings.TrimSpace(original)
+	if revision == "" {
+		return original, nil
+	}
+	if strings.IndexByte(original, '-') >= 0 {
+		return "", fmt.Errorf("cannot add a Debian revision to %s: it already has one", original)
+	}
+	return original + "-" + revision, nil
+}
+
+func semVerToDebian(original string) (string, error) {
+	matches := semVerRegEx.FindStringSubmatch(original)
+	if matches == nil {
+		return "", fmt.Errorf("cannot make a Debian version from %s: original version is not semver", original)
+	}
+
+	upstream := matches[1] + "." + matches[2] + "." + matches[3]
+	if preRelease := matches[4]; preRelease != "" {
+		upstream += "~" + preRelease
+	}
+	return upstream, nil
+}
+
+func pep440ToDebian(original string) (string, error) {
+	matches, err := matchPEP440(original)
+	if err != nil {
+		return "", err
+	}
+	if matches.local != "" {
+		return "", fmt.Errorf("cannot make a Debian version from %s: local versions cannot be ordered by dpkg", original)
+	}
+
+	var b strings.Builder
+	if epoch := removeLeadingZeros(matches.epoch); epoch != "0" {
+		b.WriteString(epoch)
+		b.WriteByte(':')
+	}
+
+	release := strings.Split(matches.release, ".")
+	for len(release) > 1 && removeLeadingZeros(release[len(release)-1]) == "0" {
+		release = release[:len(release)-1]
+	}
+	for i, r := range release {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(removeLeadingZeros(r))
+	}
+
+	// A missing number is zero, and removeLeadingZeros turns "" into "0".
+	if matches.pre != "" {
+		b.WriteByte('~')
+		switch strings.ToLower(matches.preLabel) {
+		case "a", "alpha":
+			b.WriteString("a")
+		case "b", "beta":
+			b.WriteString("b")
+		default:
+			b.WriteString("rc")
+		}
+		b.WriteString(removeLeadingZeros(matches.preNumber))
+	}
+
+	if matches.post != "" {
+		b.WriteString("+post")
+		b.WriteString(removeLeadingZeros(matches.postNumber1 + matches.postNumber2))
+	}
+
+	if matches.dev != "" {
+		// A dev release with no pre-release or post-release sorts before
+		// every pre-release of the same release.
+		if matches.pre == "" && matches.post == "" {
+			b.WriteByte('~')
+		}
+		b.WriteString("~dev")
+		b.WriteString(removeLeadingZeros(matches.devNumber))
+	}
+
+	return b.String(), nil
+}
+
+func integerSegmentsToDebian(v *Version) (string, error) {
+	var b strings.Builder
+	for i, d := range v.Decimal {
+		if !d.IsInt() || d.Sign() < 0 {
+			return "", fmt.Errorf("cannot make a Debian version from %s version %s: segment %s is not a non-negative integer", v.ParsedAs, v.Original, d)
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(d.Int(nil).String())
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,313 @@
+package version
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// debianVersionRegex matches valid Debian versions with a revision.
+var debianVersionRegex = regexp.MustCompile(`^(?:[0-9]+:)?[0-9][A-Za-z0-9.+~-]*-[A-Za-z0-9.+~]+$`)
+
+// dpkgCompare compares two Debian versions in the same way as
+// "dpkg --compare-versions".
+func dpkgCompare(a, b string) int {
+	aEpoch, aUpstream, aRevision := splitDebianVersion(a)
+	bEpoch, bUpstream, bRevision := splitDebianVersion(b)
+	if aEpoch != bEpoch {
+		if aEpoch < bEpoch {
+			return -1
+		}
+		return 1
+	}
+	if cmp := dpkgCompareString(aUpstream, bUpstream); cmp != 0 {
+		return cmp
+	}
+	return dpkgCompareString(aRevision, bRevision)
+}
+
+func splitDebianVersion(v string) (int, string, string) {
+	epoch := 0
+	if i := strings.IndexByte(v, ':'); i >= 0 {
+		epoch, _ = strconv.Atoi(v[:i])
+		v = v[i+1:]
+	}
+	if i := strings.LastIndexByte(v, '-'); i >= 0 {
+		return epoch, v[:i], v[i+1:]
+	}
+	return epoch, v, ""
+}
+
+// dpkgOrder is the order function from dpkg's verrevcmp.
+func dpkgOrder(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return 0
+	case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		return int(c)
+	case c == '~':
+		return -1
+	case c == 0:
+		return 0
+	}
+	return int(c) + 256
+}
+
+// dpkgCompareString is dpkg's verrevcmp.
+func dpkgCompareString(a, b string) int {
+	at := func(s string, i int) byte {
+		if i < len(s) {
+			return s[i]
+		}
+		return 0
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		firstDiff := 0
+		for (i < len(a) && !isASCIIDigit(a[i])) || (j < len(b) && !isASCIIDigit(b[j])) {
+			ac, bc := dpkgOrder(at(a, i)), dpkgOrder(at(b, j))
+			if ac != bc {
+				return sign(ac - bc)
+			}
+			i++
+			j++
+		}
+		for at(a, i) == '0' {
+			i++
+		}
+		for at(b, j) == '0' {
+			j++
+		}
+		for isASCIIDigit(at(a, i)) && isASCIIDigit(at(b, j)) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		if isASCIIDigit(at(a, i)) {
+			return 1
+		}
+		if isASCIIDigit(at(b, j)) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return sign(firstDiff)
+		}
+	}
+	return 0
+}
+
+func TestDpkgCompare(t *testing.T) {
+	ordered := []string{
+		"0.9-1", "1.0~~-1", "1.0~~a-1", "1.0~-1", "1.0-1", "1.0-2", "1.0a-1",
+		"1.0+b-1", "1.0.1-1", "1.2-1", "1.10-1", "1:0.1-1",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			assert.Equal(t, sign(i-j), dpkgCompare(ordered[i], ordered[j]), "%s compared to %s", ordered[i], ordered[j])
+		}
+	}
+	assert.Equal(t, 0, dpkgCompare("1.01-1", "1.1-1"))
+}
+
+func TestToDebianString(t *testing.T) {
+	tests := []struct {
+		version  *Version
+		revision string
+		expected string
+	}{
+		{parseOrFatalSemVer(t, "1.2.3"), "1", "1.2.3-1"},
+		{parseOrFatalSemVer(t, "1.2.3-rc.1"), "1", "1.2.3~rc.1-1"},
+		{parseOrFatalSemVer(t, "1.2.3-rc.1+build.5"), "1", "1.2.3~rc.1-1"},
+		{parseOrFatalSemVer(t, "1.2.3-4-foo"), "0ubuntu1", "1.2.3~4-foo-0ubuntu1"},
+		{parseOrFatalSemVer(t, "1.2.3-4-foo"), "", "1.2.3~4+foo"},
+		{parseGoOrFatal(t, "v1.2.3-rc.1"), "1", "1.2.3~rc.1-1"},
+		{parseGoOrFatal(t, "v2.0.0+incompatible"), "", "2.0.0"},
+		{parsePythonOrFatal(t, "1.0"), "1", "1-1"},
+		{parsePythonOrFatal(t, "1.0.post1"), "1", "1+post1-1"},
+		{parsePythonOrFatal(t, "1.2.0a1"), "1", "1.2~a1-1"},
+		{parsePythonOrFatal(t, "1.0.dev456"), "1", "1~~dev456-1"},
+		{parsePythonOrFatal(t, "1!1.0rc1.post2.dev3"), "1", "1:1~rc1+post2~dev3-1"},
+		{parsePythonOrFatal(t, "1.0-preview"), "", "1~rc0"},
+		{parsePerlOrFatal(t, "v1.2.3"), "1", "1.2.3-1"},
+		{parsePerlOrFatal(t, "1.002003"), "1", "1.2.3-1"},
+		{parsePHPOrFatal(t, "1.2.3"), "1", "1.2.3-1"},
+		{parseRubyOrFatal(t, "1.2.03"), "1", "1.2.3-1"},
+		{parseOrFatalGeneric(t, "2020.10.1"), "1", "2020.10.1-1"},
+		{parseDebianOrFatal(t, "1:2.34-0ubuntu3"), "", "1:2.34-0ubuntu3"},
+		{parseDebianOrFatal(t, "2.34"), "1", "2.34-1"},
+	}
+
+	for _, tt := range tests {
+		actual, err := tt.version.ToDebianString(tt.revision)
+		require.NoError(t, err, tt.version.Original)
+		assert.Equal(t, tt.expected, actual, tt.version.Original)
+	}
+}
+
+func TestToDebianStringErrors(t *testing.T) {
+	_, err := parseOrFatalSemVer(t, "1.2.3").ToDebianString("1-2")
+	assert.Error(t, err, "revisions cannot contain hyphens")
+
+	_, err = parsePythonOrFatal(t, "1.0+local").ToDebianString("1")
+	assert.Error(t, err, "local versions cannot be converted")
+
+	_, err = parseOrFatalGeneric(t, "1.2.3-alpha").ToDebianString("1")
+	assert.Error(t, err, "generic versions with pre-releases cannot be converted")
+
+	_, err = parseRubyOrFatal(t, "1.2.a").ToDebianString("1")
+	assert.Error(t, err, "ruby versions with letters cannot be converted")
+
+	_, err = parseDebianOrFatal(t, "2.34-1").ToDebianString("2")
+	assert.Error(t, err, "debian versions with a revision cannot be given another")
+}
+
+// semVerOrderDiffersInDpkg returns true if dpkg orders the Debian versions of
+// two semver versions differently from semver, which happens when the first
+// pre-release identifiers that differ mix digits with other characters, or
+// when one is a word that is a prefix of the other, like "a" and "alpha".
+func semVerOrderDiffersInDpkg(a, b string) bool {
+	aIdentifiers := strings.Split(semVerRegEx.FindStringSubmatch(a)[4], ".")
+	bIdentifiers := strings.Split(semVerRegEx.FindStringSubmatch(b)[4], ".")
+	for i := 0; i < len(aIdentifiers) && i < len(bIdentifiers); i++ {
+		aID, bID := aIdentifiers[i], bIdentifiers[i]
+		if aID == bID {
+			continue
+		}
+		if mixesDigits(aID) || mixesDigits(bID) {
+			return true
+		}
+		isWord := !strings.ContainsAny(aID, asciiDigits) && !strings.ContainsAny(bID, asciiDigits)
+		return isWord && (strings.HasPrefix(aID, bID) || strings.HasPrefix(bID, aID))
+	}
+	return false
+}
+
+func mixesDigits(identifier string) bool {
+	hasDigit, hasOther := false, false
+	for i := 0; i < len(identifier); i++ {
+		if isASCIIDigit(identifier[i]) {
+			hasDigit = true
+		} else {
+			hasOther = true
+		}
+	}
+	return hasDigit && hasOther
+}
+
+func TestToDebianStringKeepsOrder(t *testing.T) {
+	corpora := map[string][]*Version{}
+	for _, s := range testParseSemVerOrderInputs {
+		corpora["semver"] = append(corpora["semver"], parseOrFatalSemVer(t, s))
+	}
+	for _, s := range pythonTestStrings {
+		if v := parsePythonOrFatal(t, s); v.ParsedAs == PythonPEP440 {
+			corpora["python"] = append(corpora["python"], v)
+		}
+	}
+	for _, s := range perlDecimalBenchmarkStrings {
+		corpora["perl"] = append(corpora["perl"], parsePerlOrFatal(t, s))
+	}
+	for _, s := range perlVStringBenchmarkStrings {
+		corpora["perl"] = append(corpora["perl"], parsePerlOrFatal(t, s))
+	}
+	for _, s := range genericBenchmarkStrings {
+		corpora["generic"] = append(corpora["generic"], parseOrFatalGeneric(t, s))
+	}
+
+	for name, versions := range corpora {
+		t.Run(name, func(t *testing.T) {
+			var converted []*Version
+			var debian []string
+			for _, v := range versions {
+				d, err := v.ToDebianString("1")
+				if err != nil {
+					continue
+				}
+				assert.Regexp(t, debianVersionRegex, d, "%s converts to a valid Debian version", v.Original)
+				converted = append(converted, v)
+				debian = append(debian, d)
+			}
+			require.NotEmpty(t, converted, "some %s versions can be converted", name)
+
+			for i := range converted {
+				for j := range converted {
+					cmp := sign(Compare(converted[i], converted[j]))
+					if cmp == 0 {
+						// Versions that are only equal because of
+						// trailing zeros or build metadata may have
+						// different Debian versions.
+						continue
+					}
+					if name == "semver" && semVerOrderDiffersInDpkg(converted[i].Original, converted[j].Original) {
+						continue
+					}
+					assert.Equal(t, cmp, dpkgCompare(debian[i], debian[j]),
+						"%s (%s) compared to %s (%s)", converted[i].Original, debian[i], converted[j].Original, debian[j])
+				}
+			}
+		})
+	}
+}
+
+func parseDebianOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseDebian(v)
+	require.NoError(t, err, "no error parsing %v as a Debian version", v)
+	return ver
+}
+
+func TestParseDebian(t *testing.T) {
+	tests := map[string][]string{
+		"1.0":             {"0", "0", "1", "302"},
+		"1.0-1":           {"0", "0", "1", "302", "0", "0", "0", "0", "1"},
+		"1:2.34-0ubuntu3": {"1", "0", "2", "302", "0", "34", "0", "0", "0", "117", "98", "117", "110", "116", "117", "0", "3"},
+		"1.0~rc1":         {"0", "0", "1", "302", "0", "0", "-1", "114", "99", "0", "1"},
+		" 7 ":             {"0", "0", "7"},
+	}
+	for in, expected := range tests {
+		v, err := ParseDebian(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, Debian, v.ParsedAs, in)
+		assert.Equal(t, mustStringsToDecimal(t, expected), v.Decimal, in)
+	}
+}
+
+func TestParseDebianOrder(t *testing.T) {
+	versions := []string{
+		"0.9", "0.9-1", "1.0~~", "1.0~~a", "1.0~", "1.0~rc1", "1.0~rc1-1", "1.0", "1.0-0", "1.0-0ubuntu1",
+		"1.0-1~bpo11+1", "1.0-1", "1.0-1ubuntu0.1", "1.0-1+b1", "1.0-1.1", "1.0-2", "1.0-10", "1.0a", "1.0a0",
+		"1.0a0.1", "1.0+b", "1.0+dfsg-1", "1.0.1", "1.01", "1.1", "1.2", "1.10", "2.34-0ubuntu3",
+		"2.34-0ubuntu3.2", "1:0.1", "1:0.1-1", "2:1.0~beta-3",
+	}
+	for _, a := range versions {
+		for _, b := range versions {
+			assert.Equal(t, dpkgCompare(a, b), Compare(parseDebianOrFatal(t, a), parseDebianOrFatal(t, b)), "%s compared to %s", a, b)
+		}
+	}
+}
+
+func TestParseDebianErrors(t *testing.T) {
+	for in, expected := range map[string]string{
+		"":         "invalid Debian version: : upstream version does not start with a digit",
+		"a1.0":     "invalid Debian version: a1.0: upstream version does not start with a digit",
+		"1.0-":     "invalid Debian version: 1.0-: invalid revision",
+		"1.0-1_2":  "invalid Debian version: 1.0-1_2: invalid revision",
+		"1.0_1":    "invalid Debian version: 1.0_1: invalid upstream version",
+		":1.0":     "invalid Debian version: :1.0: epoch is not a number",
+		"a:1.0":    "invalid Debian version: a:1.0: epoch is not a number",
+		"1.0 beta": "invalid Debian version: 1.0 beta: invalid upstream version",
+		"1:-1":     "invalid Debian version: 1:-1: upstream version does not start with a digit",
+	} {
+		_, err := ParseDebian(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, expected, err.Error(), in)
+		}
+	}
+}
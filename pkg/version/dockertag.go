@@ -0,0 +1,113 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// dockerTagRegEx matches any tag that a registry accepts.
+var dockerTagRegEx = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+
+// dockerTagVersionRegEx matches a tag that starts with a version. The groups
+// are the numbers, the optional pre-release and the optional variant.
+var dockerTagVersionRegEx = regexp.MustCompile(
+	`^[vV]?([0-9]+(?:\.[0-9]+)*)` +
+		`(?:[-.]?((?i:preview|alpha|beta|pre|rc)(?:\.?[0-9]+)?|(?i:[ab])[0-9]+))?` +
+		`((?:[-_][A-Za-z0-9]|\.[A-Za-z])[A-Za-z0-9_.-]*)?$`)
+
+// dockerTagPreReleaseWords maps the short forms of pre-release words to the
+// long ones, so that "a1" equals "alpha1".
+var dockerTagPreReleaseWords = map[string]string{
+	"a": "alpha",
+	"b": "beta",
+}
+
+// SymbolicTagError is returned by ParseDockerTag for a tag that is valid but
+// does not start with a version, such as "latest", "stable" or "edge".
+type SymbolicTagError struct {
+	Tag string
+}
+
+func (e *SymbolicTagError) Error() string {
+	return fmt.Sprintf("docker tag %s is symbolic and has no version", e.Tag)
+}
+
+// ParseDockerTag parses a container image tag that starts with a version,
+// such as "1.25.3-alpine3.18", "v2.1.0", "1.25-bookworm" or "7.2-rc1-alpine".
+// The version is the numbers at the start of the tag, after an optional "v",
+// and an optional pre-release word of alpha, beta, pre, preview or rc, or a or
+// b followed by a number, as in "3.13.0b1". The variant is the rest of the
+// tag, which must start with "-" or "_", or with "." and a letter.
+//
+// Tags are ordered by their version first, and then by their variant, so all
+// of the tags of an image sort by the upstream version. A missing number is
+// 0, and a pre-release is less than the release, so "1.25-rc1" < "1.25" ==
+// "1.25.0" < "1.25.3". Pre-releases are ordered by their word and then their
+// number. A tag without a variant is less than a tag with one, and variants
+// are compared lexically, so "1.25.3" < "1.25.3-alpine3.18" <
+// "1.25.3-bookworm". The variant of a version is returned by
+// DockerTagVariant.
+//
+// It returns a *SymbolicTagError for a tag that does not start with a
+// version, and an error for a string that is not a valid tag.
+func ParseDockerTag(version string) (*Version, error) {
+	if !dockerTagRegEx.MatchString(version) {
+		return nil, fmt.Errorf("invalid docker tag: %s", version)
+	}
+	m := dockerTagVersionRegEx.FindStringSubmatch(version)
+	if m == nil {
+		rest := version
+		if rest[0] == 'v' || rest[0] == 'V' {
+			rest = rest[1:]
+		}
+		if rest == "" || !isASCIIDigit(rest[0]) {
+			return nil, &SymbolicTagError{Tag: version}
+		}
+		return nil, fmt.Errorf("invalid docker tag: %s", version)
+	}
+
+	numbers := strings.Split(m[1], ".")
+	for len(numbers) > 1 && strings.Trim(numbers[len(numbers)-1], "0") == "" {
+		numbers = numbers[:len(numbers)-1]
+	}
+	// The -1 after the numbers makes a version with fewer numbers less,
+	// whatever comes after them.
+	segments := append(numbers, "-1")
+
+	// The pre-release is -1, its word as a decimal and its number, or three
+	// zeros if there is none.
+	if pre := strings.ToLower(m[2]); pre != "" {
+		word := strings.TrimRight(pre, "."+asciiDigits)
+		n := pre[len(word):]
+		if long, ok := dockerTagPreReleaseWords[word]; ok {
+			word = long
+		}
+		n = strings.TrimPrefix(n, ".")
+		if n == "" {
+			n = "0"
+		}
+		segments = append(segments, "-1", asciiToDecimalString(word), n)
+	} else {
+		segments = append(segments, "0", "0", "0")
+	}
+
+	if m[3] != "" {
+		segments = append(segments, "1", asciiToDecimalString(m[3]))
+	}
+	return fromStringSlice(DockerTag, version, segments)
+}
+
+// DockerTagVariant returns the variant of a version parsed by ParseDockerTag,
+// which is the rest of the tag after the version, such as "-alpine3.18". It
+// returns "" if the tag has no variant, or if v is not a DockerTag version.
+func (v *Version) DockerTagVariant() string {
+	if v.ParsedAs != DockerTag {
+		return ""
+	}
+	m := dockerTagVersionRegEx.FindStringSubmatch(v.Original)
+	if m == nil {
+		return ""
+	}
+	return m[3]
+}
@@ -0,0 +1,84 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseDockerTagOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseDockerTag(v)
+	require.NoError(t, err, "no error parsing %v as a docker tag", v)
+	return ver
+}
+
+func TestParseDockerTag(t *testing.T) {
+	tests := map[string]struct {
+		segments []string
+		variant  string
+	}{
+		"v2.1.0":            {[]string{"2", "1", "-1"}, ""},
+		"1.25":              {[]string{"1", "25", "-1"}, ""},
+		"1.25-bookworm":     {[]string{"1", "25", "-1", "0", "0", "0", "1", "45.098111111107119111114109"}, "-bookworm"},
+		"7.2-rc1-alpine":    {[]string{"7", "2", "-1", "-1", "114.099", "1", "1", "45.097108112105110101"}, "-alpine"},
+		"3.13.0b1":          {[]string{"3", "13", "-1", "-1", "98.101116097", "1"}, ""},
+		"1.0-RC.2":          {[]string{"1", "-1", "-1", "114.099", "2"}, ""},
+		"1.2.final":         {[]string{"1", "2", "-1", "0", "0", "0", "1", "46.102105110097108"}, ".final"},
+		"20240101_slim":     {[]string{"20240101", "-1", "0", "0", "0", "1", "95.115108105109"}, "_slim"},
+		"1.25.3-alpine3.18": {[]string{"1", "25", "3", "-1", "0", "0", "0", "1", "45.097108112105110101051046049056"}, "-alpine3.18"},
+	}
+	for in, expected := range tests {
+		v := parseDockerTagOrFatal(t, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, DockerTag, v.ParsedAs, in)
+		assert.Equal(t, mustStringsToDecimal(t, expected.segments), v.Decimal, in)
+		assert.Equal(t, expected.variant, v.DockerTagVariant(), in)
+	}
+}
+
+var testParseDockerTagOrderInputs = []string{
+	"1.24.0",
+	"1.24.0-alpine",
+	"1.25-alpha",
+	"1.25-alpha2",
+	"1.25-beta1",
+	"1.25-rc1",
+	"1.25-rc1-alpine",
+	"1.25-rc2",
+	"1.25",
+	"1.25-alpine",
+	"1.25-bookworm",
+	"1.25.3",
+	"1.25.3-alpine3.18",
+	"1.25.3-alpine3.19",
+	"1.25.3-bookworm",
+	"1.25.10",
+	"v2.1.0",
+	"20240101",
+}
+
+var testParseDockerTagEqualInputs = [][]string{
+	{"1.25", "1.25.0"},
+	{"1.25", "v1.25"},
+	{"2.1.0-alpine", "V2.1-alpine"},
+	{"3.13.0a1", "3.13.0-alpha1"},
+	{"3.13.0rc1", "3.13.0-RC.1"},
+	{"1.0-rc", "1.0-rc0"},
+}
+
+func TestParseDockerTagErrors(t *testing.T) {
+	for _, in := range []string{"", "-1.0", ".1", "1.0:latest", "1.0 alpine", "1.0-", "1.0.", "1..0", "1.0--alpine", "1.0alpine"} {
+		_, err := ParseDockerTag(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid docker tag: "+in, err.Error(), in)
+		}
+	}
+
+	for _, in := range []string{"latest", "stable", "edge", "bookworm", "v", "version1"} {
+		_, err := ParseDockerTag(in)
+		if assert.IsType(t, &SymbolicTagError{}, err, in) {
+			assert.Equal(t, "docker tag "+in+" is symbolic and has no version", err.Error(), in)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// dotNetAssemblyRegEx matches the two to four numeric parts of an assembly
+// version.
+var dotNetAssemblyRegEx = regexp.MustCompile(`^([0-9]+)\.([0-9]+)(?:\.([0-9]+)(?:\.([0-9]+))?)?$`)
+
+// dotNetAssemblyMaxPart is the largest value that a part of an assembly
+// version may have.
+const dotNetAssemblyMaxPart = 65535
+
+// WithMissingPartsAsZero makes ParseDotNetAssembly treat a missing build or
+// revision as 0, so that "1.0" equals "1.0.0" and "1.0.0.0", as with other
+// version types.
+func WithMissingPartsAsZero() Option {
+	return func(o *options) {
+		o.dotNetMissingPartsAsZero = true
+	}
+}
+
+// ParseDotNetAssembly parses a .NET assembly or Windows file version, such as
+// "4.0.30319.42000", in the major.minor[.build[.revision]] form of
+// System.Version. Each part must be a number from 0 to 65535.
+//
+// As with System.Version, a missing build or revision is less than 0, so
+// "1.0" < "1.0.0" < "1.0.0.0". With the WithMissingPartsAsZero option, missing
+// parts are 0 instead.
+//
+// ParseDotNetAssembly honors the WithMissingPartsAsZero option.
+func ParseDotNetAssembly(version string, opts ...Option) (*Version, error) {
+	o := applyOptions(opts)
+
+	m := dotNetAssemblyRegEx.FindStringSubmatch(version)
+	if m == nil {
+		return nil, fmt.Errorf("invalid .NET assembly version: %s", version)
+	}
+
+	missing := "-1"
+	if o.dotNetMissingPartsAsZero {
+		missing = "0"
+	}
+	segments := make([]string, 0, 4)
+	for _, part := range m[1:] {
+		if part == "" {
+			segments = append(segments, missing)
+			continue
+		}
+		if n, err := strconv.Atoi(part); err != nil || n > dotNetAssemblyMaxPart {
+			return nil, fmt.Errorf("invalid .NET assembly version %s: %s is greater than %d", version, part, dotNetAssemblyMaxPart)
+		}
+		segments = append(segments, part)
+	}
+	return fromStringSlice(DotNetAssembly, version, segments)
+}
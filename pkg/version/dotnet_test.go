@@ -0,0 +1,70 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseDotNetAssemblyOrFatal(t *testing.T, v string, opts ...Option) *Version {
+	ver, err := ParseDotNetAssembly(v, opts...)
+	require.NoError(t, err, "no error parsing %v as a .NET assembly version", v)
+	return ver
+}
+
+func TestParseDotNetAssembly(t *testing.T) {
+	tests := map[string][]string{
+		"4.0.30319.42000": {"4", "0", "30319", "42000"},
+		"10.0.19041.1":    {"10", "0", "19041", "1"},
+		"6.0.0.0":         {"6"},
+		"1.0":             {"1", "0", "-1", "-1"},
+		"1.0.0":           {"1", "0", "0", "-1"},
+		"65535.65535":     {"65535", "65535", "-1", "-1"},
+		"01.002":          {"1", "2", "-1", "-1"},
+	}
+	for in, expected := range tests {
+		v := parseDotNetAssemblyOrFatal(t, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, DotNetAssembly, v.ParsedAs, in)
+		assert.Equal(t, mustStringsToDecimal(t, expected), v.Decimal, in)
+	}
+}
+
+var testParseDotNetAssemblyOrderInputs = []string{
+	"1.0", "1.0.0", "1.0.0.0", "1.0.0.1", "1.0.1", "1.0.1.0", "1.1", "2.0.50727.3053", "4.0",
+	"4.0.30319.1", "4.0.30319.17929", "4.0.30319.42000", "4.6.1055.0", "10.0.19041.1",
+}
+
+func TestParseDotNetAssemblyWithMissingPartsAsZero(t *testing.T) {
+	for _, pair := range [][2]string{{"1.0", "1.0.0"}, {"1.0", "1.0.0.0"}, {"4.0.30319", "4.0.30319.0"}} {
+		a := parseDotNetAssemblyOrFatal(t, pair[0], WithMissingPartsAsZero())
+		b := parseDotNetAssemblyOrFatal(t, pair[1], WithMissingPartsAsZero())
+		assert.Equal(t, 0, Compare(a, b), "%s == %s", pair[0], pair[1])
+	}
+	a := parseDotNetAssemblyOrFatal(t, "1.0", WithMissingPartsAsZero())
+	b := parseDotNetAssemblyOrFatal(t, "1.0.0.1", WithMissingPartsAsZero())
+	assert.Equal(t, -1, Compare(a, b))
+
+	v, err := Parse(DotNetAssembly, "1.0", WithMissingPartsAsZero())
+	require.NoError(t, err)
+	assert.Equal(t, mustStringsToDecimal(t, []string{"1"}), v.Decimal)
+}
+
+func TestParseDotNetAssemblyErrors(t *testing.T) {
+	for _, in := range []string{"", "1", "1.", "1.0.0.0.0", "1.0-beta", "1.0a", "v1.0", " 1.0", "1..0", "-1.0"} {
+		_, err := ParseDotNetAssembly(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid .NET assembly version: "+in, err.Error(), in)
+		}
+	}
+
+	for _, in := range []string{"65536.0", "1.0.0.99999999999999999999"} {
+		_, err := ParseDotNetAssembly(in)
+		assert.Error(t, err, in)
+	}
+	_, err := ParseDotNetAssembly("1.0.70000")
+	if assert.Error(t, err) {
+		assert.Equal(t, "invalid .NET assembly version 1.0.70000: 70000 is greater than 65535", err.Error())
+	}
+}
@@ -0,0 +1,63 @@
+package version
+
+// These export the test tables to the tests in ordering_test.go, which are in
+// the version_test package so that they can use the versiontest package.
+var (
+	SemVerOrderInputs = testParseSemVerOrderInputs
+	PHPOrderInputs    = testParsePHPOrderInputs
+	PHPEqualInputs    = testParsePHPEqualInputs
+	PythonOrderInputs = pythonTestStrings
+	RubyOrderInputs   = rubyTestStrings
+	RubyEqualInputs   = equalRubyVersions
+
+	MavenQualifierOrderInputs = testParseMavenQualifierOrderInputs
+	MavenNumberOrderInputs    = testParseMavenNumberOrderInputs
+	MavenOrderPairs           = testParseMavenOrderPairs
+	MavenEqualInputs          = testParseMavenEqualInputs
+
+	NuGetOrderInputs = testParseNuGetOrderInputs
+	NuGetEqualInputs = testParseNuGetEqualInputs
+
+	NpmOrderGroups = testParseNpmOrderGroups
+
+	GentooOrderPairs  = testParseGentooOrderPairs
+	GentooEqualInputs = testParseGentooEqualInputs
+
+	LuaRocksOrderInputs = testParseLuaRocksOrderInputs
+	LuaRocksEqualInputs = testParseLuaRocksEqualInputs
+
+	HexOrderInputs = testParseHexOrderInputs
+	HexEqualInputs = testParseHexEqualInputs
+
+	NixOrderPairs  = testParseNixOrderPairs
+	NixEqualInputs = testParseNixEqualInputs
+
+	CalVerOrderInputs = testParseCalVerOrderInputs
+	CalVerEqualInputs = testParseCalVerEqualInputs
+
+	JavaRuntimeOrderInputs = testParseJavaRuntimeOrderInputs
+	JavaRuntimeEqualInputs = testParseJavaRuntimeEqualInputs
+
+	DotNetAssemblyOrderInputs = testParseDotNetAssemblyOrderInputs
+
+	FreeBSDPortsOrderInputs = testParseFreeBSDPortsOrderInputs
+	FreeBSDPortsEqualInputs = testParseFreeBSDPortsEqualInputs
+
+	ConanOrderInputs = testParseConanOrderInputs
+	ConanEqualInputs = testParseConanEqualInputs
+
+	LinuxKernelOrderInputs = testParseLinuxKernelOrderInputs
+	LinuxKernelEqualInputs = testParseLinuxKernelEqualInputs
+
+	DockerTagOrderInputs = testParseDockerTagOrderInputs
+	DockerTagEqualInputs = testParseDockerTagEqualInputs
+
+	CPEOrderInputs = testParseCPEOrderInputs
+	CPEEqualInputs = testParseCPEEqualInputs
+
+	WindowsFileVersionOrderInputs = testParseWindowsFileVersionOrderInputs
+	WindowsFileVersionEqualInputs = testParseWindowsFileVersionEqualInputs
+
+	KubernetesOrderInputs = testParseKubernetesOrderInputs
+	KubernetesEqualInputs = testParseKubernetesEqualInputs
+)
@@ -0,0 +1,178 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// freeBSDPortsRegEx matches a port version, with the PORTVERSION, the
+// optional PORTREVISION after a "_" and the optional PORTEPOCH after a ",".
+var freeBSDPortsRegEx = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9.]*)(?:_([0-9]+))?(?:,([0-9]+))?$`)
+
+// freeBSDStages are the words that pkg gives a value of their own when they
+// start a component, in the order that pkg checks them.
+var freeBSDStages = []struct {
+	name  string
+	value string
+}{
+	{"pl", "0"},
+	{"alpha", "1"},
+	{"beta", "2"},
+	{"pre", "16"},
+	{"rc", "18"},
+}
+
+// freeBSDComponent is a component of a PORTVERSION as pkg splits it, which
+// is a number, then the value of a letter or stage, then a patch level.
+type freeBSDComponent [3]string
+
+var freeBSDZeroComponent = freeBSDComponent{"0", "0", "0"}
+
+// sign returns the sign of c compared to a missing component, which is the
+// same as freeBSDZeroComponent.
+func (c freeBSDComponent) sign() string {
+	for _, s := range c {
+		if s == "-1" {
+			return "-1"
+		}
+		if s != "0" {
+			return "1"
+		}
+	}
+	return "0"
+}
+
+// ParseFreeBSDPorts parses a FreeBSD port version, such as "1.2.3_1,1",
+// ordering it as pkg's pkg_version_cmp does. The version is the PORTVERSION,
+// then an optional PORTREVISION after a "_" and an optional PORTEPOCH after a
+// ",". The epoch is compared first, then the PORTVERSION, then the revision,
+// so "1.0_1" < "1.0_2" < "1.1" < "0.5,1". A missing revision or epoch is 0.
+//
+// The PORTVERSION is split into components at each ".", and where a letter
+// follows a number. Each component is a number, an optional letter or word
+// and an optional patch level number, compared in that order, and a missing
+// component is the same as "0". A component with a letter is greater than
+// one without, so "1.0" < "1.0.1" < "1.0a". Other than a letter, the number
+// may be followed by one of the words pl, alpha, beta, pre and rc, which
+// then starts a component with a number that is less than 0. So "1.0rc1" <
+// "1.0", and pl < alpha < beta < pre < rc.
+//
+// The PORTVERSION may contain letters, digits and "."; "+" and "*", which
+// pkg treats specially, are not allowed in ports.
+func ParseFreeBSDPorts(version string) (*Version, error) {
+	m := freeBSDPortsRegEx.FindStringSubmatch(version)
+	if m == nil {
+		return nil, fmt.Errorf("invalid freebsd ports version: %s", version)
+	}
+
+	components := freeBSDComponents(m[1])
+	for len(components) > 0 && components[len(components)-1] == freeBSDZeroComponent {
+		components = components[:len(components)-1]
+	}
+
+	epoch := m[3]
+	if epoch == "" {
+		epoch = "0"
+	}
+	segments := []string{epoch}
+	// Each component is its number, letter and patch level, then 0. A
+	// component that is the same as a missing one is three zeros, then the
+	// sign of the next component that is not, so that the end of the
+	// components, which is four zeros, compares to it as pkg compares a
+	// missing component to the components after it. The revision comes after
+	// the end.
+	for i, c := range components {
+		if c != freeBSDZeroComponent {
+			segments = append(segments, c[0], c[1], c[2], "0")
+			continue
+		}
+		for _, next := range components[i+1:] {
+			if next != freeBSDZeroComponent {
+				segments = append(segments, "0", "0", "0", next.sign())
+				break
+			}
+		}
+	}
+	segments = append(segments, "0", "0", "0", "0")
+	if m[2] != "" {
+		segments = append(segments, m[2])
+	}
+	return fromStringSlice(FreeBSDPorts, version, segments)
+}
+
+// freeBSDComponents splits a PORTVERSION into components as pkg's
+// get_component does.
+func freeBSDComponents(s string) []freeBSDComponent {
+	var components []freeBSDComponent
+	for i := 0; i < len(s); {
+		c := freeBSDZeroComponent
+		isStage := false
+		if isASCIIDigit(s[i]) {
+			start := i
+			for i < len(s) && isASCIIDigit(s[i]) {
+				i++
+			}
+			c[0] = trimLeadingZeros(s[start:i])
+		} else {
+			c[0] = "-1"
+			isStage = true
+		}
+
+		hasPatchLevel := false
+		if i < len(s) && isASCIILetter(s[i]) {
+			hasPatchLevel = true
+			name, value := freeBSDStage(s[i:])
+			switch {
+			case name != "" && isStage:
+				c[1] = value
+				i += len(name)
+			case name != "":
+				// The stage starts the next component.
+				hasPatchLevel = false
+			default:
+				c[1] = strconv.Itoa(int(strings.ToLower(s[i : i+1])[0]-'a') + 1)
+				for i < len(s) && isASCIILetter(s[i]) {
+					i++
+				}
+			}
+		}
+		if hasPatchLevel {
+			start := i
+			for i < len(s) && isASCIIDigit(s[i]) {
+				i++
+			}
+			if i > start {
+				c[2] = trimLeadingZeros(s[start:i])
+			} else {
+				c[2] = "-1"
+			}
+		}
+
+		for i < len(s) && !isASCIIDigit(s[i]) && !isASCIILetter(s[i]) {
+			i++
+		}
+		components = append(components, c)
+	}
+	return components
+}
+
+// freeBSDStage returns the name and value of the stage at the start of s, if
+// it is followed by something other than a letter.
+func freeBSDStage(s string) (string, string) {
+	for _, stage := range freeBSDStages {
+		n := len(stage.name)
+		if len(s) >= n && strings.EqualFold(s[:n], stage.name) && (len(s) == n || !isASCIILetter(s[n])) {
+			return stage.name, stage.value
+		}
+	}
+	return "", ""
+}
+
+func trimLeadingZeros(digits string) string {
+	if t := strings.TrimLeft(digits, "0"); t != "" {
+		return t
+	}
+	return "0"
+}
@@ -0,0 +1,77 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseFreeBSDPortsOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseFreeBSDPorts(v)
+	require.NoError(t, err, "no error parsing %v as a FreeBSD ports version", v)
+	return ver
+}
+
+func TestParseFreeBSDPorts(t *testing.T) {
+	tests := map[string][]string{
+		"1.0":      {"0", "1"},
+		"1.0_1,1":  {"1", "1", "0", "0", "0", "0", "0", "0", "0", "1"},
+		"1.0rc1":   {"0", "1", "0", "0", "0", "0", "0", "0", "-1", "-1", "18", "1"},
+		"1.0a":     {"0", "1", "0", "0", "0", "0", "1", "-1"},
+		"2.0.1b3":  {"0", "2", "0", "0", "0", "0", "0", "0", "1", "1", "2", "3"},
+		"1.0foo2":  {"0", "1", "0", "0", "0", "0", "6", "2"},
+		"alpha,3":  {"3", "-1", "1", "-1"},
+		"007.01_0": {"0", "7", "0", "0", "0", "1"},
+	}
+	for in, expected := range tests {
+		v := parseFreeBSDPortsOrFatal(t, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, FreeBSDPorts, v.ParsedAs, in)
+		assert.Equal(t, mustStringsToDecimal(t, expected), v.Decimal, in)
+	}
+}
+
+var testParseFreeBSDPortsOrderInputs = []string{
+	"0.9",
+	"1.0pl1",
+	"1.0alpha1",
+	"1.0alpha2",
+	"1.0beta1",
+	"1.0pre1",
+	"1.0rc1",
+	"1.0rc2",
+	"1.0",
+	"1.0_1",
+	"1.0_2",
+	"1.0.1",
+	"1.0a",
+	"1.0b",
+	"1.0b1",
+	"1.1",
+	"1.10",
+	"2.0",
+	"0.5,1",
+	"0.6,1",
+	"0.6_1,1",
+	"0.1,2",
+}
+
+var testParseFreeBSDPortsEqualInputs = [][]string{
+	{"1.0", "1"},
+	{"1.0", "1.0.0"},
+	{"1.0", "1.0_0"},
+	{"1.0", "1.0,0"},
+	{"1.0", "01.00"},
+	{"1.0rc1", "1.0RC1"},
+	{"1.0rc1", "1.0.rc1"},
+}
+
+func TestParseFreeBSDPortsErrors(t *testing.T) {
+	for _, in := range []string{"", "_1", ".1", "1.0_", "1.0,", "1.0_a", "1.0,1_1", "1.0-1", "1.0+1", "1.*", "1 0"} {
+		_, err := ParseFreeBSDPorts(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid freebsd ports version: "+in, err.Error(), in)
+		}
+	}
+}
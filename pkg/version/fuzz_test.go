@@ -0,0 +1,175 @@
+//go:build go1.18
+// +build go1.18
+
+package version
+
+import (
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// checkParsed fails the fuzz test if a parsing func returned something other
+// than an error or a well formed Version for input. Original is the input as
+// the parsing func is expected to store it.
+func checkParsed(t *testing.T, original string, v *Version, err error) {
+	if err != nil {
+		if v != nil {
+			t.Fatalf("parsing %q returned both a version and an error: %s", original, err)
+		}
+		return
+	}
+	if v == nil {
+		t.Fatalf("parsing %q returned neither a version nor an error", original)
+	}
+	if v.Original != original {
+		t.Fatalf("parsing %q stored the original as %q", original, v.Original)
+	}
+	if len(v.Decimal) == 0 {
+		t.Fatalf("parsing %q returned no segments", original)
+	}
+	for i, d := range v.Decimal {
+		if d == nil || !d.IsFinite() {
+			t.Fatalf("segment %d of %q is not a finite decimal: %v", i, original, d)
+		}
+	}
+	if cmp := Compare(v, v); cmp != 0 {
+		t.Fatalf("%q compares as %d with itself", original, cmp)
+	}
+	if cmp := Compare(v, v.Clone()); cmp != 0 {
+		t.Fatalf("%q compares as %d with its clone", original, cmp)
+	}
+}
+
+func addSeeds(f *testing.F, seeds ...string) {
+	for _, s := range seeds {
+		f.Add(s)
+	}
+}
+
+func FuzzParseGeneric(f *testing.F) {
+	for _, tt := range parseGenericTests {
+		f.Add(tt.version)
+	}
+	addSeeds(f, testParseSemVerOrderInputs...)
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := ParseGeneric(s)
+		checkParsed(t, norm.NFC.String(s), v, err)
+	})
+}
+
+func FuzzParseSemVer(f *testing.F) {
+	for _, tt := range parseSemVerTests {
+		f.Add(tt.version)
+	}
+	addSeeds(f, testParseSemVerOrderInputs...)
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := ParseSemVer(s)
+		checkParsed(t, s, v, err)
+	})
+}
+
+func FuzzParsePython(f *testing.F) {
+	for _, cases := range parsePythonTests {
+		for _, tt := range cases {
+			f.Add(tt.version)
+		}
+	}
+	addSeeds(f, pythonTestStrings...)
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := ParsePython(s)
+		checkParsed(t, s, v, err)
+	})
+}
+
+func FuzzParsePerl(f *testing.F) {
+	for _, cases := range parsePerlTests {
+		for _, tt := range cases {
+			f.Add(tt.version)
+		}
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := ParsePerl(s)
+		checkParsed(t, s, v, err)
+	})
+}
+
+func FuzzParsePHP(f *testing.F) {
+	for _, tt := range normalizePHPTests {
+		f.Add(tt[0])
+	}
+	for _, equal := range testParsePHPEqualInputs {
+		addSeeds(f, equal...)
+	}
+	addSeeds(f, testParsePHPOrderInputs...)
+	addSeeds(f, invalidPHPVersions...)
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := ParsePHP(s)
+		checkParsed(t, s, v, err)
+	})
+}
+
+func FuzzParseRuby(f *testing.F) {
+	for _, equal := range equalRubyVersions {
+		addSeeds(f, equal...)
+	}
+	addSeeds(f, invalidRubyVersions...)
+	addSeeds(f, rubyTestStrings...)
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := ParseRuby(s)
+		checkParsed(t, s, v, err)
+	})
+}
+
+func FuzzParseGo(f *testing.F) {
+	addSeeds(f, goModuleVersions...)
+	for in := range invalidGoModuleVersions {
+		f.Add(in)
+	}
+	for _, in := range testParseSemVerOrderInputs {
+		f.Add("v" + in)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := ParseGo(s)
+		checkParsed(t, s, v, err)
+	})
+}
+
+// FuzzCompare checks that Compare is antisymmetric for pairs of versions
+// parsed the same way.
+func FuzzCompare(f *testing.F) {
+	for i := 1; i < len(testParseSemVerOrderInputs); i++ {
+		f.Add(testParseSemVerOrderInputs[i-1], testParseSemVerOrderInputs[i])
+	}
+	for i := 1; i < len(pythonTestStrings); i++ {
+		f.Add(pythonTestStrings[i-1], pythonTestStrings[i])
+	}
+	for i := 1; i < len(rubyTestStrings); i++ {
+		f.Add(rubyTestStrings[i-1], rubyTestStrings[i])
+	}
+
+	parsers := []func(string) (*Version, error){
+		func(s string) (*Version, error) { return ParseGeneric(s) },
+		ParseSemVer,
+		ParsePython,
+		ParsePerl,
+		func(s string) (*Version, error) { return ParsePHP(s) },
+		ParseRuby,
+		func(s string) (*Version, error) { return ParseGo(s) },
+	}
+	f.Fuzz(func(t *testing.T, a, b string) {
+		for _, parse := range parsers {
+			va, err := parse(a)
+			if err != nil {
+				continue
+			}
+			vb, err := parse(b)
+			if err != nil {
+				continue
+			}
+			if ab, ba := Compare(va, vb), Compare(vb, va); ab != -ba {
+				t.Fatalf("Compare(%s, %s) is %d but Compare(%s, %s) is %d", va, vb, ab, vb, va, ba)
+			}
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// gentooRegEx matches an ebuild version, as with Portage's ver_regexp.
+var gentooRegEx = regexp.MustCompile(
+	`^([0-9]+)((?:\.[0-9]+)*)([a-z]?)((?:_(?:pre|p|beta|alpha|rc)[0-9]*)*)(?:-r([0-9]+))?$`)
+
+var gentooSuffixRegEx = regexp.MustCompile(`_(pre|p|beta|alpha|rc)([0-9]*)`)
+
+// gentooSuffixes maps each suffix to its segment, which orders the suffixes as
+// Portage does.
+var gentooSuffixes = map[string]string{
+	"alpha": "-4",
+	"beta":  "-3",
+	"pre":   "-2",
+	"rc":    "-1",
+	"p":     "0",
+}
+
+// ParseGentoo parses a Gentoo ebuild version, such as "1.2.3b_rc1_p2-r1",
+// ordering it as Portage's vercmp does.
+//
+// The numeric components are compared as numbers, except that a component
+// with a leading zero is compared as a decimal fraction, so "1.01" < "1.1". A
+// version with fewer components is less, so "1.0" < "1.0.0", and a letter
+// after the components is compared only when the components are equal, so
+// "1.0" < "1.0b" < "1.0.0".
+//
+// The suffixes are then compared in order, with _alpha < _beta < _pre < _rc <
+// _p, and a suffix without a number is the same as one with 0. A missing
+// suffix is less than _p and greater than the others, so
+// "1.0_alpha_rc1" < "1.0_alpha1" < "1.0" < "1.0_p1". Finally the revisions are
+// compared, and a missing revision is the same as "-r0".
+func ParseGentoo(version string) (*Version, error) {
+	matches := gentooRegEx.FindStringSubmatch(version)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("invalid gentoo version: %s", version)
+	}
+
+	segments := []string{matches[1]}
+	if matches[2] != "" {
+		for _, c := range strings.Split(matches[2][1:], ".") {
+			if len(c) > 1 && c[0] == '0' {
+				c = "0." + c
+			}
+			segments = append(segments, c)
+		}
+	}
+	// The -1 after the components makes a version with fewer components
+	// less, whatever comes after them. The letter is 0 if there is none.
+	letter := "0"
+	if matches[3] != "" {
+		letter = fmt.Sprint(matches[3][0])
+	}
+	segments = append(segments, "-1", letter)
+
+	for _, s := range gentooSuffixRegEx.FindAllStringSubmatch(matches[4], -1) {
+		n := s[2]
+		if n == "" {
+			n = "0"
+		}
+		segments = append(segments, gentooSuffixes[s[1]], n)
+	}
+	// The end of the suffixes is between _rc and _p, as a missing suffix is
+	// in Portage.
+	segments = append(segments, "-0.5")
+
+	if matches[5] != "" {
+		segments = append(segments, matches[5])
+	}
+	return fromStringSlice(Gentoo, version, segments)
+}
@@ -0,0 +1,106 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseGentooOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseGentoo(v)
+	require.NoError(t, err, "no error parsing %v as a Gentoo version", v)
+	return ver
+}
+
+func TestParseGentoo(t *testing.T) {
+	tests := map[string][]string{
+		"1":                  {"1", "-1", "0", "-0.5"},
+		"1.0":                {"1", "0", "-1", "0", "-0.5"},
+		"1.01b":              {"1", "0.01", "-1", "98", "-0.5"},
+		"1.2.3_rc1_p-r2":     {"1", "2", "3", "-1", "0", "-1", "1", "0", "0", "-0.5", "2"},
+		"2_alpha_pre2-r0":    {"2", "-1", "0", "-4", "0", "-2", "2", "-0.5"},
+		"20231001_p20240101": {"20231001", "-1", "0", "0", "20240101", "-0.5"},
+	}
+	for in, expected := range tests {
+		v := parseGentooOrFatal(t, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, Gentoo, v.ParsedAs, in)
+		assert.Equal(t, mustStringsToDecimal(t, expected), v.Decimal, in)
+	}
+}
+
+// The following versions are from Portage's test_vercmp.py.
+
+var testParseGentooOrderPairs = [][]string{
+	{"4.0", "5.0"},
+	{"5", "5.0"},
+	{"1.0_pre2", "1.0_p2"},
+	{"1.0_alpha2", "1.0_p2"},
+	{"1.0_alpha1", "1.0_beta1"},
+	{"1.0_beta3", "1.0_rc3"},
+	{"1.001000000000000000001", "1.001000000000000000002"},
+	{"1.00100000000", "1.0010000000000000001"},
+	{"999999999999999999999999999998", "999999999999999999999999999999"},
+	{"1.01", "1.1"},
+	{"1.0-r0", "1.0-r1"},
+	{"1.0", "1.0-r1"},
+	{"1.0", "1.0.0"},
+	{"1.0b", "1.0.0"},
+	{"1_p1", "1b_p1"},
+	{"1", "1b"},
+	{"1.1", "1.1b"},
+	{"12.2b", "12.2.5"},
+	{"1.0_alpha_rc1", "1.0_alpha1"},
+	{"1.0_alpha1", "1.0"},
+	{"1.0-r1", "1.0_p1"},
+	{"1.0", "1.0_p"},
+	{"1.0_rc99", "1.0"},
+	{"1.0_p1", "1.0_p1_p1"},
+	{"1.0_p1_alpha", "1.0_p1"},
+	{"1.0_p1-r9", "1.0_p2"},
+	{"1.05", "1.1"},
+	{"1.1", "1.10"},
+	{"1.09", "1.10"},
+	{"1.9", "1.10"},
+	{"1.0.9", "1.1"},
+}
+
+var testParseGentooEqualInputs = [][]string{
+	{"4.0", "4.0"},
+	{"1.0", "1.0"},
+	{"1.0-r0", "1.0"},
+	{"1.0-r0", "1.0-r0"},
+	{"1.0-r1", "1.0-r1"},
+	{"1.0_p", "1.0_p0"},
+	{"1.0_rc", "1.0_rc0"},
+	{"1.0", "1.00"},
+	{"1.010", "1.01"},
+	{"01.0", "1.0"},
+}
+
+func TestParseGentooErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"a",
+		"1.",
+		".1",
+		"1..0",
+		"1.0ab",
+		"1.0B",
+		"1.0_",
+		"1.0_pr1",
+		"1.0_gamma",
+		"1.0-r",
+		"1.0-r1-r2",
+		"1.0-1",
+		"1.0_p1b",
+		"v1.0",
+		" 1.0",
+	} {
+		_, err := ParseGentoo(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid gentoo version: "+in, err.Error(), in)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// goToolchainRegex matches a Go toolchain or language version, such as
+// "1.21", "1.21rc2" or "go1.21.3".
+var goToolchainRegex = regexp.MustCompile(`^(?:go)?(0|[1-9][0-9]*)(?:\.(0|[1-9][0-9]*)(?:\.(0|[1-9][0-9]*)|(alpha|beta|rc)(0|[1-9][0-9]*))?)?$`)
+
+// goToolchainPreReleases maps the pre-release kinds of Go toolchain versions
+// to segments that sort them below releases.
+var goToolchainPreReleases = map[string]string{
+	"alpha": "-3",
+	"beta":  "-2",
+	"rc":    "-1",
+}
+
+// ParseGo parses a Go module version, such as "v1.2.3",
+// "v0.0.0-20220314234659-1baeb1ce4c0b" or "v2.0.0+incompatible". These are
+// semver versions with a leading "v", so the Version has the same segments as
+// ParseSemVer gives for the version without the "v", and is comparable with
+// SemVer versions, but it is parsed as Go and its Original keeps the "v". The
+// only build metadata Go allows is "+incompatible", and like all build
+// metadata it is ignored when versions are compared.
+//
+// Go also accepts shorthands like "v1.2" in some places, but module versions
+// are always complete, so ParseGo does not.
+//
+// ParseGo honors the WithMaxSegments and WithSegmentOverflow options.
+func ParseGo(version string, opts ...Option) (*Version, error) {
+	if !strings.HasPrefix(version, "v") {
+		return nil, fmt.Errorf("go module version does not start with v: %s", version)
+	}
+	v, err := ParseSemVer(version[1:])
+	if err != nil {
+		return nil, err
+	}
+	if i := strings.IndexByte(version, '+'); i >= 0 && version[i:] != "+incompatible" {
+		return nil, fmt.Errorf("go module version has build metadata other than +incompatible: %s", version)
+	}
+	v.Original = version
+	v.ParsedAs = Go
+	return limitSegments(v, applyOptions(opts))
+}
+
+// ParseGoToolchain parses the name of a Go toolchain or release, such as
+// "go1.21.6", "go1.22rc1", "go1.20beta2" or "go1.19", or a version from the go
+// and toolchain directives of go.mod files, such as "1.21". The "go" prefix is
+// optional. A missing patch number is 0, and a beta or rc pre-release is less
+// than the release, so "go1.21rc4" < "go1.21.0" < "go1.21.1" < "go1.22beta1"
+// < "go1.22", and "go1.22" equals "go1.22.0".
+//
+// Unlike the go command, which treats a version without a patch number like
+// "1.21" as the language version and orders it before "1.21rc1", this orders
+// it as the release that Go names without a patch number up to go1.20.
+func ParseGoToolchain(version string) (*Version, error) {
+	m := goToolchainRegex.FindStringSubmatch(version)
+	if m == nil {
+		return nil, fmt.Errorf("invalid go toolchain version: %s", version)
+	}
+
+	major, minor, patch, kind, pre := m[1], m[2], m[3], m[4], m[5]
+	if minor == "" {
+		minor = "0"
+	}
+	segments := []string{major, minor}
+	switch {
+	case patch != "":
+		segments = append(segments, patch)
+	case kind != "":
+		segments = append(segments, goToolchainPreReleases[kind], pre)
+	default:
+		segments = append(segments, "0")
+	}
+	return fromStringSlice(GoToolchain, version, segments)
+}
+
+// GoModuleString returns v in the canonical form golang.org/x/mod/semver uses
+// for Go module versions. This is the semver version with a leading "v" and
+// without any build metadata, so "1.2.3-rc.1+build.5" becomes "v1.2.3-rc.1".
+// Like semver.Canonical, it drops "+incompatible" as well, as that is build
+// metadata too.
+//
+// It returns an error for versions not parsed as SemVer or Go, since other
+// schemes have no Go module form. Versions from ParseGo are already in this
+// form, apart from "+incompatible".
+func (v *Version) GoModuleString() (string, error) {
+	if v.ParsedAs != SemVer && v.ParsedAs != Go {
+		return "", fmt.Errorf("cannot make a Go module version from %s version %s", v.ParsedAs, v.Original)
+	}
+
+	matches := semVerRegEx.FindStringSubmatch(strings.TrimPrefix(v.Original, "v"))
+	if matches == nil {
+		return "", fmt.Errorf("cannot make a Go module version from %s: original version is not semver", v.Original)
+	}
+
+	var b strings.Builder
+	b.Grow(len(v.Original) + 1)
+	b.WriteByte('v')
+	b.WriteString(matches[1])
+	b.WriteByte('.')
+	b.WriteString(matches[2])
+	b.WriteByte('.')
+	b.WriteString(matches[3])
+	if preRelease := matches[4]; preRelease != "" {
+		b.WriteByte('-')
+		b.WriteString(preRelease)
+	}
+	return b.String(), nil
+}
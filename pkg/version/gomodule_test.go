@@ -0,0 +1,139 @@
+package version
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoModuleString(t *testing.T) {
+	// The expected values are what golang.org/x/mod/semver.Canonical returns
+	// for the input with a "v" prefix.
+	tests := map[string]string{
+		"1.2.3":                               "v1.2.3",
+		"0.0.0":                               "v0.0.0",
+		"10.20.30":                            "v10.20.30",
+		"1.2.3+build.5":                       "v1.2.3",
+		"1.2.3-rc.1+build.5":                  "v1.2.3-rc.1",
+		"1.0.0-alpha.1":                       "v1.0.0-alpha.1",
+		"2.0.0+incompatible":                  "v2.0.0",
+		"2.1.0-beta+incompatible":             "v2.1.0-beta",
+		"0.0.0-20191109021931-daa7c04131f5":   "v0.0.0-20191109021931-daa7c04131f5",
+		"1.2.4-0.20191109021931-daa7c04131f5": "v1.2.4-0.20191109021931-daa7c04131f5",
+		"1.2.3----RC-SNAPSHOT.12.9.1--.12":    "v1.2.3----RC-SNAPSHOT.12.9.1--.12",
+		"1.2.3-0A.is.legal":                   "v1.2.3-0A.is.legal",
+	}
+
+	for in, expected := range tests {
+		v := parseOrFatalSemVer(t, in)
+		actual, err := v.GoModuleString()
+		require.NoError(t, err, in)
+		assert.Equal(t, expected, actual, in)
+	}
+}
+
+func TestGoModuleStringErrors(t *testing.T) {
+	_, err := parsePythonOrFatal(t, "1.2.3").GoModuleString()
+	assert.Error(t, err, "python versions have no Go module form")
+
+	_, err = parseOrFatalGeneric(t, "1.2.3").GoModuleString()
+	assert.Error(t, err, "generic versions have no Go module form")
+
+	v := parseOrFatalSemVer(t, "1.2.3")
+	v.Original = "not semver"
+	_, err = v.GoModuleString()
+	assert.Error(t, err, "the original version must be semver")
+}
+
+func parseGoOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseGo(v)
+	require.NoError(t, err, "no error parsing %v as a Go module version", v)
+	return ver
+}
+
+var goModuleVersions = []string{
+	"v1.5.7",
+	"v0.0.0-20220314234659-1baeb1ce4c0b",
+	"v1.2.4-0.20191109021931-daa7c04131f5",
+	"v2.0.0+incompatible",
+	"v2.1.0-beta+incompatible",
+	"v1.0.0-rc.1",
+}
+
+var invalidGoModuleVersions = map[string]string{
+	"1.5.7":        "go module version does not start with v: 1.5.7",
+	"v1.5":         "Version does not match semver regex: 1.5",
+	"vx.y.z":       "Version does not match semver regex: x.y.z",
+	"v1.5.7+build": "go module version has build metadata other than +incompatible: v1.5.7+build",
+	"":             "go module version does not start with v: ",
+}
+
+func TestParseGo(t *testing.T) {
+	for _, in := range goModuleVersions {
+		v, err := ParseGo(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, Go, v.ParsedAs, in)
+		assert.Equal(t, parseOrFatalSemVer(t, in[1:]).Decimal, v.Decimal, in)
+
+		roundTrip, err := Parse(Go, in)
+		require.NoError(t, err, in)
+		assert.Equal(t, v, roundTrip, in)
+		roundTrip, err = ParseVersionString(v.String())
+		require.NoError(t, err, in)
+		assert.Equal(t, v, roundTrip, in)
+
+		canonical, err := v.GoModuleString()
+		require.NoError(t, err, in)
+		assert.Equal(t, strings.TrimSuffix(in, "+incompatible"), canonical)
+	}
+
+	for in, expected := range invalidGoModuleVersions {
+		_, err := ParseGo(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, expected, err.Error(), in)
+		}
+	}
+}
+
+func parseGoToolchainOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseGoToolchain(v)
+	require.NoError(t, err, "no error parsing %v as a Go toolchain version", v)
+	return ver
+}
+
+func TestParseGoToolchain(t *testing.T) {
+	ordered := []string{"1.9", "go1.9.2", "go1.20beta2", "1.20", "1.21alpha1", "1.21beta1", "1.21rc1", "go1.21rc2", "go1.21rc4", "1.21.0", "go1.21.1", "1.21.3", "go1.21.6", "go1.22beta1", "go1.22rc1", "go1.22", "go1.22.1"}
+	var prev *Version
+	for _, in := range ordered {
+		v, err := ParseGoToolchain(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, GoToolchain, v.ParsedAs, in)
+		if prev != nil {
+			assert.True(t, Compare(prev, v) < 0, "%s < %s", prev.Original, in)
+		}
+		prev = v
+	}
+
+	a, err := ParseGoToolchain("go1.21.3")
+	require.NoError(t, err)
+	b, err := ParseGoToolchain("1.21.3")
+	require.NoError(t, err)
+	assert.Equal(t, 0, Compare(a, b), "the go prefix is optional")
+
+	for _, pair := range [][2]string{{"go1.22", "go1.22.0"}, {"go1.19", "1.19.0"}, {"go1", "go1.0.0"}} {
+		a, err := ParseGoToolchain(pair[0])
+		require.NoError(t, err, pair[0])
+		b, err := ParseGoToolchain(pair[1])
+		require.NoError(t, err, pair[1])
+		assert.Equal(t, 0, Compare(a, b), "%s == %s", pair[0], pair[1])
+	}
+
+	for _, in := range []string{"", "go", "1.21.", "v1.21.0", "1.21-rc1", "1.021", "1rc1", "1.21.0rc1", "1.21rc", "1.21 "} {
+		_, err := ParseGoToolchain(in)
+		assert.Error(t, err, in)
+	}
+}
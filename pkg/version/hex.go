@@ -0,0 +1,24 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseHex parses the version of a Hex package, as used by Elixir and Erlang,
+// such as "1.7.14" or "1.0.0-rc.0". Hex requires complete semver 2.0
+// versions, so versions without a patch number, like "1.0", are rejected.
+// Versions are ordered as with ParseSemVer, which matches Elixir's
+// Version.compare/2. Build metadata does not affect the order, but is kept in
+// the BuildMetadata field of the returned Version.
+func ParseHex(version string) (*Version, error) {
+	v, err := ParseSemVer(version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex version: %s", version)
+	}
+	v.ParsedAs = Hex
+	if i := strings.IndexByte(version, '+'); i >= 0 {
+		v.BuildMetadata = version[i+1:]
+	}
+	return v, nil
+}
@@ -0,0 +1,50 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseHexOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseHex(v)
+	require.NoError(t, err, "no error parsing %v as a Hex version", v)
+	return ver
+}
+
+func TestParseHex(t *testing.T) {
+	tests := map[string]string{
+		"1.7.14":                "",
+		"0.0.0":                 "",
+		"1.0.0-rc.0":            "",
+		"2.0.0-beta.2+exp.sha":  "exp.sha",
+		"1.0.0+20240101-abcdef": "20240101-abcdef",
+	}
+	for in, metadata := range tests {
+		v := parseHexOrFatal(t, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, Hex, v.ParsedAs, in)
+		assert.Equal(t, parseOrFatalSemVer(t, in).Decimal, v.Decimal, in)
+		assert.Equal(t, metadata, v.BuildMetadata, in)
+	}
+}
+
+var testParseHexOrderInputs = []string{
+	"0.9.9", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-beta", "1.0.0-rc.0", "1.0.0-rc.1", "1.0.0", "1.0.1",
+	"1.10.0", "2.0.0",
+}
+
+// Build metadata is ignored.
+var testParseHexEqualInputs = [][]string{
+	{"1.0.0+a", "1.0.0+b"},
+}
+
+func TestParseHexErrors(t *testing.T) {
+	for _, in := range []string{"", "1", "1.0", "v1.0.0", "1.0.0.0", "01.0.0", "1.0.0-", "1.0.0+", " 1.0.0"} {
+		_, err := ParseHex(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid hex version: "+in, err.Error(), in)
+		}
+	}
+}
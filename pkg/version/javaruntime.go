@@ -0,0 +1,80 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// javaRuntimeRegEx is the version string format from Java's Runtime.Version.
+// The groups are the version number, the pre-release, the "+", the build
+// number and the optional information.
+var javaRuntimeRegEx = regexp.MustCompile(
+	`^([1-9][0-9]*(?:(?:\.0)*\.[1-9][0-9]*)*)` +
+		`(?:-([a-zA-Z0-9]+))?` +
+		`(?:(\+)(0|[1-9][0-9]*)?)?` +
+		`(?:-([-a-zA-Z0-9.]+))?$`)
+
+// matchJavaRuntime returns the groups of javaRuntimeRegEx for version, or nil
+// if it is not a valid version string.
+func matchJavaRuntime(version string) []string {
+	m := javaRuntimeRegEx.FindStringSubmatch(version)
+	if m == nil {
+		return nil
+	}
+	pre, plus, build, opt := m[2], m[3], m[4], m[5]
+	// As in Runtime.Version.parse, the optional information must come after
+	// a pre-release or a "+", and a "+" must come before a build number or
+	// the optional information.
+	if plus == "" && opt != "" && pre == "" || plus != "" && build == "" && opt == "" {
+		return nil
+	}
+	return m
+}
+
+// ParseJavaRuntime parses a Java runtime version string as described in JEP
+// 223, such as "17", "11.0.21+9-LTS", "21.0.2+13" or "9-ea+19", ordering it as
+// Java's Runtime.Version.compareTo does.
+//
+// The version numbers are compared first, and a version with more numbers is
+// greater, so "9" < "9.0.1". The last number may not be 0. Then a version with
+// a pre-release is less than one without, so "9-ea" < "9". Pre-releases that
+// are numbers are compared as numbers, and are less than other pre-releases,
+// which are compared lexically. Unlike in semver, the build number is
+// compared next, and a version without one is less than a version with one,
+// so "9" < "9+0" < "9+1". Finally the optional information is compared
+// lexically, and a version without it is less.
+func ParseJavaRuntime(version string) (*Version, error) {
+	m := matchJavaRuntime(version)
+	if m == nil {
+		return nil, fmt.Errorf("invalid java runtime version: %s", version)
+	}
+
+	segments := strings.Split(m[1], ".")
+	// The -1 after the version numbers makes a version with fewer numbers
+	// less, whatever comes after them.
+	segments = append(segments, "-1")
+
+	// The pre-release is -2 and its number, or -1 and its string as a
+	// decimal, or 0 and 0 if there is none.
+	switch pre := m[2]; {
+	case pre == "":
+		segments = append(segments, "0", "0")
+	case strings.Trim(pre, asciiDigits) == "":
+		segments = append(segments, "-2", pre)
+	default:
+		segments = append(segments, "-1", asciiToDecimalString(pre))
+	}
+
+	// The build and optional information are 1 and their value, or 0 and 0
+	// if there is none.
+	if m[4] != "" {
+		segments = append(segments, "1", m[4])
+	} else {
+		segments = append(segments, "0", "0")
+	}
+	if m[5] != "" {
+		segments = append(segments, "1", asciiToDecimalString(m[5]))
+	}
+	return fromStringSlice(JavaRuntime, version, segments)
+}
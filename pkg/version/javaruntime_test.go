@@ -0,0 +1,83 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseJavaRuntimeOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseJavaRuntime(v)
+	require.NoError(t, err, "no error parsing %v as a Java runtime version", v)
+	return ver
+}
+
+func TestParseJavaRuntime(t *testing.T) {
+	tests := map[string][]string{
+		"17":            {"17", "-1"},
+		"21.0.2+13":     {"21", "0", "2", "-1", "0", "0", "1", "13"},
+		"9-ea+19":       {"9", "-1", "-1", "101.097", "1", "19"},
+		"11.0.21+9-LTS": {"11", "0", "21", "-1", "0", "0", "1", "9", "1", "76.084083"},
+		"9-1":           {"9", "-1", "-2", "1"},
+		"9+-opt":        {"9", "-1", "0", "0", "0", "0", "1", "111.112116"},
+		"9-ea-opt":      {"9", "-1", "-1", "101.097", "0", "0", "1", "111.112116"},
+	}
+	for in, expected := range tests {
+		v := parseJavaRuntimeOrFatal(t, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, JavaRuntime, v.ParsedAs, in)
+		assert.Equal(t, mustStringsToDecimal(t, expected), v.Decimal, in)
+	}
+}
+
+var testParseJavaRuntimeOrderInputs = []string{
+	"8.1",
+	"9-1",
+	"9-2+1",
+	"9-10",
+	"9-ea",
+	"9-ea+19",
+	"9-ea+20",
+	"9",
+	"9+0",
+	"9+1",
+	"9+1-a",
+	"9+1-b",
+	"9+2",
+	"9.0.1",
+	"9.0.1.1",
+	"9.1",
+	"11.0.21+9",
+	"11.0.21+9-LTS",
+	"17",
+	"21.0.2+13",
+}
+
+var testParseJavaRuntimeEqualInputs = [][]string{
+	{"9-007", "9-7"},
+}
+
+func TestParseJavaRuntimeErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"0",
+		"1.8.0_392",
+		"9.0",
+		"9.1.0",
+		"09",
+		"9-",
+		"9+",
+		"9-ea+",
+		"9+01",
+		"9-opt-",
+		"9-e.a",
+		"v17",
+		"17 ",
+	} {
+		_, err := ParseJavaRuntime(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid java runtime version: "+in, err.Error(), in)
+		}
+	}
+}
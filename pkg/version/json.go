@@ -0,0 +1,89 @@
+package version
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// EncodeJSONStream writes vs to w as a JSON array. The output is the same as
+// json.Marshal(vs), but each version is encoded and written in turn, so the
+// memory used does not grow with the length of vs.
+func EncodeJSONStream(w io.Writer, vs []*Version) error {
+	if vs == nil {
+		// This is what json.Marshal does with a nil slice.
+		_, err := io.WriteString(w, "null")
+		return err
+	}
+
+	i := 0
+	return encodeJSONArray(w, func() (*Version, bool) {
+		if i == len(vs) {
+			return nil, false
+		}
+		i++
+		return vs[i-1], true
+	})
+}
+
+// EncodeJSONStreamChan writes each version received from vs to w as a JSON
+// array, until vs is closed. If writing fails it returns the error without
+// reading any more versions, so the sender should have some other way to stop
+// in that case, such as a context.
+func EncodeJSONStreamChan(w io.Writer, vs <-chan *Version) error {
+	return encodeJSONArray(w, func() (*Version, bool) {
+		v, ok := <-vs
+		return v, ok
+	})
+}
+
+// EncodeNDJSON writes vs to w as newline delimited JSON, with each version
+// encoded on its own line as json.Marshal would encode it.
+func EncodeNDJSON(w io.Writer, vs []*Version) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, v := range vs {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func encodeJSONArray(w io.Writer, next func() (*Version, bool)) error {
+	bw := bufio.NewWriter(w)
+
+	// json.Encoder always follows each value with a newline, which
+	// json.Marshal does not, so values are encoded into buf first and written
+	// without it.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+
+	if err := bw.WriteByte('['); err != nil {
+		return err
+	}
+	for first := true; ; first = false {
+		v, ok := next()
+		if !ok {
+			break
+		}
+		if !first {
+			if err := bw.WriteByte(','); err != nil {
+				return err
+			}
+		}
+
+		buf.Reset()
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		if _, err := bw.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))); err != nil {
+			return err
+		}
+	}
+	if err := bw.WriteByte(']'); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,144 @@
+package version
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func jsonTestVersions(t *testing.T) []*Version {
+	return []*Version{
+		parseOrFatalSemVer(t, "1.0.0-alpha.1"),
+		parsePythonOrFatal(t, "1.0+ubuntu.1"),
+		parseRubyOrFatal(t, "2.0.b1"),
+		nil,
+		parseOrFatalGeneric(t, `1.0 "<quoted>" & more`),
+	}
+}
+
+func TestEncodeJSONStream(t *testing.T) {
+	for name, vs := range map[string][]*Version{
+		"nil":      nil,
+		"empty":    {},
+		"one":      {parseOrFatalGeneric(t, "1.2.3")},
+		"several":  jsonTestVersions(t),
+		"only nil": {nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			expected, err := json.Marshal(vs)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, EncodeJSONStream(&buf, vs))
+			assert.Equal(t, string(expected), buf.String())
+		})
+	}
+}
+
+func TestEncodeJSONStreamChan(t *testing.T) {
+	vs := jsonTestVersions(t)
+	expected, err := json.Marshal(vs)
+	require.NoError(t, err)
+
+	ch := make(chan *Version)
+	go func() {
+		for _, v := range vs {
+			ch <- v
+		}
+		close(ch)
+	}()
+
+	var buf bytes.Buffer
+	require.NoError(t, EncodeJSONStreamChan(&buf, ch))
+	assert.Equal(t, string(expected), buf.String())
+
+	empty := make(chan *Version)
+	close(empty)
+	buf.Reset()
+	require.NoError(t, EncodeJSONStreamChan(&buf, empty))
+	assert.Equal(t, "[]", buf.String())
+}
+
+func TestEncodeNDJSON(t *testing.T) {
+	vs := jsonTestVersions(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, EncodeNDJSON(&buf, vs))
+
+	scanner := bufio.NewScanner(&buf)
+	i := 0
+	for scanner.Scan() {
+		require.True(t, i < len(vs), "no more lines than versions")
+		expected, err := json.Marshal(vs[i])
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), scanner.Text())
+		i++
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, len(vs), i, "one line per version")
+}
+
+type failingWriter struct{}
+
+var errFailingWriter = errors.New("write failed")
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errFailingWriter
+}
+
+func TestEncodeJSONStreamWriteError(t *testing.T) {
+	// Enough versions to fill the buffer so that the error happens part way
+	// through and not when flushing at the end.
+	var vs []*Version
+	for i := 0; i < 1000; i++ {
+		vs = append(vs, parseOrFatalGeneric(t, strings.Repeat("1.", 10)+"1"))
+	}
+
+	assert.Equal(t, errFailingWriter, EncodeJSONStream(failingWriter{}, vs))
+	assert.Equal(t, errFailingWriter, EncodeNDJSON(failingWriter{}, vs))
+	assert.Equal(t, errFailingWriter, EncodeJSONStream(failingWriter{}, nil))
+}
+
+func TestJSONSegmentsAreStrings(t *testing.T) {
+	v := parseOrFatalGeneric(t, "1.0-supercalifragilistic")
+	longIndex := len(v.Decimal) - 1
+	long := v.Decimal[longIndex].String()
+	require.True(t, len(long) > 20, "the word segment has more digits than a float64 holds")
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	var generic struct {
+		SortableVersion []interface{} `json:"sortable_version"`
+	}
+	require.NoError(t, json.Unmarshal(data, &generic))
+	for _, segment := range generic.SortableVersion {
+		assert.IsType(t, "", segment, "segments are JSON strings")
+	}
+	assert.Equal(t, long, generic.SortableVersion[longIndex])
+
+	// This is what a consumer that reads JSON numbers as float64, as
+	// JavaScript does, would have got if the segment were a number.
+	var f float64
+	require.NoError(t, json.Unmarshal([]byte(long), &f))
+	assert.NotEqual(t, long, strconv.FormatFloat(f, 'f', -1, 64), "a float64 loses precision")
+
+	var decoded Version
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, 0, Compare(v, &decoded))
+	assert.Equal(t, long, decoded.Decimal[longIndex].String())
+}
+
+func TestJSONUnmarshalAcceptsNumberSegments(t *testing.T) {
+	var v Version
+	require.NoError(t, json.Unmarshal([]byte(`{"version":"1.2.5","sortable_version":[1,"2",5.0]}`), &v))
+	assert.Equal(t, "1.2.5", v.Original)
+	assert.Equal(t, 0, Compare(parseOrFatalGeneric(t, "1.2.5"), &v))
+}
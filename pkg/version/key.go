@@ -0,0 +1,278 @@
+package version
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// Each segment of a compare key starts with one of these class bytes. A zero
+// segment sorts before or after the end of the key depending on whether the
+// first non-zero segment after it is negative or positive, which is what makes
+// "1.0.-1" < "1" < "1.0.1" hold for keys as it does for Compare.
+const (
+	keyNegative         = 0x01
+	keyZeroThenNegative = 0x02
+	keyEnd              = 0x03
+	keyZeroThenPositive = 0x04
+	keyPositive         = 0x05
+)
+
+// Exponents in this range are encoded as a single byte, anything else takes
+// nine.
+const (
+	keyMinSmallExponent = -64
+	keyMaxSmallExponent = 63
+
+	keyLargeNegativeExponent = 0x10
+	keySmallExponentBase     = 0x20
+	keyLargePositiveExponent = 0xa0
+)
+
+// CompareKey returns a byte string that orders the same way as v does under
+// Compare: for any two versions, bytes.Compare(v1.CompareKey(),
+// v2.CompareKey()) has the same sign as Compare(v1, v2). Versions that only
+// differ by trailing zeros have identical keys.
+//
+// The key is 1 byte for the end of the version, plus 1 byte for each zero
+// segment and 3 + ceil(n/2) bytes for each other segment, where n is the
+// number of significant digits in the segment. Segments whose magnitude is
+// outside of 1e-64 to 1e63 take another 8 bytes. A typical segment such as
+// "12" takes 4 bytes.
+//
+// Keys are only meaningful for versions with finite decimals, which is all
+// versions produced by this package's parsers.
+func (v *Version) CompareKey() []byte {
+	return v.appendCompareKey(make([]byte, 0, 4*len(v.Decimal)+1))
+}
+
+// OrderedKey returns v's CompareKey for use as a key in an ordered key-value
+// store such as BadgerDB, where a range scan over the keys returns versions in
+// the order given by Compare. Segments are encoded with a sign byte and a
+// variable length magnitude, so there is no limit on their size or
+// precision.
+//
+// Unlike CompareKey, OrderedKey returns an error if v has a segment that is
+// not a finite decimal, since such a key would not order correctly.
+func (v *Version) OrderedKey() ([]byte, error) {
+	for _, d := range v.Decimal {
+		if !d.IsFinite() {
+			return nil, fmt.Errorf("cannot make an ordered key for version %s: segment %s is not finite", v.Original, d)
+		}
+	}
+	return v.CompareKey(), nil
+}
+
+// appendCompareKey appends v's CompareKey to key.
+func (v *Version) appendCompareKey(key []byte) []byte {
+	segments := v.Decimal
+	for len(segments) > 0 && segments[len(segments)-1].Sign() == 0 {
+		segments = segments[:len(segments)-1]
+	}
+
+	var scratch [24]byte
+	for i := 0; i < len(segments); i++ {
+		switch segments[i].Sign() {
+		case 0:
+			// Trailing zeros were trimmed, so there is always a non-zero
+			// segment after a run of zeros.
+			next := i + 1
+			for segments[next].Sign() == 0 {
+				next++
+			}
+			class := byte(keyZeroThenPositive)
+			if segments[next].Sign() < 0 {
+				class = keyZeroThenNegative
+			}
+			for ; i < next; i++ {
+				key = append(key, class)
+			}
+			i--
+		case 1:
+			key = append(key, keyPositive)
+			key = appendKeyMagnitude(key, scratch[:0], segments[i], false)
+		default:
+			key = append(key, keyNegative)
+			key = appendKeyMagnitude(key, scratch[:0], segments[i], true)
+		}
+	}
+
+	return append(key, keyEnd)
+}
+
+// appendKeyMagnitude appends the order preserving encoding of the absolute
+// value of d to key. If invert is true the encoding is bitwise inverted so that
+// larger magnitudes sort first, as they must for negative numbers.
+func appendKeyMagnitude(key, scratch []byte, d *decimal.Big, invert bool) []byte {
+	start := len(key)
+
+	// |d| = 0.digits × 10^exponent. Comparing the exponents first and then
+	// the digits gives numeric order.
+	digits, exponent := significantDigits(scratch, d)
+	key = appendKeyExponent(key, exponent)
+
+	// Pack two digits per byte as 1 + 10*a + b, leaving 0 free as a
+	// terminator so that a shorter run of digits sorts first.
+	for i := 0; i < len(digits); i += 2 {
+		b := 1 + 10*(digits[i]-'0')
+		if i+1 < len(digits) {
+			b += digits[i+1] - '0'
+		}
+		key = append(key, b)
+	}
+	key = append(key, 0)
+
+	if invert {
+		for i := start; i < len(key); i++ {
+			key[i] = ^key[i]
+		}
+	}
+	return key
+}
+
+func appendKeyExponent(key []byte, exponent int64) []byte {
+	switch {
+	case exponent < keyMinSmallExponent:
+		key = append(key, keyLargeNegativeExponent)
+	case exponent > keyMaxSmallExponent:
+		key = append(key, keyLargePositiveExponent)
+	default:
+		return append(key, byte(keySmallExponentBase+exponent-keyMinSmallExponent))
+	}
+
+	u := uint64(exponent) ^ (1 << 63)
+	for shift := 56; shift >= 0; shift -= 8 {
+		key = append(key, byte(u>>uint(shift)))
+	}
+	return key
+}
+
+// significantDigits returns the decimal digits of the absolute value of d
+// without any leading or trailing zeros, and the exponent such that |d| =
+// 0.digits × 10^exponent. d must be finite and not zero. The digits are
+// appended to scratch where possible.
+func significantDigits(scratch []byte, d *decimal.Big) ([]byte, int64) {
+	var digits []byte
+	var exponent int64
+
+	if i, ok := d.Int64(); ok && d.IsInt() {
+		u := uint64(i)
+		if i < 0 {
+			u = -u
+		}
+		digits = strconv.AppendUint(scratch, u, 10)
+		exponent = int64(len(digits))
+	} else {
+		digits, exponent = parseDecimalDigits(scratch, d.String())
+	}
+
+	for len(digits) > 0 && digits[0] == '0' {
+		digits = digits[1:]
+		exponent--
+	}
+	for len(digits) > 0 && digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+	}
+	return digits, exponent
+}
+
+// parseDecimalDigits splits a string produced by decimal.Big.String, such as
+// "-12.5" or "1.2E+5", into all of its digits and the exponent such that the
+// absolute value is 0.digits × 10^exponent.
+func parseDecimalDigits(dst []byte, s string) ([]byte, int64) {
+	var exponent int64
+	sawPoint := false
+
+	i := 0
+	if i < len(s) && (s[i] == '-' || s[i] == '+') {
+		i++
+	}
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case '0' <= c && c <= '9':
+			dst = append(dst, c)
+			if !sawPoint {
+				exponent++
+			}
+		case c == '.':
+			sawPoint = true
+		case c == 'e' || c == 'E':
+			e, _ := strconv.ParseInt(s[i+1:], 10, 64)
+			return dst, exponent + e
+		}
+	}
+	return dst, exponent
+}
+
+// SortByKey sorts vs into the same order as Sort, but computes each version's
+// CompareKey once up front and sorts by comparing keys. This is much faster
+// than Sort for large slices at the cost of holding a key for every version in
+// memory while sorting.
+//
+// If StrictCompare is true, SortByKey returns an error without sorting if vs
+// contains versions which cannot be compared with each other.
+func SortByKey(vs []*Version) error {
+	if err := checkSortable(vs); err != nil {
+		return err
+	}
+
+	keyed := sortedKeyedVersions(vs)
+	for i, k := range keyed {
+		vs[i] = k.version
+	}
+	return nil
+}
+
+// sortedKeyedVersions returns the keyed versions of vs in sorted order.
+func sortedKeyedVersions(vs []*Version) keyedVersions {
+	keyed := make(keyedVersions, len(vs))
+	fillKeyedVersions(keyed, vs, 0)
+	sort.Sort(keyed)
+	return keyed
+}
+
+// fillKeyedVersions sets keyed[i] to the key for vs[i], whose index in the
+// whole slice being sorted is offset+i.
+func fillKeyedVersions(keyed keyedVersions, vs []*Version, offset int) {
+	// All of the keys share one buffer to save an allocation per version.
+	size := 0
+	for _, v := range vs {
+		size += 4*len(v.Decimal) + 1
+	}
+	buf := make([]byte, 0, size)
+
+	for i, v := range vs {
+		start := len(buf)
+		buf = v.appendCompareKey(buf)
+		keyed[i] = keyedVersion{key: buf[start:len(buf):len(buf)], index: offset + i, version: v}
+	}
+}
+
+type keyedVersion struct {
+	key     []byte
+	index   int
+	version *Version
+}
+
+// keyedVersions sorts by key, falling back to the original index for equal
+// keys. This gives the same order as a stable sort while letting sort.Sort do
+// O(n log n) comparisons rather than the O(n log² n) of sort.Stable.
+type keyedVersions []keyedVersion
+
+func (k keyedVersions) Len() int      { return len(k) }
+func (k keyedVersions) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+
+func (k keyedVersions) Less(i, j int) bool {
+	return k[i].less(&k[j])
+}
+
+func (k *keyedVersion) less(other *keyedVersion) bool {
+	if cmp := bytes.Compare(k.key, other.key); cmp != 0 {
+		return cmp < 0
+	}
+	return k.index < other.index
+}
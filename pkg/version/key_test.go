@@ -0,0 +1,212 @@
+package version
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+func assertKeyOrderMatchesCompare(t *testing.T, versions []*Version) {
+	keys := make([][]byte, len(versions))
+	for i, v := range versions {
+		keys[i] = v.CompareKey()
+	}
+
+	for i, v1 := range versions {
+		for j, v2 := range versions {
+			assert.Equal(t,
+				sign(Compare(v1, v2)), sign(bytes.Compare(keys[i], keys[j])),
+				"keys for %s and %s order the same as Compare", v1, v2)
+		}
+	}
+}
+
+func keyTestCorpora(t *testing.T) map[string][]*Version {
+	corpora := map[string][]*Version{}
+	for _, s := range pythonTestStrings {
+		corpora["python"] = append(corpora["python"], parsePythonOrFatal(t, s))
+	}
+	for _, s := range testParseSemVerOrderInputs {
+		corpora["semver"] = append(corpora["semver"], parseOrFatalSemVer(t, s))
+	}
+	for _, s := range testParsePHPOrderInputs {
+		corpora["php"] = append(corpora["php"], parsePHPOrFatal(t, s))
+	}
+	for _, equal := range testParsePHPEqualInputs {
+		for _, s := range equal {
+			corpora["php"] = append(corpora["php"], parsePHPOrFatal(t, s))
+		}
+	}
+	for _, s := range rubyTestStrings {
+		corpora["ruby"] = append(corpora["ruby"], parseRubyOrFatal(t, s))
+	}
+	for _, equal := range equalRubyVersions {
+		for _, s := range equal {
+			corpora["ruby"] = append(corpora["ruby"], parseRubyOrFatal(t, s))
+		}
+	}
+	for _, s := range genericBenchmarkStrings {
+		corpora["generic"] = append(corpora["generic"], parseOrFatalGeneric(t, s))
+	}
+	return corpora
+}
+
+func TestCompareKeyOrderMatchesCompare(t *testing.T) {
+	for name, versions := range keyTestCorpora(t) {
+		t.Run(name, func(t *testing.T) {
+			assertKeyOrderMatchesCompare(t, versions)
+		})
+	}
+}
+
+func TestCompareKeyOrderMatchesCompareForEdgeCases(t *testing.T) {
+	decimals := [][]string{
+		{"0"},
+		{"0", "0", "0"},
+		{"1"},
+		{"1", "0"},
+		{"1", "0", "0", "-1"},
+		{"1", "0", "-1"},
+		{"1", "0", "-2"},
+		{"1", "-1"},
+		{"1", "0", "0", "1"},
+		{"1", "0", "1"},
+		{"1", "1"},
+		{"-1"},
+		{"-10"},
+		{"-9"},
+		{"-9.5"},
+		{"-0.001"},
+		{"0.001"},
+		{"0.0011"},
+		{"0.01"},
+		{"9"},
+		{"9.5"},
+		{"10"},
+		{"10.00"},
+		{"100"},
+		{"1E+80"},
+		{"1E+81"},
+		{"-1E+80"},
+		{"1E-80"},
+		{"2E-80"},
+		{"-1E-80"},
+		{"99999999999999999999999"},
+		{"100000000000000000000000"},
+		{"-99999999999999999999999"},
+		{"9223372036854775807"},
+		{"-9223372036854775808"},
+		{"43.0000000097"},
+		{"43.0000000098"},
+		{"43.00000000970000000001"},
+	}
+
+	var versions []*Version
+	for _, d := range decimals {
+		versions = append(versions, &Version{Decimal: mustStringsToDecimal(t, d)})
+	}
+	assertKeyOrderMatchesCompare(t, versions)
+}
+
+func TestCompareKeyIgnoresTrailingZeros(t *testing.T) {
+	a := parseOrFatalGeneric(t, "1.2")
+	b := parseOrFatalGeneric(t, "1.2.0.0")
+	assert.Equal(t, a.CompareKey(), b.CompareKey())
+}
+
+func TestOrderedKeyOrderMatchesCompare(t *testing.T) {
+	corpora := keyTestCorpora(t)
+
+	pieces := []string{
+		"0", "1", "9", "10", "99", "2020", "123456789012345678901234567890",
+		".", "-", "_", "+", "alpha", "beta", "RC", "pre", "dev", "x", "supercalifragilistic", "é",
+	}
+	r := rand.New(rand.NewSource(1))
+	for len(corpora["fuzzed generic"]) < 300 {
+		var b strings.Builder
+		for n := r.Intn(8); n >= 0; n-- {
+			b.WriteString(pieces[r.Intn(len(pieces))])
+		}
+		if v, err := ParseGeneric(b.String()); err == nil {
+			corpora["fuzzed generic"] = append(corpora["fuzzed generic"], v)
+		}
+	}
+
+	for name, versions := range corpora {
+		t.Run(name, func(t *testing.T) {
+			keys := make([][]byte, len(versions))
+			for i, v := range versions {
+				key, err := v.OrderedKey()
+				require.NoError(t, err, v.Original)
+				keys[i] = key
+			}
+
+			for i, v1 := range versions {
+				for j, v2 := range versions {
+					assert.Equal(t,
+						Compare(v1, v2) < 0, bytes.Compare(keys[i], keys[j]) < 0,
+						"Compare(%s, %s) < 0 if and only if their ordered keys are", v1, v2)
+				}
+			}
+		})
+	}
+}
+
+func TestOrderedKeyRejectsNonFiniteSegments(t *testing.T) {
+	for _, s := range []string{"NaN", "Inf", "-Inf"} {
+		v := &Version{Original: s, Decimal: mustStringsToDecimal(t, []string{"1", s})}
+		_, err := v.OrderedKey()
+		assert.Error(t, err, s)
+	}
+}
+
+func TestSortByKey(t *testing.T) {
+	for name, versions := range keyTestCorpora(t) {
+		t.Run(name, func(t *testing.T) {
+			shuffled := make([]*Version, len(versions))
+			copy(shuffled, versions)
+			rand.New(rand.NewSource(1)).Shuffle(len(shuffled), func(i, j int) {
+				shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+			})
+
+			expected := make([]*Version, len(shuffled))
+			copy(expected, shuffled)
+			require.NoError(t, Sort(expected))
+
+			actual := make([]*Version, len(shuffled))
+			copy(actual, shuffled)
+			require.NoError(t, SortByKey(actual))
+
+			// Sort and SortByKey are both stable, so even versions that
+			// compare as equal end up in the same positions.
+			for i := range expected {
+				assert.True(t, expected[i] == actual[i], "%s sorted into position %d", expected[i], i)
+			}
+		})
+	}
+}
+
+func TestSortByKeyWithStrictCompare(t *testing.T) {
+	defer func(strict bool) { StrictCompare = strict }(StrictCompare)
+	StrictCompare = true
+
+	vs := []*Version{parseOrFatalGeneric(t, "2"), parsePythonOrFatal(t, "1")}
+	err := SortByKey(vs)
+	require.Error(t, err)
+	assert.IsType(t, &IncomparableError{}, err)
+	assert.Equal(t, "2", vs[0].Original, "slice is unchanged")
+}
@@ -0,0 +1,66 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// kubernetesRegEx matches a Kubernetes version. The groups are the major,
+// minor and patch numbers, the optional pre-release kind and number, and the
+// optional distro suffix after a "-" or "+".
+var kubernetesRegEx = regexp.MustCompile(
+	`^v(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)` +
+		`(?:-(alpha|beta|rc)\.(0|[1-9][0-9]*))?` +
+		`(?:[-+]([0-9A-Za-z][0-9A-Za-z._+-]*))?$`)
+
+// kubernetesPreReleases maps the pre-release kinds of Kubernetes versions to
+// segments that sort them below releases.
+var kubernetesPreReleases = map[string]string{
+	"alpha": "-3",
+	"beta":  "-2",
+	"rc":    "-1",
+}
+
+// ParseKubernetes parses the version of Kubernetes or of a component or
+// distribution of it, such as "v1.29.2", "v1.30.0-alpha.1",
+// "v1.28.7-eks-b9c9ed7" or "v1.27.3+k3s1". The version must start with "v",
+// then have a major, minor and patch number and an optional alpha, beta or rc
+// pre-release with a number, as in "-rc.1". A pre-release is less than the
+// release, and pre-releases are ordered by their kind and then their number,
+// so "v1.30.0-alpha.1" < "v1.30.0-beta.0" < "v1.30.0-rc.1" < "v1.30.0".
+//
+// The version may be followed by a distro suffix after a "-" or "+", such as
+// "eks-b9c9ed7" or "k3s1". The suffix is ignored when comparing versions, so
+// "v1.28.7-eks-b9c9ed7" equals "v1.28.7", and is stored in the BuildMetadata
+// field of the returned version.
+func ParseKubernetes(version string) (*Version, error) {
+	m := kubernetesRegEx.FindStringSubmatch(version)
+	if m == nil || m[4] == "" && isKubernetesPreRelease(m[6]) {
+		return nil, fmt.Errorf("invalid kubernetes version: %s", version)
+	}
+
+	segments := []string{m[1], m[2], m[3]}
+	if m[4] != "" {
+		segments = append(segments, kubernetesPreReleases[m[4]], m[5])
+	}
+
+	v, err := fromStringSlice(Kubernetes, version, segments)
+	if err != nil {
+		return nil, err
+	}
+	v.BuildMetadata = m[6]
+	return v, nil
+}
+
+// isKubernetesPreRelease returns true if suffix starts with a pre-release
+// kind, so that a pre-release without a valid number, like "alpha" or
+// "rc.01", is not taken as a distro suffix.
+func isKubernetesPreRelease(suffix string) bool {
+	kind := suffix
+	if i := strings.IndexAny(suffix, ".-+"+asciiDigits); i >= 0 {
+		kind = suffix[:i]
+	}
+	_, ok := kubernetesPreReleases[kind]
+	return ok
+}
@@ -0,0 +1,70 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseKubernetesOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseKubernetes(v)
+	require.NoError(t, err, "no error parsing %v as a Kubernetes version", v)
+	return ver
+}
+
+func TestParseKubernetes(t *testing.T) {
+	tests := map[string]struct {
+		segments []string
+		suffix   string
+	}{
+		"v1.29.2":                  {[]string{"1", "29", "2"}, ""},
+		"v1.30.0-alpha.1":          {[]string{"1", "30", "0", "-3", "1"}, ""},
+		"v1.30.0-beta.0":           {[]string{"1", "30", "0", "-2"}, ""},
+		"v1.30.0-rc.2":             {[]string{"1", "30", "0", "-1", "2"}, ""},
+		"v1.28.7-eks-b9c9ed7":      {[]string{"1", "28", "7"}, "eks-b9c9ed7"},
+		"v1.27.3+k3s1":             {[]string{"1", "27", "3"}, "k3s1"},
+		"v1.28.3-gke.1286000":      {[]string{"1", "28", "3"}, "gke.1286000"},
+		"v1.30.0-rc.1-eks-b9c9ed7": {[]string{"1", "30", "0", "-1", "1"}, "eks-b9c9ed7"},
+		"v1.26.4+rke2r1":           {[]string{"1", "26", "4"}, "rke2r1"},
+	}
+	for in, expected := range tests {
+		v := parseKubernetesOrFatal(t, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, Kubernetes, v.ParsedAs, in)
+		assert.Equal(t, mustStringsToDecimal(t, expected.segments), v.Decimal, in)
+		assert.Equal(t, expected.suffix, v.BuildMetadata, in)
+	}
+}
+
+var testParseKubernetesOrderInputs = []string{
+	"v1.27.3+k3s1",
+	"v1.28.7-eks-b9c9ed7",
+	"v1.29.2",
+	"v1.30.0-alpha.0",
+	"v1.30.0-alpha.1",
+	"v1.30.0-alpha.10",
+	"v1.30.0-beta.0",
+	"v1.30.0-rc.1",
+	"v1.30.0-rc.2",
+	"v1.30.0",
+	"v1.30.1",
+	"v1.30.10",
+	"v2.0.0",
+}
+
+var testParseKubernetesEqualInputs = [][]string{
+	{"v1.28.7", "v1.28.7-eks-b9c9ed7"},
+	{"v1.27.3", "v1.27.3+k3s1"},
+	{"v1.27.3+k3s1", "v1.27.3+k3s2"},
+	{"v1.30.0-rc.1", "v1.30.0-rc.1-eks-b9c9ed7"},
+}
+
+func TestParseKubernetesErrors(t *testing.T) {
+	for _, in := range []string{"", "1.29.2", "v1.29", "v01.29.2", "v1.29.2-", "v1.29.2+", "v1.29.2 ", "v1.30.0-alpha", "v1.30.0-rc.01", "v1.30.0-beta1", "v1.30.0-rc.1x", "v1.30.0_eks"} {
+		_, err := ParseKubernetes(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid kubernetes version: "+in, err.Error(), in)
+		}
+	}
+}
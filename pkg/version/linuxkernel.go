@@ -0,0 +1,45 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// linuxKernelRegEx matches a kernel version. The groups are the major, minor
+// and optional patch numbers, the optional release candidate number and the
+// optional localversion.
+var linuxKernelRegEx = regexp.MustCompile(`^([0-9]+)\.([0-9]+)(?:\.([0-9]+))?(?:-rc([0-9]+))?([-+][!-~]*)?$`)
+
+// ParseLinuxKernel parses a Linux kernel version, such as "6.8-rc3",
+// "6.7.9" or "5.15.0-91-generic", ordering it as kernel.org does. The version
+// is two or three numbers, then an optional "-rcN" release candidate, then an
+// optional localversion that starts with "-" or "+".
+//
+// A missing patch number is 0, and a release candidate is less than the
+// release, so "6.8-rc1" < "6.8-rc7" < "6.8" < "6.8.1". The localversion, such
+// as "-generic" or "+", is ignored when comparing versions, and is stored in
+// the BuildMetadata field of the returned version.
+func ParseLinuxKernel(version string) (*Version, error) {
+	m := linuxKernelRegEx.FindStringSubmatch(version)
+	if m == nil {
+		return nil, fmt.Errorf("invalid linux kernel version: %s", version)
+	}
+
+	patch := m[3]
+	if patch == "" {
+		patch = "0"
+	}
+	segments := []string{m[1], m[2], patch}
+	// A release candidate is -1 and its number, which is less than the
+	// missing segments of a release.
+	if m[4] != "" {
+		segments = append(segments, "-1", m[4])
+	}
+
+	v, err := fromStringSlice(LinuxKernel, version, segments)
+	if err != nil {
+		return nil, err
+	}
+	v.BuildMetadata = m[5]
+	return v, nil
+}
@@ -0,0 +1,70 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseLinuxKernelOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseLinuxKernel(v)
+	require.NoError(t, err, "no error parsing %v as a Linux kernel version", v)
+	return ver
+}
+
+func TestParseLinuxKernel(t *testing.T) {
+	tests := map[string]struct {
+		segments     []string
+		localVersion string
+	}{
+		"6.8":               {[]string{"6", "8"}, ""},
+		"6.7.9":             {[]string{"6", "7", "9"}, ""},
+		"6.8-rc3":           {[]string{"6", "8", "0", "-1", "3"}, ""},
+		"2.6.39-rc1":        {[]string{"2", "6", "39", "-1", "1"}, ""},
+		"5.15.0-91-generic": {[]string{"5", "15"}, "-91-generic"},
+		"6.1.0-13-amd64":    {[]string{"6", "1"}, "-13-amd64"},
+		"6.9.0-rc2+":        {[]string{"6", "9", "0", "-1", "2"}, "+"},
+		"4.19.307-rcfoo":    {[]string{"4", "19", "307"}, "-rcfoo"},
+	}
+	for in, expected := range tests {
+		v := parseLinuxKernelOrFatal(t, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, LinuxKernel, v.ParsedAs, in)
+		assert.Equal(t, mustStringsToDecimal(t, expected.segments), v.Decimal, in)
+		assert.Equal(t, expected.localVersion, v.BuildMetadata, in)
+	}
+}
+
+var testParseLinuxKernelOrderInputs = []string{
+	"2.6.39-rc1",
+	"2.6.39",
+	"3.0",
+	"4.19.307",
+	"5.15.148",
+	"6.7.9",
+	"6.8-rc1",
+	"6.8-rc3",
+	"6.8-rc7",
+	"6.8",
+	"6.8.1",
+	"6.8.10",
+	"6.10-rc1",
+	"6.10",
+}
+
+var testParseLinuxKernelEqualInputs = [][]string{
+	{"6.8", "6.8.0"},
+	{"6.8", "6.8+"},
+	{"5.15.0", "5.15.0-91-generic"},
+	{"6.8-rc3", "6.8.0-rc3-next"},
+}
+
+func TestParseLinuxKernelErrors(t *testing.T) {
+	for _, in := range []string{"", "6", "6.", "6.8.", ".8", "6.8.1.2", "v6.8", "6.8rc1", "6.8 generic", "6.8-gen eric", "6.8_1"} {
+		_, err := ParseLinuxKernel(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid linux kernel version: "+in, err.Error(), in)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// luaRocksDeltas maps the words that LuaRocks knows to their values, from
+// the deltas table in LuaRocks' core/vers.lua.
+var luaRocksDeltas = map[string]int64{
+	"dev":   120000000,
+	"scm":   110000000,
+	"cvs":   100000000,
+	"rc":    -1000,
+	"pre":   -10000,
+	"beta":  -100000,
+	"alpha": -1000000,
+}
+
+var (
+	luaRocksRevisionRegEx = regexp.MustCompile(`^(.+)-([0-9]+)$`)
+	luaRocksNumberRegEx   = regexp.MustCompile(`^([0-9]+)[._-]*`)
+	luaRocksWordRegEx     = regexp.MustCompile(`^([a-zA-Z]+)[._-]*`)
+)
+
+// ParseLuaRocks parses a LuaRocks rockspec version, such as "2.4.1-1" or
+// "scm-3", ordering it as LuaRocks' parse_version and compare_versions do.
+// The rockspec revision after the last "-" is required, and is compared only
+// when the rest of the versions are equal, so "1.0-2" < "1.0.1-1".
+//
+// The rest of the version is split into numbers and words, which may be
+// separated by ".", "-" or "_". A word takes the place of the next number,
+// and its value is 120000000 for dev, 110000000 for scm, 100000000 for cvs,
+// -1000 for rc, -10000 for pre, -100000 for beta and -1000000 for alpha, so
+// "1.0rc1" < "1.0" < "scm". Other words are the code of their first letter
+// divided by 1000. A number after a word is divided by 100000 and added to
+// it. Items are compared in order, and a missing item is 0, so "1" equals
+// "1.0".
+func ParseLuaRocks(version string) (*Version, error) {
+	m := luaRocksRevisionRegEx.FindStringSubmatch(version)
+	if m == nil {
+		return nil, fmt.Errorf("invalid luarocks version: %s", version)
+	}
+
+	var items []*decimal.Big
+	var word *decimal.Big
+	for rest := m[1]; rest != ""; {
+		if n := luaRocksNumberRegEx.FindStringSubmatch(rest); n != nil {
+			d := &decimal.Big{Context: decimal.Context{Precision: decimal.UnlimitedPrecision}}
+			if word != nil {
+				d.SetString(n[1] + "e-5")
+				d.Add(d, word)
+				word = nil
+			} else {
+				d.SetString(n[1])
+			}
+			items = append(items, d)
+			rest = rest[len(n[0]):]
+		} else if w := luaRocksWordRegEx.FindStringSubmatch(rest); w != nil {
+			if delta, ok := luaRocksDeltas[w[1]]; ok {
+				word = decimal.New(delta, 0)
+			} else {
+				word = decimal.New(int64(w[1][0]), 3)
+			}
+			rest = rest[len(w[0]):]
+		} else {
+			return nil, fmt.Errorf("invalid luarocks version: %s", version)
+		}
+	}
+	if word != nil {
+		items = append(items, word)
+	}
+	for len(items) > 0 && items[len(items)-1].Sign() == 0 {
+		items = items[:len(items)-1]
+	}
+
+	// Each item is its value, then 0. A 0 is 0, then the sign of the next item
+	// that is not 0, so that the end of the items, which is 0 and then 0,
+	// compares to it as LuaRocks compares a missing item to the items after
+	// it. The revision comes after the end.
+	segments := make([]string, 0, len(items)*2+3)
+	for i, item := range items {
+		if item.Sign() != 0 {
+			segments = append(segments, item.String(), "0")
+			continue
+		}
+		for _, next := range items[i+1:] {
+			if next.Sign() != 0 {
+				segments = append(segments, "0", fmt.Sprint(next.Sign()))
+				break
+			}
+		}
+	}
+	segments = append(segments, "0", "0", m[2])
+	return fromStringSlice(LuaRocks, version, segments)
+}
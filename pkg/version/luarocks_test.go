@@ -0,0 +1,83 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseLuaRocksOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseLuaRocks(v)
+	require.NoError(t, err, "no error parsing %v as a LuaRocks version", v)
+	return ver
+}
+
+func TestParseLuaRocks(t *testing.T) {
+	tests := map[string][]string{
+		"2.4.1-1":    {"2", "0", "4", "0", "1", "0", "0", "0", "1"},
+		"1.0-1":      {"1", "0", "0", "0", "1"},
+		"1.0rc2-1":   {"1", "0", "0", "-1", "-999.99998", "0", "0", "0", "1"},
+		"scm-3":      {"110000000", "0", "0", "0", "3"},
+		"1.foo-0":    {"1", "0", "0.102"},
+		"0.0.5-02":   {"0", "1", "0", "1", "5", "0", "0", "0", "2"},
+		"1.0-1-2":    {"1", "0", "0", "1", "1", "0", "0", "0", "2"},
+		"3_1-beta-1": {"3", "0", "1", "0", "-100000", "0", "0", "0", "1"},
+	}
+	for in, expected := range tests {
+		v := parseLuaRocksOrFatal(t, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, LuaRocks, v.ParsedAs, in)
+		assert.Equal(t, mustStringsToDecimal(t, expected), v.Decimal, in)
+	}
+}
+
+var testParseLuaRocksOrderInputs = []string{
+	"0.9-1",
+	"1.0.alpha-1",
+	"1.0alpha2-1",
+	"1.0beta-1",
+	"1.0pre-1",
+	"1.0rc1-1",
+	"1.0rc2-1",
+	"1.0-1",
+	"1.0-2",
+	"1.0.1-1",
+	"1.foo-1",
+	"1.1-1",
+	"1.10-1",
+	"2.0-1",
+	"cvs-1",
+	"scm-1",
+	"scm-2",
+	"dev-1",
+}
+
+var testParseLuaRocksEqualInputs = [][]string{
+	{"1-1", "1.0-1"},
+	{"1-1", "1.0.0-1"},
+	{"1_0-1", "1.0-1"},
+	{"1.0-01", "1.0-1"},
+	{"1.alpha.beta-1", "1.beta-1"},
+	{"1.0rc-1", "1.0.rc-1"},
+}
+
+func TestParseLuaRocksErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"1.0",
+		"scm",
+		"-1",
+		"1.0-",
+		"1.0-a",
+		".1-1",
+		" 1.0-1",
+		"1.0 -1",
+		"1.0+x-1",
+	} {
+		_, err := ParseLuaRocks(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid luarocks version: "+in, err.Error(), in)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// mavenQualifiers maps the qualifiers that Maven knows to the last segment of
+// their items, which orders them as Maven does. The release, which has no
+// qualifier, is 0, so that it is the same as a missing item.
+var mavenQualifiers = map[string]string{
+	"alpha":     "-5",
+	"beta":      "-4",
+	"milestone": "-3",
+	"rc":        "-2",
+	"snapshot":  "-1",
+	"":          "0",
+	"sp":        "1",
+}
+
+// mavenQualifierAliases maps other spellings of the known qualifiers to the
+// qualifiers in mavenQualifiers.
+var mavenQualifierAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+// mavenLetterQualifiers maps the single letters that are short for a
+// qualifier when a number follows them, as in "1.0a1".
+var mavenLetterQualifiers = map[string]string{
+	"a": "alpha",
+	"b": "beta",
+	"m": "milestone",
+}
+
+// mavenItem is a number or qualifier in a Maven version.
+type mavenItem struct {
+	number    bool
+	value     string
+	qualifier string
+}
+
+func (i mavenItem) isNull() bool {
+	if i.number {
+		return strings.Trim(i.value, "0") == ""
+	}
+	return i.qualifier == ""
+}
+
+// isAfterNull returns true if i sorts after a missing item.
+func (i mavenItem) isAfterNull() bool {
+	if i.number {
+		return !i.isNull()
+	}
+	s, ok := mavenQualifiers[i.qualifier]
+	return !ok || s == "1"
+}
+
+// segments returns the three segments of i. A number other than 0 is its
+// value, then 0, then 0. A known qualifier is 0, then 0, then its segment
+// from mavenQualifiers, and an unknown qualifier is 0, then 1, then the
+// qualifier as a decimal. The start of a sublist is three zeros, the same as
+// a missing item, so the items in it decide how it compares to one.
+//
+// Maven orders 0 the same as a missing item, but after a sublist or a
+// qualifier. This is only consistent when the items after the 0 sort after a
+// missing item, so a 0 is 0, then 2, then 0 when the next item in its list
+// that is not the same as a missing item sorts after one, and three zeros
+// otherwise.
+func (i mavenItem) segments(rest []mavenItem) []string {
+	if i.number {
+		if !i.isNull() {
+			return []string{i.value, "0", "0"}
+		}
+		for _, next := range rest {
+			if !next.isNull() {
+				if next.isAfterNull() {
+					return []string{"0", "2", "0"}
+				}
+				break
+			}
+		}
+		return []string{"0", "0", "0"}
+	}
+	if s, ok := mavenQualifiers[i.qualifier]; ok {
+		return []string{"0", "0", s}
+	}
+	return []string{"0", "1", asciiToDecimalString(i.qualifier)}
+}
+
+func newMavenItem(s string, isNumber, followedByNumber bool) mavenItem {
+	if isNumber {
+		return mavenItem{number: true, value: s}
+	}
+	if q, ok := mavenLetterQualifiers[s]; ok && followedByNumber {
+		s = q
+	}
+	if q, ok := mavenQualifierAliases[s]; ok {
+		s = q
+	}
+	return mavenItem{qualifier: s}
+}
+
+// ParseMaven parses a Maven version, ordering it as Maven 3's
+// ComparableVersion does. The version is lower cased and split into items at
+// each "." and "-", and wherever a number and a letter meet, so "1.0-alpha1"
+// has the items 1, 0, alpha and 1. A "-", or a change between a number and a
+// letter, starts a sublist that holds the rest of the items, and a qualifier
+// at the end is always in a sublist, so "1.0.final" is the same as
+// "1.0-final".
+//
+// Items are compared in order, and a missing item is the same as 0 or the
+// release. Numbers are greater than qualifiers, and a sublist is greater than
+// a qualifier but less than a number, so "1-1" < "1.1". Trailing items that
+// are the same as a missing item are removed from each list, so "1", "1.0.0",
+// "1-0" and "1.0-ga" are equal.
+//
+// The known qualifiers are ordered as alpha < beta < milestone < rc = cr <
+// snapshot < "" = ga = final = release < sp, and "a", "b" and "m" are short
+// for alpha, beta and milestone when a number follows them. Unknown
+// qualifiers sort after the known ones, in lexical order.
+//
+// ComparableVersion is not a total order for some unusual versions, where a
+// 0 or a qualifier that is not at the end is compared to a sublist. For those,
+// ParseMaven keeps the order of each version relative to the versions that
+// end before that item. For example, it orders "1.0.rc.1" < "1" < "1-1",
+// where Maven orders "1.0.rc.1" after "1-1".
+//
+// Versions may contain printable ASCII characters other than space.
+func ParseMaven(version string) (*Version, error) {
+	if version == "" {
+		return nil, fmt.Errorf("invalid maven version: %s", version)
+	}
+	for i := 0; i < len(version); i++ {
+		if version[i] <= ' ' || version[i] > '~' {
+			return nil, fmt.Errorf("invalid maven version: %s", version)
+		}
+	}
+
+	// Each sublist is the last item of the list before it, so they are kept
+	// as a slice of lists, from the outermost in.
+	v := strings.ToLower(version)
+	lists := [][]mavenItem{nil}
+	add := func(item mavenItem) {
+		lists[len(lists)-1] = append(lists[len(lists)-1], item)
+	}
+	addNumberOrZero := func(start, end int, isNumber bool) {
+		if start == end {
+			add(mavenItem{number: true, value: "0"})
+		} else {
+			add(newMavenItem(v[start:end], isNumber, false))
+		}
+	}
+
+	isNumber := false
+	start := 0
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case c == '.':
+			addNumberOrZero(start, i, isNumber)
+			start = i + 1
+		case c == '-':
+			addNumberOrZero(start, i, isNumber)
+			start = i + 1
+			lists = append(lists, nil)
+		case isASCIIDigit(c):
+			if !isNumber && i > start {
+				add(newMavenItem(v[start:i], false, true))
+				start = i
+				lists = append(lists, nil)
+			}
+			isNumber = true
+		default:
+			if isNumber && i > start {
+				add(newMavenItem(v[start:i], true, false))
+				start = i
+				lists = append(lists, nil)
+			}
+			isNumber = false
+		}
+	}
+	if start < len(v) {
+		if !isNumber && len(lists[len(lists)-1]) > 0 {
+			lists = append(lists, nil)
+		}
+		add(newMavenItem(v[start:], isNumber, false))
+	}
+
+	// Remove the trailing null items of each list, from the innermost out,
+	// and the lists that are left empty.
+	for i := len(lists) - 1; i >= 0; i-- {
+		l := lists[i]
+		for len(l) > 0 && l[len(l)-1].isNull() {
+			l = l[:len(l)-1]
+		}
+		lists[i] = l
+		if len(l) == 0 && i == len(lists)-1 {
+			lists = lists[:i]
+		}
+	}
+
+	segments := []string{}
+	for i, l := range lists {
+		if i > 0 {
+			segments = append(segments, "0", "0", "0")
+		}
+		for j, item := range l {
+			segments = append(segments, item.segments(l[j+1:])...)
+		}
+	}
+	if len(segments) == 0 {
+		segments = append(segments, "0")
+	}
+	return fromStringSlice(Maven, version, segments)
+}
@@ -0,0 +1,138 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseMavenOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseMaven(v)
+	require.NoError(t, err, "no error parsing %v as a Maven version", v)
+	return ver
+}
+
+// The following versions are from Maven's ComparableVersionTest.
+
+var testParseMavenQualifierOrderInputs = []string{
+	"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11", "1-rc", "1-cr2",
+	"1-rc123", "1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1", "1-1-snapshot",
+	"1-1", "1-2", "1-123",
+}
+
+var testParseMavenNumberOrderInputs = []string{
+	"2.0", "2.0.a", "2-1", "2.0.2", "2.0.123", "2.1.0", "2.1-a", "2.1b", "2.1-c", "2.1-1", "2.1.0.1", "2.2",
+	"2.123", "11.a2", "11.a11", "11.b2", "11.b11", "11.m2", "11.m11", "11", "11.a", "11b", "11c", "11m",
+}
+
+var testParseMavenOrderPairs = [][]string{
+	{"1", "2"},
+	{"1.5", "2"},
+	{"1", "2.5"},
+	{"1.0", "1.1"},
+	{"1.1", "1.2"},
+	{"1.0.0", "1.1"},
+	{"1.0.1", "1.1"},
+	{"1.1", "1.2.0"},
+	{"1.0-alpha-1", "1.0"},
+	{"1.0-alpha-1", "1.0-alpha-2"},
+	{"1.0-alpha-1", "1.0-beta-1"},
+	{"1.0-beta-1", "1.0-SNAPSHOT"},
+	{"1.0-SNAPSHOT", "1.0"},
+	{"1.0-alpha-1-SNAPSHOT", "1.0-alpha-1"},
+	{"1.0", "1.0-1"},
+	{"1.0-1", "1.0-2"},
+	{"1.0.0", "1.0-1"},
+	{"2.0-1", "2.0.1"},
+	{"2.0.1-klm", "2.0.1-lmn"},
+	{"2.0.1", "2.0.1-xyz"},
+	{"2.0.1", "2.0.1-123"},
+	{"2.0.1-xyz", "2.0.1-123"},
+	{"1.0-sp1", "1.0-sp2"},
+	{"1.0", "1.0-sp1"},
+	{"2.0.0.CR1", "2.0.0.Final"},
+	{"2.0.0.CR1", "2.0.0.CR2"},
+	{"1.0.0.Alpha1", "1.0.0.Beta1"},
+	{"5.4.33.Final", "6.0.0.Alpha1"},
+	{"6.0.0.Alpha1", "6.0.0"},
+	{"1.0.0-1", "1.0.0.1"},
+	{"2-1", "2.0.0.1"},
+	{"1.0.rc.1", "1"},
+	{"1", "1-1"},
+	{"1.0-jre", "1.0.1-jre"},
+	{"31.1-android", "31.1-jre"},
+	{"2147483647", "2147483648"},
+	{"9223372036854775807", "9223372036854775808"},
+}
+
+var testParseMavenEqualInputs = [][]string{
+	{"1", "1"},
+	{"1", "1.0"},
+	{"1", "1.0.0"},
+	{"1.0", "1.0.0"},
+	{"1", "1-0"},
+	{"1", "1.0-0"},
+	{"1.0", "1.0-0"},
+	{"1a", "1-a"},
+	{"1a", "1.0-a"},
+	{"1a", "1.0.0-a"},
+	{"1.0a", "1-a"},
+	{"1.0.0a", "1-a"},
+	{"1x", "1-x"},
+	{"1x", "1.0-x"},
+	{"1x", "1.0.0-x"},
+	{"1.0x", "1-x"},
+	{"1.0.0x", "1-x"},
+	{"1ga", "1"},
+	{"1release", "1"},
+	{"1final", "1"},
+	{"1cr", "1rc"},
+	{"1a1", "1-alpha-1"},
+	{"1b2", "1-beta-2"},
+	{"1m3", "1-milestone-3"},
+	{"1X", "1x"},
+	{"1A", "1a"},
+	{"1B", "1b"},
+	{"1M", "1m"},
+	{"1Ga", "1"},
+	{"1GA", "1"},
+	{"1RELEASE", "1"},
+	{"1RELeaSE", "1"},
+	{"1Final", "1"},
+	{"1FINAL", "1"},
+	{"1Cr", "1Rc"},
+	{"1cR", "1rC"},
+	{"1m3", "1Milestone3"},
+	{"1m3", "1MileStone3"},
+	{"1m3", "1MILESTONE3"},
+	{"2.0.0.Final", "2.0.0"},
+	{"2.0.0.GA", "2"},
+}
+
+func TestParseMaven(t *testing.T) {
+	v := parseMavenOrFatal(t, "1.0-SNAPSHOT")
+	assert.Equal(t, "1.0-SNAPSHOT", v.Original)
+	assert.Equal(t, Maven, v.ParsedAs)
+	assert.Equal(t, mustStringsToDecimal(t, []string{"1", "0", "0", "0", "0", "0", "0", "0", "-1"}), v.Decimal)
+
+	v = parseMavenOrFatal(t, "1-sp1")
+	assert.Equal(t, mustStringsToDecimal(t, []string{"1", "0", "0", "0", "0", "0", "0", "0", "1", "0", "0", "0", "1"}), v.Decimal)
+
+	v = parseMavenOrFatal(t, "1-xyz")
+	assert.Equal(t, mustStringsToDecimal(t, []string{"1", "0", "0", "0", "0", "0", "0", "1", "120.121122"}), v.Decimal)
+
+	v = parseMavenOrFatal(t, "2.0.0.CR1")
+	assert.Equal(t, mustStringsToDecimal(t, []string{"2", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "-2", "0", "0", "0", "1"}), v.Decimal)
+
+	assert.Equal(t, mustStringsToDecimal(t, []string{"0"}), parseMavenOrFatal(t, "0.0.GA").Decimal)
+}
+
+func TestParseMavenErrors(t *testing.T) {
+	for _, in := range []string{"", "1.0 beta", "1.0\t", "1.0-β"} {
+		_, err := ParseMaven(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid maven version: "+in, err.Error(), in)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package version
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// ErrTooManySegments is returned by ParseGeneric and ParseGo when a version
+// has more segments than WithMaxSegments allows and the overflow is Reject.
+// It is returned as is, so it can be compared with ==.
+var ErrTooManySegments = errors.New("version has too many segments")
+
+// SegmentOverflow says what ParseGeneric and ParseGo do with a version that
+// has more segments than WithMaxSegments allows.
+type SegmentOverflow int
+
+const (
+	// Reject makes the parsing func return ErrTooManySegments. This is the
+	// default.
+	Reject SegmentOverflow = iota
+	// Truncate makes the parsing func keep the first n-1 segments and fold
+	// the rest into a single final segment.
+	Truncate
+)
+
+// WithMaxSegments limits ParseGeneric and ParseGo to versions with at most n
+// segments, for storage that cannot hold arbitrarily long arrays. For
+// ParseGeneric, trailing zero segments are trimmed before they are counted. What happens to longer versions is set by
+// WithSegmentOverflow. Values of n less than one mean there is no limit,
+// which is the default.
+func WithMaxSegments(n int) Option {
+	return func(o *options) {
+		o.maxSegments = n
+	}
+}
+
+// WithSegmentOverflow sets what ParseGeneric and ParseGo do with a version
+// that has more segments than WithMaxSegments allows. It has no effect without
+// WithMaxSegments.
+//
+// With Truncate, the segments from the nth onwards are folded into a single
+// segment, which orders the same way as the segments it replaces. So two
+// versions truncated to the same n compare the same way they would have
+// without the limit. A truncated version does not compare meaningfully with
+// one that was not truncated but has the same first n-1 segments.
+func WithSegmentOverflow(overflow SegmentOverflow) Option {
+	return func(o *options) {
+		o.segmentOverflow = overflow
+	}
+}
+
+// limitSegments applies the WithMaxSegments limit to v.
+func limitSegments(v *Version, o options) (*Version, error) {
+	if o.maxSegments < 1 || len(v.Decimal) <= o.maxSegments {
+		return v, nil
+	}
+	if o.segmentOverflow != Truncate {
+		return nil, ErrTooManySegments
+	}
+
+	n := o.maxSegments
+	decimals := make([]*decimal.Big, n)
+	copy(decimals, v.Decimal[:n-1])
+	decimals[n-1] = foldSegments(v.Decimal[n-1:])
+	v.Decimal = decimals
+	return v, nil
+}
+
+// foldSegments returns a single decimal that orders the same way as segments
+// do under Compare. It is made from the CompareKey of the segments, whose
+// first byte is the integer part and each following byte is three digits of
+// the fraction, so the decimal orders the same way as the key. A key always
+// ends with a non-zero byte, so no key is equal to another followed by zeros.
+func foldSegments(segments []*decimal.Big) *decimal.Big {
+	key := (&Version{Decimal: segments}).CompareKey()
+
+	var b strings.Builder
+	b.Grow(3*len(key) + 1)
+	var digits [20]byte
+	b.Write(strconv.AppendInt(digits[:0], int64(key[0]), 10))
+	if len(key) > 1 {
+		b.WriteByte('.')
+	}
+	for _, c := range key[1:] {
+		writeZeroPadded(&b, digits[:0], int64(c), 3)
+	}
+
+	// The string is always a valid decimal.
+	return stringToDecimal(b.String())
+}
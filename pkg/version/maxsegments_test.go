@@ -0,0 +1,138 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sentence returns a "version" of 25 words like "word7", which ParseGeneric
+// splits into 50 segments, followed by suffix.
+func sentence(suffix string) string {
+	words := make([]string, 25)
+	for i := range words {
+		words[i] = fmt.Sprintf("word%d", i)
+	}
+	return strings.Join(words, " ") + suffix
+}
+
+func TestWithMaxSegmentsReject(t *testing.T) {
+	v, err := ParseGeneric(sentence(""))
+	require.NoError(t, err)
+	assert.Len(t, v.Decimal, 50, "no limit by default")
+
+	_, err = ParseGeneric(sentence(""), WithMaxSegments(10))
+	assert.Equal(t, ErrTooManySegments, err)
+	_, err = ParseGeneric(sentence(""), WithMaxSegments(10), WithSegmentOverflow(Reject))
+	assert.Equal(t, ErrTooManySegments, err)
+
+	v, err = ParseGeneric(sentence(""), WithMaxSegments(50))
+	require.NoError(t, err)
+	assert.Len(t, v.Decimal, 50)
+
+	v, err = ParseGeneric("1.2.3.0.0", WithMaxSegments(3))
+	require.NoError(t, err, "trailing zeros do not count")
+	assert.Equal(t, []string{"1", "2", "3"}, v.Segments())
+
+	v, err = ParseGeneric(sentence(""), WithMaxSegments(0))
+	require.NoError(t, err)
+	assert.Len(t, v.Decimal, 50)
+}
+
+func TestWithMaxSegmentsTruncate(t *testing.T) {
+	full := parseOrFatalGeneric(t, sentence(""))
+	v, err := ParseGeneric(sentence(""), WithMaxSegments(10), WithSegmentOverflow(Truncate))
+	require.NoError(t, err)
+	assert.Equal(t, sentence(""), v.Original)
+	require.Len(t, v.Decimal, 10)
+	assert.Equal(t, full.Segments()[:9], v.Segments()[:9])
+
+	v, err = ParseGeneric(sentence(""), WithMaxSegments(1), WithSegmentOverflow(Truncate))
+	require.NoError(t, err)
+	assert.Len(t, v.Decimal, 1)
+
+	v, err = ParseGeneric("1.2.3", WithMaxSegments(10), WithSegmentOverflow(Truncate))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, v.Segments(), "short versions are unchanged")
+}
+
+func TestWithMaxSegmentsTruncatePreservesOrder(t *testing.T) {
+	suffixes := []string{
+		" 1-alpha",
+		" 1-rc1",
+		" 1",
+		" 1.0.1",
+		" 1.2",
+		" 1.10",
+		" 2",
+		" 2 a",
+		" 2 b",
+		" 10",
+		" a",
+		" b",
+	}
+	for _, n := range []int{1, 2, 10, 49} {
+		var prev, prevFull *Version
+		for _, suffix := range suffixes {
+			full := parseOrFatalGeneric(t, sentence(suffix))
+			v, err := ParseGeneric(sentence(suffix), WithMaxSegments(n), WithSegmentOverflow(Truncate))
+			require.NoError(t, err)
+			require.Len(t, v.Decimal, n)
+
+			if prev != nil {
+				require.True(t, Compare(prevFull, full) < 0, "the test inputs are in order")
+				assert.True(t, Compare(prev, v) < 0, "%s < %s when truncated to %d segments", prev.Original, v.Original, n)
+			}
+			prev, prevFull = v, full
+		}
+	}
+
+	a, err := ParseGeneric(sentence(" 1.0"), WithMaxSegments(10), WithSegmentOverflow(Truncate))
+	require.NoError(t, err)
+	b, err := ParseGeneric(sentence(" 1"), WithMaxSegments(10), WithSegmentOverflow(Truncate))
+	require.NoError(t, err)
+	assert.Equal(t, 0, Compare(a, b), "equal versions are still equal")
+}
+
+// goPreRelease returns a Go module version whose pre-release is the 25
+// identifiers "id0" to "id24", followed by suffix.
+func goPreRelease(suffix string) string {
+	ids := make([]string, 25)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("id%d", i)
+	}
+	return "v1.0.0-" + strings.Join(ids, ".") + suffix
+}
+
+func TestParseGoWithMaxSegments(t *testing.T) {
+	full, err := ParseGo(goPreRelease(""))
+	require.NoError(t, err)
+	require.True(t, len(full.Decimal) > 10, "no limit by default")
+
+	_, err = ParseGo(goPreRelease(""), WithMaxSegments(10))
+	assert.Equal(t, ErrTooManySegments, err)
+
+	v, err := ParseGo("v1.2.3", WithMaxSegments(3))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, v.Segments())
+
+	v, err = ParseGo(goPreRelease(""), WithMaxSegments(10), WithSegmentOverflow(Truncate))
+	require.NoError(t, err)
+	assert.Equal(t, Go, v.ParsedAs)
+	assert.Equal(t, goPreRelease(""), v.Original)
+	require.Len(t, v.Decimal, 10)
+	assert.Equal(t, full.Segments()[:9], v.Segments()[:9])
+
+	var prev *Version
+	for _, suffix := range []string{"", ".1", ".2", ".10", ".a"} {
+		v, err := ParseGo(goPreRelease(suffix), WithMaxSegments(10), WithSegmentOverflow(Truncate))
+		require.NoError(t, err)
+		if prev != nil {
+			assert.True(t, Compare(prev, v) < 0, "%s < %s when truncated", prev.Original, v.Original)
+		}
+		prev = v
+	}
+}
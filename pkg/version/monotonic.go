@@ -0,0 +1,78 @@
+package version
+
+// MonotonicOption configures CheckMonotonic.
+type MonotonicOption func(*monotonicOptions)
+
+type monotonicOptions struct {
+	ignorePreReleases bool
+	allowEqual        bool
+}
+
+// WithIgnorePreReleases skips pre-release versions, so they are neither
+// reported as violations nor used as the version that later ones must
+// exceed.
+func WithIgnorePreReleases() MonotonicOption {
+	return func(o *monotonicOptions) {
+		o.ignorePreReleases = true
+	}
+}
+
+// WithAllowEqual stops CheckMonotonic from reporting a version that is equal
+// to the highest earlier version, such as "1.2" published after "1.2.0".
+func WithAllowEqual() MonotonicOption {
+	return func(o *monotonicOptions) {
+		o.allowEqual = true
+	}
+}
+
+// Violation describes a version that was not greater than every version
+// before it. Index is the position of Version in the list, and
+// PreviousIndex is the position of Previous, the highest version before it.
+type Violation struct {
+	Index         int
+	Version       *Version
+	PreviousIndex int
+	Previous      *Version
+}
+
+// CheckMonotonic looks for versions in ordered, which is expected to be in
+// the order the versions were published, that are lower than or equal to a
+// version published before them. Each such version is reported once, against
+// the highest version before it, and the result is nil if there are none.
+//
+// The versions are compared with Compare, so they should all have been parsed
+// the same way. The input is not sorted or modified.
+func CheckMonotonic(ordered []*Version, opts ...MonotonicOption) []Violation {
+	var o monotonicOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var violations []Violation
+	highest := -1
+	for i, v := range ordered {
+		if o.ignorePreReleases && v.IsPreRelease() {
+			continue
+		}
+		if highest < 0 {
+			highest = i
+			continue
+		}
+
+		cmp := Compare(v, ordered[highest])
+		if cmp > 0 {
+			highest = i
+			continue
+		}
+		if cmp == 0 && o.allowEqual {
+			continue
+		}
+		violations = append(violations, Violation{
+			Index:         i,
+			Version:       v,
+			PreviousIndex: highest,
+			Previous:      ordered[highest],
+		})
+	}
+	return violations
+}
@@ -0,0 +1,70 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func genericVersions(t *testing.T, ss ...string) []*Version {
+	vs := make([]*Version, len(ss))
+	for i, s := range ss {
+		vs[i] = parseOrFatalGeneric(t, s)
+	}
+	return vs
+}
+
+func TestCheckMonotonic(t *testing.T) {
+	vs := semVers(t, "1.0.0", "1.1.0", "2.0.0", "1.1.1", "2.1.0")
+	before := semVers(t, "1.0.0", "1.1.0", "2.0.0", "1.1.1", "2.1.0")
+
+	violations := CheckMonotonic(vs)
+	require.Len(t, violations, 1)
+	assert.Equal(t, 3, violations[0].Index)
+	assert.Equal(t, "1.1.1", violations[0].Version.Original)
+	assert.Equal(t, 2, violations[0].PreviousIndex)
+	assert.Equal(t, "2.0.0", violations[0].Previous.Original)
+	assert.Equal(t, before, vs, "the input is not modified")
+
+	assert.Nil(t, CheckMonotonic(semVers(t, "1.0.0", "1.0.1", "1.1.0")))
+	assert.Nil(t, CheckMonotonic(nil))
+}
+
+func TestCheckMonotonicReportsEachVersionOnce(t *testing.T) {
+	violations := CheckMonotonic(semVers(t, "3.0.0", "1.0.0", "2.0.0", "3.0.1"))
+	require.Len(t, violations, 2)
+	for i, expected := range []int{1, 2} {
+		assert.Equal(t, expected, violations[i].Index)
+		assert.Equal(t, 0, violations[i].PreviousIndex, "compared with the highest earlier version")
+	}
+}
+
+func TestCheckMonotonicEqual(t *testing.T) {
+	vs := genericVersions(t, "1.0", "1.2.0", "1.2", "1.3")
+
+	violations := CheckMonotonic(vs)
+	require.Len(t, violations, 1)
+	assert.Equal(t, 2, violations[0].Index)
+	assert.Equal(t, 1, violations[0].PreviousIndex)
+
+	assert.Nil(t, CheckMonotonic(vs, WithAllowEqual()))
+}
+
+func TestCheckMonotonicPreReleases(t *testing.T) {
+	vs := semVers(t, "1.0.0", "2.0.0-rc.1", "1.5.0", "2.0.0", "2.1.0-beta.1", "2.0.1")
+
+	violations := CheckMonotonic(vs)
+	require.Len(t, violations, 2)
+	assert.Equal(t, 2, violations[0].Index)
+	assert.Equal(t, 1, violations[0].PreviousIndex)
+	assert.Equal(t, 5, violations[1].Index)
+	assert.Equal(t, 4, violations[1].PreviousIndex)
+
+	assert.Nil(t, CheckMonotonic(vs, WithIgnorePreReleases()))
+
+	violations = CheckMonotonic(semVers(t, "2.0.0", "2.0.0-rc.1", "1.0.0"), WithIgnorePreReleases())
+	require.Len(t, violations, 1)
+	assert.Equal(t, 2, violations[0].Index)
+	assert.Equal(t, 0, violations[0].PreviousIndex)
+}
@@ -0,0 +1,146 @@
+package version
+
+import (
+	"github.com/ericlagergren/decimal"
+)
+
+// NearestOption configures Nearest and NearestAtLeast.
+type NearestOption func(*nearestOptions)
+
+type nearestOptions struct {
+	sameMajor bool
+}
+
+// WithSameMajor restricts the candidates to versions with the same major
+// version as the target. The major version is the first segment, except for
+// PythonPEP440 versions, where the epoch must match as well.
+func WithSameMajor() NearestOption {
+	return func(o *nearestOptions) {
+		o.sameMajor = true
+	}
+}
+
+// Nearest returns the version in vs that is closest to target, or nil if
+// there are no candidates.
+//
+// Closeness is measured segment by segment, with missing segments treated as
+// zero. A candidate that matches more leading segments of the target is
+// closer, and of those that match the same number, the one whose first
+// different segment is nearest in value is closer. So for a target of 1.2.3,
+// 1.2.4 is closer than 1.3.0, and 1.3.0 is closer than 2.0.0. If two
+// candidates are equally close, the greater one is chosen, and if they are
+// equal the first in vs is chosen.
+func Nearest(target *Version, vs []*Version, opts ...NearestOption) *Version {
+	return nearest(target, vs, false, opts)
+}
+
+// NearestAtLeast works like Nearest, but only considers versions that are
+// greater than or equal to target. It returns nil if there are none.
+func NearestAtLeast(target *Version, vs []*Version, opts ...NearestOption) *Version {
+	return nearest(target, vs, true, opts)
+}
+
+func nearest(target *Version, vs []*Version, atLeast bool, opts []NearestOption) *Version {
+	var o nearestOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var (
+		best     *Version
+		bestDist versionDistance
+	)
+	for _, v := range vs {
+		if atLeast && Compare(v, target) < 0 {
+			continue
+		}
+		if o.sameMajor && !sameMajor(v, target) {
+			continue
+		}
+
+		dist := distance(target, v)
+		if best == nil {
+			best, bestDist = v, dist
+			continue
+		}
+		cmp := dist.compare(bestDist)
+		if cmp < 0 || (cmp == 0 && Compare(v, best) > 0) {
+			best, bestDist = v, dist
+		}
+	}
+	return best
+}
+
+// versionDistance describes how far apart two versions are. Same is the
+// number of leading segments they have in common, and diff is the absolute
+// difference between the first segments that are different. Diff is nil if
+// the versions are equal.
+type versionDistance struct {
+	same int
+	diff *decimal.Big
+}
+
+// compare returns a negative number if d is less than other, zero if they are
+// the same, and a positive number if d is greater.
+func (d versionDistance) compare(other versionDistance) int {
+	switch {
+	case d.diff == nil && other.diff == nil:
+		return 0
+	case d.diff == nil:
+		return -1
+	case other.diff == nil:
+		return 1
+	case d.same != other.same:
+		return other.same - d.same
+	}
+	return compareDecimals(d.diff, other.diff)
+}
+
+var zeroSegment = decimal.New(0, 0)
+
+func distance(v1, v2 *Version) versionDistance {
+	n := len(v1.Decimal)
+	if len(v2.Decimal) > n {
+		n = len(v2.Decimal)
+	}
+	for i := 0; i < n; i++ {
+		s1, s2 := segmentOrZero(v1, i), segmentOrZero(v2, i)
+		if compareDecimals(s1, s2) == 0 {
+			continue
+		}
+		diff := &decimal.Big{Context: decimal.Context{Precision: decimal.UnlimitedPrecision}}
+		diff.Sub(s1, s2)
+		return versionDistance{same: i, diff: diff.Abs(diff)}
+	}
+	return versionDistance{same: n}
+}
+
+func segmentOrZero(v *Version, i int) *decimal.Big {
+	if i < len(v.Decimal) {
+		return v.Decimal[i]
+	}
+	return zeroSegment
+}
+
+// majorSegments returns the number of leading segments of v that make up its
+// major version.
+func majorSegments(v *Version) int {
+	if v.ParsedAs == PythonPEP440 {
+		// The epoch comes before the release.
+		return 2
+	}
+	return 1
+}
+
+func sameMajor(v1, v2 *Version) bool {
+	n := majorSegments(v1)
+	if majorSegments(v2) != n {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if compareDecimals(segmentOrZero(v1, i), segmentOrZero(v2, i)) != 0 {
+			return false
+		}
+	}
+	return true
+}
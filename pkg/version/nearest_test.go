@@ -0,0 +1,94 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func semVers(t *testing.T, ss ...string) []*Version {
+	vs := make([]*Version, len(ss))
+	for i, s := range ss {
+		vs[i] = parseOrFatalSemVer(t, s)
+	}
+	return vs
+}
+
+func TestNearest(t *testing.T) {
+	target := parseOrFatalSemVer(t, "1.2.3")
+	candidates := semVers(t, "1.2.1", "1.2.4", "1.3.0", "2.0.0")
+
+	assert.Equal(t, "1.2.4", Nearest(target, candidates).Original)
+	assert.Equal(t, "1.2.4", NearestAtLeast(target, candidates).Original)
+
+	assert.Equal(t, "1.2.1", Nearest(target, semVers(t, "1.2.1")).Original)
+	assert.Nil(t, NearestAtLeast(target, semVers(t, "1.2.1")))
+	assert.Nil(t, Nearest(target, nil))
+	assert.Nil(t, NearestAtLeast(target, nil))
+
+	tests := []struct {
+		candidates []string
+		nearest    string
+		atLeast    string
+	}{
+		{[]string{"2.0.0", "1.3.0", "1.2.3"}, "1.2.3", "1.2.3"},
+		{[]string{"1.2.2", "1.2.4"}, "1.2.4", "1.2.4"},
+		{[]string{"1.2.4", "1.2.2"}, "1.2.4", "1.2.4"},
+		{[]string{"1.2.0", "1.3.0"}, "1.2.0", "1.3.0"},
+		{[]string{"0.9.0", "2.0.0"}, "2.0.0", "2.0.0"},
+		{[]string{"1.1.9", "1.3.0"}, "1.3.0", "1.3.0"},
+		{[]string{"1.2.3-rc.1", "1.2.5"}, "1.2.3-rc.1", "1.2.5"},
+	}
+	for _, tt := range tests {
+		vs := semVers(t, tt.candidates...)
+		assert.Equal(t, tt.nearest, Nearest(target, vs).Original, "%v", tt.candidates)
+		assert.Equal(t, tt.atLeast, NearestAtLeast(target, vs).Original, "%v", tt.candidates)
+	}
+}
+
+func TestNearestTies(t *testing.T) {
+	target := parseOrFatalGeneric(t, "1.2")
+	vs := []*Version{
+		parseOrFatalGeneric(t, "1.1"),
+		parseOrFatalGeneric(t, "1.3.0"),
+		parseOrFatalGeneric(t, "1.3"),
+	}
+	assert.True(t, Nearest(target, vs) == vs[1], "the greater version wins, then the first of equal versions")
+
+	target = parseOrFatalGeneric(t, "1.0")
+	vs = []*Version{parseOrFatalGeneric(t, "1.0.0"), parseOrFatalGeneric(t, "1")}
+	assert.True(t, Nearest(target, vs) == vs[0])
+	assert.True(t, NearestAtLeast(target, vs) == vs[0])
+}
+
+func TestNearestSameMajor(t *testing.T) {
+	target := parseOrFatalSemVer(t, "1.9.0")
+	vs := semVers(t, "0.9.0", "2.0.0")
+	assert.Equal(t, "2.0.0", Nearest(target, vs).Original)
+	assert.Nil(t, Nearest(target, vs, WithSameMajor()))
+
+	vs = semVers(t, "0.9.0", "2.0.0", "1.2.0")
+	assert.Equal(t, "1.2.0", Nearest(target, vs, WithSameMajor()).Original)
+	assert.Equal(t, "2.0.0", NearestAtLeast(target, vs).Original)
+	assert.Nil(t, NearestAtLeast(target, vs, WithSameMajor()))
+
+	target = parsePythonOrFatal(t, "1.5")
+	pvs := []*Version{
+		parsePythonOrFatal(t, "1!1.5"),
+		parsePythonOrFatal(t, "2.0"),
+		parsePythonOrFatal(t, "1.0"),
+	}
+	assert.Equal(t, "1.0", Nearest(target, pvs, WithSameMajor()).Original, "the epoch is part of the major version")
+	assert.Nil(t, NearestAtLeast(target, pvs, WithSameMajor()))
+}
+
+func TestNearestLongSegments(t *testing.T) {
+	// The words are converted to long decimals, which must be subtracted
+	// without rounding.
+	target := parseOrFatalGeneric(t, "1.0-alpha")
+	vs := []*Version{
+		parseOrFatalGeneric(t, "1.0-alphb"),
+		parseOrFatalGeneric(t, "1.0-alphc"),
+	}
+	assert.Equal(t, "1.0-alphb", Nearest(target, vs).Original)
+}
@@ -0,0 +1,68 @@
+package version
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ParseNix parses a version from nixpkgs, ordering it as Nix's
+// builtins.compareVersions does.
+//
+// The version is split into components at each "." and "-", and wherever a
+// digit and another character meet, so "2.3pre1" has the components 2, 3, pre
+// and 1. Components are compared in order, and a missing component is
+// empty. The component "pre" is less than all others, then the empty
+// component, then other strings in lexical order, then numbers, so
+// "2.3pre1" < "2.3" < "2.3a" < "2.3.1".
+//
+// As in Nix, a component of digits that is too large for a 32 bit int is
+// compared as a string.
+//
+// Versions may contain printable ASCII characters other than space.
+func ParseNix(version string) (*Version, error) {
+	if version == "" {
+		return nil, fmt.Errorf("invalid nix version: %s", version)
+	}
+	for i := 0; i < len(version); i++ {
+		if version[i] <= ' ' || version[i] > '~' {
+			return nil, fmt.Errorf("invalid nix version: %s", version)
+		}
+	}
+
+	// Each component is two segments: its kind and then its value. Pre is -1
+	// and 0, strings are 1 and the string as a decimal, and numbers are 2 and
+	// the number. A missing component is 0 and 0.
+	var segments []string
+	for i := 0; i < len(version); {
+		c := version[i]
+		if c == '.' || c == '-' {
+			i++
+			continue
+		}
+		start := i
+		if isASCIIDigit(c) {
+			for i < len(version) && isASCIIDigit(version[i]) {
+				i++
+			}
+		} else {
+			for i < len(version) && !isASCIIDigit(version[i]) && version[i] != '.' && version[i] != '-' {
+				i++
+			}
+		}
+		component := version[start:i]
+
+		switch n, err := strconv.ParseInt(component, 10, 64); {
+		case err == nil && n <= math.MaxInt32:
+			segments = append(segments, "2", component)
+		case component == "pre":
+			segments = append(segments, "-1", "0")
+		default:
+			segments = append(segments, "1", asciiToDecimalString(component))
+		}
+	}
+	if len(segments) == 0 {
+		segments = append(segments, "0")
+	}
+	return fromStringSlice(Nix, version, segments)
+}
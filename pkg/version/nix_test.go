@@ -0,0 +1,76 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseNixOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseNix(v)
+	require.NoError(t, err, "no error parsing %v as a Nix version", v)
+	return ver
+}
+
+func TestParseNix(t *testing.T) {
+	tests := map[string][]string{
+		"2.3":        {"2", "2", "2", "3"},
+		"2.3pre1":    {"2", "2", "2", "3", "-1", "0", "2", "1"},
+		"1.0a":       {"2", "1", "2", "0", "1", "97"},
+		"0-unstable": {"2", "0", "1", "117.110115116097098108101"},
+		"4294967296": {"1", "52.050057052057054055050057054"},
+		"...":        {"0"},
+		"007.x_y":    {"2", "7", "1", "120.095121"},
+	}
+	for in, expected := range tests {
+		v := parseNixOrFatal(t, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, Nix, v.ParsedAs, in)
+		assert.Equal(t, mustStringsToDecimal(t, expected), v.Decimal, in)
+	}
+}
+
+// The pairs up to the blank line are from Nix's eval-okay-versions.nix test.
+
+var testParseNixOrderPairs = [][]string{
+	{"1.0", "2.3"},
+	{"2.1", "2.3"},
+	{"2.3", "2.5"},
+	{"2.3", "3.1"},
+	{"2.3", "2.3.1"},
+	{"2.3a", "2.3.1"},
+	{"2.3pre1", "2.3"},
+	{"2.3pre3", "2.3pre12"},
+	{"2.3a", "2.3c"},
+	{"2.3pre1", "2.3c"},
+	{"2.3pre1", "2.3q"},
+
+	{"2.3", "2.3a"},
+	{"2.3pre1", "2.3pre1a"},
+	{"2.3pre", "2.3"},
+	{"2.3pre9", "2.3.0"},
+	{"2.3beta", "2.3rc"},
+	{"2.3rc1", "2.3.0"},
+	{"0-unstable-2024-01-01", "0-unstable-2024-02-01"},
+	{"0-unstable-2024-12-31", "0.1"},
+	{"1.9", "1.10"},
+	{"4294967296", "1"},
+}
+
+var testParseNixEqualInputs = [][]string{
+	{"2.3", "2.3"},
+	{"2.3", "2-3"},
+	{"2.3", "2..3."},
+	{"2.3pre1", "2.3.pre.1"},
+	{"2.03", "2.3"},
+}
+
+func TestParseNixErrors(t *testing.T) {
+	for _, in := range []string{"", " ", "1.0 ", "1.0\n", "1.0é"} {
+		_, err := ParseNix(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid nix version: "+in, err.Error(), in)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// npmLooseIdentifier is node-semver's loose pre-release identifier, which
+// allows numbers with leading zeros.
+const npmLooseIdentifier = `(?:[0-9]+|[0-9]*[a-zA-Z-][a-zA-Z0-9-]*)`
+
+// npmLooseRegEx matches a version in node-semver's loose form, except that the
+// minor and patch versions may be missing.
+var npmLooseRegEx = regexp.MustCompile(
+	`^[v=\s]*([0-9]+)(?:\.([0-9]+)(?:\.([0-9]+))?)?` +
+		`(?:-?(` + npmLooseIdentifier + `(?:\.` + npmLooseIdentifier + `)*))?` +
+		`(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+
+// ParseNpm parses a version from the npm registry, ordering it as
+// node-semver's compareLoose does. It accepts the versions that node-semver
+// accepts in loose mode, which may have surrounding whitespace, a leading "v"
+// or "=", and a pre-release without a "-", as in "1.2.3beta". Missing minor
+// and patch versions are 0, so "1.2" is the same as "1.2.0".
+//
+// As in semver, a pre-release is less than its release, and build metadata is
+// ignored. Pre-release identifiers are compared with case, so "1.0.0-RC" is
+// less than "1.0.0-alpha", as in node-semver.
+func ParseNpm(version string) (*Version, error) {
+	matches := npmLooseRegEx.FindStringSubmatch(strings.TrimSpace(version))
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("invalid npm version: %s", version)
+	}
+
+	segments := []string{matches[1], matches[2], matches[3]}
+	for i, s := range segments {
+		if s == "" {
+			segments[i] = "0"
+		}
+	}
+
+	if preRelease := matches[4]; preRelease != "" {
+		// As with ParseSemVer, the -1 before the pre-release makes it less
+		// than its release, and the -1 after it makes a pre-release with more
+		// identifiers greater than one with fewer.
+		segments = append(segments, "-1")
+		segments = append(segments, parseSemVerPreRelease(preRelease)...)
+		segments = append(segments, "-1")
+	}
+
+	return fromStringSlice(Npm, version, segments)
+}
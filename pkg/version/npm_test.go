@@ -0,0 +1,95 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseNpmOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseNpm(v)
+	require.NoError(t, err, "no error parsing %v as an npm version", v)
+	return ver
+}
+
+func TestParseNpm(t *testing.T) {
+	tests := map[string][]string{
+		"1.2.3":         {"1", "2", "3"},
+		"v1.2.3":        {"1", "2", "3"},
+		"=1.2.3":        {"1", "2", "3"},
+		" v= 1.2.3 ":    {"1", "2", "3"},
+		"1.2":           {"1", "2"},
+		"1":             {"1"},
+		"01.002.3":      {"1", "2", "3"},
+		"1.2.3+build.7": {"1", "2", "3"},
+		"1.2.3-0":       {"1", "2", "3", "-1", "0", "0", "-1"},
+		"1.2.3beta":     {"1", "2", "3", "-1", "98.101116097", "-1"},
+		"1.2-rc.01":     {"1", "2", "0", "-1", "114.099", "0", "1", "-1"},
+		// node-semver's loose identifiers may be just a "-".
+		"1.2.3-": {"1", "2", "3", "-1", "45", "-1"},
+	}
+	for in, expected := range tests {
+		v := parseNpmOrFatal(t, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, Npm, v.ParsedAs, in)
+		assert.Equal(t, mustStringsToDecimal(t, expected), v.Decimal, in)
+	}
+}
+
+// testParseNpmOrderGroups are versions of the kind found in npm registry data,
+// in the order given by node-semver's compareLoose. The versions in each group
+// are equal.
+var testParseNpmOrderGroups = [][]string{
+	{"0.0.0"},
+	{"0.0.1", "v0.0.1", "=0.0.1"},
+	{"v0.1", "0.1.0"},
+	{" 0.2.0 ", "0.2.0+build.1"},
+	{"1.0.0-0", "1.0.0-00"},
+	{"1.0.0-1"},
+	{"1.0.0-2"},
+	{"1.0.0-10"},
+	{"1.0.0-RC"},
+	{"1.0.0-alpha", "1.0.0alpha"},
+	{"1.0.0-alpha.1"},
+	{"1.0.0-alpha.beta"},
+	{"1.0.0-beta"},
+	{"1.0.0-beta.2"},
+	{"1.0.0-beta.11"},
+	{"1.0.0-beta.11.0"},
+	{"1.0.0-beta-2"},
+	{"1.0.0-rc.1"},
+	{"1", "1.0", "1.0.0", "v1.0.0", "=1.0.0"},
+	{"1.0.1beta"},
+	{"1.0.1"},
+	{"1.2", "1.2.0"},
+	{"v1.2.3-0"},
+	{"1.2.3", "=v1.2.3+sha.5114f85"},
+	{"1.10.0"},
+	{"2.0.0-pre"},
+	{"10.0.0"},
+}
+
+func TestParseNpmErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"   ",
+		"v",
+		"1.2.3.4",
+		"1..2",
+		"1.2.",
+		"1.2.3+",
+		"1.2.3-beta..1",
+		"1.2.x",
+		"1.2.*",
+		"^1.2.3",
+		">=1.0.0",
+		"1.2.3 - 2.0.0",
+		"latest",
+	} {
+		_, err := ParseNpm(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid npm version: "+in, err.Error(), in)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// nuGetRegEx matches a NuGet version, with one to four numeric parts, an
+// optional pre-release label and optional build metadata.
+var nuGetRegEx = regexp.MustCompile(
+	`^(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:\.(\d+))?` +
+		`(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?` +
+		`(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`)
+
+// ParseNuGet parses a NuGet package version, ordering it as NuGet's
+// VersionComparer does. NuGet versions are semver 2.0 versions that may also
+// have a fourth, revision, part, as in the legacy System.Version form
+// "2.5.1.6205". Missing minor, patch and revision parts are 0, so "1", "1.0",
+// "1.0.0" and "1.0.0.0" are equal.
+//
+// The pre-release label is compared without regard to case, so "1.0.0-BETA"
+// equals "1.0.0-beta", and a pre-release is less than its release. Its
+// identifiers are compared as in semver. The build metadata after a "+" is
+// ignored, and is stored in the BuildMetadata field of the returned Version.
+//
+// Floating versions and ranges, such as "1.0.*" or "[1.0,2.0)", are rejected.
+func ParseNuGet(version string) (*Version, error) {
+	var matches []string
+	if len(version) > 0 && isASCIIDigit(version[0]) && semVerBytes.containsAll(version) {
+		matches = nuGetRegEx.FindStringSubmatch(version)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("invalid nuget version: %s", version)
+	}
+
+	segments := make([]string, 0, 4)
+	for _, part := range matches[1:5] {
+		if part == "" {
+			part = "0"
+		}
+		segments = append(segments, part)
+	}
+
+	if preRelease := matches[5]; preRelease != "" {
+		// As with ParseSemVer, the -1 before the label makes a pre-release
+		// less than its release, and the -1 after it makes a label with more
+		// identifiers greater than one with fewer.
+		segments = append(segments, "-1")
+		segments = append(segments, parseSemVerPreRelease(strings.ToLower(preRelease))...)
+		segments = append(segments, "-1")
+	}
+
+	v, err := fromStringSlice(NuGet, version, segments)
+	if err != nil {
+		return nil, err
+	}
+	v.BuildMetadata = matches[6]
+	return v, nil
+}
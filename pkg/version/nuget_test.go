@@ -0,0 +1,90 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseNuGetOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseNuGet(v)
+	require.NoError(t, err, "no error parsing %v as a NuGet version", v)
+	return ver
+}
+
+func TestParseNuGet(t *testing.T) {
+	tests := []struct {
+		in       string
+		decimal  []string
+		metadata string
+	}{
+		{"1", []string{"1"}, ""},
+		{"1.0", []string{"1"}, ""},
+		{"1.0.0.0", []string{"1"}, ""},
+		{"2.5.1.6205", []string{"2", "5", "1", "6205"}, ""},
+		{"1.2.3", []string{"1", "2", "3"}, ""},
+		{"01.02.03", []string{"1", "2", "3"}, ""},
+		{"1.0.0+sha.abc123", []string{"1"}, "sha.abc123"},
+		{"1.0.0-rc.1", []string{"1", "0", "0", "0", "-1", "114.099", "0", "1", "-1"}, ""},
+		{"1.0.0-RC.1", []string{"1", "0", "0", "0", "-1", "114.099", "0", "1", "-1"}, ""},
+		{"1.0.0.1-beta+build", []string{"1", "0", "0", "1", "-1", "98.101116097", "-1"}, "build"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			v := parseNuGetOrFatal(t, tt.in)
+			assert.Equal(t, tt.in, v.Original)
+			assert.Equal(t, NuGet, v.ParsedAs)
+			assert.Equal(t, mustStringsToDecimal(t, tt.decimal), v.Decimal, "decimal for %s", tt.in)
+			assert.Equal(t, tt.metadata, v.BuildMetadata, "build metadata for %s", tt.in)
+		})
+	}
+}
+
+var testParseNuGetOrderInputs = []string{
+	"0.9",
+	"1.0.0-alpha",
+	"1.0.0-alpha.1",
+	"1.0.0-alpha.beta",
+	"1.0.0-beta.2",
+	"1.0.0-beta.11",
+	"1.0.0-rc.1",
+	"1.0.0",
+	"1.0.0.1-beta",
+	"1.0.0.1",
+	"1.0.1",
+	"2.5.1.6205",
+	"10.0",
+}
+
+var testParseNuGetEqualInputs = [][]string{
+	{"1.0.0-BETA", "1.0.0-beta"},
+	{"1.0.0-Alpha.Beta", "1.0.0-alpha.BETA"},
+	{"1", "1.0.0.0"},
+	{"1.0", "1.0.0+build"},
+	{"1.0.0-rc.1+a", "1.0.0-rc.1+b"},
+}
+
+func TestParseNuGetErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"1.0.*",
+		"1.*",
+		"*",
+		"1.0.0-*",
+		"[1.0,2.0)",
+		"1.0.0.0.0",
+		"v1.0.0",
+		" 1.0.0",
+		"1.0.0-",
+		"1.0.0-beta..1",
+		"1.0.0+",
+		"1..0",
+		"1.0.0-beta_1",
+	} {
+		_, err := ParseNuGet(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid nuget version: "+in, err.Error(), in)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package version_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/ActiveState/langtools/pkg/version/versiontest"
+)
+
+func parsePHP(s string) (*version.Version, error) {
+	return version.ParsePHP(s)
+}
+
+func assertAllEqual(t *testing.T, parse versiontest.ParseFunc, groups [][]string) {
+	t.Helper()
+	for _, group := range groups {
+		versiontest.AssertAllEqual(t, parse, group)
+	}
+}
+
+// assertOrderedPairs checks that the first version in each pair is less than
+// the second.
+func assertOrderedPairs(t *testing.T, parse versiontest.ParseFunc, pairs [][]string) {
+	t.Helper()
+	for _, pair := range pairs {
+		versiontest.AssertOrdered(t, parse, pair)
+	}
+}
+
+// assertOrderedGroups checks that the versions in each group are equal and
+// that each version is less than every version in the groups after it.
+func assertOrderedGroups(t *testing.T, parse versiontest.ParseFunc, groups [][]string) {
+	t.Helper()
+	assertAllEqual(t, parse, groups)
+
+	longest := 0
+	for _, group := range groups {
+		if len(group) > longest {
+			longest = len(group)
+		}
+	}
+	// Each pass takes one version from every group, so that every version is
+	// compared with every group after its own.
+	for i := 0; i < longest; i++ {
+		inputs := make([]string, len(groups))
+		for j, group := range groups {
+			if i < len(group) {
+				inputs[j] = group[i]
+			} else {
+				inputs[j] = group[len(group)-1]
+			}
+		}
+		versiontest.AssertOrdered(t, parse, inputs)
+	}
+}
+
+// parseCalVer parses a version and a layout separated by a space.
+func parseCalVer(s string) (*version.Version, error) {
+	parts := strings.SplitN(s, " ", 2)
+	return version.ParseCalVer(parts[0], parts[1])
+}
+
+func parseDotNetAssembly(s string) (*version.Version, error) {
+	return version.ParseDotNetAssembly(s)
+}
+
+// parseCPE parses the version and update attributes of a CPE name separated
+// by a colon.
+func parseCPE(s string) (*version.Version, error) {
+	i := strings.LastIndex(s, ":")
+	return version.ParseCPE(s[:i], s[i+1:])
+}
+
+func TestParseSemVerOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, version.ParseSemVer, version.SemVerOrderInputs)
+}
+
+func TestParsePHPOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, parsePHP, version.PHPOrderInputs)
+}
+
+func TestParsePHPEqual(t *testing.T) {
+	assertAllEqual(t, parsePHP, version.PHPEqualInputs)
+}
+
+func TestParsePythonOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, version.ParsePython, version.PythonOrderInputs)
+}
+
+func TestParsePythonOrderingEqual(t *testing.T) {
+	versiontest.AssertAllEqual(t, version.ParsePython, []string{"1", "1.0", "1.0.0", "1.0.0.0"})
+}
+
+func TestParseRubyOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, version.ParseRuby, version.RubyOrderInputs)
+}
+
+func TestParseRubyEqual(t *testing.T) {
+	assertAllEqual(t, version.ParseRuby, version.RubyEqualInputs)
+}
+
+func TestParseMavenOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, version.ParseMaven, version.MavenQualifierOrderInputs)
+	versiontest.AssertOrdered(t, version.ParseMaven, version.MavenNumberOrderInputs)
+	assertOrderedPairs(t, version.ParseMaven, version.MavenOrderPairs)
+}
+
+func TestParseMavenEqual(t *testing.T) {
+	assertAllEqual(t, version.ParseMaven, version.MavenEqualInputs)
+}
+
+func TestParseNuGetOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, version.ParseNuGet, version.NuGetOrderInputs)
+}
+
+func TestParseNuGetEqual(t *testing.T) {
+	assertAllEqual(t, version.ParseNuGet, version.NuGetEqualInputs)
+}
+
+func TestParseNpmOrdering(t *testing.T) {
+	assertOrderedGroups(t, version.ParseNpm, version.NpmOrderGroups)
+}
+
+func TestParseGentooOrdering(t *testing.T) {
+	assertOrderedPairs(t, version.ParseGentoo, version.GentooOrderPairs)
+}
+
+func TestParseGentooEqual(t *testing.T) {
+	assertAllEqual(t, version.ParseGentoo, version.GentooEqualInputs)
+}
+
+func TestParseLuaRocksOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, version.ParseLuaRocks, version.LuaRocksOrderInputs)
+}
+
+func TestParseLuaRocksEqual(t *testing.T) {
+	assertAllEqual(t, version.ParseLuaRocks, version.LuaRocksEqualInputs)
+}
+
+func TestParseHexOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, version.ParseHex, version.HexOrderInputs)
+}
+
+func TestParseHexEqual(t *testing.T) {
+	assertAllEqual(t, version.ParseHex, version.HexEqualInputs)
+}
+
+func TestParseNixOrdering(t *testing.T) {
+	assertOrderedPairs(t, version.ParseNix, version.NixOrderPairs)
+}
+
+func TestParseNixEqual(t *testing.T) {
+	assertAllEqual(t, version.ParseNix, version.NixEqualInputs)
+}
+
+func TestParseCalVerOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, parseCalVer, version.CalVerOrderInputs)
+}
+
+func TestParseCalVerEqual(t *testing.T) {
+	assertAllEqual(t, parseCalVer, version.CalVerEqualInputs)
+}
+
+func TestParseJavaRuntimeOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, version.ParseJavaRuntime, version.JavaRuntimeOrderInputs)
+}
+
+func TestParseJavaRuntimeEqual(t *testing.T) {
+	assertAllEqual(t, version.ParseJavaRuntime, version.JavaRuntimeEqualInputs)
+}
+
+func TestParseDotNetAssemblyOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, parseDotNetAssembly, version.DotNetAssemblyOrderInputs)
+}
+
+func TestParseFreeBSDPortsOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, version.ParseFreeBSDPorts, version.FreeBSDPortsOrderInputs)
+}
+
+func TestParseFreeBSDPortsEqual(t *testing.T) {
+	assertAllEqual(t, version.ParseFreeBSDPorts, version.FreeBSDPortsEqualInputs)
+}
+
+func TestParseConanOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, version.ParseConan, version.ConanOrderInputs)
+}
+
+func TestParseConanEqual(t *testing.T) {
+	assertAllEqual(t, version.ParseConan, version.ConanEqualInputs)
+}
+
+func TestParseLinuxKernelOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, version.ParseLinuxKernel, version.LinuxKernelOrderInputs)
+}
+
+func TestParseLinuxKernelEqual(t *testing.T) {
+	assertAllEqual(t, version.ParseLinuxKernel, version.LinuxKernelEqualInputs)
+}
+
+func TestParseDockerTagOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, version.ParseDockerTag, version.DockerTagOrderInputs)
+}
+
+func TestParseDockerTagEqual(t *testing.T) {
+	assertAllEqual(t, version.ParseDockerTag, version.DockerTagEqualInputs)
+}
+
+func TestParseCPEOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, parseCPE, version.CPEOrderInputs)
+}
+
+func TestParseCPEEqual(t *testing.T) {
+	assertAllEqual(t, parseCPE, version.CPEEqualInputs)
+}
+
+func TestParseWindowsFileVersionOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, version.ParseWindowsFileVersion, version.WindowsFileVersionOrderInputs)
+}
+
+func TestParseWindowsFileVersionEqual(t *testing.T) {
+	assertAllEqual(t, version.ParseWindowsFileVersion, version.WindowsFileVersionEqualInputs)
+}
+
+func TestParseKubernetesOrdering(t *testing.T) {
+	versiontest.AssertOrdered(t, version.ParseKubernetes, version.KubernetesOrderInputs)
+}
+
+func TestParseKubernetesEqual(t *testing.T) {
+	assertAllEqual(t, version.ParseKubernetes, version.KubernetesEqualInputs)
+}
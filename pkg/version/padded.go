@@ -0,0 +1,63 @@
+package version
+
+import (
+	"fmt"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// PaddedSegments returns exactly k segments of v, as Segments would return
+// them, with zero segments added to the end as needed. This suits storage
+// with a fixed number of columns. Trailing zero segments do not count
+// towards k, so a version made with FromSortable from "1", "2", "0" still
+// fits in two segments.
+//
+// It returns an error if v has more than k segments once trailing zeros are
+// trimmed, or if k is less than one.
+func (v *Version) PaddedSegments(k int) ([]string, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("cannot pad %s to %d segments", v, k)
+	}
+
+	segments := TrimTrailingZeroSegments(v.Segments())
+	if len(segments) > k {
+		return nil, fmt.Errorf("cannot pad %s to %d segments, as it has %d", v, k, len(segments))
+	}
+	for len(segments) < k {
+		segments = append(segments, "0")
+	}
+	return segments, nil
+}
+
+// WithFixedSegments makes ParseGeneric and ParsePHP return versions with
+// exactly k segments, by adding zero segments after the usual trimming of
+// trailing zeros. A version that has more than k segments once trailing
+// zeros are trimmed is an error. This has no effect on how versions compare,
+// since trailing zeros are ignored by Compare. Values of k less than one have
+// no effect.
+//
+// With ParseGeneric, any WithMaxSegments limit is applied first.
+func WithFixedSegments(k int) Option {
+	return func(o *options) {
+		o.fixedSegments = k
+	}
+}
+
+// padToFixedSegments applies the WithFixedSegments option to v.
+func padToFixedSegments(v *Version, o options) (*Version, error) {
+	k := o.fixedSegments
+	if k < 1 || len(v.Decimal) == k {
+		return v, nil
+	}
+	if len(v.Decimal) > k {
+		return nil, fmt.Errorf("version %s has %d segments, which is more than the %d allowed", v.Original, len(v.Decimal), k)
+	}
+
+	padded := make([]*decimal.Big, k)
+	copy(padded, v.Decimal)
+	for i := len(v.Decimal); i < k; i++ {
+		padded[i] = bigZero
+	}
+	v.Decimal = padded
+	return v, nil
+}
@@ -0,0 +1,89 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaddedSegments(t *testing.T) {
+	v := parseOrFatalSemVer(t, "1.2.0")
+	segments, err := v.PaddedSegments(5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "0", "0", "0"}, segments)
+
+	segments, err = v.PaddedSegments(2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, segments)
+
+	_, err = v.PaddedSegments(1)
+	assert.Error(t, err)
+	_, err = v.PaddedSegments(0)
+	assert.Error(t, err)
+
+	v, err = FromSortable("1.2", []string{"1", "2", "0", "0"}, Generic)
+	require.NoError(t, err)
+	segments, err = v.PaddedSegments(3)
+	require.NoError(t, err, "trailing zeros do not count")
+	assert.Equal(t, []string{"1", "2", "0"}, segments)
+}
+
+func TestPaddedSegmentsRoundTrip(t *testing.T) {
+	var vs []*Version
+	for _, s := range testParseSemVerOrderInputs {
+		vs = append(vs, parseOrFatalSemVer(t, s))
+	}
+
+	var padded []*Version
+	for _, v := range vs {
+		segments, err := v.PaddedSegments(16)
+		require.NoError(t, err, v.Original)
+		require.Len(t, segments, 16)
+
+		p, err := FromSortable(v.Original, segments, v.ParsedAs)
+		require.NoError(t, err)
+		assert.Equal(t, segments, p.Segments())
+		assert.Equal(t, 0, Compare(v, p), "%s compares equal when padded", v.Original)
+		assert.Equal(t, v.Segments(), TrimTrailingZeroSegments(p.Segments()))
+		padded = append(padded, p)
+	}
+
+	for i := range vs {
+		for j := range vs {
+			assert.Equal(t, Compare(vs[i], vs[j]), Compare(padded[i], padded[j]), "%s and %s", vs[i].Original, vs[j].Original)
+		}
+	}
+}
+
+func TestWithFixedSegments(t *testing.T) {
+	v, err := ParseGeneric("1.2", WithFixedSegments(4))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "0", "0"}, v.Segments())
+	assert.Equal(t, 0, Compare(v, parseOrFatalGeneric(t, "1.2")))
+
+	v, err = ParseGeneric("1.2-beta", WithFixedSegments(4))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "-25", "0"}, v.Segments())
+	assert.True(t, Compare(v, parseOrFatalGeneric(t, "1.2")) < 0)
+
+	v, err = ParseGeneric("1.2.0.0.0", WithFixedSegments(2))
+	require.NoError(t, err, "trailing zeros are trimmed before the version is padded")
+	assert.Equal(t, []string{"1", "2"}, v.Segments())
+
+	_, err = ParseGeneric("1.2.3", WithFixedSegments(2))
+	assert.Error(t, err)
+
+	v, err = ParsePHP("1.0.0-beta2", WithFixedSegments(8))
+	require.NoError(t, err)
+	assert.Len(t, v.Decimal, 8)
+	assert.Equal(t, 0, Compare(v, parsePHPOrFatal(t, "1.0.0-beta2")))
+
+	v, err = Parse(PHP, "1.0", WithFixedSegments(6))
+	require.NoError(t, err, "Parse passes the option along")
+	assert.Len(t, v.Decimal, 6)
+
+	v, err = ParseGeneric(sentence(""), WithMaxSegments(10), WithSegmentOverflow(Truncate), WithFixedSegments(12))
+	require.NoError(t, err)
+	assert.Len(t, v.Decimal, 12, "the segment limit is applied first")
+}
@@ -7,9 +7,9 @@ import (
 	"fmt"
 )
 
-const _ParsedAsName = "UnknownGenericSemVerPerlDecimalPerlVStringPHPPythonLegacyPythonPEP440Ruby"
+const _ParsedAsName = "UnknownGenericSemVerPerlDecimalPerlVStringPHPPythonLegacyPythonPEP440RubyRawDebianMavenNuGetNpmGentooLuaRocksHexNixCalVerJavaRuntimeDotNetAssemblyFreeBSDPortsConanLinuxKernelDockerTagCPEWindowsFileVersionGoToolchainKubernetesGo"
 
-var _ParsedAsIndex = [...]uint8{0, 7, 14, 20, 31, 42, 45, 57, 69, 73}
+var _ParsedAsIndex = [...]uint8{0, 7, 14, 20, 31, 42, 45, 57, 69, 73, 76, 82, 87, 92, 95, 101, 109, 112, 115, 121, 132, 146, 158, 163, 174, 183, 186, 204, 215, 225, 227}
 
 func (i ParsedAs) String() string {
 	if i < 0 || i >= ParsedAs(len(_ParsedAsIndex)-1) {
@@ -18,18 +18,39 @@ func (i ParsedAs) String() string {
 	return _ParsedAsName[_ParsedAsIndex[i]:_ParsedAsIndex[i+1]]
 }
 
-var _ParsedAsValues = []ParsedAs{0, 1, 2, 3, 4, 5, 6, 7, 8}
+var _ParsedAsValues = []ParsedAs{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29}
 
 var _ParsedAsNameToValueMap = map[string]ParsedAs{
-	_ParsedAsName[0:7]:   0,
-	_ParsedAsName[7:14]:  1,
-	_ParsedAsName[14:20]: 2,
-	_ParsedAsName[20:31]: 3,
-	_ParsedAsName[31:42]: 4,
-	_ParsedAsName[42:45]: 5,
-	_ParsedAsName[45:57]: 6,
-	_ParsedAsName[57:69]: 7,
-	_ParsedAsName[69:73]: 8,
+	_ParsedAsName[0:7]:     0,
+	_ParsedAsName[7:14]:    1,
+	_ParsedAsName[14:20]:   2,
+	_ParsedAsName[20:31]:   3,
+	_ParsedAsName[31:42]:   4,
+	_ParsedAsName[42:45]:   5,
+	_ParsedAsName[45:57]:   6,
+	_ParsedAsName[57:69]:   7,
+	_ParsedAsName[69:73]:   8,
+	_ParsedAsName[73:76]:   9,
+	_ParsedAsName[76:82]:   10,
+	_ParsedAsName[82:87]:   11,
+	_ParsedAsName[87:92]:   12,
+	_ParsedAsName[92:95]:   13,
+	_ParsedAsName[95:101]:  14,
+	_ParsedAsName[101:109]: 15,
+	_ParsedAsName[109:112]: 16,
+	_ParsedAsName[112:115]: 17,
+	_ParsedAsName[115:121]: 18,
+	_ParsedAsName[121:132]: 19,
+	_ParsedAsName[132:146]: 20,
+	_ParsedAsName[146:158]: 21,
+	_ParsedAsName[158:163]: 22,
+	_ParsedAsName[163:174]: 23,
+	_ParsedAsName[174:183]: 24,
+	_ParsedAsName[183:186]: 25,
+	_ParsedAsName[186:204]: 26,
+	_ParsedAsName[204:215]: 27,
+	_ParsedAsName[215:225]: 28,
+	_ParsedAsName[225:227]: 29,
 }
 
 // ParsedAsString retrieves an enum value from the enum constants string name.
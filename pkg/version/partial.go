@@ -0,0 +1,92 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// ComparePartial compares the first n release segments of v1 and v2, such as
+// the major and minor versions when n is 2, and returns -1, 0 or 1 in the
+// same way as Compare. Missing segments are treated as zero, so 2 and 2.0.1
+// are equal when n is 2.
+//
+// Only the release is compared. The epoch of a PythonPEP440 version is
+// compared first but does not count towards n, so a depth of 2 still means
+// major.minor. A segment that is not a whole number marks the start of a
+// pre-release or other suffix, and it and any segments after it are treated
+// as zero, so 1.2.0-rc.1 and 1.2.0 are equal when n is 3.
+//
+// It returns an *IncomparableError if the versions cannot be compared (see
+// Comparable), and an error if n is less than one or either version was
+// parsed as PythonLegacy, whose segments encode characters rather than
+// release numbers.
+func ComparePartial(v1, v2 *Version, n int) (int, error) {
+	if !Comparable(v1.ParsedAs, v2.ParsedAs) {
+		return 0, &IncomparableError{ParsedAs1: v1.ParsedAs, ParsedAs2: v2.ParsedAs}
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("cannot compare the first %d segments of versions", n)
+	}
+	if v1.ParsedAs == PythonLegacy || v2.ParsedAs == PythonLegacy {
+		return 0, errors.New("cannot compare part of a legacy Python version")
+	}
+
+	start := releaseStart(v1)
+	if start > 0 {
+		if cmp := compareDecimals(segmentOrZero(v1, 0), segmentOrZero(v2, 0)); cmp != 0 {
+			return cmp, nil
+		}
+	}
+
+	ended1, ended2 := false, false
+	for i := start; i < start+n; i++ {
+		var s1, s2 *decimal.Big
+		s1, ended1 = releaseSegment(v1, i, ended1)
+		s2, ended2 = releaseSegment(v2, i, ended2)
+		if cmp := compareDecimals(s1, s2); cmp != 0 {
+			return cmp, nil
+		}
+	}
+	return 0, nil
+}
+
+// SameSeries returns true if v1 and v2 are in the same release series, which
+// is when ComparePartial finds that their first depth release segments are
+// equal. So with a depth of 2, 1.4.0 and 1.4.7 are in the same series. Values
+// of depth less than one are treated as one, as in GroupBySeries. It returns
+// false if ComparePartial returns an error.
+func SameSeries(v1, v2 *Version, depth int) bool {
+	if depth < 1 {
+		depth = 1
+	}
+	cmp, err := ComparePartial(v1, v2, depth)
+	return err == nil && cmp == 0
+}
+
+// releaseStart returns the index of the first release segment of v.
+func releaseStart(v *Version) int {
+	if v.ParsedAs == PythonPEP440 {
+		// The epoch comes before the release.
+		return 1
+	}
+	return 0
+}
+
+// releaseSegment returns segment i of v, or zero if the release has already
+// ended or ends at this segment, along with whether the release has ended.
+func releaseSegment(v *Version, i int, ended bool) (*decimal.Big, bool) {
+	seg := segmentOrZero(v, i)
+	if ended || !isWholeNumber(seg) {
+		return zeroSegment, true
+	}
+	return seg, false
+}
+
+// isWholeNumber returns true if d is zero or a positive integer. In the
+// segments of most types anything else is part of a pre-release or other
+// suffix.
+func isWholeNumber(d *decimal.Big) bool {
+	return d.Sign() >= 0 && d.IsInt()
+}
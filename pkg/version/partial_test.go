@@ -0,0 +1,90 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComparePartial(t *testing.T) {
+	tests := []struct {
+		v1, v2   *Version
+		n        int
+		expected int
+	}{
+		{parseOrFatalSemVer(t, "1.2.3"), parseOrFatalSemVer(t, "1.2.9"), 2, 0},
+		{parseOrFatalSemVer(t, "1.2.3"), parseOrFatalSemVer(t, "1.2.9"), 3, -1},
+		{parseOrFatalSemVer(t, "1.3.0"), parseOrFatalSemVer(t, "1.2.9"), 2, 1},
+		{parseOrFatalSemVer(t, "1.2.3"), parseOrFatalSemVer(t, "2.0.0"), 1, -1},
+		{parseOrFatalSemVer(t, "1.2.0-rc.1"), parseOrFatalSemVer(t, "1.2.4"), 2, 0},
+		{parseOrFatalSemVer(t, "1.2.0-rc.1"), parseOrFatalSemVer(t, "1.2.0"), 3, 0},
+		{parseOrFatalSemVer(t, "1.2.0-rc.1"), parseOrFatalSemVer(t, "1.2.0-beta"), 10, 0},
+		{parseOrFatalGeneric(t, "2"), parseOrFatalGeneric(t, "2.0.1"), 2, 0},
+		{parseOrFatalGeneric(t, "2"), parseOrFatalGeneric(t, "2.0.1"), 3, -1},
+		{parseOrFatalGeneric(t, "1-rc1"), parseOrFatalGeneric(t, "1.0"), 2, 0},
+		{parseOrFatalGeneric(t, "1.4-beta"), parseOrFatalGeneric(t, "1.4.2"), 2, 0},
+		{parseOrFatalGeneric(t, "1.4-beta"), parseOrFatalGeneric(t, "1.5-beta"), 2, -1},
+		{parseOrFatalGeneric(t, "1.0.2k"), parseOrFatalGeneric(t, "1.0.2"), 3, 0},
+		{parsePythonOrFatal(t, "1.2"), parsePythonOrFatal(t, "1.2.5"), 2, 0},
+		{parsePythonOrFatal(t, "1.2rc1"), parsePythonOrFatal(t, "1.2.5"), 2, 0},
+		{parsePythonOrFatal(t, "1.2.dev3"), parsePythonOrFatal(t, "1.2.post1"), 2, 0},
+		{parsePythonOrFatal(t, "1.3"), parsePythonOrFatal(t, "1.2.5"), 2, 1},
+		{parsePythonOrFatal(t, "1!1.2"), parsePythonOrFatal(t, "1.2"), 2, 1},
+		{parsePythonOrFatal(t, "1!1.2"), parsePythonOrFatal(t, "5.0"), 1, 1},
+	}
+	for _, tt := range tests {
+		actual, err := ComparePartial(tt.v1, tt.v2, tt.n)
+		require.NoError(t, err, "%s and %s", tt.v1, tt.v2)
+		assert.Equal(t, tt.expected, actual, "%s and %s with n = %d", tt.v1, tt.v2, tt.n)
+
+		actual, err = ComparePartial(tt.v2, tt.v1, tt.n)
+		require.NoError(t, err, "%s and %s", tt.v2, tt.v1)
+		assert.Equal(t, -tt.expected, actual, "%s and %s with n = %d", tt.v2, tt.v1, tt.n)
+
+		assert.Equal(t, tt.expected == 0, SameSeries(tt.v1, tt.v2, tt.n), "%s and %s with depth %d", tt.v1, tt.v2, tt.n)
+	}
+}
+
+func TestComparePartialErrors(t *testing.T) {
+	_, err := ComparePartial(parseOrFatalSemVer(t, "1.0.0"), parseOrFatalGeneric(t, "1.0"), 2)
+	assert.IsType(t, &IncomparableError{}, err)
+	assert.False(t, SameSeries(parseOrFatalSemVer(t, "1.0.0"), parseOrFatalGeneric(t, "1.0"), 2))
+
+	_, err = ComparePartial(parseOrFatalSemVer(t, "1.0.0"), parseOrFatalSemVer(t, "1.0.1"), 0)
+	assert.Error(t, err)
+
+	_, err = ComparePartial(parsePythonOrFatal(t, "1.0-foo"), parsePythonOrFatal(t, "1.0"), 2)
+	assert.Error(t, err)
+}
+
+func TestSameSeriesDepth(t *testing.T) {
+	v1, v2 := parseOrFatalSemVer(t, "1.4.0"), parseOrFatalSemVer(t, "1.9.2")
+	assert.True(t, SameSeries(v1, v2, 1))
+	assert.True(t, SameSeries(v1, v2, 0), "a depth below one is treated as one")
+	assert.False(t, SameSeries(v1, v2, 2))
+}
+
+func TestSameSeriesMatchesGroupBySeries(t *testing.T) {
+	var vs []*Version
+	for _, s := range testParseSemVerOrderInputs {
+		vs = append(vs, parseOrFatalSemVer(t, s))
+	}
+	for _, s := range pythonTestStrings {
+		if v := parsePythonOrFatal(t, s); v.ParsedAs == PythonPEP440 {
+			vs = append(vs, v)
+		}
+	}
+
+	for depth := 1; depth <= 3; depth++ {
+		for _, v1 := range vs {
+			for _, v2 := range vs {
+				if !Comparable(v1.ParsedAs, v2.ParsedAs) {
+					continue
+				}
+				sameKey := seriesKey(v1, depth) == seriesKey(v2, depth)
+				assert.Equal(t, sameKey, SameSeries(v1, v2, depth), "%s and %s with depth %d", v1, v2, depth)
+			}
+		}
+	}
+}
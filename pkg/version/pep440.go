@@ -0,0 +1,313 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// This file contains a hand written matcher for pep440VersionPattern. The
+// regex is large enough that matching it dominated the time spent parsing
+// Python versions.
+
+// The pre-release labels, in the order the alternation in
+// pep440VersionPattern tries them.
+var pep440PreReleaseLabels = []string{"a", "b", "c", "rc", "alpha", "beta", "pre", "preview"}
+
+// The post-release labels, in the order the alternation in
+// pep440VersionPattern tries them.
+var pep440PostReleaseLabels = []string{"post", "rev", "r"}
+
+// matchPEP440 matches version against the same grammar as
+// pep440NormalizationRegex and returns the same groups that findPEP440Matches
+// would. If version does not match, the error gives the offset of the
+// furthest character that could not be matched.
+func matchPEP440(version string) (pep440Matches, error) {
+	m := pep440Matcher{s: version}
+	if m.match() {
+		return m.matches, nil
+	}
+
+	r, _ := utf8.DecodeRuneInString(version[m.furthest:])
+	return pep440Matches{}, fmt.Errorf("not PEP440 version: %s: unexpected %q at offset %d", version, r, m.furthest)
+}
+
+// pep440Matcher is a backtracking matcher for pep440VersionPattern. Where the
+// regex has a choice to make, each stage tries the same alternatives in the
+// same order as Go's regexp does and passes the rest of the string on to the
+// next stage, backtracking if that fails. This means the first complete match
+// found is the same one the regex would find.
+//
+// Each stage sets all of its groups before calling the next stage so that a
+// failed alternative never leaves stale groups behind.
+type pep440Matcher struct {
+	s        string
+	matches  pep440Matches
+	furthest int
+}
+
+func (m *pep440Matcher) reached(pos int) {
+	if pos > m.furthest {
+		m.furthest = pos
+	}
+}
+
+// match matches `^\s*v?(?:(?P<epoch>[0-9]+)!)?(?P<release>[0-9]+(?:\.[0-9]+)*)`
+// and then the optional parts of the version. None of these have a choice that
+// could lead to a different match.
+func (m *pep440Matcher) match() bool {
+	pos := m.skipSpace(0)
+	if pos < len(m.s) && (m.s[pos] == 'v' || m.s[pos] == 'V') {
+		pos++
+	}
+	m.reached(pos)
+
+	m.matches.epoch = ""
+	if end := m.skipDigits(pos); end > pos && end < len(m.s) && m.s[end] == '!' {
+		m.matches.epoch = m.s[pos:end]
+		pos = end + 1
+		m.reached(pos)
+	}
+
+	end := m.skipDigits(pos)
+	if end == pos {
+		return false
+	}
+	for end+1 < len(m.s) && m.s[end] == '.' && isASCIIDigit(m.s[end+1]) {
+		end = m.skipDigits(end + 1)
+	}
+	m.matches.release = m.s[pos:end]
+
+	return m.matchPre(end)
+}
+
+// matchPre matches
+// `(?P<pre>[-_\.]?(?P<pre_l>(a|b|c|rc|alpha|beta|pre|preview))[-_\.]?(?P<pre_n>[0-9]+)?)?`
+// and the rest of the version after it.
+func (m *pep440Matcher) matchPre(pos int) bool {
+	m.reached(pos)
+
+	labelStarts, nLabelStarts := m.separatorChoices(pos)
+	for _, labelStart := range labelStarts[:nLabelStarts] {
+		for _, label := range pep440PreReleaseLabels {
+			labelEnd, ok := m.matchLabel(labelStart, label)
+			if !ok {
+				continue
+			}
+			numberStarts, nNumberStarts := m.separatorChoices(labelEnd)
+			for _, numberStart := range numberStarts[:nNumberStarts] {
+				numberEnds, nNumberEnds := m.numberChoices(numberStart)
+				for _, numberEnd := range numberEnds[:nNumberEnds] {
+					m.matches.pre = m.s[pos:numberEnd]
+					m.matches.preLabel = m.s[labelStart:labelEnd]
+					m.matches.preNumber = m.s[numberStart:numberEnd]
+					if m.matchPost(numberEnd) {
+						return true
+					}
+				}
+			}
+		}
+	}
+
+	m.matches.pre, m.matches.preLabel, m.matches.preNumber = "", "", ""
+	return m.matchPost(pos)
+}
+
+// matchPost matches
+// `(?P<post>(?:-(?P<post_n1>[0-9]+))|(?:[-_\.]?(?P<post_l>post|rev|r)[-_\.]?(?P<post_n2>[0-9]+)?))?`
+// and the rest of the version after it.
+func (m *pep440Matcher) matchPost(pos int) bool {
+	m.reached(pos)
+
+	if pos < len(m.s) && m.s[pos] == '-' {
+		if end := m.skipDigits(pos + 1); end > pos+1 {
+			m.matches.post = m.s[pos:end]
+			m.matches.postNumber1 = m.s[pos+1 : end]
+			m.matches.postNumber2 = ""
+			if m.matchDev(end) {
+				return true
+			}
+		}
+	}
+
+	labelStarts, nLabelStarts := m.separatorChoices(pos)
+	for _, labelStart := range labelStarts[:nLabelStarts] {
+		for _, label := range pep440PostReleaseLabels {
+			labelEnd, ok := m.matchLabel(labelStart, label)
+			if !ok {
+				continue
+			}
+			numberStarts, nNumberStarts := m.separatorChoices(labelEnd)
+			for _, numberStart := range numberStarts[:nNumberStarts] {
+				numberEnds, nNumberEnds := m.numberChoices(numberStart)
+				for _, numberEnd := range numberEnds[:nNumberEnds] {
+					m.matches.post = m.s[pos:numberEnd]
+					m.matches.postNumber1 = ""
+					m.matches.postNumber2 = m.s[numberStart:numberEnd]
+					if m.matchDev(numberEnd) {
+						return true
+					}
+				}
+			}
+		}
+	}
+
+	m.matches.post, m.matches.postNumber1, m.matches.postNumber2 = "", "", ""
+	return m.matchDev(pos)
+}
+
+// matchDev matches `(?P<dev>[-_\.]?(?P<dev_l>dev)[-_\.]?(?P<dev_n>[0-9]+)?)?`
+// and the rest of the version after it.
+func (m *pep440Matcher) matchDev(pos int) bool {
+	m.reached(pos)
+
+	labelStarts, nLabelStarts := m.separatorChoices(pos)
+	for _, labelStart := range labelStarts[:nLabelStarts] {
+		labelEnd, ok := m.matchLabel(labelStart, "dev")
+		if !ok {
+			continue
+		}
+		numberStarts, nNumberStarts := m.separatorChoices(labelEnd)
+		for _, numberStart := range numberStarts[:nNumberStarts] {
+			numberEnds, nNumberEnds := m.numberChoices(numberStart)
+			for _, numberEnd := range numberEnds[:nNumberEnds] {
+				m.matches.dev = m.s[pos:numberEnd]
+				m.matches.devNumber = m.s[numberStart:numberEnd]
+				if m.matchLocal(numberEnd) {
+					return true
+				}
+			}
+		}
+	}
+
+	m.matches.dev, m.matches.devNumber = "", ""
+	return m.matchLocal(pos)
+}
+
+// matchLocal matches `(?:\+(?P<local>[a-z0-9]+(?:[-_\.][a-z0-9]+)*))?\s*$`.
+// Only the longest local segment can be followed by the end of the string, so
+// there is nothing to backtrack over.
+func (m *pep440Matcher) matchLocal(pos int) bool {
+	m.reached(pos)
+
+	m.matches.local = ""
+	if pos < len(m.s) && m.s[pos] == '+' {
+		start := pos + 1
+		end := m.skipLocalChars(start)
+		if end > start {
+			for end < len(m.s) && isPEP440Separator(m.s[end]) {
+				next := m.skipLocalChars(end + 1)
+				if next == end+1 {
+					break
+				}
+				end = next
+			}
+			m.matches.local = m.s[start:end]
+			pos = end
+			m.reached(pos)
+		}
+	}
+
+	pos = m.skipSpace(pos)
+	m.reached(pos)
+	return pos == len(m.s)
+}
+
+// separatorChoices returns the positions `[-_\.]?` can end at when matched at
+// pos, most preferred first, and how many of them there are.
+func (m *pep440Matcher) separatorChoices(pos int) ([2]int, int) {
+	if pos < len(m.s) && isPEP440Separator(m.s[pos]) {
+		return [2]int{pos + 1, pos}, 2
+	}
+	return [2]int{pos}, 1
+}
+
+// numberChoices returns the positions `([0-9]+)?` can end at when matched at
+// pos, most preferred first, and how many of them there are. Stopping part way
+// through a run of digits is not one of the choices as nothing that can follow
+// a number starts with a digit.
+func (m *pep440Matcher) numberChoices(pos int) ([2]int, int) {
+	if end := m.skipDigits(pos); end > pos {
+		return [2]int{end, pos}, 2
+	}
+	return [2]int{pos}, 1
+}
+
+// matchLabel matches label case insensitively at pos, the same way as the
+// regex's (?i) flag does.
+func (m *pep440Matcher) matchLabel(pos int, label string) (int, bool) {
+	for i := 0; i < len(label); i++ {
+		if pos >= len(m.s) {
+			return 0, false
+		}
+		if n := foldedASCIILetterLen(m.s[pos:], label[i]); n > 0 {
+			pos += n
+			continue
+		}
+		return 0, false
+	}
+	return pos, true
+}
+
+func (m *pep440Matcher) skipDigits(pos int) int {
+	for pos < len(m.s) && isASCIIDigit(m.s[pos]) {
+		pos++
+	}
+	return pos
+}
+
+// skipSpace skips the characters matched by \s, which are only ASCII.
+func (m *pep440Matcher) skipSpace(pos int) int {
+	for pos < len(m.s) {
+		switch m.s[pos] {
+		case '\t', '\n', '\f', '\r', ' ':
+			pos++
+		default:
+			return pos
+		}
+	}
+	return pos
+}
+
+// skipLocalChars skips the characters matched by `(?i)[a-z0-9]`.
+func (m *pep440Matcher) skipLocalChars(pos int) int {
+	for pos < len(m.s) {
+		c := m.s[pos]
+		switch {
+		case isASCIIDigit(c) || isASCIILower(c|0x20):
+			pos++
+		case c >= utf8.RuneSelf:
+			n := foldedASCIILetterLen(m.s[pos:], 's')
+			if n == 0 {
+				n = foldedASCIILetterLen(m.s[pos:], 'k')
+			}
+			if n == 0 {
+				return pos
+			}
+			pos += n
+		default:
+			return pos
+		}
+	}
+	return pos
+}
+
+// foldedASCIILetterLen returns the length in bytes of the character at the
+// start of s if it is equal to the lowercase ASCII letter c under Unicode
+// simple case folding, or 0 if it is not. Besides the upper and lowercase
+// ASCII letters, 's' also folds to 'ſ' (U+017F) and 'k' to 'K' (U+212A).
+func foldedASCIILetterLen(s string, c byte) int {
+	switch {
+	case s[0]|0x20 == c && isASCIILower(c):
+		return 1
+	case c == 's' && strings.HasPrefix(s, "ſ"):
+		return len("ſ")
+	case c == 'k' && strings.HasPrefix(s, "K"):
+		return len("K")
+	}
+	return 0
+}
+
+func isPEP440Separator(c byte) bool {
+	return c == '-' || c == '_' || c == '.'
+}
@@ -0,0 +1,145 @@
+package version
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pep440MatcherTestStrings = []string{
+	"",
+	" ",
+	"v",
+	"1",
+	"V1.0",
+	" \t1.0\n",
+	"1.0\v",
+	"1!",
+	"1!2",
+	"1!2!3",
+	"1.",
+	"1..0",
+	"1.0a",
+	"1.0alpha",
+	"1.0alph",
+	"1.0ALPHA1",
+	"1.0a.1",
+	"1.0a-1",
+	"1.0a_1",
+	"1.0a--1",
+	"1.0-a1",
+	"1.0--a1",
+	"1.0pre",
+	"1.0prev",
+	"1.0preview",
+	"1.0previewpost",
+	"1.0prer1",
+	"1.0rc",
+	"1.0r",
+	"1.0rc1r2",
+	"1.0-1",
+	"1.0-1-1",
+	"1.0-post",
+	"1.0.post1",
+	"1.0post-1",
+	"1.0poſt1",
+	"1.0POſT1",
+	"1.0rev",
+	"1.0a-dev",
+	"1.0a1-1.dev1",
+	"1.0.dev",
+	"1.0dev-1",
+	"1.0devv",
+	"1.0+",
+	"1.0+a",
+	"1.0+a-",
+	"1.0+a..b",
+	"1.0+a.b-c_d",
+	"1.0+ABC",
+	"1.0+ſK",
+	"1.0+é",
+	"1.0+1 ",
+	"1.0 +1",
+	"1.0a1.post2.dev3+local.1",
+}
+
+// randomPEP440String returns a string built from pieces that are significant
+// to the PEP440 grammar, so that it has a good chance of matching.
+func randomPEP440String(r *rand.Rand) string {
+	pieces := []string{
+		"0", "1", "12", "v", "V", "!", ".", "-", "_", "+", " ", "\t", "\v",
+		"a", "b", "c", "rc", "alpha", "beta", "pre", "preview", "post",
+		"rev", "r", "dev", "A", "POST", "Dev", "ſ", "K", "x", "é", "l", "p",
+	}
+
+	var b strings.Builder
+	for n := r.Intn(12); n >= 0; n-- {
+		b.WriteString(pieces[r.Intn(len(pieces))])
+	}
+	return b.String()
+}
+
+func TestMatchPEP440MatchesRegex(t *testing.T) {
+	inputs := append([]string{}, pep440MatcherTestStrings...)
+	inputs = append(inputs, pythonTestStrings...)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		inputs = append(inputs, randomPEP440String(r))
+	}
+
+	matched := 0
+	for _, in := range inputs {
+		expected, expectedOK := findPEP440Matches(in)
+		actual, err := matchPEP440(in)
+		if !assert.Equal(t, expectedOK, err == nil, "%q matches", in) {
+			continue
+		}
+		if expectedOK {
+			matched++
+		}
+		assert.Equal(t, expected, actual, "groups for %q", in)
+	}
+	assert.True(t, matched > 1000, "enough random inputs match to be useful (%d)", matched)
+}
+
+func TestParsePEP440FastMatchesRegex(t *testing.T) {
+	inputs := append([]string{}, pep440MatcherTestStrings...)
+	inputs = append(inputs, pythonTestStrings...)
+
+	for _, in := range inputs {
+		expected, expectedErr := parsePEP440Regex(in)
+		actual, err := parsePEP440Fast(in)
+		if expectedErr != nil {
+			assert.Error(t, err, "%q is rejected", in)
+			continue
+		}
+
+		require.NoError(t, err, "%q is accepted", in)
+		assert.Equal(t, expected.Original, actual.Original)
+		assert.Equal(t, expected.ParsedAs, actual.ParsedAs)
+		assert.Equal(t, expected.String(), actual.String())
+		assert.Equal(t, 0, Compare(expected, actual), "%q parses the same way", in)
+	}
+}
+
+func TestMatchPEP440ErrorOffset(t *testing.T) {
+	tests := []struct {
+		version string
+		message string
+	}{
+		{"", `not PEP440 version: : unexpected '�' at offset 0`},
+		{"x1.0", `not PEP440 version: x1.0: unexpected 'x' at offset 0`},
+		{"1.0x", `not PEP440 version: 1.0x: unexpected 'x' at offset 3`},
+		{"1.0a1x", `not PEP440 version: 1.0a1x: unexpected 'x' at offset 5`},
+		{"1.0+a-é", `not PEP440 version: 1.0+a-é: unexpected '-' at offset 5`},
+	}
+
+	for _, tt := range tests {
+		_, err := matchPEP440(tt.version)
+		require.Error(t, err, tt.version)
+		assert.Equal(t, tt.message, err.Error())
+	}
+}
@@ -47,26 +47,30 @@ var (
 // dotted-decimal (v1.2.3). This function parses both types and normalizes
 // them to dotted-decimal for comparison purposes.
 func ParsePerl(version string) (*Version, error) {
-	if decimalRegex.MatchString(version) {
-		return parsePerlDecimalVersion(version)
-	}
+	if perlMayMatch(version) {
+		if decimalRegex.MatchString(version) {
+			return parsePerlDecimalVersion(version)
+		}
 
-	if dottedDecimalRegex.MatchString(version) {
-		return parsePerlVStringVersion(version)
+		if dottedDecimalRegex.MatchString(version) {
+			return parsePerlVStringVersion(version)
+		}
 	}
 
 	return nil, fmt.Errorf("not valid perl version: %s", version)
 }
 
-func parsePerlDecimalVersion(version string) (*Version, error) {
-	version = strings.ReplaceAll(version, "_", "")
-	parts := strings.Split(version, ".")
-	segments := make([]string, 0, len(parts))
-	segments = append(segments, decimalIntegerPartToSegment(parts[0]))
-	if len(parts) == 2 {
-		segments = append(segments, decimalFractionAndAlphaPartToSegments(parts[1])...)
+func parsePerlDecimalVersion(original string) (*Version, error) {
+	version := strings.ReplaceAll(original, "_", "")
+	integer, fraction := version, ""
+	if i := strings.IndexByte(version, '.'); i >= 0 {
+		integer, fraction = version[:i], version[i+1:]
 	}
-	return fromStringSlice(PerlDecimal, version, segments)
+
+	segments := make([]string, 0, 1+(len(fraction)+2)/3)
+	segments = append(segments, decimalIntegerPartToSegment(integer))
+	segments = appendDecimalFractionAndAlphaPartSegments(segments, fraction)
+	return fromStringSlice(PerlDecimal, original, segments)
 }
 
 func decimalIntegerPartToSegment(part string) string {
@@ -77,29 +81,20 @@ func decimalIntegerPartToSegment(part string) string {
 	return part
 }
 
-func decimalFractionAndAlphaPartToSegments(part string) []string {
-	// Pad part out to a multiple of three.
-	if padN := len(part) % 3; padN != 0 {
-		part += "000"[padN:]
+// appendDecimalFractionAndAlphaPartSegments splits part into three-digit long
+// segments and appends them to segments. The last segment is padded out to
+// three digits with zeros.
+func appendDecimalFractionAndAlphaPartSegments(segments []string, part string) []string {
+	i := 0
+	for ; i+3 <= len(part); i += 3 {
+		segments = append(segments, removeLeadingZeros(part[i:i+3]))
 	}
-
-	// Split part into three-digit long segments.
-	segments := make([]string, 0, 2)
-	for i := 0; i < len(part); i = i + 3 {
-		j := min(i+3, len(part))
-		segments = append(segments, removeLeadingZeros(part[i:j]))
+	if rest := part[i:]; rest != "" {
+		segments = append(segments, removeLeadingZeros(rest+"000"[len(rest):]))
 	}
-
 	return segments
 }
 
-func min(lhs, rhs int) int {
-	if lhs <= rhs {
-		return lhs
-	}
-	return rhs
-}
-
 func removeLeadingZeros(s string) string {
 	if s = strings.TrimLeft(s, "0"); s != "" {
 		return s
@@ -107,8 +102,8 @@ func removeLeadingZeros(s string) string {
 	return "0"
 }
 
-func parsePerlVStringVersion(version string) (*Version, error) {
-	version = strings.TrimPrefix(version, "v")
+func parsePerlVStringVersion(original string) (*Version, error) {
+	version := strings.TrimPrefix(original, "v")
 	version = strings.ReplaceAll(version, "_", "")
 	segments := strings.Split(version, ".")
 	for i, s := range segments {
@@ -116,5 +111,5 @@ func parsePerlVStringVersion(version string) (*Version, error) {
 			segments[i] = "0"
 		}
 	}
-	return fromStringSlice(PerlVString, version, segments)
+	return fromStringSlice(PerlVString, original, segments)
 }
@@ -6,6 +6,10 @@ import (
 	"strings"
 )
 
+// phpClassicalSegments is the number of numeric parts composer allows in a
+// classical version and the number the normalized form is padded to.
+const phpClassicalSegments = 4
+
 var (
 	phpAliasRegex = regexp.MustCompile(
 		`^([^,\s]+) +as +([^,\s]+)$`,
@@ -16,8 +20,11 @@ var (
 	phpBuildRegex = regexp.MustCompile(
 		`^([^,\s+]+)\+[^\s]+$`,
 	)
+	// phpClassicalRegex matches the first four numeric parts of a classical
+	// version in their own groups and any further parts together in the
+	// fifth, so that one regex serves every WithExtraSegments limit.
 	phpClassicalRegex = regexp.MustCompile(
-		`(?i)^v?(\d{1,5})(\.\d+)?(\.\d+)?(\.\d+)?[._-]?(?:(stable|beta|b|RC|alpha|a|patch|pl|p)((?:[.-]?\d+)*)?)?([.-]?dev)?$`,
+		`(?i)^v?(\d{1,5})(\.\d+)?(\.\d+)?(\.\d+)?((?:\.\d+)*)[._-]?(?:(stable|beta|b|RC|alpha|a|patch|pl|p)((?:[.-]?\d+)*)?)?([.-]?dev)?$`,
 	)
 	phpDatetimeRegex = regexp.MustCompile(
 		`(?i)^v?(\d{4}(?:[.:-]?\d{2}){1,6}(?:[.:-]?\d{1,3})?)[._-]?(?:(stable|beta|b|RC|alpha|a|patch|pl|p)((?:[.-]?\d+)*)?)?([.-]?dev)?$`,
@@ -25,38 +32,78 @@ var (
 	phpNondigitRegex = regexp.MustCompile(
 		`\D`,
 	)
-	phpDigitWordRegex = regexp.MustCompile(
-		`(\d)([a-zA-Z])`,
-	)
-	phpWordDigitRegex = regexp.MustCompile(
-		`([a-zA-Z])(\d)`,
-	)
 )
 
+// WithExtraSegments allows ParsePHP to accept classical versions with up to n
+// numeric parts instead of the four that composer allows, so "1.2.3.4.5" can
+// be parsed. Values of n less than or equal to four have no effect, and large
+// values cost no more than small ones.
+//
+// Versions with four or fewer numeric parts are parsed exactly as they are
+// without this option. Zero-valued parts beyond the fourth are dropped from
+// the end of the release, so "1.2.3.4.0-beta" and "1.2.3.4-beta" are equal.
+func WithExtraSegments(n int) Option {
+	return func(o *options) {
+		o.phpMaxSegments = n
+	}
+}
+
 // ParsePHP attempts to parse a version according to the same rules used by
 // composer (https://github.com/composer/semver)
-func ParsePHP(version string) (*Version, error) {
+//
+// ParsePHP honors the WithExtraSegments and WithFixedSegments options.
+func ParsePHP(version string, opts ...Option) (*Version, error) {
 	original := version
+	o := applyOptions(opts)
 
-	version, err := normalizePHP(version)
+	version, err := normalizePHPMaxSegments(version, o.phpMaxSegments)
 	if err != nil {
 		return nil, err
 	}
 
-	version = strings.ReplaceAll(version, "_", ".")
-	version = strings.ReplaceAll(version, "-", ".")
-	version = strings.ReplaceAll(version, "+", ".")
+	segments := splitPHPSegments(version)
+	numericSegments := convertPHPSegments(segments)
+	v, err := fromStringSlice(PHP, original, numericSegments)
+	if err != nil {
+		return nil, err
+	}
+	return padToFixedSegments(v, o)
+}
 
-	version = phpDigitWordRegex.ReplaceAllString(version, "$1.$2")
-	version = phpWordDigitRegex.ReplaceAllString(version, "$1.$2")
+// splitPHPSegments splits a normalized version at every ".", "_", "-" and
+// "+", and between each run of ASCII digits and run of ASCII letters. This is
+// the same as replacing the separators with "." and inserting a "." at each
+// boundary between a digit and a letter before splitting at ".", but it is
+// done in one pass without building the intermediate strings.
+func splitPHPSegments(version string) []string {
+	// Besides the "."-separated numeric parts, a normalized version has at
+	// most a stability flag, its number and "dev", so this is usually enough.
+	segments := make([]string, 0, strings.Count(version, ".")+4)
 
-	segments := strings.Split(version, ".")
-	numericSegments := convertPHPSegments(segments)
-	return fromStringSlice(PHP, original, numericSegments)
+	start := 0
+	for i := 0; i < len(version); i++ {
+		switch c := version[i]; {
+		case c == '.' || c == '_' || c == '-' || c == '+':
+			segments = append(segments, version[start:i])
+			start = i + 1
+		case i > start && isPHPDigitLetterBoundary(version[i-1], c):
+			segments = append(segments, version[start:i])
+			start = i
+		}
+	}
+	return append(segments, version[start:])
+}
+
+func isPHPDigitLetterBoundary(prev, c byte) bool {
+	return (isASCIIDigit(prev) && isASCIILetter(c)) || (isASCIILetter(prev) && isASCIIDigit(c))
+}
+
+func isASCIILetter(c byte) bool {
+	return isASCIILower(c) || (c >= 'A' && c <= 'Z')
 }
 
 func convertPHPSegments(segments []string) []string {
-	results := []string{}
+	results := make([]string, 0, len(segments)+2)
 	leadingSegmentCount := 0
 	hasSpecial := false
 	lastIsSpecial := false
@@ -100,10 +147,9 @@ func convertPHPSegments(segments []string) []string {
 		} else {
 			value = "-0.5"
 		}
-		results = append(
-			results[:leadingSegmentCount],
-			append([]string{value}, results[leadingSegmentCount:]...)...,
-		)
+		results = append(results, "")
+		copy(results[leadingSegmentCount+1:], results[leadingSegmentCount:])
+		results[leadingSegmentCount] = value
 	}
 
 	// Ensure that "1.0.patch" < "1.0.patch.0".
@@ -132,7 +178,21 @@ func expandPHPStability(stability string) string {
 }
 
 func normalizePHP(version string) (string, error) {
+	return normalizePHPMaxSegments(version, phpClassicalSegments)
+}
+
+func normalizePHPMaxSegments(version string, maxSegments int) (string, error) {
+	if !phpMayMatch(version) {
+		return "", fmt.Errorf("invalid php version: %v", version)
+	}
+	return normalizePHPWithRegexes(version, maxSegments)
+}
+
+func normalizePHPWithRegexes(version string, maxSegments int) (string, error) {
 	original := version
+	if maxSegments < phpClassicalSegments {
+		maxSegments = phpClassicalSegments
+	}
 
 	// Extra whitespace is tolerated
 	version = strings.TrimSpace(version)
@@ -161,7 +221,10 @@ func normalizePHP(version string) (string, error) {
 	// Try normal matching first
 	index := 0
 	matches = phpClassicalRegex.FindStringSubmatch(version)
-	if len(matches) > 4 {
+	if len(matches) > 0 && strings.Count(matches[5], ".") > maxSegments-phpClassicalSegments {
+		matches = nil
+	}
+	if len(matches) > 0 {
 		if matches[2] == "" {
 			matches[2] = ".0"
 		}
@@ -171,8 +234,9 @@ func normalizePHP(version string) (string, error) {
 		if matches[4] == "" {
 			matches[4] = ".0"
 		}
-		version = matches[1] + matches[2] + matches[3] + matches[4]
-		index = 5
+		version = matches[1] + matches[2] + matches[3] + matches[4] +
+			trimPHPExtraSegments(matches[5])
+		index = 6
 	}
 	if len(matches) == 0 {
 		// Then try datetime matching
@@ -205,3 +269,17 @@ func normalizePHP(version string) (string, error) {
 
 	return "", fmt.Errorf("invalid php version: %v", original)
 }
+
+// trimPHPExtraSegments drops the trailing zero-valued parts from the numeric
+// parts matched beyond the classical four, so that the normalized form of a
+// version is the same whether or not they were given.
+func trimPHPExtraSegments(extra string) string {
+	for extra != "" {
+		i := strings.LastIndexByte(extra, '.')
+		if strings.Trim(extra[i+1:], "0") != "" {
+			break
+		}
+		extra = extra[:i]
+	}
+	return extra
+}
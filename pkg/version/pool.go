@@ -0,0 +1,115 @@
+package version
+
+import (
+	"sync"
+)
+
+// segmentsPool holds the slices that parseGenericInto builds segments in.
+// It holds pointers to slices so that Put does not allocate.
+var segmentsPool = sync.Pool{
+	New: func() interface{} {
+		s := make([]string, 0, 16)
+		return &s
+	},
+}
+
+// putSegments returns segments to segmentsPool, clearing it first so the
+// pool does not keep the strings in it alive.
+func putSegments(pooled *[]string, segments []string) {
+	for i := range segments {
+		segments[i] = ""
+	}
+	*pooled = segments[:0]
+	segmentsPool.Put(pooled)
+}
+
+// Parser parses versions into Versions that are reused once they are
+// released, for pipelines that parse and then discard very large numbers of
+// versions, and where the garbage collector would otherwise dominate. The
+// zero Parser is ready to use, and a Parser is safe for concurrent use.
+//
+// Pooling is only safe if nothing keeps a reference to a released Version.
+// Once a Version is passed to ReleaseVersion it may be returned again by
+// AcquireVersion or Parse and overwritten, so the caller must not keep the
+// Version itself, its Decimal slice, or any subslice of it. Copy anything
+// that must outlive the Version, for example with Clone, before releasing
+// it. The values in the Decimal slice are never modified, so they may be
+// kept.
+//
+// The package level parsing funcs never use a Parser's Versions, and are not
+// affected by it.
+type Parser struct {
+	versions sync.Pool
+}
+
+// AcquireVersion returns an empty Version from the pool, or a new one if the
+// pool is empty.
+func (p *Parser) AcquireVersion() *Version {
+	if v, ok := p.versions.Get().(*Version); ok {
+		return v
+	}
+	return &Version{}
+}
+
+// ReleaseVersion resets v to an empty Version and returns it to the pool. It
+// clears every element of the Decimal slice's backing array, so nothing from
+// v can leak into the next version parsed into it. v must not be used after
+// it is released.
+func (p *Parser) ReleaseVersion(v *Version) {
+	if v == nil {
+		return
+	}
+	resetVersion(v)
+	p.versions.Put(v)
+}
+
+// Parse parses version as the given type, as the package level Parse does,
+// into a Version from the pool. The Version should be passed to
+// ReleaseVersion once it is no longer needed.
+func (p *Parser) Parse(pa ParsedAs, version string, opts ...Option) (*Version, error) {
+	v := p.AcquireVersion()
+	if err := ParseInto(v, pa, version, opts...); err != nil {
+		p.ReleaseVersion(v)
+		return nil, err
+	}
+	return v, nil
+}
+
+// ParseInto parses version as the given type, as Parse does, and stores the
+// result in dst, reusing dst's Decimal slice if it is big enough. Any
+// previous contents of dst are overwritten, so the same caveats as for
+// Parser apply: nothing may keep a reference to dst's Decimal slice from
+// before the call. If parsing fails, dst is reset to an empty Version.
+//
+// Generic versions are parsed without allocating anything other than the
+// decimals for segments that are not integers from 0 to 1024, such as the
+// negative segments of pre-releases. Versions of other types are parsed with
+// Parse and then copied into dst, which saves only the Decimal slice.
+func ParseInto(dst *Version, pa ParsedAs, version string, opts ...Option) error {
+	if pa == Generic {
+		return parseGenericInto(dst, version, applyOptions(opts))
+	}
+
+	v, err := Parse(pa, version, opts...)
+	if err != nil {
+		resetVersion(dst)
+		return err
+	}
+	*dst = Version{
+		Original:      v.Original,
+		Decimal:       append(dst.Decimal[:0], v.Decimal...),
+		ParsedAs:      v.ParsedAs,
+		BuildMetadata: v.BuildMetadata,
+	}
+	return nil
+}
+
+// resetVersion sets v to an empty Version, keeping the backing array of its
+// Decimal slice after clearing every element of it.
+func resetVersion(v *Version) {
+	decimals := v.Decimal[:cap(v.Decimal)]
+	for i := range decimals {
+		decimals[i] = nil
+	}
+	*v = Version{Decimal: decimals[:0]}
+}
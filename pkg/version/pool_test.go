@@ -0,0 +1,147 @@
+package version
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInto(t *testing.T) {
+	tests := []struct {
+		pa      ParsedAs
+		version string
+		opts    []Option
+	}{
+		{Generic, "1.2.3", nil},
+		{Generic, "1.1.0-pre1", nil},
+		{Generic, "v2.7.18-rc1+build.5", []Option{WithIgnoreBuildMetadata()}},
+		{Generic, "小寸-1.1", nil},
+		{SemVer, "1.0.0-alpha.1", nil},
+		{PythonPEP440, "1.0.post1", nil},
+		{PHP, "1.2.3.4.5", []Option{WithExtraSegments(5)}},
+		{Ruby, "1.0.a.2", nil},
+	}
+
+	// All of the versions are parsed into the same Version, to check that
+	// nothing from one is left in the next.
+	var dst Version
+	for _, tt := range tests {
+		expected, err := Parse(tt.pa, tt.version, tt.opts...)
+		require.NoError(t, err, tt.version)
+		require.NoError(t, ParseInto(&dst, tt.pa, tt.version, tt.opts...), tt.version)
+		assert.Equal(t, expected.Original, dst.Original, tt.version)
+		assert.Equal(t, expected.ParsedAs, dst.ParsedAs, tt.version)
+		assert.Equal(t, expected.BuildMetadata, dst.BuildMetadata, tt.version)
+		assert.Equal(t, expected.Decimal, dst.Decimal, tt.version)
+	}
+}
+
+func TestParseIntoReusesDecimalSlice(t *testing.T) {
+	var dst Version
+	require.NoError(t, ParseInto(&dst, Generic, "1.2.3.4.5"))
+	first := &dst.Decimal[:1][0]
+
+	for _, s := range []string{"6.7", "8.9.10.11", "12"} {
+		require.NoError(t, ParseInto(&dst, Generic, s))
+		assert.True(t, first == &dst.Decimal[:1][0], "%s is parsed into the same backing array", s)
+		assert.Equal(t, s, dst.Original)
+	}
+}
+
+func TestParseIntoError(t *testing.T) {
+	// ParseGeneric accepts any string, so only the other types can fail.
+	for _, pa := range []ParsedAs{SemVer, Unknown} {
+		var dst Version
+		require.NoError(t, ParseInto(&dst, Generic, "1.2.3+build", WithIgnoreBuildMetadata()))
+
+		assert.Error(t, ParseInto(&dst, pa, "not semver"), pa)
+		assert.Equal(t, "", dst.Original, pa)
+		assert.Equal(t, Unknown, dst.ParsedAs, pa)
+		assert.Equal(t, "", dst.BuildMetadata, pa)
+		assert.Empty(t, dst.Decimal, pa)
+	}
+}
+
+func TestParserReleaseVersionZeroesState(t *testing.T) {
+	var p Parser
+	v, err := p.Parse(Generic, "1.2.3-rc4+build.5", WithIgnoreBuildMetadata())
+	require.NoError(t, err)
+	backing := v.Decimal[:cap(v.Decimal)]
+
+	p.ReleaseVersion(v)
+	assert.Equal(t, "", v.Original)
+	assert.Equal(t, Unknown, v.ParsedAs)
+	assert.Equal(t, "", v.BuildMetadata)
+	assert.Len(t, v.Decimal, 0)
+	for i, d := range backing {
+		assert.Nil(t, d, "element %d of the backing array is cleared", i)
+	}
+
+	// A shorter version parsed into the released Version must not pick up
+	// any of the segments of the longer one.
+	require.NoError(t, ParseInto(v, Generic, "7"))
+	assert.Equal(t, mustStringsToDecimal(t, []string{"7"}), v.Decimal)
+	assert.Equal(t, "", v.BuildMetadata)
+}
+
+func TestParserParse(t *testing.T) {
+	var p Parser
+	for _, s := range genericBenchmarkStrings {
+		expected, err := ParseGeneric(s)
+		require.NoError(t, err, s)
+
+		v, err := p.Parse(Generic, s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, v, s)
+		p.ReleaseVersion(v)
+	}
+
+	_, err := p.Parse(SemVer, "1.0")
+	assert.Error(t, err)
+	p.ReleaseVersion(nil)
+}
+
+func TestParserConcurrentUse(t *testing.T) {
+	expected := make([]*Version, len(genericBenchmarkStrings))
+	for i, s := range genericBenchmarkStrings {
+		v, err := ParseGeneric(s)
+		require.NoError(t, err, s)
+		expected[i] = v
+	}
+
+	var p Parser
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				e := expected[i%len(expected)]
+				v, err := p.Parse(Generic, e.Original)
+				if assert.NoError(t, err, e.Original) {
+					assert.Equal(t, e, v, e.Original)
+					p.ReleaseVersion(v)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestParseIntoAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not meaningful with the race detector")
+	}
+
+	var dst Version
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, s := range []string{"1.2.3", "2.7.18", "10.0.1", "0"} {
+			if err := ParseInto(&dst, Generic, s); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+	assert.Equal(t, 0.0, allocs, "parsing generic versions of small integers into a Version does not allocate")
+}
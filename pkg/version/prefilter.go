@@ -0,0 +1,96 @@
+package version
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The parsers for some schemes run several regexes on every input. The funcs
+// in this file check the bytes of an input for things that no version in a
+// scheme can have, so that the parsers can reject most versions from other
+// schemes without running any regexes. Each of them only ever rejects inputs
+// that the regexes would reject as well.
+
+// byteSet is a set of bytes stored as a bitmask.
+type byteSet [4]uint64
+
+func newByteSet(chars string) byteSet {
+	var s byteSet
+	for i := 0; i < len(chars); i++ {
+		s[chars[i]/64] |= 1 << (chars[i] % 64)
+	}
+	return s
+}
+
+func (s *byteSet) contains(c byte) bool {
+	return s[c/64]&(1<<(c%64)) != 0
+}
+
+// containsAll reports whether every byte of str is in s.
+func (s *byteSet) containsAll(str string) bool {
+	for i := 0; i < len(str); i++ {
+		if !s.contains(str[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+const asciiDigits = "0123456789"
+
+var (
+	semVerBytes = newByteSet(asciiDigits + "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.+-")
+	perlBytes   = newByteSet(asciiDigits + "._")
+	phpFirst    = newByteSet(asciiDigits + "vV")
+	// phpBytes holds the bytes the classical and datetime regexes allow,
+	// which are digits, separators, a leading "v" and the letters of the
+	// stability flags, in either case.
+	phpBytes = newByteSet(asciiDigits + "._-:" + "vstablercphd" + "VSTABLERCPHD")
+)
+
+// semVerMayMatch returns false if version cannot match semVerRegEx. The
+// shortest semver version is "0.0.0" and it always starts with the major
+// version.
+func semVerMayMatch(version string) bool {
+	return len(version) >= len("0.0.0") &&
+		isASCIIDigit(version[0]) &&
+		semVerBytes.containsAll(version)
+}
+
+// perlMayMatch returns false if version cannot match decimalRegex or
+// dottedDecimalRegex. Both only allow digits, periods and underscores, apart
+// from a leading "v".
+func perlMayMatch(version string) bool {
+	if version == "" {
+		return false
+	}
+	if version[0] == 'v' {
+		version = version[1:]
+	}
+	return perlBytes.containsAll(version)
+}
+
+// phpMayMatch returns false if normalizePHPMaxSegments would reject version.
+// Removing an alias, stability flag or build metadata leaves the start of the
+// version as it was, and both the classical and datetime regexes require that
+// to be a "v" or a digit once leading whitespace is trimmed.
+//
+// If there is nothing to remove, which there can't be without a space, "@" or
+// "+", the whole of the trimmed version must match one of the regexes, so it
+// can only contain the bytes in phpBytes. The only non-ASCII runes that
+// strings.ToLower turns into ASCII are 'K' and 'İ', which become "k" and "i",
+// and neither of those is in phpBytes either.
+func phpMayMatch(version string) bool {
+	version = strings.TrimFunc(version, unicode.IsSpace)
+	if version == "" {
+		return false
+	}
+	if version[0] < utf8.RuneSelf && !phpFirst.contains(version[0]) {
+		return false
+	}
+	if strings.ContainsAny(version, " @+") {
+		return true
+	}
+	return phpBytes.containsAll(version)
+}
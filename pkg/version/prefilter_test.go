@@ -0,0 +1,90 @@
+package version
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// prefilterTestStrings returns every version string from the test corpora
+// plus random strings built from pieces that matter to the semver, perl and
+// php grammars.
+func prefilterTestStrings() []string {
+	var inputs []string
+	inputs = append(inputs, pythonTestStrings...)
+	inputs = append(inputs, pep440MatcherTestStrings...)
+	inputs = append(inputs, testParseSemVerOrderInputs...)
+	inputs = append(inputs, testParsePHPOrderInputs...)
+	inputs = append(inputs, invalidPHPVersions...)
+	for _, equal := range testParsePHPEqualInputs {
+		inputs = append(inputs, equal...)
+	}
+	inputs = append(inputs, rubyTestStrings...)
+	inputs = append(inputs, invalidRubyVersions...)
+	for _, equal := range equalRubyVersions {
+		inputs = append(inputs, equal...)
+	}
+	inputs = append(inputs, genericBenchmarkStrings...)
+	inputs = append(inputs, perlDecimalBenchmarkStrings...)
+	inputs = append(inputs, perlVStringBenchmarkStrings...)
+
+	pieces := []string{
+		"0", "1", "12", "2020", "v", "V", ".", "_", "-", "+", ":", ",", "@",
+		" ", "\t", " ", " as ", "alpha", "RC", "beta", "dev", "stable",
+		"pl", "x", "K", "é", "İ", "K",
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		var b strings.Builder
+		for n := r.Intn(10); n >= 0; n-- {
+			b.WriteString(pieces[r.Intn(len(pieces))])
+		}
+		inputs = append(inputs, b.String())
+	}
+	return inputs
+}
+
+func TestPrefiltersOnlyRejectNonMatches(t *testing.T) {
+	rejected := map[string]int{}
+	for _, in := range prefilterTestStrings() {
+		if !semVerMayMatch(in) {
+			rejected["semver"]++
+			assert.False(t, semVerRegEx.MatchString(in), "semver prefilter rejects %q", in)
+		}
+
+		if !perlMayMatch(in) {
+			rejected["perl"]++
+			assert.False(t, decimalRegex.MatchString(in), "perl prefilter rejects decimal %q", in)
+			assert.False(t, dottedDecimalRegex.MatchString(in), "perl prefilter rejects dotted decimal %q", in)
+		}
+
+		for _, maxSegments := range []int{phpClassicalSegments, 6} {
+			_, expected := normalizePHPWithRegexes(in, maxSegments)
+			_, actual := normalizePHPMaxSegments(in, maxSegments)
+			if !phpMayMatch(in) {
+				rejected["php"]++
+				assert.Error(t, expected, "php prefilter rejects %q", in)
+			}
+			if expected != nil && assert.Error(t, actual, "php rejects %q", in) {
+				assert.Equal(t, expected.Error(), actual.Error())
+			}
+		}
+	}
+
+	for _, scheme := range []string{"semver", "perl", "php"} {
+		assert.True(t, rejected[scheme] > 10000, "%s prefilter rejects enough inputs to be useful (%d)", scheme, rejected[scheme])
+	}
+}
+
+func TestPrefiltersKeepErrors(t *testing.T) {
+	_, err := ParseSemVer("x")
+	assert.EqualError(t, err, "Version does not match semver regex: x")
+
+	_, err = ParsePerl("1.0a")
+	assert.EqualError(t, err, "not valid perl version: 1.0a")
+
+	_, err = ParsePHP(" abc ")
+	assert.EqualError(t, err, "invalid php version:  abc ")
+}
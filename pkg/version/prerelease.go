@@ -0,0 +1,125 @@
+package version
+
+import "strings"
+
+// IsPreRelease returns true if v is a pre-release or development version
+// according to the rules of the scheme it was parsed as:
+//
+//   - SemVer, Go, Hex and Conan: the version has a pre-release part, as in
+//     "1.0.0-alpha" or "v1.0.0-alpha".
+//   - PythonPEP440: the version has a pre-release or development release
+//     part, as in "1.0a1" or "1.0.dev2". Post-releases are not pre-releases.
+//   - PythonLegacy: never, as with packaging's LegacyVersion.
+//   - Ruby: the version contains a letter, as with Gem::Version#prerelease?.
+//   - PHP: the version has a dev, alpha, beta or RC stability. Patch versions
+//     like "1.0.0-p1" are not pre-releases.
+//   - PerlDecimal and PerlVString: the version contains an underscore, which
+//     CPAN uses for development releases.
+//   - Generic: the version has a word that sorts before the release, as in
+//     "1.0-rc1". Words with no separator, like "1.0.1g", sort after the
+//     release and are not pre-releases.
+//   - Debian: the version contains a "~", which sorts before the release, as
+//     in "1.0~rc1-1".
+//   - Maven: the version has an alpha, beta, milestone, rc or snapshot
+//     qualifier, as in "1.0-rc1" or "1.0-SNAPSHOT". Service packs like
+//     "1.0-sp1" are not pre-releases.
+//   - NuGet: the version has a pre-release label, as in "1.0.0-beta" or
+//     "1.0.0.1-rc.1".
+//   - Npm: the version has a pre-release, as in "1.0.0-rc.1" or "1.0.0beta".
+//   - Gentoo: the version has an _alpha, _beta, _pre or _rc suffix, as in
+//     "1.0_rc1". Patch releases like "1.0_p1" are not pre-releases.
+//   - LuaRocks: the version has an alpha, beta, pre or rc word, as in
+//     "1.0rc1-1".
+//   - Nix: the version has a "pre" component, as in "2.3pre1".
+//   - JavaRuntime: the version has a pre-release, as in "9-ea+19".
+//   - FreeBSDPorts: the version has an alpha, beta, pre or rc component, as
+//     in "1.0rc1_1".
+//   - LinuxKernel: the version is a release candidate, as in "6.8-rc3".
+//   - DockerTag: the version has a pre-release, as in "7.2-rc1-alpine".
+//   - CPE: the version or update has a dev, alpha, beta, milestone, pre,
+//     preview or rc word, as in "3.10.0:rc1".
+//   - GoToolchain: the version is an alpha, beta or rc, as in "go1.22rc1".
+//   - Kubernetes: the version has an alpha, beta or rc pre-release, as in
+//     "v1.30.0-alpha.1".
+//
+// It returns false for versions of any other type.
+func (v *Version) IsPreRelease() bool {
+	switch v.ParsedAs {
+	case SemVer, Go, NuGet, Hex, Conan:
+		release := v.Original
+		if i := strings.IndexByte(release, '+'); i >= 0 {
+			release = release[:i]
+		}
+		return strings.IndexByte(release, '-') >= 0
+	case PythonPEP440:
+		m, err := matchPEP440(v.Original)
+		return err == nil && (m.pre != "" || m.dev != "")
+	case Ruby:
+		for i := 0; i < len(v.Original); i++ {
+			if c := v.Original[i]; isASCIILower(c) || ('A' <= c && c <= 'Z') {
+				return true
+			}
+		}
+		return false
+	case PHP:
+		// The stability is encoded as -4 for dev through -1 for RC. Other
+		// negative segments, such as the -0.5 for a missing stability
+		// number, are not integers.
+		for _, d := range v.Decimal {
+			if d.Sign() < 0 && d.IsInt() {
+				return true
+			}
+		}
+		return false
+	case Npm:
+		// A release has at most the three segments of its major, minor and
+		// patch versions.
+		return len(v.Decimal) > 3
+	case Gentoo:
+		for _, s := range gentooSuffixRegEx.FindAllStringSubmatch(v.Original, -1) {
+			if s[1] != "p" {
+				return true
+			}
+		}
+		return false
+	case JavaRuntime:
+		m := matchJavaRuntime(v.Original)
+		return m != nil && m[2] != ""
+	case FreeBSDPorts:
+		m := freeBSDPortsRegEx.FindStringSubmatch(v.Original)
+		if m == nil {
+			return false
+		}
+		for _, c := range freeBSDComponents(m[1]) {
+			if c[0] == "-1" && c[1] != "0" {
+				return true
+			}
+		}
+		return false
+	case LinuxKernel:
+		m := linuxKernelRegEx.FindStringSubmatch(v.Original)
+		return m != nil && m[4] != ""
+	case DockerTag:
+		m := dockerTagVersionRegEx.FindStringSubmatch(v.Original)
+		return m != nil && m[2] != ""
+	case GoToolchain:
+		m := goToolchainRegex.FindStringSubmatch(v.Original)
+		return m != nil && m[4] != ""
+	case Kubernetes:
+		m := kubernetesRegEx.FindStringSubmatch(v.Original)
+		return m != nil && m[4] != ""
+	case PerlDecimal, PerlVString:
+		return strings.IndexByte(v.Original, '_') >= 0
+	case Debian:
+		return strings.IndexByte(v.Original, '~') >= 0
+	case Generic, Maven, LuaRocks, Nix, CPE:
+		for _, d := range v.Decimal {
+			if d.Sign() < 0 {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
@@ -0,0 +1,121 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPreRelease(t *testing.T) {
+	tests := []struct {
+		version    *Version
+		preRelease bool
+	}{
+		{parseOrFatalSemVer(t, "1.0.0"), false},
+		{parseOrFatalSemVer(t, "1.0.0+build-1"), false},
+		{parseOrFatalSemVer(t, "1.0.0-alpha"), true},
+		{parseOrFatalSemVer(t, "1.0.0-0.3.7+build"), true},
+		{parseGoOrFatal(t, "v1.0.0"), false},
+		{parseGoOrFatal(t, "v2.0.0+incompatible"), false},
+		{parseGoOrFatal(t, "v0.0.0-20220314234659-1baeb1ce4c0b"), true},
+		{parsePythonOrFatal(t, "1.0"), false},
+		{parsePythonOrFatal(t, "1.0.post1"), false},
+		{parsePythonOrFatal(t, "1.0-1"), false},
+		{parsePythonOrFatal(t, "1.0+local.1"), false},
+		{parsePythonOrFatal(t, "1.0a1"), true},
+		{parsePythonOrFatal(t, "1.0rc2"), true},
+		{parsePythonOrFatal(t, "1.0.dev2"), true},
+		{parsePythonOrFatal(t, "1.0.post1.dev2"), true},
+		{parsePythonOrFatal(t, "1.0.foo"), false},
+		{parseRubyOrFatal(t, "2.0.0"), false},
+		{parseRubyOrFatal(t, "2.0.b1"), true},
+		{parseRubyOrFatal(t, "2.0.0.RC1"), true},
+		{parsePHPOrFatal(t, "1.0.0"), false},
+		{parsePHPOrFatal(t, "1.0.0-stable"), false},
+		{parsePHPOrFatal(t, "1.0.0-p1"), false},
+		{parsePHPOrFatal(t, "1.0.0-patch"), false},
+		{parsePHPOrFatal(t, "201102-p"), false},
+		{parsePHPOrFatal(t, "1.0.0.0-dev"), true},
+		{parsePHPOrFatal(t, "1.0.0-alpha"), true},
+		{parsePHPOrFatal(t, "1.0.0-beta2"), true},
+		{parsePHPOrFatal(t, "1.0.0-RC1"), true},
+		{parsePerlOrFatal(t, "1.002003"), false},
+		{parsePerlOrFatal(t, "v1.2.3"), false},
+		{parsePerlOrFatal(t, "1.002_003"), true},
+		{parsePerlOrFatal(t, "v1.2.3_1"), true},
+		{parseOrFatalGeneric(t, "1.0"), false},
+		{parseOrFatalGeneric(t, "1.0.1g"), false},
+		{parseOrFatalGeneric(t, "1.0-1"), false},
+		{parseOrFatalGeneric(t, "1.0-alpha"), true},
+		{parseOrFatalGeneric(t, "1.0-rc1"), true},
+		{parseDebianOrFatal(t, "1.0-1"), false},
+		{parseDebianOrFatal(t, "1:2.34-0ubuntu3"), false},
+		{parseDebianOrFatal(t, "1.0~rc1-1"), true},
+		{parseDebianOrFatal(t, "1.0-1~bpo11+1"), true},
+		{parseMavenOrFatal(t, "1.0"), false},
+		{parseMavenOrFatal(t, "2.0.0.Final"), false},
+		{parseMavenOrFatal(t, "1.0-sp1"), false},
+		{parseMavenOrFatal(t, "1.0-xyz"), false},
+		{parseMavenOrFatal(t, "1.0-alpha-1"), true},
+		{parseMavenOrFatal(t, "1.0b2"), true},
+		{parseMavenOrFatal(t, "6.0.0.CR1"), true},
+		{parseMavenOrFatal(t, "1.0-SNAPSHOT"), true},
+		{parseNuGetOrFatal(t, "1.0.0.0"), false},
+		{parseNuGetOrFatal(t, "1.0.0+build-1"), false},
+		{parseNuGetOrFatal(t, "1.0.0-RC.1"), true},
+		{parseNuGetOrFatal(t, "2.5.1.6205-beta"), true},
+		{parseNpmOrFatal(t, "v1.2"), false},
+		{parseNpmOrFatal(t, "1.2.3+build-1"), false},
+		{parseNpmOrFatal(t, "1.2.3-0"), true},
+		{parseNpmOrFatal(t, "1.2.3beta"), true},
+		{parseGentooOrFatal(t, "1.0b-r1"), false},
+		{parseGentooOrFatal(t, "1.0_p1"), false},
+		{parseGentooOrFatal(t, "1.0_pre20230101"), true},
+		{parseGentooOrFatal(t, "1.0_rc1_p2"), true},
+		{parseLuaRocksOrFatal(t, "2.4.1-1"), false},
+		{parseLuaRocksOrFatal(t, "scm-1"), false},
+		{parseLuaRocksOrFatal(t, "1.0.beta2-1"), true},
+		{parseLuaRocksOrFatal(t, "1.0rc1-3"), true},
+		{parseHexOrFatal(t, "1.7.14"), false},
+		{parseHexOrFatal(t, "1.0.0+build-1"), false},
+		{parseHexOrFatal(t, "1.0.0-rc.0"), true},
+		{parseNixOrFatal(t, "2.3"), false},
+		{parseNixOrFatal(t, "2.3a"), false},
+		{parseNixOrFatal(t, "0-unstable-2024-05-01"), false},
+		{parseNixOrFatal(t, "2.3pre1"), true},
+		{parseJavaRuntimeOrFatal(t, "17"), false},
+		{parseJavaRuntimeOrFatal(t, "11.0.21+9-LTS"), false},
+		{parseJavaRuntimeOrFatal(t, "9-ea+19"), true},
+		{parseJavaRuntimeOrFatal(t, "22-ea"), true},
+		{parseFreeBSDPortsOrFatal(t, "1.0_1,1"), false},
+		{parseFreeBSDPortsOrFatal(t, "1.0a"), false},
+		{parseFreeBSDPortsOrFatal(t, "1.0pl1"), false},
+		{parseFreeBSDPortsOrFatal(t, "1.0rc1_1"), true},
+		{parseFreeBSDPortsOrFatal(t, "2.0.b3"), true},
+		{parseConanOrFatal(t, "cci.20230101"), false},
+		{parseConanOrFatal(t, "1.0+build-1"), false},
+		{parseConanOrFatal(t, "1.2.3.4-alpha.1"), true},
+		{parseLinuxKernelOrFatal(t, "5.15.0-91-generic"), false},
+		{parseLinuxKernelOrFatal(t, "6.8-rc3"), true},
+		{parseLinuxKernelOrFatal(t, "6.8-rc3+"), true},
+		{parseDockerTagOrFatal(t, "1.25.3-alpine3.18"), false},
+		{parseDockerTagOrFatal(t, "7.2-rc1-alpine"), true},
+		{parseDockerTagOrFatal(t, "3.13.0b1-slim"), true},
+		{parseCPEOrFatal(t, "1.1.1", "k"), false},
+		{parseCPEOrFatal(t, "7.4", "p1"), false},
+		{parseCPEOrFatal(t, "3.10.0", "rc1"), true},
+		{parseCPEOrFatal(t, "2.0.0-beta1", "-"), true},
+		{parseGoToolchainOrFatal(t, "go1.22"), false},
+		{parseGoToolchainOrFatal(t, "go1.21.6"), false},
+		{parseGoToolchainOrFatal(t, "go1.22rc1"), true},
+		{parseGoToolchainOrFatal(t, "go1.20beta2"), true},
+		{parseKubernetesOrFatal(t, "v1.28.7-eks-b9c9ed7"), false},
+		{parseKubernetesOrFatal(t, "v1.27.3+k3s1"), false},
+		{parseKubernetesOrFatal(t, "v1.30.0-alpha.1"), true},
+		{&Version{Original: "1.0-alpha", ParsedAs: Unknown, Decimal: mustStringsToDecimal(t, []string{"1", "0", "-26"})}, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.preRelease, tt.version.IsPreRelease(), "%s", tt.version)
+	}
+}
@@ -1,7 +1,6 @@
 package version
 
 import (
-	"bytes"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -12,7 +11,7 @@ import (
 // (https://www.python.org/dev/peps/pep-0440/) and falls back to legacy Python
 // parsing if that fails.
 func ParsePython(version string) (*Version, error) {
-	result, err := parsePEP440(version)
+	result, err := parsePEP440Fast(version)
 	if err != nil {
 		result, err = parseLegacyPython(version)
 	}
@@ -60,18 +59,90 @@ const (
 
 var pep440NormalizationRegex = regexp.MustCompile(pep440VersionPattern)
 
-// parsePEP440 parses version using the version parsing algorithm defined in
-// python PEP 440 (https://www.python.org/dev/peps/pep-0440/).  Normalization,
-// as defined in PEP 440, is performed on version before parsing occurs. If
-// version is a local version identifier its local segment will be part of the
-// result.
-func parsePEP440(version string) (*Version, error) {
-	matches := findNamedMatches(version, pep440NormalizationRegex)
-	if matches == nil {
+// pep440Groups holds the index of each named group of
+// pep440NormalizationRegex that the parser reads.
+var pep440Groups = struct {
+	epoch, release                 int
+	pre, preLabel, preNumber       int
+	post, postNumber1, postNumber2 int
+	dev, devNumber                 int
+	local                          int
+}{
+	epoch:       subexpIndex(pep440NormalizationRegex, "epoch"),
+	release:     subexpIndex(pep440NormalizationRegex, "release"),
+	pre:         subexpIndex(pep440NormalizationRegex, "pre"),
+	preLabel:    subexpIndex(pep440NormalizationRegex, "pre_l"),
+	preNumber:   subexpIndex(pep440NormalizationRegex, "pre_n"),
+	post:        subexpIndex(pep440NormalizationRegex, "post"),
+	postNumber1: subexpIndex(pep440NormalizationRegex, "post_n1"),
+	postNumber2: subexpIndex(pep440NormalizationRegex, "post_n2"),
+	dev:         subexpIndex(pep440NormalizationRegex, "dev"),
+	devNumber:   subexpIndex(pep440NormalizationRegex, "dev_n"),
+	local:       subexpIndex(pep440NormalizationRegex, "local"),
+}
+
+// pep440Matches holds the groups matched in a PEP440 version. A group that did
+// not match, or matched the empty string, is "".
+type pep440Matches struct {
+	epoch, release                 string
+	pre, preLabel, preNumber       string
+	post, postNumber1, postNumber2 string
+	dev, devNumber                 string
+	local                          string
+}
+
+// findPEP440Matches matches version against pep440NormalizationRegex. The
+// boolean result is false if version did not match.
+func findPEP440Matches(version string) (pep440Matches, bool) {
+	m := pep440NormalizationRegex.FindStringSubmatch(version)
+	if m == nil {
+		return pep440Matches{}, false
+	}
+
+	return pep440Matches{
+		epoch:       m[pep440Groups.epoch],
+		release:     m[pep440Groups.release],
+		pre:         m[pep440Groups.pre],
+		preLabel:    m[pep440Groups.preLabel],
+		preNumber:   m[pep440Groups.preNumber],
+		post:        m[pep440Groups.post],
+		postNumber1: m[pep440Groups.postNumber1],
+		postNumber2: m[pep440Groups.postNumber2],
+		dev:         m[pep440Groups.dev],
+		devNumber:   m[pep440Groups.devNumber],
+		local:       m[pep440Groups.local],
+	}, true
+}
+
+// parsePEP440Fast parses version using the version parsing algorithm defined
+// in python PEP 440 (https://www.python.org/dev/peps/pep-0440/).
+// Normalization, as defined in PEP 440, is performed on version before parsing
+// occurs. If version is a local version identifier its local segment will be
+// part of the result.
+//
+// The version is matched with matchPEP440, which is much faster than
+// pep440NormalizationRegex and reports where matching failed.
+func parsePEP440Fast(version string) (*Version, error) {
+	matches, err := matchPEP440(version)
+	if err != nil {
+		return nil, err
+	}
+	return pep440FromMatches(version, matches)
+}
+
+// parsePEP440Regex is parsePEP440Fast using pep440NormalizationRegex to match
+// the version. It is the reference implementation that parsePEP440Fast is
+// tested against.
+func parsePEP440Regex(version string) (*Version, error) {
+	matches, ok := findPEP440Matches(version)
+	if !ok {
 		return nil, fmt.Errorf("not PEP440 version: %s", version)
 	}
+	return pep440FromMatches(version, matches)
+}
 
-	releaseSegments := strings.Split(matches["release"], ".")
+func pep440FromMatches(version string, matches pep440Matches) (*Version, error) {
+	releaseSegments := strings.Split(matches.release, ".")
 	if len(releaseSegments) > pep440MaxReleaseSegments {
 		return nil, fmt.Errorf("exceeds max number of release segments: %s", version)
 	}
@@ -111,20 +182,20 @@ func parsePEP440(version string) (*Version, error) {
 	return fromStringSlice(PythonPEP440, version, segments)
 }
 
-func pep440EpochSegment(matches map[string]string) string {
-	if v, ok := matches["epoch"]; ok {
-		return v
+func pep440EpochSegment(matches pep440Matches) string {
+	if matches.epoch != "" {
+		return matches.epoch
 	}
 	return pep440Implicit
 }
 
-func pep440PreReleaseSegments(matches map[string]string) (string, string) {
-	if _, ok := matches["pre"]; !ok {
+func pep440PreReleaseSegments(matches pep440Matches) (string, string) {
+	if matches.pre == "" {
 		return pep440Implicit, pep440Implicit
 	}
 
 	var label string
-	switch strings.ToLower(matches["pre_l"]) {
+	switch strings.ToLower(matches.preLabel) {
 	case "a", "alpha":
 		label = pep440AlphaRelease
 	case "b", "beta":
@@ -135,44 +206,44 @@ func pep440PreReleaseSegments(matches map[string]string) (string, string) {
 		panic("PEP440 regex has bad pre-release label match group")
 	}
 
-	if n, ok := matches["pre_n"]; ok {
-		return label, n
+	if matches.preNumber != "" {
+		return label, matches.preNumber
 	}
 
 	return label, pep440Implicit
 }
 
-func pep440PostReleaseSegments(matches map[string]string) (string, string) {
-	if _, ok := matches["post"]; !ok {
+func pep440PostReleaseSegments(matches pep440Matches) (string, string) {
+	if matches.post == "" {
 		return pep440Implicit, pep440Implicit
 	}
 
-	if n, ok := matches["post_n1"]; ok {
-		return pep440PostRelease, n
+	if matches.postNumber1 != "" {
+		return pep440PostRelease, matches.postNumber1
 	}
 
-	if n, ok := matches["post_n2"]; ok {
-		return pep440PostRelease, n
+	if matches.postNumber2 != "" {
+		return pep440PostRelease, matches.postNumber2
 	}
 
 	return pep440PostRelease, pep440Implicit
 }
 
-func pep440DevReleaseSegments(matches map[string]string) (string, string) {
-	if _, ok := matches["dev"]; !ok {
+func pep440DevReleaseSegments(matches pep440Matches) (string, string) {
+	if matches.dev == "" {
 		return pep440Implicit, pep440Implicit
 	}
 
-	if n, ok := matches["dev_n"]; ok {
-		return pep440DevRelease, n
+	if matches.devNumber != "" {
+		return pep440DevRelease, matches.devNumber
 	}
 
 	return pep440DevRelease, pep440Implicit
 }
 
-func pep440LocalSegments(matches map[string]string) []string {
-	local, ok := matches["local"]
-	if !ok {
+func pep440LocalSegments(matches pep440Matches) []string {
+	local := matches.local
+	if local == "" {
 		return nil
 	}
 
@@ -200,8 +271,6 @@ func pep440LocalSegments(matches map[string]string) []string {
 	return segments
 }
 
-var legacyPythonSegmentsRegex = regexp.MustCompile(`\d+|[a-z]+|\.|-`)
-
 var legacyPythonReplacements = map[string]string{
 	"pre":     "c",
 	"preview": "c",
@@ -210,45 +279,70 @@ var legacyPythonReplacements = map[string]string{
 	"dev":     "@",
 }
 
+// splitLegacyPythonSegments tokenizes version into runs of digits, runs of
+// lowercase letters, '.' and '-'. Any other characters are kept as a prefix of
+// the token that follows them, or as a final segment of their own if nothing
+// follows.
 func splitLegacyPythonSegments(version string) []string {
-	// Split the version based on matches in legacyPythonSegmentsRegex, but
-	// keep both the matches and the things between the matches
-	b := []byte(version)
-	repl := func(in []byte) []byte {
-		out := make([]byte, len(in))
-		copy(out, in)
-		out = append(out, '\x00')
-		return out
+	segments := make([]string, 0, len(version)/2+2)
+
+	start := 0
+	for i := 0; i < len(version); {
+		end := i
+		switch c := version[i]; {
+		case isASCIIDigit(c):
+			for end < len(version) && isASCIIDigit(version[end]) {
+				end++
+			}
+		case isASCIILower(c):
+			for end < len(version) && isASCIILower(version[end]) {
+				end++
+			}
+		case c == '.' || c == '-':
+			end++
+		default:
+			i++
+			continue
+		}
+
+		segments = appendLegacyPythonSegment(segments, version[start:end])
+		start, i = end, end
 	}
-	b = legacyPythonSegmentsRegex.ReplaceAllFunc(b, repl)
-	bSegments := bytes.Split(b, []byte{'\x00'})
+	segments = appendLegacyPythonSegment(segments, version[start:])
 
-	var segments []string
-	for _, bSegment := range bSegments {
-		segment := string(bSegment)
+	return append(segments, "*final")
+}
 
-		if replacement, ok := legacyPythonReplacements[segment]; ok {
-			segment = replacement
-		}
+func appendLegacyPythonSegment(segments []string, segment string) []string {
+	if replacement, ok := legacyPythonReplacements[segment]; ok {
+		segment = replacement
+	}
 
-		if segment == "" || segment == "." {
-			continue
-		}
+	if segment == "" || segment == "." {
+		return segments
+	}
 
-		if numSegment, err := strconv.Atoi(segment); err == nil {
-			if len(segment) <= 8 {
-				segment = fmt.Sprintf("%08d", numSegment)
-			}
-		} else {
-			segment = "*" + segment
+	if numSegment, err := strconv.Atoi(segment); err == nil {
+		if len(segment) <= 8 {
+			var b strings.Builder
+			b.Grow(8)
+			var digits [20]byte
+			writeZeroPadded(&b, digits[:0], int64(numSegment), 8)
+			segment = b.String()
 		}
-
-		segments = append(segments, segment)
+	} else {
+		segment = "*" + segment
 	}
 
-	segments = append(segments, "*final")
+	return append(segments, segment)
+}
 
-	return segments
+func isASCIIDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
 }
 
 // parseLegacyPython parses as described at
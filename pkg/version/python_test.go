@@ -1,111 +1,115 @@
 package version
 
 import (
+	"bytes"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestParsePython(t *testing.T) {
-	tests := map[ParsedAs]map[string]struct {
-		version  string
-		expected []string
-	}{
-		PythonPEP440: {
-			"Minimal": {
-				version: "1",
-				expected: []string{
-					"0",
-					"1",
-				},
+var parsePythonTests = map[ParsedAs]map[string]struct {
+	version  string
+	expected []string
+}{
+	PythonPEP440: {
+		"Minimal": {
+			version: "1",
+			expected: []string{
+				"0",
+				"1",
 			},
-			"Leading v is ignored": {
-				version: "v1",
-				expected: []string{
-					"0",
-					"1",
-				},
+		},
+		"Leading v is ignored": {
+			version: "v1",
+			expected: []string{
+				"0",
+				"1",
 			},
-			"Maximum release digits used": {
-				version: "1.2.3.4.5.6.7.8.9.10.11.12.13.14.15",
-				expected: []string{
-					"0",
-					"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15",
-				},
+		},
+		"Maximum release digits used": {
+			version: "1.2.3.4.5.6.7.8.9.10.11.12.13.14.15",
+			expected: []string{
+				"0",
+				"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15",
 			},
-			"Alpha": {
-				version: "1a2",
-				expected: []string{
-					"0",
-					"1", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0",
-					"-3", "2",
-				},
+		},
+		"Alpha": {
+			version: "1a2",
+			expected: []string{
+				"0",
+				"1", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0",
+				"-3", "2",
 			},
-			"Beta": {
-				version: "1b2",
-				expected: []string{
-					"0",
-					"1", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0",
-					"-2", "2",
-				},
+		},
+		"Beta": {
+			version: "1b2",
+			expected: []string{
+				"0",
+				"1", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0",
+				"-2", "2",
 			},
-			"RC": {
-				version: "1rc2",
-				expected: []string{
-					"0",
-					"1", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0",
-					"-1", "2",
-				},
+		},
+		"RC": {
+			version: "1rc2",
+			expected: []string{
+				"0",
+				"1", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0",
+				"-1", "2",
 			},
-			"C is RC": {
-				version: "1c2",
-				expected: []string{
-					"0",
-					"1", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0",
-					"-1", "2",
-				},
+		},
+		"C is RC": {
+			version: "1c2",
+			expected: []string{
+				"0",
+				"1", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0",
+				"-1", "2",
 			},
-			"Canonical Public Version Identifier": {
-				version: "99!1.2.3.4.5a6.post7.dev8",
-				expected: []string{
-					"99",                                                                      // epoch
-					"1", "2", "3", "4", "5", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", // release
-					"-3", "6", // pre-release
-					"1", "7", // post-release
-					"-4", "8", // dev release
-				},
+		},
+		"Canonical Public Version Identifier": {
+			version: "99!1.2.3.4.5a6.post7.dev8",
+			expected: []string{
+				"99",                                                                      // epoch
+				"1", "2", "3", "4", "5", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", // release
+				"-3", "6", // pre-release
+				"1", "7", // post-release
+				"-4", "8", // dev release
 			},
-			"Local Version Identifier": {
-				version: "1+aA.2B.3",
-				expected: []string{
-					"0",
-					"1", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0",
-					"0", "0",
-					"0", "0",
-					"0", "0",
-					"97.0000000097", "50.0000000098", "128", "3",
-				},
+		},
+		"Local Version Identifier": {
+			version: "1+aA.2B.3",
+			expected: []string{
+				"0",
+				"1", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0",
+				"0", "0",
+				"0", "0",
+				"0", "0",
+				"97.0000000097", "50.0000000098", "128", "3",
 			},
 		},
-		PythonLegacy: {
-			"Fall back to legacy version parsing": {
-				version: "2.6.0-0.1",
-				expected: []string{
-					"-1", // epoch is always -1 for legacy
-					"48.0000000048000000004800000000480000000048000000004800000000480000000050", // "00000002"
-					"48.0000000048000000004800000000480000000048000000004800000000480000000054", // "00000006"
-					"42.000000010200000001050000000110000000009700000001080000000045",           // "*final-"
-					"48.0000000048000000004800000000480000000048000000004800000000480000000048", // "00000000"
-					"48.0000000048000000004800000000480000000048000000004800000000480000000049", // "00000001"
-					"42.00000001020000000105000000011000000000970000000108",                     // "*final"
-				},
+	},
+	PythonLegacy: {
+		"Fall back to legacy version parsing": {
+			version: "2.6.0-0.1",
+			expected: []string{
+				"-1", // epoch is always -1 for legacy
+				"48.0000000048000000004800000000480000000048000000004800000000480000000050", // "00000002"
+				"48.0000000048000000004800000000480000000048000000004800000000480000000054", // "00000006"
+				"42.000000010200000001050000000110000000009700000001080000000045",           // "*final-"
+				"48.0000000048000000004800000000480000000048000000004800000000480000000048", // "00000000"
+				"48.0000000048000000004800000000480000000048000000004800000000480000000049", // "00000001"
+				"42.00000001020000000105000000011000000000970000000108",                     // "*final"
 			},
 		},
-	}
+	},
+}
 
-	for pa, cases := range tests {
+func TestParsePython(t *testing.T) {
+	for pa, cases := range parsePythonTests {
 		for name, tt := range cases {
 			t.Run(name, func(t *testing.T) {
 				actual, err := ParsePython(tt.version)
@@ -118,20 +122,6 @@ func TestParsePython(t *testing.T) {
 	}
 }
 
-func TestParsePythonOrderingEqual(t *testing.T) {
-	versions := []string{
-		"1",
-		"1.0",
-		"1.0.0",
-		"1.0.0.0",
-	}
-	for i := 0; i < len(versions)-1; i++ {
-		v1 := parsePythonOrFatal(t, versions[i])
-		v2 := parsePythonOrFatal(t, versions[i+1])
-		assert.True(t, Compare(v1, v2) == 0, fmt.Sprintf("%s == %s", versions[i], versions[i+1]))
-	}
-}
-
 // Many of these tests are from
 // https://github.com/pypa/packaging/blob/19.2/tests/test_version.py
 //
@@ -245,20 +235,74 @@ var pythonTestStrings = []string{
 	"1!1.2.rev33+123456",
 }
 
-func TestParsePythonOrdering(t *testing.T) {
-	for i := 0; i < len(pythonTestStrings)-1; i++ {
-		v1 := parsePythonOrFatal(t, pythonTestStrings[i])
-		v2 := parsePythonOrFatal(t, pythonTestStrings[i+1])
-		assert.True(
-			t,
-			Compare(v1, v2) < 0,
-			fmt.Sprintf("%s < %s", pythonTestStrings[i], pythonTestStrings[i+1]),
-		)
-	}
-}
-
 func parsePythonOrFatal(t *testing.T, v string) *Version {
 	ver, err := ParsePython(v)
 	assert.NoError(t, err, "no error parsing %s as a python version", v)
 	return ver
 }
+
+func TestSplitLegacyPythonSegmentsMatchesRegex(t *testing.T) {
+	// The regex based implementation the single scan replaced.
+	segmentsRegex := regexp.MustCompile(`\d+|[a-z]+|\.|-`)
+	regexSplit := func(version string) []string {
+		b := segmentsRegex.ReplaceAllFunc([]byte(version), func(in []byte) []byte {
+			return append(append([]byte{}, in...), '\x00')
+		})
+
+		var segments []string
+		for _, bSegment := range bytes.Split(b, []byte{'\x00'}) {
+			segment := string(bSegment)
+			if replacement, ok := legacyPythonReplacements[segment]; ok {
+				segment = replacement
+			}
+			if segment == "" || segment == "." {
+				continue
+			}
+			if numSegment, err := strconv.Atoi(segment); err == nil {
+				if len(segment) <= 8 {
+					segment = fmt.Sprintf("%08d", numSegment)
+				}
+			} else {
+				segment = "*" + segment
+			}
+			segments = append(segments, segment)
+		}
+		return append(segments, "*final")
+	}
+
+	inputs := []string{
+		"", ".", "-", "1", "1.0", "1.0-dev", "1.0rc1", "1.0.preview2",
+		"0.9_beta+12", "1..2", "1.2.", "123456789", "12345678901234567890",
+		"1_", "~", "日本1", "1.0-日本", "a\xffb",
+	}
+	for _, s := range pythonTestStrings {
+		inputs = append(inputs, strings.ToLower(s))
+	}
+
+	for _, in := range inputs {
+		assert.Equal(t, regexSplit(in), splitLegacyPythonSegments(in), "splitting %q", in)
+	}
+}
+
+func TestFindPEP440MatchesAgreesWithFindNamedMatches(t *testing.T) {
+	for _, s := range pythonTestStrings {
+		named := findNamedMatches(s, pep440NormalizationRegex)
+		matches, ok := findPEP440Matches(s)
+		require.Equal(t, named != nil, ok, "%s matches", s)
+		if !ok {
+			continue
+		}
+
+		assert.Equal(t, named["epoch"], matches.epoch, "%s epoch", s)
+		assert.Equal(t, named["release"], matches.release, "%s release", s)
+		assert.Equal(t, named["pre"], matches.pre, "%s pre", s)
+		assert.Equal(t, named["pre_l"], matches.preLabel, "%s pre_l", s)
+		assert.Equal(t, named["pre_n"], matches.preNumber, "%s pre_n", s)
+		assert.Equal(t, named["post"], matches.post, "%s post", s)
+		assert.Equal(t, named["post_n1"], matches.postNumber1, "%s post_n1", s)
+		assert.Equal(t, named["post_n2"], matches.postNumber2, "%s post_n2", s)
+		assert.Equal(t, named["dev"], matches.dev, "%s dev", s)
+		assert.Equal(t, named["dev_n"], matches.devNumber, "%s dev_n", s)
+		assert.Equal(t, named["local"], matches.local, "%s local", s)
+	}
+}
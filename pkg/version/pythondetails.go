@@ -0,0 +1,115 @@
+package version
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PythonDetails holds the components of a PEP440 version, normalized as PEP440
+// describes. Use it instead of reading components out of Decimal, whose
+// layout is an implementation detail.
+type PythonDetails struct {
+	// Epoch is the epoch, which is 0 if the version does not have one.
+	Epoch int
+	// Release is the release segment, such as [1 2 0] for "1.2.0". Trailing
+	// zeros are kept.
+	Release []int
+	// PreLabel is "a", "b" or "rc" for a pre-release, and "" otherwise.
+	// Alternative spellings are normalized, so "1.0alpha1" has a PreLabel of
+	// "a" and "1.0c1" has a PreLabel of "rc".
+	PreLabel string
+	// PreNumber is the number of the pre-release. It is 0 if there is no
+	// number, or if PreLabel is "".
+	PreNumber int
+	// Post is the number of the post-release, or nil if the version is not a
+	// post-release. A post-release with no number, like "1.0.post", has a
+	// Post of 0.
+	Post *int
+	// Dev is the number of the development release, or nil if the version
+	// is not a development release. A development release with no number
+	// has a Dev of 0.
+	Dev *int
+	// Local is the local version label as it appears in the original
+	// version, without the leading "+", or "" if there is none.
+	Local string
+	// LocalSegments is Local split into segments, in lower case, with "-"
+	// and "_" treated as "." as PEP440 requires. It is nil if Local is "".
+	LocalSegments []string
+}
+
+// PythonDetails returns the components of v, which must have been parsed as
+// a PythonPEP440 version. It returns false for any other type of version, and
+// for versions with a number too large to fit in an int.
+//
+// The components are found by matching v.Original again rather than being
+// stored when v is parsed, so that parsing does not allocate any more than it
+// has to.
+func (v *Version) PythonDetails() (*PythonDetails, bool) {
+	if v.ParsedAs != PythonPEP440 {
+		return nil, false
+	}
+	m, err := matchPEP440(v.Original)
+	if err != nil {
+		return nil, false
+	}
+
+	p := pythonDetailsParser{}
+	d := &PythonDetails{
+		Epoch: p.atoi(m.epoch),
+		Local: m.local,
+	}
+	for _, s := range strings.Split(m.release, ".") {
+		d.Release = append(d.Release, p.atoi(s))
+	}
+
+	if m.pre != "" {
+		switch strings.ToLower(m.preLabel) {
+		case "a", "alpha":
+			d.PreLabel = "a"
+		case "b", "beta":
+			d.PreLabel = "b"
+		default:
+			d.PreLabel = "rc"
+		}
+		d.PreNumber = p.atoi(m.preNumber)
+	}
+	if m.post != "" {
+		post := p.atoi(m.postNumber1 + m.postNumber2)
+		d.Post = &post
+	}
+	if m.dev != "" {
+		dev := p.atoi(m.devNumber)
+		d.Dev = &dev
+	}
+	if m.local != "" {
+		local := strings.ToLower(m.local)
+		local = strings.Replace(local, "-", ".", -1)
+		local = strings.Replace(local, "_", ".", -1)
+		d.LocalSegments = strings.Split(local, ".")
+	}
+
+	if p.failed {
+		return nil, false
+	}
+	return d, true
+}
+
+// pythonDetailsParser converts the numbers in a PEP440 version, remembering
+// whether any of them did not fit in an int.
+type pythonDetailsParser struct {
+	failed bool
+}
+
+// atoi returns s as an int, or 0 if s is "". Any string the PEP440 matcher
+// returns for a number contains only ASCII digits, so it can only fail if the
+// number is too large.
+func (p *pythonDetailsParser) atoi(s string) int {
+	if s == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		p.failed = true
+	}
+	return n
+}
@@ -0,0 +1,127 @@
+package version
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPythonDetails(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+
+	tests := map[string]PythonDetails{
+		"1.0":    {Release: []int{1, 0}},
+		"v1.2.3": {Release: []int{1, 2, 3}},
+		"2!1.0a1": {
+			Epoch:     2,
+			Release:   []int{1, 0},
+			PreLabel:  "a",
+			PreNumber: 1,
+		},
+		"1.0-ALPHA.2": {Release: []int{1, 0}, PreLabel: "a", PreNumber: 2},
+		"1.0beta":     {Release: []int{1, 0}, PreLabel: "b"},
+		"1.0c3":       {Release: []int{1, 0}, PreLabel: "rc", PreNumber: 3},
+		"1.0preview4": {Release: []int{1, 0}, PreLabel: "rc", PreNumber: 4},
+		"1.0.post":    {Release: []int{1, 0}, Post: intPtr(0)},
+		"1.0-7":       {Release: []int{1, 0}, Post: intPtr(7)},
+		"1.0.rev3":    {Release: []int{1, 0}, Post: intPtr(3)},
+		"1.0.dev":     {Release: []int{1, 0}, Dev: intPtr(0)},
+		"1.0b2.post345.dev456": {
+			Release:   []int{1, 0},
+			PreLabel:  "b",
+			PreNumber: 2,
+			Post:      intPtr(345),
+			Dev:       intPtr(456),
+		},
+		"1.2+Ubuntu-1_abc.5": {
+			Release:       []int{1, 2},
+			Local:         "Ubuntu-1_abc.5",
+			LocalSegments: []string{"ubuntu", "1", "abc", "5"},
+		},
+	}
+
+	for s, expected := range tests {
+		d, ok := parsePythonOrFatal(t, s).PythonDetails()
+		require.True(t, ok, s)
+		assert.Equal(t, &expected, d, s)
+	}
+}
+
+func TestPythonDetailsNotAvailable(t *testing.T) {
+	for _, v := range []*Version{
+		parsePythonOrFatal(t, "1.0-foo-bar"),
+		parseOrFatalSemVer(t, "1.0.0"),
+		parseOrFatalGeneric(t, "1.0"),
+		parsePythonOrFatal(t, "1.99999999999999999999999"),
+	} {
+		d, ok := v.PythonDetails()
+		assert.False(t, ok, v.String())
+		assert.Nil(t, d, v.String())
+	}
+}
+
+func TestPythonDetailsSurviveClone(t *testing.T) {
+	v := parsePythonOrFatal(t, "1!2.0rc1.post2+local")
+	d, ok := v.PythonDetails()
+	require.True(t, ok)
+
+	cloned, ok := v.Clone().PythonDetails()
+	require.True(t, ok)
+	assert.Equal(t, d, cloned)
+}
+
+// The details must describe the same version as the sortable array, so this
+// rebuilds the array from them the same way pep440FromMatches does.
+func TestPythonDetailsMatchSortableArray(t *testing.T) {
+	labels := map[string]string{"a": pep440AlphaRelease, "b": pep440BetaRelease, "rc": pep440RCRelease}
+
+	for _, s := range pythonTestStrings {
+		v := parsePythonOrFatal(t, s)
+		d, ok := v.PythonDetails()
+		if v.ParsedAs != PythonPEP440 {
+			assert.False(t, ok, s)
+			continue
+		}
+		require.True(t, ok, s)
+
+		segments := []string{strconv.Itoa(d.Epoch)}
+		for i := 0; i < pep440MaxReleaseSegments; i++ {
+			if i < len(d.Release) {
+				segments = append(segments, strconv.Itoa(d.Release[i]))
+			} else {
+				segments = append(segments, pep440Implicit)
+			}
+		}
+
+		preLabel, preNumber := pep440Implicit, pep440Implicit
+		if d.PreLabel != "" {
+			preLabel, preNumber = labels[d.PreLabel], strconv.Itoa(d.PreNumber)
+		}
+		postLabel, postNumber := pep440Implicit, pep440Implicit
+		if d.Post != nil {
+			postLabel, postNumber = pep440PostRelease, strconv.Itoa(*d.Post)
+		}
+		devLabel, devNumber := pep440Implicit, pep440Implicit
+		if d.Dev != nil {
+			devLabel, devNumber = pep440DevRelease, strconv.Itoa(*d.Dev)
+			if d.PreLabel == "" && d.Post == nil {
+				preLabel = pep440DevRelease
+			}
+		}
+		segments = append(segments, preLabel, preNumber, postLabel, postNumber, devLabel, devNumber)
+
+		for _, l := range d.LocalSegments {
+			if _, err := strconv.Atoi(l); err == nil {
+				segments = append(segments, "128", l)
+			} else {
+				segments = append(segments, toDecimalString(l))
+			}
+		}
+
+		rebuilt, err := fromStringSlice(PythonPEP440, s, segments)
+		require.NoError(t, err, s)
+		assert.Equal(t, v.Segments(), rebuilt.Segments(), s)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package version
+
+// raceEnabled is true when the tests are built with the race detector, which
+// adds allocations of its own. Allocation tests are skipped in that case.
+const raceEnabled = true
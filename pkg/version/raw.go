@@ -0,0 +1,28 @@
+package version
+
+import "github.com/ericlagergren/decimal"
+
+// rawSentinel is the first segment of every Raw version. It is lower than the
+// first segment that any parsing func produces, the lowest of which is the
+// -26 that ParseGeneric uses for "alpha", so Raw versions sort below every
+// parsed version.
+var rawSentinel = decimal.New(-1000000000, 0)
+
+// NewRaw returns a Version of type Raw for a string that no parsing func
+// accepts, such as "latest-stable", so that it can be kept in the same slice
+// as parsed versions. Raw versions sort below every parsed version, and below
+// one another according to the Unicode code points of their originals, in
+// the same way that ParseGeneric orders words.
+//
+// Raw versions are comparable with versions of every type (see Comparable),
+// and can be round tripped through String and ParseVersionString.
+func NewRaw(original string) *Version {
+	decimals := []*decimal.Big{rawSentinel}
+	if original != "" {
+		// toDecimalString always returns a valid decimal for a non-empty
+		// string.
+		d, _ := stringsToDecimals([]string{toDecimalString(original)})
+		decimals = append(decimals, d...)
+	}
+	return &Version{Original: original, Decimal: decimals, ParsedAs: Raw}
+}
@@ -0,0 +1,89 @@
+package version
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRaw(t *testing.T) {
+	v := NewRaw("latest-stable")
+	assert.Equal(t, "latest-stable", v.Original)
+	assert.Equal(t, Raw, v.ParsedAs)
+	assert.Equal(t, "latest-stable (Raw)", v.String())
+	assert.False(t, v.IsPreRelease())
+
+	empty := NewRaw("")
+	assert.Len(t, empty.Decimal, 1)
+	assert.True(t, Compare(empty, v) < 0)
+}
+
+func TestRawSortsBelowParsedVersions(t *testing.T) {
+	parsed := []*Version{
+		parseOrFatalGeneric(t, "alpha"),
+		parseOrFatalGeneric(t, "0"),
+		parseOrFatalSemVer(t, "0.0.0-0"),
+		parsePythonOrFatal(t, "foo"),
+		parsePythonOrFatal(t, "0.dev0"),
+		parsePerlOrFatal(t, "0"),
+		parsePHPOrFatal(t, "0.0.0-dev"),
+		parseRubyOrFatal(t, "0.a"),
+	}
+	raws := []*Version{NewRaw("see-notes"), NewRaw("latest-stable"), NewRaw("")}
+	for _, r := range raws {
+		for _, p := range parsed {
+			assert.True(t, Compare(r, p) < 0, "%s < %s", r, p)
+			cmp, err := CompareChecked(p, r)
+			require.NoError(t, err)
+			assert.True(t, cmp > 0, "%s > %s", p, r)
+		}
+	}
+
+	vs := []*Version{
+		parseOrFatalSemVer(t, "1.0.0"),
+		NewRaw("see-notes"),
+		parseOrFatalSemVer(t, "0.1.0"),
+		NewRaw("latest-stable"),
+		NewRaw("latest"),
+	}
+	require.NoError(t, Sort(vs))
+	var originals []string
+	for _, v := range vs {
+		originals = append(originals, v.Original)
+	}
+	assert.Equal(t, []string{"latest", "latest-stable", "see-notes", "0.1.0", "1.0.0"}, originals)
+}
+
+func TestRawWithStrictCompare(t *testing.T) {
+	defer func(strict bool) { StrictCompare = strict }(StrictCompare)
+	StrictCompare = true
+
+	assert.NoError(t, Sort([]*Version{NewRaw("x"), parseOrFatalSemVer(t, "1.0.0"), NewRaw("y"), parseOrFatalSemVer(t, "0.1.0")}))
+	assert.Error(t, Sort([]*Version{NewRaw("x"), parseOrFatalSemVer(t, "1.0.0"), parseRubyOrFatal(t, "0.1.0")}))
+}
+
+func TestRawJSONRoundTrip(t *testing.T) {
+	v := NewRaw("see-notes")
+	j, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	var actual Version
+	require.NoError(t, json.Unmarshal(j, &actual))
+	assert.Equal(t, v.Original, actual.Original)
+	assert.Equal(t, v.Segments(), actual.Segments())
+	assert.Equal(t, 0, Compare(v, &actual))
+}
+
+func TestRawClone(t *testing.T) {
+	v := NewRaw("see-notes")
+	c := v.Clone()
+	assert.Equal(t, Raw, c.ParsedAs)
+	assert.Equal(t, 0, Compare(v, c))
+	assert.False(t, v.Decimal[0] == c.Decimal[0], "the sentinel is copied")
+
+	parsed, err := ParseVersionString(v.String())
+	require.NoError(t, err)
+	assert.Equal(t, v, parsed)
+}
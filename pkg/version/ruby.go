@@ -56,24 +56,22 @@ func splitSegments(version string) []string {
 
 	// Create two segment groups by splitting at the first non-integer
 	// Also normalize integer formats as we go (e.g. change "002" to "2")
-	before := []string{}
+	before := make([]string, 0, len(segments))
 	after := []string{}
 	i := 0
 	for i < len(segments) {
-		s, err := strconv.Atoi(segments[i])
-		if err != nil {
+		if !isRubyInteger(segments[i]) {
 			break
 		}
 
-		before = append(before, strconv.Itoa(s))
+		before = append(before, removeLeadingZeros(segments[i]))
 		i++
 	}
 	for i < len(segments) {
-		s, err := strconv.Atoi(segments[i])
-		if err != nil {
-			after = append(after, segments[i])
+		if isRubyInteger(segments[i]) {
+			after = append(after, removeLeadingZeros(segments[i]))
 		} else {
-			after = append(after, strconv.Itoa(s))
+			after = append(after, segments[i])
 		}
 		i++
 	}
@@ -84,6 +82,13 @@ func splitSegments(version string) []string {
 	return append(before, after...)
 }
 
+// isRubyInteger reports whether strconv.Atoi accepts segment. Segments that
+// are too large for an int are treated as strings.
+func isRubyInteger(segment string) bool {
+	_, err := strconv.Atoi(segment)
+	return err == nil
+}
+
 func dropTrailingZeroes(segments []string) []string {
 	lastNonzeroIndex := len(segments) - 1
 	for i := lastNonzeroIndex; i >= 0; i-- {
@@ -0,0 +1,76 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// OutputJSONSchema is a JSON Schema describing the JSON that parseversion
+// prints, which is a JSON array of Versions as encoded by json.Marshal or
+// EncodeJSONStream. Each Version is an object with a "version" string and a
+// non-empty "sortable_version" array of decimal strings.
+//
+// This is a Go constant rather than an embedded file, since go:embed needs a
+// newer Go than this module supports.
+const OutputJSONSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "https://github.com/ActiveState/langtools/parseversion-output.json",
+  "title": "parseversion output",
+  "type": "array",
+  "items": {"$ref": "#/definitions/version"},
+  "definitions": {
+    "version": {
+      "type": "object",
+      "properties": {
+        "version": {
+          "description": "The original version string.",
+          "type": "string"
+        },
+        "sortable_version": {
+          "description": "The segments of the version. Versions of the same type can be sorted by comparing these numerically, treating missing segments as zero.",
+          "type": "array",
+          "minItems": 1,
+          "items": {"$ref": "#/definitions/segment"}
+        }
+      },
+      "required": ["version", "sortable_version"],
+      "additionalProperties": false
+    },
+    "segment": {
+      "description": "A decimal number written as a string, so that it does not lose precision when parsed as a float.",
+      "type": "string",
+      "pattern": "^-?[0-9]+(\\.[0-9]+)?([eE][-+]?[0-9]+)?$"
+    }
+  }
+}
+`
+
+var outputSchema = mustLoadOutputSchema()
+
+func mustLoadOutputSchema() *gojsonschema.Schema {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(OutputJSONSchema))
+	if err != nil {
+		panic(fmt.Sprintf("OutputJSONSchema is not a valid JSON Schema: %s", err))
+	}
+	return s
+}
+
+// ValidateOutputJSON returns an error if data is not valid according to
+// OutputJSONSchema. The error lists every problem that was found.
+func ValidateOutputJSON(data []byte) error {
+	result, err := outputSchema.Validate(gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return fmt.Errorf("cannot validate parseversion output: %s", err)
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	problems := make([]string, len(result.Errors()))
+	for i, e := range result.Errors() {
+		problems[i] = e.String()
+	}
+	return fmt.Errorf("invalid parseversion output: %s", strings.Join(problems, "; "))
+}
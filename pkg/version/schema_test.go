@@ -0,0 +1,97 @@
+package version
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateOutputJSONAcceptsParserOutput(t *testing.T) {
+	vs := []*Version{
+		parseOrFatalGeneric(t, "1.0-alpha"),
+		parseOrFatalSemVer(t, "1.0.0-alpha.1+build"),
+		parsePerlOrFatal(t, "v1.2.3"),
+		parsePerlOrFatal(t, "1.002003"),
+		parsePHPOrFatal(t, "1.0.0.0-dev"),
+		parsePythonOrFatal(t, "1.0.post1.dev2"),
+		parsePythonOrFatal(t, "1.0-foo-bar"),
+		parseRubyOrFatal(t, "2.0.b1"),
+		parseOrFatalGeneric(t, `1.0 "<quoted>" & more`),
+	}
+	for _, corpus := range keyTestCorpora(t) {
+		vs = append(vs, corpus...)
+	}
+
+	data, err := json.Marshal(vs)
+	require.NoError(t, err)
+	assert.NoError(t, ValidateOutputJSON(data))
+
+	for _, v := range vs {
+		data, err := json.Marshal([]*Version{v})
+		require.NoError(t, err)
+		assert.NoError(t, ValidateOutputJSON(data), "output for %s", v)
+	}
+
+	assert.NoError(t, ValidateOutputJSON([]byte(`[]`)))
+}
+
+func TestValidateOutputJSONRejectsCorruptOutput(t *testing.T) {
+	tests := map[string]string{
+		"not an array":          `{"version":"1.0","sortable_version":["1","0"]}`,
+		"null version":          `[null]`,
+		"number segment":        `[{"version":"1.0","sortable_version":[1,0]}]`,
+		"word segment":          `[{"version":"1.0","sortable_version":["1","x"]}]`,
+		"no segments":           `[{"version":"1.0","sortable_version":[]}]`,
+		"missing segments":      `[{"version":"1.0"}]`,
+		"missing version":       `[{"sortable_version":["1"]}]`,
+		"number version":        `[{"version":1.0,"sortable_version":["1"]}]`,
+		"unknown key":           `[{"version":"1.0","sortable_version":["1"],"extra":true}]`,
+		"truncated":             `[{"version":"1.0","sortable_version":["1"`,
+		"NaN segment":           `[{"version":"1.0","sortable_version":["NaN"]}]`,
+		"trailing dot segment":  `[{"version":"1.0","sortable_version":["1."]}]`,
+		"second version broken": `[{"version":"1.0","sortable_version":["1"]},{"version":"2.0"}]`,
+	}
+	for name, data := range tests {
+		assert.Error(t, ValidateOutputJSON([]byte(data)), name)
+	}
+}
+
+// If this fails then Version's JSON fields have changed and OutputJSONSchema
+// must be updated to match.
+func TestOutputJSONSchemaMatchesVersion(t *testing.T) {
+	var schema struct {
+		Definitions struct {
+			Version struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+				Required   []string                   `json:"required"`
+			} `json:"version"`
+		} `json:"definitions"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(OutputJSONSchema), &schema))
+
+	var fields []string
+	typ := reflect.TypeOf(Version{})
+	for i := 0; i < typ.NumField(); i++ {
+		name := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+		if name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+
+	var properties []string
+	for name := range schema.Definitions.Version.Properties {
+		properties = append(properties, name)
+	}
+	sort.Strings(properties)
+	required := append([]string{}, schema.Definitions.Version.Required...)
+	sort.Strings(required)
+
+	assert.Equal(t, fields, properties, "schema properties match the JSON fields of Version")
+	assert.Equal(t, fields, required, "every JSON field of Version is required by the schema")
+}
@@ -0,0 +1,97 @@
+package version
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A float64 holds every integer up to 2^53 exactly. Float64Score uses 51 of
+// those bits, giving 17 bits to each of the first three segments, so each of
+// them can be from 0 to 131071.
+const (
+	scoreSegments    = 3
+	scoreSegmentBits = 17
+	maxScoreSegment  = 1<<scoreSegmentBits - 1
+)
+
+// NotRepresentableError is returned by Float64Score when a version cannot be
+// turned into a float64 without colliding with other versions.
+type NotRepresentableError struct {
+	Version *Version
+	Reason  string
+}
+
+func (e *NotRepresentableError) Error() string {
+	return fmt.Sprintf("cannot represent %s as a float64 score: %s", e.Version, e.Reason)
+}
+
+// Float64Score returns a float64 that sorts in the same order as v, for use
+// as the score of a Redis sorted set or anywhere else that can only sort
+// floats. The first three segments are packed into the score as 17 bit
+// unsigned integers, so the score of 1.2.3 is 1<<34 + 2<<17 + 3. Missing
+// segments are zero.
+//
+// Only versions whose first three segments are integers from 0 to 131071,
+// and whose other segments are all zero, can be represented. This covers
+// typical semver release versions but not pre-releases, which have negative
+// segments, or versions with words. For any other version a
+// *NotRepresentableError is returned rather than a score which might collide
+// with the score of an unequal version.
+//
+// Two versions with representable scores have the same score if and only if
+// Compare returns 0 for them.
+func (v *Version) Float64Score() (float64, error) {
+	var score uint64
+	for i, d := range v.Decimal {
+		if i >= scoreSegments {
+			if d.Sign() != 0 {
+				return 0, &NotRepresentableError{v, fmt.Sprintf("it has more than %d non-zero segments", scoreSegments)}
+			}
+			continue
+		}
+
+		n, ok := d.Int64()
+		if !ok || !d.IsInt() {
+			return 0, &NotRepresentableError{v, fmt.Sprintf("segment %s is not an integer", d)}
+		}
+		if n < 0 || n > maxScoreSegment {
+			return 0, &NotRepresentableError{v, fmt.Sprintf("segment %s is not between 0 and %d", d, maxScoreSegment)}
+		}
+		score |= uint64(n) << (scoreSegmentBits * uint(scoreSegments-1-i))
+	}
+	return float64(score), nil
+}
+
+// CheckFloat64Scores returns an error if any version in vs cannot be given a
+// float64 score, or if the scores of any two versions would sort them
+// differently than Compare does. It should be called before using
+// Float64Score for a set of versions.
+func CheckFloat64Scores(vs []*Version) error {
+	type scored struct {
+		v     *Version
+		score float64
+	}
+	scores := make([]scored, len(vs))
+	for i, v := range vs {
+		score, err := v.Float64Score()
+		if err != nil {
+			return err
+		}
+		scores[i] = scored{v, score}
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].score < scores[j].score
+	})
+	for i := 1; i < len(scores); i++ {
+		prev, cur := scores[i-1], scores[i]
+		cmp := Compare(prev.v, cur.v)
+		if prev.score == cur.score && cmp != 0 {
+			return fmt.Errorf("%s and %s have the same float64 score, %v", prev.v, cur.v, cur.score)
+		}
+		if prev.score < cur.score && cmp >= 0 {
+			return fmt.Errorf("the float64 score of %s is less than the score of %s, but it is not a lower version", prev.v, cur.v)
+		}
+	}
+	return nil
+}
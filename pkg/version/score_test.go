@@ -0,0 +1,87 @@
+package version
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFloat64Score(t *testing.T) {
+	score, err := parseOrFatalSemVer(t, "1.2.3").Float64Score()
+	require.NoError(t, err)
+	assert.Equal(t, float64(1<<34+2<<17+3), score)
+
+	score, err = parseOrFatalGeneric(t, "7").Float64Score()
+	require.NoError(t, err)
+	assert.Equal(t, float64(7<<34), score)
+
+	score, err = parseOrFatalGeneric(t, "131071.131071.131071.0.0").Float64Score()
+	require.NoError(t, err)
+	assert.Equal(t, float64(1<<51-1), score)
+}
+
+func TestFloat64ScoreOrdersSemVer(t *testing.T) {
+	strs := []string{
+		"0.0.0", "0.0.1", "0.0.2", "0.0.10", "0.1.0", "0.1.1", "0.2.0", "0.10.0",
+		"1.0.0", "1.0.1", "1.1.0", "1.2.3", "1.10.0", "2.0.0", "10.0.0",
+		"10.20.30", "99.99.99", "131071.0.0", "131071.131071.131071",
+	}
+	var vs []*Version
+	for _, s := range strs {
+		vs = append(vs, parseOrFatalSemVer(t, s))
+	}
+	require.NoError(t, CheckFloat64Scores(vs))
+
+	scores := make([]float64, len(vs))
+	for i, v := range vs {
+		var err error
+		scores[i], err = v.Float64Score()
+		require.NoError(t, err)
+	}
+	assert.True(t, sort.Float64sAreSorted(scores), "scores are in the same order as the versions: %v", scores)
+	for i := 1; i < len(scores); i++ {
+		assert.NotEqual(t, scores[i-1], scores[i], "%s and %s have different scores", vs[i-1], vs[i])
+	}
+
+	equal := []*Version{parseOrFatalGeneric(t, "1.2"), parseOrFatalGeneric(t, "1.2.0.0")}
+	require.NoError(t, CheckFloat64Scores(equal))
+	s1, _ := equal[0].Float64Score()
+	s2, _ := equal[1].Float64Score()
+	assert.Equal(t, s1, s2)
+}
+
+func TestFloat64ScoreNotRepresentable(t *testing.T) {
+	tests := map[string]*Version{
+		"huge segment":     parseOrFatalSemVer(t, "1.131072.0"),
+		"very huge":        parseOrFatalSemVer(t, "123456789012345678901234567890.0.0"),
+		"deep":             parseOrFatalGeneric(t, "1.2.3.4"),
+		"pre-release":      parseOrFatalSemVer(t, "1.0.0-alpha"),
+		"word":             parseOrFatalGeneric(t, "1.0-beta"),
+		"decimal segment":  {Original: "1.0.5", Decimal: mustStringsToDecimal(t, []string{"1", "0.5"})},
+		"negative segment": {Original: "-1", Decimal: mustStringsToDecimal(t, []string{"-1"})},
+	}
+	for name, v := range tests {
+		_, err := v.Float64Score()
+		require.Error(t, err, name)
+		_, ok := err.(*NotRepresentableError)
+		assert.True(t, ok, "%s: error is a *NotRepresentableError: %s", name, err)
+
+		err = CheckFloat64Scores([]*Version{parseOrFatalSemVer(t, "1.0.0"), v})
+		assert.Error(t, err, name)
+	}
+}
+
+func TestCheckFloat64ScoresCorpora(t *testing.T) {
+	for name, versions := range keyTestCorpora(t) {
+		var representable []*Version
+		for _, v := range versions {
+			if _, err := v.Float64Score(); err == nil {
+				representable = append(representable, v)
+			}
+		}
+		assert.NoError(t, CheckFloat64Scores(representable), name)
+		t.Logf("%d of %d %s versions are representable", len(representable), len(versions), name)
+	}
+}
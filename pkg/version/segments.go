@@ -0,0 +1,135 @@
+package version
+
+import (
+	"fmt"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// Segments returns the segments of v as strings, in the same form as they
+// are written to JSON in the sortable_version field. The result can be
+// compared with other versions using CompareSegments or CompareToSegments.
+func (v *Version) Segments() []string {
+	segments := make([]string, len(v.Decimal))
+	for i, d := range v.Decimal {
+		segments[i] = d.String()
+	}
+	return segments
+}
+
+// CompareSegments compares two versions given as slices of segment strings,
+// such as the sortable_version arrays stored by earlier releases of this
+// package, without parsing the original versions again. The result is the
+// same as Compare would return for Versions with these segments, so segments
+// that differ only by trailing zeros are equal.
+//
+// An error is returned if any segment is not a finite decimal. The error
+// says which argument and segment is malformed.
+func CompareSegments(a, b []string) (int, error) {
+	da, err := segmentsToDecimals("a", a)
+	if err != nil {
+		return 0, err
+	}
+	db, err := segmentsToDecimals("b", b)
+	if err != nil {
+		return 0, err
+	}
+	return Compare(&Version{Decimal: da}, &Version{Decimal: db}), nil
+}
+
+// CompareToSegments compares v to a version given as a slice of segment
+// strings, in the same way as CompareSegments.
+func CompareToSegments(v *Version, segments []string) (int, error) {
+	d, err := segmentsToDecimals("segments", segments)
+	if err != nil {
+		return 0, err
+	}
+	return Compare(v, &Version{Decimal: d}), nil
+}
+
+func segmentsToDecimals(name string, segments []string) ([]*decimal.Big, error) {
+	decimals := make([]*decimal.Big, len(segments))
+	for i, s := range segments {
+		if d := internedDecimal(s); d != nil {
+			decimals[i] = d
+			continue
+		}
+
+		d, ok := new(decimal.Big).SetString(s)
+		if !ok || !d.IsFinite() {
+			return nil, fmt.Errorf("segment %d of %s is not a valid decimal: %q", i, name, s)
+		}
+		decimals[i] = d
+	}
+	return decimals, nil
+}
+
+// FromSortable returns a Version made from a stored original version and
+// its segments, such as a row written using an earlier release of this
+// package, without parsing the original version again. The segments are used
+// exactly as given, so trailing zeros are kept. This allows versions stored
+// using an older release to be compared with the same versions parsed using
+// the current one.
+//
+// An error is returned if pa is not a known type, if there are no segments,
+// or if any segment is not a finite decimal.
+func FromSortable(original string, segments []string, pa ParsedAs) (*Version, error) {
+	if !pa.IsAParsedAs() || pa == Unknown {
+		return nil, fmt.Errorf("cannot make a version of unknown type %d from %s", int(pa), original)
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("cannot make a version from %s with no segments", original)
+	}
+
+	d, err := segmentsToDecimals("segments", segments)
+	if err != nil {
+		return nil, fmt.Errorf("cannot make a version from %s: %s", original, err)
+	}
+	return &Version{Original: original, Decimal: d, ParsedAs: pa}, nil
+}
+
+// NormalizeSegments returns segments in the canonical form that this package
+// stores them in, as written by Segments and to the sortable_version field in
+// JSON. Each segment is rewritten the way the parsing funcs write it, and
+// trailing zero segments are trimmed, keeping at least one segment. This can
+// be used to normalize stored rows that were written by other tools, so that
+// they match the rows that this package would write for the same versions.
+//
+// isIntRepresentable is true if every trimmed segment is a whole number that
+// fits in an int64, so the segments can be stored as integers rather than
+// decimals without losing anything. An error is returned if there are no
+// segments, or if any segment is not a finite decimal.
+func NormalizeSegments(segments []string) (trimmed []string, isIntRepresentable bool, err error) {
+	decimals, err := normalizedDecimals(segments)
+	if err != nil {
+		return nil, false, err
+	}
+
+	trimmed = make([]string, len(decimals))
+	isIntRepresentable = true
+	for i, d := range decimals {
+		if _, ok := d.Int64(); !ok || !d.IsInt() {
+			isIntRepresentable = false
+		}
+		trimmed[i] = d.String()
+	}
+	return trimmed, isIntRepresentable, nil
+}
+
+// TrimTrailingZeroSegments returns segments without any trailing segments
+// that are zero, such as "0" or "0.00", keeping at least one segment. This is
+// the same trimming that the parsing funcs do. Segments are otherwise left as
+// they are, and a segment that is not a finite decimal is treated as non-zero.
+// The result shares its backing array with segments.
+func TrimTrailingZeroSegments(segments []string) []string {
+	decimals := make([]*decimal.Big, len(segments))
+	for i, s := range segments {
+		d := stringToDecimal(s)
+		if d == nil || !d.IsFinite() {
+			// Any non-zero value will do, as only zeros are trimmed.
+			d = internedDecimals[1]
+		}
+		decimals[i] = d
+	}
+	return segments[:len(trimTrailingZeros(decimals))]
+}
@@ -0,0 +1,198 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegments(t *testing.T) {
+	assert.Equal(t, []string{"1", "2", "3"}, parseOrFatalSemVer(t, "1.2.3").Segments())
+	assert.Equal(t, []string{"1", "0", "-26"}, parseOrFatalGeneric(t, "1.0-alpha").Segments())
+}
+
+func TestCompareSegmentsMatchesCompare(t *testing.T) {
+	for name, versions := range keyTestCorpora(t) {
+		t.Run(name, func(t *testing.T) {
+			segments := make([][]string, len(versions))
+			for i, v := range versions {
+				segments[i] = v.Segments()
+			}
+
+			for i, v1 := range versions {
+				for j, v2 := range versions {
+					expected := Compare(v1, v2)
+
+					actual, err := CompareSegments(segments[i], segments[j])
+					require.NoError(t, err)
+					assert.Equal(t, expected, actual, "CompareSegments for %s and %s", v1, v2)
+
+					actual, err = CompareToSegments(v1, segments[j])
+					require.NoError(t, err)
+					assert.Equal(t, expected, actual, "CompareToSegments for %s and %s", v1, v2)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareSegmentsLengths(t *testing.T) {
+	tests := []struct {
+		a, b     []string
+		expected int
+	}{
+		{[]string{"1", "2"}, []string{"1", "2", "0", "0"}, 0},
+		{[]string{"1", "2"}, []string{"1", "2.00"}, 0},
+		{[]string{"1", "2"}, []string{"1", "2", "0", "1"}, -1},
+		{[]string{"1", "2"}, []string{"1", "2", "-1"}, 1},
+		{[]string{"1"}, []string{"0.9", "99"}, 1},
+		{[]string{}, []string{"0"}, 0},
+		{[]string{}, []string{"0", "-5"}, 1},
+	}
+
+	for _, tt := range tests {
+		actual, err := CompareSegments(tt.a, tt.b)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, actual, "%v compared to %v", tt.a, tt.b)
+
+		actual, err = CompareSegments(tt.b, tt.a)
+		require.NoError(t, err)
+		assert.Equal(t, -tt.expected, actual, "%v compared to %v", tt.b, tt.a)
+	}
+}
+
+func TestCompareSegmentsErrors(t *testing.T) {
+	_, err := CompareSegments([]string{"1", "x"}, []string{"1"})
+	require.Error(t, err)
+	assert.Equal(t, `segment 1 of a is not a valid decimal: "x"`, err.Error())
+
+	_, err = CompareSegments([]string{"1"}, []string{"1", "2", ""})
+	require.Error(t, err)
+	assert.Equal(t, `segment 2 of b is not a valid decimal: ""`, err.Error())
+
+	_, err = CompareToSegments(parseOrFatalSemVer(t, "1.2.3"), []string{"NaN"})
+	require.Error(t, err)
+	assert.Equal(t, `segment 0 of segments is not a valid decimal: "NaN"`, err.Error())
+}
+
+func assertFromSortableMatchesParse(t *testing.T, original string, segments []string, pa ParsedAs, parsed *Version) {
+	v, err := FromSortable(original, segments, pa)
+	require.NoError(t, err, original)
+	assert.Equal(t, original, v.Original)
+	assert.Equal(t, pa, v.ParsedAs, original)
+	assert.Equal(t, segments, v.Segments(), "segments of %s", original)
+	assert.Equal(t, 0, Compare(v, parsed), "%s compares equal to the parsed version", original)
+}
+
+func TestFromSortableMatchesParsers(t *testing.T) {
+	for _, tt := range parseGenericTests {
+		assertFromSortableMatchesParse(t, tt.version, tt.expected, Generic, parseOrFatalGeneric(t, tt.version))
+	}
+	for _, tt := range parseSemVerTests {
+		if len(tt.expected) > 0 {
+			assertFromSortableMatchesParse(t, tt.version, tt.expected, SemVer, parseOrFatalSemVer(t, tt.version))
+		}
+	}
+	for pa, cases := range parsePerlTests {
+		for _, tt := range cases {
+			if tt.expected != nil {
+				assertFromSortableMatchesParse(t, tt.version, tt.expected, pa, parsePerlOrFatal(t, tt.version))
+			}
+		}
+	}
+	for pa, cases := range parsePythonTests {
+		for _, tt := range cases {
+			assertFromSortableMatchesParse(t, tt.version, tt.expected, pa, parsePythonOrFatal(t, tt.version))
+		}
+	}
+}
+
+func TestFromSortableKeepsTrailingZeros(t *testing.T) {
+	v, err := FromSortable("1.2", []string{"1", "2", "0", "0"}, Generic)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "0", "0"}, v.Segments())
+	assert.Equal(t, 0, Compare(v, parseOrFatalGeneric(t, "1.2")))
+}
+
+func TestFromSortableErrors(t *testing.T) {
+	_, err := FromSortable("1.2", []string{"1", "2"}, Unknown)
+	assert.Error(t, err)
+	_, err = FromSortable("1.2", []string{"1", "2"}, ParsedAs(100))
+	assert.Error(t, err)
+	_, err = FromSortable("1.2", nil, Generic)
+	assert.Error(t, err)
+	_, err = FromSortable("1.2", []string{"1", "two"}, Generic)
+	require.Error(t, err)
+	assert.Equal(t, `cannot make a version from 1.2: segment 1 of segments is not a valid decimal: "two"`, err.Error())
+}
+
+func assertNormalizeSegmentsMatchesParse(t *testing.T, original string, segments []string, parsed *Version) {
+	padded := append(append([]string{}, segments...), "0", "0.00")
+
+	trimmed, _, err := NormalizeSegments(padded)
+	require.NoError(t, err, original)
+	assert.Equal(t, parsed.Segments(), trimmed, "normalized segments of %s", original)
+	assert.Equal(t, parsed.Segments(), TrimTrailingZeroSegments(padded), "trimmed segments of %s", original)
+
+	again, _, err := NormalizeSegments(trimmed)
+	require.NoError(t, err, original)
+	assert.Equal(t, trimmed, again, "normalizing %s again changes nothing", original)
+}
+
+func TestNormalizeSegmentsMatchesParsers(t *testing.T) {
+	for _, tt := range parseGenericTests {
+		assertNormalizeSegmentsMatchesParse(t, tt.version, tt.expected, parseOrFatalGeneric(t, tt.version))
+	}
+	for _, tt := range parseSemVerTests {
+		if len(tt.expected) > 0 {
+			assertNormalizeSegmentsMatchesParse(t, tt.version, tt.expected, parseOrFatalSemVer(t, tt.version))
+		}
+	}
+	for _, cases := range parsePerlTests {
+		for _, tt := range cases {
+			if tt.expected != nil {
+				assertNormalizeSegmentsMatchesParse(t, tt.version, tt.expected, parsePerlOrFatal(t, tt.version))
+			}
+		}
+	}
+	for _, cases := range parsePythonTests {
+		for _, tt := range cases {
+			assertNormalizeSegmentsMatchesParse(t, tt.version, tt.expected, parsePythonOrFatal(t, tt.version))
+		}
+	}
+}
+
+func TestNormalizeSegments(t *testing.T) {
+	tests := []struct {
+		input, expected []string
+		isInt           bool
+	}{
+		{[]string{"0"}, []string{"0"}, true},
+		{[]string{"0", "0"}, []string{"0"}, true},
+		{[]string{"1", "0", "2", "0.000"}, []string{"1", "0", "2"}, true},
+		{[]string{"1", "-1", "0"}, []string{"1", "-1"}, true},
+		{[]string{"1", "97.108"}, []string{"1", "97.108"}, false},
+		{[]string{"20200101", "-0.5"}, []string{"20200101", "-0.5"}, false},
+		{[]string{"1", "99999999999999999999"}, []string{"1", "99999999999999999999"}, false},
+	}
+	for _, tt := range tests {
+		trimmed, isInt, err := NormalizeSegments(tt.input)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.expected, trimmed, tt.input)
+		assert.Equal(t, tt.isInt, isInt, tt.input)
+	}
+
+	for _, input := range [][]string{nil, {"1", "two"}, {"1", ""}, {"NaN"}, {"1", "Inf", "0"}} {
+		_, _, err := NormalizeSegments(input)
+		assert.Error(t, err, "%q", input)
+	}
+}
+
+func TestTrimTrailingZeroSegments(t *testing.T) {
+	assert.Equal(t, []string{"1", "0", "2"}, TrimTrailingZeroSegments([]string{"1", "0", "2", "0", "00", "0.0"}))
+	assert.Equal(t, []string{"0"}, TrimTrailingZeroSegments([]string{"0", "0"}))
+	assert.Equal(t, []string{"1", "x"}, TrimTrailingZeroSegments([]string{"1", "x", "0"}))
+	assert.Equal(t, []string{"1", "NaN"}, TrimTrailingZeroSegments([]string{"1", "NaN", "0"}))
+	assert.Empty(t, TrimTrailingZeroSegments(nil))
+}
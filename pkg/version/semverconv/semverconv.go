@@ -0,0 +1,76 @@
+// Package semverconv converts between Versions from the version package and
+// github.com/Masterminds/semver/v3 Versions, so that code built around
+// Masterminds constraints can be used with versions parsed by this repo. It is
+// a separate package so that programs which only import the version package
+// don't depend on Masterminds.
+package semverconv
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/Masterminds/semver/v3"
+)
+
+// npmLooseRegEx splits a version in node-semver's loose form, as accepted by
+// version.ParseNpm, into its release numbers and the rest of the version.
+var npmLooseRegEx = regexp.MustCompile(`^[v=\s]*([0-9]+(?:\.[0-9]+)*)-?(.*?)\s*$`)
+
+// ToMastermindsSemVer returns v as a Masterminds Version. It returns an error
+// if v was not parsed as version.SemVer, version.Go or version.Npm. The
+// leading "v" of a Go module version is dropped, and npm versions are put in
+// strict semver form first, so "=1.2beta" becomes "1.2.0-beta". The
+// Masterminds Version's Original is the version in this form.
+func ToMastermindsSemVer(v *version.Version) (*semver.Version, error) {
+	s := v.Original
+	switch v.ParsedAs {
+	case version.SemVer:
+	case version.Go:
+		s = strings.TrimPrefix(s, "v")
+	case version.Npm:
+		s = strictNpmVersion(s)
+	default:
+		return nil, fmt.Errorf("cannot convert %s to a Masterminds semver version: it is a %s version", v.Original, v.ParsedAs)
+	}
+
+	sv, err := semver.StrictNewVersion(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %s to a Masterminds semver version: %w", v.Original, err)
+	}
+	return sv, nil
+}
+
+// FromMastermindsSemVer returns sv as a Version with the same segments that
+// version.ParseSemVer gives. The Version's Original is sv.String(), which
+// drops any leading "v" and fills in a missing minor or patch version, since
+// sv.Original() may not be a valid semver version.
+func FromMastermindsSemVer(sv *semver.Version) *version.Version {
+	v, err := version.ParseSemVer(sv.String())
+	if err != nil {
+		// Masterminds only creates versions whose String() is valid semver.
+		panic(fmt.Sprintf("Masterminds semver version %s is not valid semver: %s", sv, err))
+	}
+	return v
+}
+
+// strictNpmVersion returns a version parsed by version.ParseNpm in strict
+// semver form, without the leading characters and surrounding whitespace that
+// node-semver allows, with any missing minor and patch versions, and with a
+// "-" before the pre-release.
+func strictNpmVersion(s string) string {
+	m := npmLooseRegEx.FindStringSubmatch(s)
+	if m == nil {
+		return s
+	}
+
+	release, rest := m[1], m[2]
+	for i := strings.Count(release, "."); i < 2; i++ {
+		release += ".0"
+	}
+	if rest != "" && rest[0] != '+' {
+		rest = "-" + rest
+	}
+	return release + rest
+}
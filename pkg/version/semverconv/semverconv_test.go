@@ -0,0 +1,149 @@
+package semverconv
+
+import (
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// This is a copy of testParseSemVerOrderInputs from the version package's
+// tests, in ascending order.
+var semVerOrderInputs = []string{
+	"0.0.0-foo",
+	"0.0.0",
+	"0.0.1",
+	"0.1.2",
+	"0.9.0",
+	"0.9.9",
+	"0.10.0",
+	"0.99.0",
+	"1.0.0-alpha",
+	"1.0.0-alpha.0",
+	"1.0.0-alpha.1",
+	"1.0.0-alpha.100",
+	"1.0.0-alpha.100.0",
+	"1.0.0-alpha.100.a",
+	"1.0.0-alpha.beta",
+	"1.0.0-beta",
+	"1.0.0-beta.2",
+	"1.0.0-beta.11",
+	"1.0.0-rc.1",
+	"1.0.0",
+	"1.0.1",
+	"1.2.2",
+	"1.2.3-4",
+	"1.2.3-5",
+	"1.2.3-4-foo",
+	"1.2.3-5-Foo",
+	"1.2.3-5-foo",
+	"1.2.3-R2",
+	"1.2.3-a",
+	"1.2.3-a.0",
+	"1.2.3-a.5",
+	"1.2.3-a.10",
+	"1.2.3-a.100",
+	"1.2.3-a.b",
+	"1.2.3-a.b.c.5.d.100",
+	"1.2.3-a.b.c.10.d.5",
+	"1.2.3-alpha.0.2",
+	"1.2.3-alpha.0.pr.1",
+	"1.2.3-alpha.0.pr.2",
+	"1.2.3-asdf",
+	"1.2.3-pre",
+	"1.2.3-r100",
+	"1.2.3-r2",
+	"1.2.3",
+	"1.2.4-1",
+	"1.2.4",
+	"2.0.0",
+	"2.3.4",
+	"2.7.2+asdf",
+	"3.0.0",
+	"9.9.9-alpha.0.pr.1",
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+func TestOrderingAgreesWithMasterminds(t *testing.T) {
+	versions := make([]*version.Version, len(semVerOrderInputs))
+	mastermindsVersions := make([]*semver.Version, len(semVerOrderInputs))
+	for i, s := range semVerOrderInputs {
+		v, err := version.ParseSemVer(s)
+		require.NoError(t, err)
+		versions[i] = v
+
+		mastermindsVersions[i], err = ToMastermindsSemVer(v)
+		require.NoError(t, err, s)
+	}
+
+	for i := range versions {
+		for j := range versions {
+			assert.Equal(t,
+				sign(version.Compare(versions[i], versions[j])),
+				mastermindsVersions[i].Compare(mastermindsVersions[j]),
+				"%s compared to %s", semVerOrderInputs[i], semVerOrderInputs[j])
+		}
+	}
+}
+
+func TestFromMastermindsSemVer(t *testing.T) {
+	for _, s := range semVerOrderInputs {
+		expected, err := version.ParseSemVer(s)
+		require.NoError(t, err)
+
+		actual := FromMastermindsSemVer(semver.MustParse(s))
+		assert.Equal(t, expected.String(), actual.String(), s)
+	}
+
+	v := FromMastermindsSemVer(semver.MustParse("v1.2"))
+	assert.Equal(t, "1.2.0", v.Original)
+	assert.Equal(t, version.SemVer, v.ParsedAs)
+}
+
+func TestToMastermindsSemVer(t *testing.T) {
+	v, err := version.ParseSemVer("1.2.3-beta.1+build.5")
+	require.NoError(t, err)
+	sv, err := ToMastermindsSemVer(v)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3-beta.1+build.5", sv.Original())
+	assert.Equal(t, "beta.1", sv.Prerelease())
+	assert.Equal(t, "build.5", sv.Metadata())
+
+	v, err = version.ParseGo("v2.1.0-beta+incompatible")
+	require.NoError(t, err)
+	sv, err = ToMastermindsSemVer(v)
+	require.NoError(t, err)
+	assert.Equal(t, "2.1.0-beta+incompatible", sv.Original())
+	assert.Equal(t, "beta", sv.Prerelease())
+	assert.Equal(t, "incompatible", sv.Metadata())
+
+	for in, expected := range map[string]string{
+		"1.2.3":           "1.2.3",
+		" =v1.2.3-rc.1 ":  "1.2.3-rc.1",
+		"1.2":             "1.2.0",
+		"1.2.3beta+build": "1.2.3-beta+build",
+		"2+build":         "2.0.0+build",
+	} {
+		v, err = version.ParseNpm(in)
+		require.NoError(t, err, in)
+		sv, err = ToMastermindsSemVer(v)
+		require.NoError(t, err, in)
+		assert.Equal(t, expected, sv.Original(), in)
+	}
+
+	v, err = version.ParsePython("1.2.3")
+	require.NoError(t, err)
+	_, err = ToMastermindsSemVer(v)
+	assert.Error(t, err, "only semver versions can be converted")
+}
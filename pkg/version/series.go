@@ -0,0 +1,108 @@
+package version
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Series is a group of versions from the same release series, as returned by
+// GroupBySeries.
+type Series struct {
+	// Key identifies the series, such as "1.4". It is "" for versions that
+	// do not start with a number.
+	Key string
+	// Versions are the versions in the series, sorted in descending order.
+	Versions []*Version
+}
+
+// GroupBySeries groups vs into release series, where a series is all of the
+// versions that share the same first depth release segments. For example,
+// with a depth of 2, 1.4.0 and 1.4.7 are in the "1.4" series, and with a
+// depth of 1 they are in the "1" series along with 1.9.2. Values of depth less
+// than one are treated as one.
+//
+// The series are sorted in descending order of their greatest version, and
+// each series' versions are sorted in descending order, with equal versions
+// kept in their original relative order. vs is not modified.
+//
+// Missing segments are treated as zero, so 2 is in the "2.0" series. A
+// pre-release is in the series of the release it precedes, so 2.0.0-rc.1 is
+// in the "2.0" series too. The epoch of a PythonPEP440 version is only part of
+// the key if it is not zero, as in "1!2.0". PythonLegacy versions are grouped
+// by the leading numbers in their original string.
+func GroupBySeries(vs []*Version, depth int) []Series {
+	if depth < 1 {
+		depth = 1
+	}
+
+	sorted := append([]*Version(nil), vs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return Compare(sorted[i], sorted[j]) > 0
+	})
+
+	var series []Series
+	indexes := map[string]int{}
+	for _, v := range sorted {
+		key := seriesKey(v, depth)
+		i, ok := indexes[key]
+		if !ok {
+			i = len(series)
+			indexes[key] = i
+			series = append(series, Series{Key: key})
+		}
+		series[i].Versions = append(series[i].Versions, v)
+	}
+	return series
+}
+
+var legacyPythonRelease = regexp.MustCompile(`^\s*v?([0-9]+(?:\.[0-9]+)*)`)
+
+func seriesKey(v *Version, depth int) string {
+	var (
+		prefix string
+		parts  = make([]string, 0, depth)
+	)
+
+	if v.ParsedAs == PythonLegacy {
+		// The segments of a legacy version encode its characters rather
+		// than its numbers, so the numbers are read from the original.
+		m := legacyPythonRelease.FindStringSubmatch(v.Original)
+		if m == nil {
+			return ""
+		}
+		for _, p := range strings.Split(m[1], ".") {
+			if len(parts) == depth {
+				break
+			}
+			if p = strings.TrimLeft(p, "0"); p == "" {
+				p = "0"
+			}
+			parts = append(parts, p)
+		}
+	} else {
+		start := releaseStart(v)
+		if start > 0 {
+			if epoch := segmentOrZero(v, 0); epoch.Sign() != 0 {
+				prefix = epoch.String() + "!"
+			}
+		}
+		for i := 0; i < depth; i++ {
+			// A segment that is not a whole number marks the start of a
+			// pre-release or other suffix, which is not part of the key.
+			seg := segmentOrZero(v, start+i)
+			if !isWholeNumber(seg) {
+				break
+			}
+			parts = append(parts, seg.String())
+		}
+		if len(parts) == 0 {
+			return ""
+		}
+	}
+
+	for len(parts) < depth {
+		parts = append(parts, "0")
+	}
+	return prefix + strings.Join(parts, ".")
+}
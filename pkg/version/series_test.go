@@ -0,0 +1,108 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// seriesOriginals returns the keys of series and the original strings of
+// their versions.
+func seriesOriginals(series []Series) ([]string, [][]string) {
+	var (
+		keys      []string
+		originals [][]string
+	)
+	for _, s := range series {
+		keys = append(keys, s.Key)
+		var o []string
+		for _, v := range s.Versions {
+			o = append(o, v.Original)
+		}
+		originals = append(originals, o)
+	}
+	return keys, originals
+}
+
+func TestGroupBySeriesSemVer(t *testing.T) {
+	vs := semVers(t, "1.4.0", "2.0.0-rc.1", "1.4.7", "2.0.0", "1.10.1", "1.4.7-beta.1", "2.1.0", "1.9.0")
+	original := append([]*Version{}, vs...)
+
+	keys, originals := seriesOriginals(GroupBySeries(vs, 2))
+	assert.Equal(t, []string{"2.1", "2.0", "1.10", "1.9", "1.4"}, keys)
+	assert.Equal(t, [][]string{
+		{"2.1.0"},
+		{"2.0.0", "2.0.0-rc.1"},
+		{"1.10.1"},
+		{"1.9.0"},
+		{"1.4.7", "1.4.7-beta.1", "1.4.0"},
+	}, originals)
+	assert.Equal(t, original, vs, "vs is not modified")
+
+	keys, originals = seriesOriginals(GroupBySeries(vs, 1))
+	assert.Equal(t, []string{"2", "1"}, keys)
+	assert.Equal(t, [][]string{
+		{"2.1.0", "2.0.0", "2.0.0-rc.1"},
+		{"1.10.1", "1.9.0", "1.4.7", "1.4.7-beta.1", "1.4.0"},
+	}, originals)
+
+	keys, _ = seriesOriginals(GroupBySeries(semVers(t, "2.0.0-rc.1", "3.0.0"), 3))
+	assert.Equal(t, []string{"3.0.0", "2.0.0"}, keys)
+
+	keys, _ = seriesOriginals(GroupBySeries(semVers(t, "1.2.3"), 0))
+	assert.Equal(t, []string{"1"}, keys, "a depth less than one is treated as one")
+
+	assert.Empty(t, GroupBySeries(nil, 2))
+}
+
+func TestGroupBySeriesPython(t *testing.T) {
+	vs := []*Version{
+		parsePythonOrFatal(t, "2.0rc1"),
+		parsePythonOrFatal(t, "1.4.post1"),
+		parsePythonOrFatal(t, "2.0"),
+		parsePythonOrFatal(t, "1!1.0"),
+		parsePythonOrFatal(t, "1.4"),
+		parsePythonOrFatal(t, "2.0.dev3"),
+		parsePythonOrFatal(t, "2"),
+		parsePythonOrFatal(t, "1.4.2.dev1"),
+	}
+	keys, originals := seriesOriginals(GroupBySeries(vs, 2))
+	assert.Equal(t, []string{"1!1.0", "2.0", "1.4"}, keys)
+	assert.Equal(t, [][]string{
+		{"1!1.0"},
+		{"2.0", "2", "2.0rc1", "2.0.dev3"},
+		{"1.4.2.dev1", "1.4.post1", "1.4"},
+	}, originals, "equal versions keep their original order")
+
+	legacy := []*Version{
+		parsePythonOrFatal(t, "1.4-foo-bar"),
+		parsePythonOrFatal(t, "01.04.5pl0"),
+		parsePythonOrFatal(t, "a cat is fine too"),
+	}
+	for _, v := range legacy {
+		assert.Equal(t, PythonLegacy, v.ParsedAs, v.Original)
+	}
+	keys, originals = seriesOriginals(GroupBySeries(legacy, 2))
+	assert.ElementsMatch(t, []string{"1.4", ""}, keys)
+	for i, k := range keys {
+		if k == "1.4" {
+			assert.ElementsMatch(t, []string{"1.4-foo-bar", "01.04.5pl0"}, originals[i])
+		}
+	}
+}
+
+func TestGroupBySeriesGeneric(t *testing.T) {
+	vs := []*Version{
+		parseOrFatalGeneric(t, "2-rc1"),
+		parseOrFatalGeneric(t, "2.0.1"),
+		parseOrFatalGeneric(t, "release"),
+		parseOrFatalGeneric(t, "1.0-beta"),
+	}
+	keys, originals := seriesOriginals(GroupBySeries(vs, 2))
+	assert.Equal(t, []string{"", "2.0", "1.0"}, keys)
+	assert.Equal(t, [][]string{
+		{"release"},
+		{"2.0.1", "2-rc1"},
+		{"1.0-beta"},
+	}, originals)
+}
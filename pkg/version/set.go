@@ -0,0 +1,170 @@
+package version
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// VersionSet is a set of versions of a single package. Membership is decided
+// by Compare rather than by the original strings, so "1.2" and "1.2.0" are
+// the same member of a set of Generic versions. All members must be
+// comparable with each other (see Comparable).
+//
+// The zero value is an empty set ready to use. A VersionSet is not safe for
+// concurrent use.
+type VersionSet struct {
+	// members maps each member's CompareKey to the member. Versions that
+	// are equal under Compare have identical keys.
+	members map[string]*Version
+	// sorted caches the members in ascending order. It is nil when it needs
+	// to be rebuilt.
+	sorted []*Version
+	// parsedAs is the type of the first member that is not Raw, which all
+	// other members must be comparable with. nonRaw counts those members,
+	// so that the type can be reset when the last one is removed.
+	parsedAs ParsedAs
+	nonRaw   int
+}
+
+// NewVersionSet returns a set containing vs. Versions that are equal to one
+// earlier in vs are left out. It returns an *IncomparableError if vs
+// contains versions that are not comparable with each other.
+func NewVersionSet(vs ...*Version) (*VersionSet, error) {
+	s := &VersionSet{}
+	for _, v := range vs {
+		if _, err := s.Add(v); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Add adds v to the set. It returns false without changing the set if the
+// set already has a member that is equal to v under Compare, and returns an
+// *IncomparableError if v is not comparable with the members of the set.
+func (s *VersionSet) Add(v *Version) (bool, error) {
+	if s.nonRaw > 0 && !Comparable(s.parsedAs, v.ParsedAs) {
+		return false, &IncomparableError{ParsedAs1: s.parsedAs, ParsedAs2: v.ParsedAs}
+	}
+
+	key := string(v.CompareKey())
+	if _, ok := s.members[key]; ok {
+		return false, nil
+	}
+	if s.members == nil {
+		s.members = map[string]*Version{}
+	}
+	s.members[key] = v
+	s.sorted = nil
+
+	if v.ParsedAs != Raw {
+		if s.nonRaw == 0 {
+			s.parsedAs = v.ParsedAs
+		}
+		s.nonRaw++
+	}
+	return true, nil
+}
+
+// Contains returns true if the set has a member that is equal to v under
+// Compare.
+func (s *VersionSet) Contains(v *Version) bool {
+	_, ok := s.members[string(v.CompareKey())]
+	return ok
+}
+
+// Remove removes the member that is equal to v under Compare, and returns
+// false if there is none.
+func (s *VersionSet) Remove(v *Version) bool {
+	key := string(v.CompareKey())
+	member, ok := s.members[key]
+	if !ok {
+		return false
+	}
+	delete(s.members, key)
+	s.sorted = nil
+	if member.ParsedAs != Raw {
+		s.nonRaw--
+	}
+	return true
+}
+
+// Len returns the number of members in the set.
+func (s *VersionSet) Len() int {
+	return len(s.members)
+}
+
+// Sorted returns the members of the set in ascending order. The returned
+// slice belongs to the caller.
+func (s *VersionSet) Sorted() []*Version {
+	sorted := s.sortedMembers()
+	out := make([]*Version, len(sorted))
+	copy(out, sorted)
+	return out
+}
+
+// Latest returns the greatest member of the set, or nil if the set is empty.
+func (s *VersionSet) Latest() *Version {
+	sorted := s.sortedMembers()
+	if len(sorted) == 0 {
+		return nil
+	}
+	return sorted[len(sorted)-1]
+}
+
+// LatestStable returns the greatest member of the set that is not a
+// pre-release (see IsPreRelease) or a Raw version, or nil if there is none.
+func (s *VersionSet) LatestStable() *Version {
+	sorted := s.sortedMembers()
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if v := sorted[i]; v.ParsedAs != Raw && !v.IsPreRelease() {
+			return v
+		}
+	}
+	return nil
+}
+
+// Filter returns a new set containing the members of s for which keep
+// returns true.
+func (s *VersionSet) Filter(keep func(*Version) bool) *VersionSet {
+	filtered := &VersionSet{}
+	for _, v := range s.sortedMembers() {
+		if keep(v) {
+			// The members of s are comparable with each other and are
+			// all different, so this cannot fail or skip v.
+			_, _ = filtered.Add(v)
+		}
+	}
+	return filtered
+}
+
+// MarshalJSON encodes the set as a JSON array of its members in ascending
+// order, as json.Marshal would encode the result of Sorted.
+func (s *VersionSet) MarshalJSON() ([]byte, error) {
+	sorted := s.sortedMembers()
+	if sorted == nil {
+		sorted = []*Version{}
+	}
+	return json.Marshal(sorted)
+}
+
+// sortedMembers returns the cached sorted members, rebuilding them if
+// needed. The result must not be modified.
+func (s *VersionSet) sortedMembers() []*Version {
+	if s.sorted != nil || len(s.members) == 0 {
+		return s.sorted
+	}
+
+	keys := make([]string, 0, len(s.members))
+	for key := range s.members {
+		keys = append(keys, key)
+	}
+	// Compare keys order the same way as Compare.
+	sort.Strings(keys)
+
+	s.sorted = make([]*Version, len(keys))
+	for i, key := range keys {
+		s.sorted[i] = s.members[key]
+	}
+	return s.sorted
+}
@@ -0,0 +1,132 @@
+package version
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func originalsOf(vs []*Version) []string {
+	originals := make([]string, len(vs))
+	for i, v := range vs {
+		originals[i] = v.Original
+	}
+	return originals
+}
+
+func TestVersionSetAdd(t *testing.T) {
+	var s VersionSet
+	assert.Equal(t, 0, s.Len())
+	assert.Nil(t, s.Latest())
+
+	for _, v := range genericVersions(t, "1.2.0", "1.10", "1.3", "1.2.0-rc1") {
+		added, err := s.Add(v)
+		require.NoError(t, err)
+		assert.True(t, added, v.Original)
+	}
+
+	for _, v := range genericVersions(t, "1.2", "1.2.0.0", "1.10.0") {
+		added, err := s.Add(v)
+		require.NoError(t, err)
+		assert.False(t, added, "%s is equal to a member", v.Original)
+		assert.True(t, s.Contains(v), v.Original)
+	}
+
+	assert.Equal(t, 4, s.Len())
+	assert.Equal(t, []string{"1.2.0-rc1", "1.2.0", "1.3", "1.10"}, originalsOf(s.Sorted()))
+	assert.False(t, s.Contains(parseOrFatalGeneric(t, "1.4")))
+}
+
+func TestVersionSetIncomparable(t *testing.T) {
+	s, err := NewVersionSet(semVers(t, "1.0.0", "2.0.0")...)
+	require.NoError(t, err)
+
+	added, err := s.Add(parseOrFatalGeneric(t, "3.0"))
+	assert.False(t, added)
+	assert.IsType(t, &IncomparableError{}, err)
+	assert.Equal(t, 2, s.Len())
+
+	added, err = s.Add(NewRaw("latest"))
+	require.NoError(t, err)
+	assert.True(t, added, "raw versions are comparable with everything")
+
+	_, err = NewVersionSet(parsePerlOrFatal(t, "1.002"), parsePerlOrFatal(t, "v1.3.0"), parsePythonOrFatal(t, "1.0"))
+	assert.IsType(t, &IncomparableError{}, err)
+
+	p, err := NewVersionSet(parsePythonOrFatal(t, "1.0"), parsePythonOrFatal(t, "foo"))
+	require.NoError(t, err, "legacy and PEP440 versions are comparable")
+	assert.Equal(t, 2, p.Len())
+}
+
+func TestVersionSetRemove(t *testing.T) {
+	s, err := NewVersionSet(semVers(t, "1.0.0", "2.0.0", "3.0.0")...)
+	require.NoError(t, err)
+	assert.Equal(t, "3.0.0", s.Latest().Original)
+
+	assert.True(t, s.Remove(parseOrFatalSemVer(t, "3.0.0+build")))
+	assert.False(t, s.Remove(parseOrFatalSemVer(t, "3.0.0")))
+	assert.Equal(t, "2.0.0", s.Latest().Original)
+	assert.Equal(t, []string{"1.0.0", "2.0.0"}, originalsOf(s.Sorted()))
+
+	assert.True(t, s.Remove(parseOrFatalSemVer(t, "1.0.0")))
+	assert.True(t, s.Remove(parseOrFatalSemVer(t, "2.0.0")))
+	assert.Equal(t, 0, s.Len())
+	assert.Empty(t, s.Sorted())
+
+	added, err := s.Add(parseOrFatalGeneric(t, "1.0"))
+	require.NoError(t, err, "an emptied set takes the type of its next member")
+	assert.True(t, added)
+}
+
+func TestVersionSetSortedIsACopy(t *testing.T) {
+	s, err := NewVersionSet(semVers(t, "1.0.0", "2.0.0")...)
+	require.NoError(t, err)
+
+	sorted := s.Sorted()
+	sorted[0] = nil
+	assert.Equal(t, []string{"1.0.0", "2.0.0"}, originalsOf(s.Sorted()))
+}
+
+func TestVersionSetLatestStable(t *testing.T) {
+	s, err := NewVersionSet(semVers(t, "1.0.0", "1.1.0", "2.0.0-rc.1")...)
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0-rc.1", s.Latest().Original)
+	assert.Equal(t, "1.1.0", s.LatestStable().Original)
+
+	s, err = NewVersionSet(NewRaw("latest"), parseOrFatalSemVer(t, "1.0.0-beta"))
+	require.NoError(t, err)
+	assert.Nil(t, s.LatestStable())
+}
+
+func TestVersionSetFilter(t *testing.T) {
+	s, err := NewVersionSet(semVers(t, "0.9.0", "1.0.0", "1.4.2", "1.5.0-rc.1", "2.0.0")...)
+	require.NoError(t, err)
+
+	min, max := parseOrFatalSemVer(t, "1.0.0"), parseOrFatalSemVer(t, "2.0.0")
+	filtered := s.Filter(func(v *Version) bool {
+		return Compare(v, min) >= 0 && Compare(v, max) < 0 && !v.IsPreRelease()
+	})
+	assert.Equal(t, []string{"1.0.0", "1.4.2"}, originalsOf(filtered.Sorted()))
+	assert.Equal(t, 5, s.Len(), "the original set is unchanged")
+
+	empty := s.Filter(func(*Version) bool { return false })
+	assert.Equal(t, 0, empty.Len())
+}
+
+func TestVersionSetMarshalJSON(t *testing.T) {
+	vs := semVers(t, "2.0.0", "1.0.0", "1.5.0")
+	s, err := NewVersionSet(vs...)
+	require.NoError(t, err)
+
+	actual, err := json.Marshal(s)
+	require.NoError(t, err)
+	expected, err := json.Marshal([]*Version{vs[1], vs[2], vs[0]})
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+
+	actual, err = json.Marshal(&VersionSet{})
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(actual))
+}
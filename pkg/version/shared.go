@@ -8,24 +8,13 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/text/unicode/norm"
 )
 
-const (
-	delimiter           = "-"
-	delimitedSubsection = delimiter + "$1" + delimiter
-)
-
 var (
-	// See https://github.com/google/re2/wiki/Syntax for go regex character classes.
-	// \pZ  = unicode separator character class
-	// \pP  = unicode punctuation character class
-	anyPunctuationOrSeparator = regexp.MustCompile(`[\p{P}\p{Z}]+`)
-	wholeNumber               = regexp.MustCompile(`([0-9]+)`)
-	decimalNumber             = regexp.MustCompile(`^(\d+\.\d*|\.?\d+)$`)
-	notZero                   = regexp.MustCompile(`[^0]`)
-
 	// Matches semver 2.0
 	semVerRegEx = regexp.MustCompile(`^(?P<major>0|[1-9]\d*)\.(?P<minor>0|[1-9]\d*)\.(?P<patch>0|[1-9]\d*)(?:-(?P<prerelease>(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+(?P<buildmetadata>[0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
 
@@ -63,11 +52,31 @@ var (
 // such that two parsed version strings can be compared. This function treats
 // numbers as individually comparable segments and not as decimal numbers,
 // i.e. 1.2 is parsed to be compared as two numbers: 1 and 2.
-func ParseGeneric(version string) (*Version, error) {
+//
+// ParseGeneric honors the WithIgnoreBuildMetadata, WithMaxSegments,
+// WithSegmentOverflow and WithFixedSegments options.
+func ParseGeneric(version string, opts ...Option) (*Version, error) {
+	v := &Version{}
+	if err := parseGenericInto(v, version, applyOptions(opts)); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// parseGenericInto does the work of ParseGeneric, storing the result in dst
+// as ParseInto does. The segments are built in a pooled slice, since they
+// are only needed until they are converted to decimals.
+func parseGenericInto(dst *Version, version string, o options) error {
 	version = normalizeUnicode(version)
-	segments := parseBySeparator(
-		version,
-		anyPunctuationOrSeparator,
+	release, build := version, ""
+	if o.ignoreBuildMetadata {
+		release, build = splitGenericBuildMetadata(version)
+	}
+
+	pooled := segmentsPool.Get().(*[]string)
+	segments := appendBySeparator(
+		(*pooled)[:0],
+		release,
 		toDecimalStringWithGenericPreReleaseIdentifierHandling,
 	)
 
@@ -75,7 +84,49 @@ func ParseGeneric(version string) (*Version, error) {
 		segments = append(segments, "0")
 	}
 
-	return fromStringSlice(Generic, version, segments)
+	err := fillFromStringSlice(dst, Generic, version, segments)
+	putSegments(pooled, segments)
+	if err != nil {
+		return err
+	}
+	dst.BuildMetadata = build
+	if _, err := limitSegments(dst, o); err != nil {
+		resetVersion(dst)
+		return err
+	}
+	if _, err := padToFixedSegments(dst, o); err != nil {
+		resetVersion(dst)
+		return err
+	}
+	return nil
+}
+
+// WithIgnoreBuildMetadata makes ParseGeneric drop semver-style build metadata
+// ("1.4.2+20230917.abcdef") before the version is split into segments, so
+// that builds of the same release compare as equal. The metadata is stored in
+// the BuildMetadata field of the returned Version.
+//
+// A "+" only starts build metadata if at least one digit precedes it, so a
+// version like "c++-1.0" is parsed as it would be without this option.
+func WithIgnoreBuildMetadata() Option {
+	return func(o *options) {
+		o.ignoreBuildMetadata = true
+	}
+}
+
+// splitGenericBuildMetadata splits version at the first "+" that follows a
+// digit, returning the parts before and after it.
+func splitGenericBuildMetadata(version string) (string, string) {
+	seenDigit := false
+	for i, r := range version {
+		switch {
+		case r >= '0' && r <= '9':
+			seenDigit = true
+		case r == '+' && seenDigit:
+			return version[:i], version[i+1:]
+		}
+	}
+	return version, ""
 }
 
 // ParseSemVer parses the semantic version (https://semver.org/) version
@@ -83,7 +134,10 @@ func ParseGeneric(version string) (*Version, error) {
 // strings can be compared as required by the semantic versioning
 // specification.
 func ParseSemVer(version string) (*Version, error) {
-	matches := semVerRegEx.FindStringSubmatch(version)
+	var matches []string
+	if semVerMayMatch(version) {
+		matches = semVerRegEx.FindStringSubmatch(version)
+	}
 	if len(matches) == 0 {
 		return nil, fmt.Errorf("Version does not match semver regex: %s", version)
 	}
@@ -135,6 +189,10 @@ func normalizeUnicode(s string) string {
 // findNamedMatches returns a map of group names to matched strings from the
 // leftmost match of the regular expression in version. A return value of nil
 // indicates no match.
+//
+// This is the slow path as it allocates a map on every call. Parsers that run
+// on every version should look up their groups with subexpIndex once and read
+// the result of FindStringSubmatch by index instead.
 func findNamedMatches(version string, regex *regexp.Regexp) map[string]string {
 	matches := regex.FindStringSubmatch(version)
 	if matches == nil {
@@ -150,21 +208,93 @@ func findNamedMatches(version string, regex *regexp.Regexp) map[string]string {
 	return groups
 }
 
+// subexpIndex returns the index of the group called name in regex. It panics
+// if there is no such group, so it should only be used to initialize package
+// level variables.
+func subexpIndex(regex *regexp.Regexp, name string) int {
+	for i, subexpName := range regex.SubexpNames() {
+		if subexpName == name {
+			return i
+		}
+	}
+	panic(fmt.Sprintf("regex %q has no group named %q", regex.String(), name))
+}
+
 // decimalStringConverter converts a string into a decimal number string. The
 // input string is typically not expected to contain any numbers.
 type decimalStringConverter func(string) string
 
-func parseBySeparator(version string, separatorRegex *regexp.Regexp, convert decimalStringConverter) []string {
-	parsed := []string{}
-	for _, section := range separatorRegex.Split(version, -1) {
-		section = wholeNumber.ReplaceAllString(section, delimitedSubsection)
-		for _, piece := range strings.Split(section, delimiter) {
-			parsed = maybeAppendDecimalString(parsed, piece, convert)
+// parseBySeparator splits version into segments at runs of Unicode
+// punctuation (\pP) and separator (\pZ) characters, and further splits each
+// section between runs of ASCII digits and runs of anything else. Each
+// resulting piece is converted to a normalized decimal string, using convert
+// for pieces that are not numbers.
+//
+// This is a single pass over the string rather than a series of regex
+// splits and replacements, since this is on the hot path for ParseGeneric.
+func parseBySeparator(version string, convert decimalStringConverter) []string {
+	// Most versions alternate between a single character separator and a
+	// number, so this is usually enough room for every segment plus the
+	// extra segment ParseGeneric appends.
+	return appendBySeparator(make([]string, 0, len(version)/2+2), version, convert)
+}
+
+// appendBySeparator is parseBySeparator, but appends the segments to parsed.
+func appendBySeparator(parsed []string, version string, convert decimalStringConverter) []string {
+	start := 0
+	class := runeClassSeparator
+	for i, r := range version {
+		c := classifyRune(r)
+		if c == class {
+			continue
+		}
+		if class != runeClassSeparator {
+			parsed = maybeAppendDecimalString(parsed, version[start:i], convert)
 		}
+		start = i
+		class = c
 	}
+	if class != runeClassSeparator {
+		parsed = maybeAppendDecimalString(parsed, version[start:], convert)
+	}
+
 	return parsed
 }
 
+type runeClass int
+
+const (
+	runeClassSeparator runeClass = iota
+	runeClassDigit
+	runeClassOther
+)
+
+func classifyRune(r rune) runeClass {
+	switch {
+	case r >= '0' && r <= '9':
+		return runeClassDigit
+	case r < utf8.RuneSelf:
+		if asciiPunctuationOrSeparator[r] {
+			return runeClassSeparator
+		}
+		return runeClassOther
+	case unicode.In(r, unicode.P, unicode.Z):
+		return runeClassSeparator
+	default:
+		return runeClassOther
+	}
+}
+
+// asciiPunctuationOrSeparator is a lookup table for the ASCII characters in
+// the \pP and \pZ Unicode classes.
+var asciiPunctuationOrSeparator = func() [utf8.RuneSelf]bool {
+	var table [utf8.RuneSelf]bool
+	for r := rune(0); r < utf8.RuneSelf; r++ {
+		table[r] = unicode.In(r, unicode.P, unicode.Z)
+	}
+	return table
+}()
+
 // maybeAppendDecimalString appends the string representation of a decimal
 // number to the given string slice, if s is not the empty string. The convert
 // converts a string to the proper decimal string form, which can be specific
@@ -181,25 +311,47 @@ func maybeAppendDecimalString(slice []string, s string, convert decimalStringCon
 	return append(slice, normalizeDecimal(s))
 }
 
+// isNumber returns true if s is a decimal number made of ASCII digits with at
+// most one ".", and at least one digit before any ".", or after it if it is
+// the first character. That is, "1", "1.", "1.2", and ".2" are numbers.
 func isNumber(s string) bool {
-	return decimalNumber.MatchString(s)
+	digitsBefore, digitsAfter, dots := 0, 0, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			if dots == 0 {
+				digitsBefore++
+			} else {
+				digitsAfter++
+			}
+		case c == '.':
+			dots++
+			if dots > 1 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return digitsBefore > 0 || (digitsAfter > 0 && s[0] == '.')
 }
 
 func normalizeDecimal(s string) string {
 	// Any leading and trailing zeroes don't change the value of the decimal,
 	// so strip them off to have a canonical string representation of the
-	// decimal
-	parts := strings.Split(s, ".")
-
-	var normalized string
-	if notZero.MatchString(parts[0]) {
-		normalized = strings.TrimLeft(parts[0], "0")
-	} else {
-		normalized = "0"
+	// decimal. Anything after a second "." is ignored.
+	integer, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		integer, fraction = s[:i], s[i+1:]
+		if strings.IndexByte(fraction, '.') >= 0 {
+			fraction = ""
+		}
 	}
 
-	if len(parts) == 2 && notZero.MatchString(parts[1]) {
-		normalized = fmt.Sprintf("%s.%s", normalized, strings.TrimRight(parts[1], "0"))
+	normalized := removeLeadingZeros(integer)
+
+	if fraction = strings.TrimRight(fraction, "0"); fraction != "" {
+		normalized = normalized + "." + fraction
 	}
 
 	return normalized
@@ -214,7 +366,11 @@ func toDecimalStringWithGenericPreReleaseIdentifierHandling(s string) string {
 }
 
 func toDecimalString(s string) string {
-	decimal := ""
+	// Each rune takes at most 10 digits, plus one byte for the decimal point.
+	var b strings.Builder
+	b.Grow(10*utf8.RuneCountInString(s) + 1)
+
+	var digits [20]byte
 	runeIndex := 0
 	// The index returned when iterating over a string is the starting byte of
 	// the current rune, which will jump by the number of bytes of the
@@ -222,21 +378,32 @@ func toDecimalString(s string) string {
 	// ourself.
 	for _, r := range s {
 		if runeIndex == 0 {
-			decimal = fmt.Sprintf("%d", r)
+			b.Write(strconv.AppendInt(digits[:0], int64(r), 10))
 			runeIndex++
 			continue
 		}
 
 		if runeIndex == 1 {
-			decimal += "."
+			b.WriteByte('.')
 		}
 
 		// Pad to 10 digits using zeros because Unicode characters are 32-bit
 		// integers and a 32-bit integer is a maximum of 10 digits long.
-		decimal += fmt.Sprintf("%010d", r)
+		writeZeroPadded(&b, digits[:0], int64(r), 10)
 		runeIndex++
 	}
-	return decimal
+	return b.String()
+}
+
+// writeZeroPadded writes n to b, left padded with zeros to width digits. It
+// produces the same output as fmt.Sprintf("%0*d", width, n) for non-negative
+// n. The scratch slice is used to format n without allocating.
+func writeZeroPadded(b *strings.Builder, scratch []byte, n int64, width int) {
+	digits := strconv.AppendInt(scratch, n, 10)
+	for i := len(digits); i < width; i++ {
+		b.WriteByte('0')
+	}
+	b.Write(digits)
 }
 
 func containsGenericPreReleaseIdentifierValue(numbers []string) bool {
@@ -251,19 +418,24 @@ func containsGenericPreReleaseIdentifierValue(numbers []string) bool {
 }
 
 func asciiToDecimalString(s string) string {
-	decimal := ""
+	// Each character takes at most 3 digits, plus one byte for the decimal
+	// point.
+	var b strings.Builder
+	b.Grow(3*len(s) + 1)
+
+	var digits [20]byte
 	for i, r := range s {
 		if i == 0 {
-			decimal = fmt.Sprintf("%d", r)
+			b.Write(strconv.AppendInt(digits[:0], int64(r), 10))
 			continue
 		}
 
 		if i == 1 {
-			decimal += "."
+			b.WriteByte('.')
 		}
 
 		// Pad to 3 digits because ASCII characters are at most 3 digits
-		decimal += fmt.Sprintf("%03d", r)
+		writeZeroPadded(&b, digits[:0], int64(r), 3)
 	}
-	return decimal
+	return b.String()
 }
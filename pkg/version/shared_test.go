@@ -2,6 +2,8 @@ package version
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/ericlagergren/decimal"
@@ -9,47 +11,47 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestParseGeneric(t *testing.T) {
-	tests := []struct {
-		name     string
-		version  string
-		expected []string
-	}{
-		{"Numbers", "0", []string{"0"}},
-		{"Numbers", "1", []string{"1"}},
-		{"Numbers", "1.0", []string{"1"}},
-		{"Numbers", "0.92", []string{"0", "92"}},
-		{"Numbers", "1-1.2", []string{"1", "1", "2"}},
-		{"Sequential Dots", "1..2", []string{"1", "2"}},
-		{"Sequential Dashes", "1--2", []string{"1", "2"}},
-		{"Sequential Dot Dash", "1.-2", []string{"1", "2"}},
-		{"Uppercase A", "A1", []string{"65", "1"}},
-		{"Lowercase a", "a1", []string{"97", "1"}},
-		{"Single Unicode", "小1", []string{"23567", "1"}},
-		{"Ascii Word", "1.0bet", []string{"1", "0", "98.00000001010000000116"}},
-		{"Unicode Word", "小寸-1.1", []string{"23567.0000023544", "1", "1"}},
-		{"Unicode Separators", "1 2\u20013\u2002\u20034", []string{"1", "2", "3", "4"}},
-		{"Normalizes Unicode", "e\u0301", []string{"233"}},
-		{
-			"Splits On Space",
-			"10 Generic 142910-17",
-			[]string{
-				"10",
-				"71.000000010100000001100000000101000000011400000001050000000099",
-				"142910",
-				"17",
-			},
+var parseGenericTests = []struct {
+	name     string
+	version  string
+	expected []string
+}{
+	{"Numbers", "0", []string{"0"}},
+	{"Numbers", "1", []string{"1"}},
+	{"Numbers", "1.0", []string{"1"}},
+	{"Numbers", "0.92", []string{"0", "92"}},
+	{"Numbers", "1-1.2", []string{"1", "1", "2"}},
+	{"Sequential Dots", "1..2", []string{"1", "2"}},
+	{"Sequential Dashes", "1--2", []string{"1", "2"}},
+	{"Sequential Dot Dash", "1.-2", []string{"1", "2"}},
+	{"Uppercase A", "A1", []string{"65", "1"}},
+	{"Lowercase a", "a1", []string{"97", "1"}},
+	{"Single Unicode", "小1", []string{"23567", "1"}},
+	{"Ascii Word", "1.0bet", []string{"1", "0", "98.00000001010000000116"}},
+	{"Unicode Word", "小寸-1.1", []string{"23567.0000023544", "1", "1"}},
+	{"Unicode Separators", "1 2\u20013\u2002\u20034", []string{"1", "2", "3", "4"}},
+	{"Normalizes Unicode", "e\u0301", []string{"233"}},
+	{
+		"Splits On Space",
+		"10 Generic 142910-17",
+		[]string{
+			"10",
+			"71.000000010100000001100000000101000000011400000001050000000099",
+			"142910",
+			"17",
 		},
-		{"Drops Leading Zeros", "100.02.01", []string{"100", "2", "1"}},
-		{"Pre-Release Identifier", "1.0-alpha", []string{"1", "0", "-26"}},
-		{"Pre-Release Identifier Ignores Case", "1.0-AlPHa", []string{"1", "0", "-26"}},
-		{"Pre-Release Identifier In Middle", "1.0-alpha.1", []string{"1", "0", "-26", "1"}},
-		{"2 Pre-Release Identifiers", "1.0-alpha.beta", []string{"1", "0", "-26", "-25"}},
-		{"Pre-Release Identifier Beta", "1.0-beta", []string{"1", "0", "-25"}},
-		{"Pre-Release Identifier RC", "1.0-rc", []string{"1", "0", "-1"}},
-	}
+	},
+	{"Drops Leading Zeros", "100.02.01", []string{"100", "2", "1"}},
+	{"Pre-Release Identifier", "1.0-alpha", []string{"1", "0", "-26"}},
+	{"Pre-Release Identifier Ignores Case", "1.0-AlPHa", []string{"1", "0", "-26"}},
+	{"Pre-Release Identifier In Middle", "1.0-alpha.1", []string{"1", "0", "-26", "1"}},
+	{"2 Pre-Release Identifiers", "1.0-alpha.beta", []string{"1", "0", "-26", "-25"}},
+	{"Pre-Release Identifier Beta", "1.0-beta", []string{"1", "0", "-25"}},
+	{"Pre-Release Identifier RC", "1.0-rc", []string{"1", "0", "-1"}},
+}
 
-	for _, tt := range tests {
+func TestParseGeneric(t *testing.T) {
+	for _, tt := range parseGenericTests {
 		t.Run(tt.name, func(t *testing.T) {
 			actual, err := ParseGeneric(tt.version)
 			require.NoError(t, err)
@@ -99,50 +101,50 @@ func TestParseGenericParsesOpenSSLVersionsCorrectly(t *testing.T) {
 	assert.True(t, Compare(baseB, baseC) < 0)
 }
 
-func TestParseSemVer(t *testing.T) {
-	tests := map[string]struct {
-		version  string
-		expected []string
-	}{
-		"One Section Is Error": {
-			version:  "1",
-			expected: []string{},
-		},
-		"Two Sections Is Error": {
-			version:  "1.0",
-			expected: []string{},
-		},
-		"Number cannot have leading zero": {
-			version:  "01.2.3",
-			expected: []string{},
-		},
-		"Another invalid input": {
-			version:  "0.0.0-.",
-			expected: []string{},
-		},
-		"Parses Major.Minor.Patch": {
-			version:  "1.2.3",
-			expected: []string{"1", "2", "3"},
-		},
-		"Parses PreReleaseIdentifer": {
-			version:  "1.2.3-a.1",
-			expected: []string{"1", "2", "3", "-1", "97", "0", "1", "-1"},
-		},
-		"Parses alpha as pre-release": {
-			version:  "1.2.3-alpha",
-			expected: []string{"1", "2", "3", "-1", "97.108112104097", "-1"},
-		},
-		"Build Metadata Is Ignored": {
-			version:  "1.2.3+ignored",
-			expected: []string{"1", "2", "3"},
-		},
-		"Parses When All Sections Present": {
-			version:  "1.2.3-a.1+ignored",
-			expected: []string{"1", "2", "3", "-1", "97", "0", "1", "-1"},
-		},
-	}
+var parseSemVerTests = map[string]struct {
+	version  string
+	expected []string
+}{
+	"One Section Is Error": {
+		version:  "1",
+		expected: []string{},
+	},
+	"Two Sections Is Error": {
+		version:  "1.0",
+		expected: []string{},
+	},
+	"Number cannot have leading zero": {
+		version:  "01.2.3",
+		expected: []string{},
+	},
+	"Another invalid input": {
+		version:  "0.0.0-.",
+		expected: []string{},
+	},
+	"Parses Major.Minor.Patch": {
+		version:  "1.2.3",
+		expected: []string{"1", "2", "3"},
+	},
+	"Parses PreReleaseIdentifer": {
+		version:  "1.2.3-a.1",
+		expected: []string{"1", "2", "3", "-1", "97", "0", "1", "-1"},
+	},
+	"Parses alpha as pre-release": {
+		version:  "1.2.3-alpha",
+		expected: []string{"1", "2", "3", "-1", "97.108112104097", "-1"},
+	},
+	"Build Metadata Is Ignored": {
+		version:  "1.2.3+ignored",
+		expected: []string{"1", "2", "3"},
+	},
+	"Parses When All Sections Present": {
+		version:  "1.2.3-a.1+ignored",
+		expected: []string{"1", "2", "3", "-1", "97", "0", "1", "-1"},
+	},
+}
 
-	for name, test := range tests {
+func TestParseSemVer(t *testing.T) {
+	for name, test := range parseSemVerTests {
 		t.Run(name, func(t *testing.T) {
 			actual, err := ParseSemVer(test.version)
 			if len(test.expected) == 0 {
@@ -211,20 +213,6 @@ var testParseSemVerOrderInputs = []string{
 	"9.9.9-alpha.0.pr.1",
 }
 
-func TestParseSemVerOrdering(t *testing.T) {
-	for i := 0; i < len(testParseSemVerOrderInputs)-1; i++ {
-		v1 := parseOrFatalSemVer(t, testParseSemVerOrderInputs[i])
-		v2 := parseOrFatalSemVer(t, testParseSemVerOrderInputs[i+1])
-		assert.True(
-			t,
-			Compare(v1, v2) < 0,
-			"%v should be less than %v",
-			testParseSemVerOrderInputs[i],
-			testParseSemVerOrderInputs[i+1],
-		)
-	}
-}
-
 func TestIsNumber(t *testing.T) {
 	assert.True(t, isNumber("1"))
 	assert.True(t, isNumber("1.0"))
@@ -412,3 +400,172 @@ func mustStringsToDecimal(t *testing.T, s []string) []*decimal.Big {
 
 	return d
 }
+
+func TestParseGenericWithIgnoreBuildMetadata(t *testing.T) {
+	a, err := ParseGeneric("1.4.2+a", WithIgnoreBuildMetadata())
+	require.NoError(t, err)
+	b, err := ParseGeneric("1.4.2+b", WithIgnoreBuildMetadata())
+	require.NoError(t, err)
+	plain, err := ParseGeneric("1.4.2", WithIgnoreBuildMetadata())
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, Compare(a, b), "1.4.2+a == 1.4.2+b")
+	assert.Equal(t, 0, Compare(a, plain), "1.4.2+a == 1.4.2")
+	assert.Equal(t, "1.4.2+a", a.Original, "Original includes the build metadata")
+	assert.Equal(t, "a", a.BuildMetadata)
+	assert.Equal(t, "", plain.BuildMetadata)
+
+	long, err := ParseGeneric("1.4.2+20230917.abcdef", WithIgnoreBuildMetadata())
+	require.NoError(t, err)
+	assertDecimalEqualString(t, []string{"1", "4", "2"}, long.Decimal)
+	assert.Equal(t, "20230917.abcdef", long.BuildMetadata)
+	assert.Equal(t, long.BuildMetadata, long.Clone().BuildMetadata, "Clone copies BuildMetadata")
+
+	cpp, err := ParseGeneric("c++-1.0", WithIgnoreBuildMetadata())
+	require.NoError(t, err)
+	assert.Equal(t, 0, Compare(cpp, parseOrFatalGeneric(t, "c++-1.0")), "a + before any digit is not build metadata")
+	assert.Equal(t, "", cpp.BuildMetadata)
+}
+
+func TestParseGenericWithoutIgnoreBuildMetadata(t *testing.T) {
+	a := parseOrFatalGeneric(t, "1.4.2+a")
+	b := parseOrFatalGeneric(t, "1.4.2+b")
+
+	assert.True(t, Compare(a, b) < 0, "1.4.2+a < 1.4.2+b")
+	assertDecimalEqualString(t, []string{"1", "4", "2", "43.0000000097"}, a.Decimal)
+	assert.Equal(t, "", a.BuildMetadata)
+}
+
+func TestParseBySeparatorAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not meaningful with the race detector")
+	}
+
+	tests := []struct {
+		version   string
+		maxAllocs float64
+	}{
+		{"1.2.3", 4},
+		{"1.0.0-alpha.beta", 6},
+		{"1.1.0c", 6},
+	}
+
+	for _, tt := range tests {
+		allocs := testing.AllocsPerRun(100, func() {
+			parseBySeparator(tt.version, toDecimalStringWithGenericPreReleaseIdentifierHandling)
+		})
+		assert.True(t, allocs <= tt.maxAllocs, "parsing %s allocates at most %v times (got %v)", tt.version, tt.maxAllocs, allocs)
+	}
+}
+
+func TestToDecimalStringMatchesSprintf(t *testing.T) {
+	// The reference implementations the builder based versions replaced.
+	sprintfToDecimalString := func(s string) string {
+		decimal := ""
+		runeIndex := 0
+		for _, r := range s {
+			if runeIndex == 0 {
+				decimal = fmt.Sprintf("%d", r)
+			} else {
+				if runeIndex == 1 {
+					decimal += "."
+				}
+				decimal += fmt.Sprintf("%010d", r)
+			}
+			runeIndex++
+		}
+		return decimal
+	}
+	sprintfASCIIToDecimalString := func(s string) string {
+		decimal := ""
+		for i, r := range s {
+			if i == 0 {
+				decimal = fmt.Sprintf("%d", r)
+				continue
+			}
+			if i == 1 {
+				decimal += "."
+			}
+			decimal += fmt.Sprintf("%03d", r)
+		}
+		return decimal
+	}
+
+	inputs := []string{
+		"", "a", "z", "ab", "alpha", "snapshotbuildrelease", "Z9~",
+		"ñ", "ña", "日本語", "a日本", "\U0010FFFF", "\xff", "a\xffb",
+	}
+	for _, in := range inputs {
+		assert.Equal(t, sprintfToDecimalString(in), toDecimalString(in), "toDecimalString(%q)", in)
+		assert.Equal(t, sprintfASCIIToDecimalString(in), asciiToDecimalString(in), "asciiToDecimalString(%q)", in)
+	}
+}
+
+func TestToDecimalStringAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not meaningful with the race detector")
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		toDecimalString("snapshotbuildrelease")
+	})
+	assert.True(t, allocs <= 1, "toDecimalString allocates at most once (got %v)", allocs)
+
+	allocs = testing.AllocsPerRun(100, func() {
+		asciiToDecimalString("snapshotbuildrelease")
+	})
+	assert.True(t, allocs <= 1, "asciiToDecimalString allocates at most once (got %v)", allocs)
+}
+
+func TestNormalizeDecimalMatchesSprintf(t *testing.T) {
+	// The reference implementation the allocation free version replaced.
+	notZero := regexp.MustCompile(`[^0]`)
+	sprintfNormalizeDecimal := func(s string) string {
+		parts := strings.Split(s, ".")
+
+		var normalized string
+		if notZero.MatchString(parts[0]) {
+			normalized = strings.TrimLeft(parts[0], "0")
+		} else {
+			normalized = "0"
+		}
+
+		if len(parts) == 2 && notZero.MatchString(parts[1]) {
+			normalized = fmt.Sprintf("%s.%s", normalized, strings.TrimRight(parts[1], "0"))
+		}
+
+		return normalized
+	}
+
+	inputs := []string{
+		"", "0", "00", "1", "01", "10", "100", "-26", "-0", ".", "0.", ".0",
+		"1.", ".5", "0.50", "01.010", "1.2.3", "1..2", "0.0.0", "97.0000000108",
+	}
+	inputs = append(inputs, normalizeDecimalBenchmarkStrings...)
+	for _, in := range inputs {
+		assert.Equal(t, sprintfNormalizeDecimal(in), normalizeDecimal(in), "normalizeDecimal(%q)", in)
+	}
+}
+
+func TestNormalizeDecimalAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not meaningful with the race detector")
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		normalizeDecimal("007")
+	})
+	assert.Equal(t, float64(0), allocs, "normalizeDecimal does not allocate for integers")
+
+	allocs = testing.AllocsPerRun(100, func() {
+		normalizeDecimal("01.50")
+	})
+	assert.True(t, allocs <= 1, "normalizeDecimal allocates at most once (got %v)", allocs)
+}
+
+func TestSubexpIndex(t *testing.T) {
+	regex := regexp.MustCompile(`(?P<major>\d+)\.(\d+)(?:-(?P<pre>\w+))?`)
+	assert.Equal(t, 1, subexpIndex(regex, "major"))
+	assert.Equal(t, 3, subexpIndex(regex, "pre"))
+	assert.Panics(t, func() { subexpIndex(regex, "minor") })
+}
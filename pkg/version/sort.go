@@ -0,0 +1,168 @@
+package version
+
+import (
+	"sort"
+	"sync"
+)
+
+// StrictCompare controls whether the sorting funcs in this package check that
+// all of the versions they are given can be compared with each other (see
+// Comparable) before sorting. When this is true and a slice mixes
+// incomparable types, the sorting funcs return an *IncomparableError and
+// leave the slice unchanged.
+//
+// This defaults to false for backwards compatibility. It should be set once,
+// before any sorting happens, as it is not safe to change while other
+// goroutines are sorting.
+var StrictCompare = false
+
+// Sort sorts vs in ascending order using Compare. The sort is stable, so
+// versions which compare as equal (e.g. "1.2" and "1.2.0") keep their
+// original relative order.
+//
+// If StrictCompare is true, Sort returns an error without sorting if vs
+// contains versions which cannot be compared with each other.
+func Sort(vs []*Version) error {
+	if err := checkSortable(vs); err != nil {
+		return err
+	}
+
+	sort.SliceStable(vs, func(i, j int) bool {
+		return Compare(vs[i], vs[j]) < 0
+	})
+	return nil
+}
+
+// checkSortable returns an error if StrictCompare is true and vs contains
+// versions that are not comparable with each other.
+func checkSortable(vs []*Version) error {
+	if !StrictCompare || len(vs) == 0 {
+		return nil
+	}
+
+	// Raw versions are comparable with everything, so the first version of
+	// any other type is the one the rest must be comparable with.
+	first := Raw
+	for _, v := range vs {
+		if first == Raw {
+			first = v.ParsedAs
+			continue
+		}
+		if !Comparable(first, v.ParsedAs) {
+			return &IncomparableError{ParsedAs1: first, ParsedAs2: v.ParsedAs}
+		}
+	}
+	return nil
+}
+
+// parallelSortThreshold is the smallest number of versions per worker for
+// which SortParallel uses more than one goroutine. Below this, starting
+// goroutines and merging their results costs more than it saves.
+const parallelSortThreshold = 1 << 14
+
+// SortParallel sorts vs into the same order as Sort, using up to workers
+// goroutines. Values of workers less than one are treated as one.
+//
+// Like SortByKey, it computes each version's CompareKey up front. The slice is
+// split into one part per worker, each part is keyed and sorted on its own
+// goroutine, and the sorted parts are then merged in pairs, also in parallel.
+// Equal versions keep their original relative order, so the result does not
+// depend on the number of workers. The Versions themselves are not modified.
+//
+// If StrictCompare is true, SortParallel returns an error without sorting if
+// vs contains versions which cannot be compared with each other.
+func SortParallel(vs []*Version, workers int) error {
+	if err := checkSortable(vs); err != nil {
+		return err
+	}
+
+	if limit := len(vs) / parallelSortThreshold; workers > limit {
+		workers = limit
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	// bounds holds the start of each sorted run, followed by len(vs).
+	bounds := make([]int, workers+1)
+	for i := range bounds {
+		bounds[i] = i * len(vs) / workers
+	}
+
+	keyed := make(keyedVersions, len(vs))
+	inParallel(workers, func(i int) {
+		lo, hi := bounds[i], bounds[i+1]
+		fillKeyedVersions(keyed[lo:hi], vs[lo:hi], lo)
+		sort.Sort(keyed[lo:hi])
+	})
+
+	if workers > 1 {
+		scratch := make(keyedVersions, len(keyed))
+		for len(bounds) > 2 {
+			src, dst := keyed, scratch
+			runs := len(bounds) - 1
+			inParallel((runs+1)/2, func(p int) {
+				lo, mid := bounds[2*p], bounds[2*p+1]
+				if 2*p+2 >= len(bounds) {
+					// An odd run out has nothing to merge with.
+					copy(dst[lo:mid], src[lo:mid])
+					return
+				}
+				hi := bounds[2*p+2]
+				mergeKeyedVersions(dst[lo:hi], src[lo:mid], src[mid:hi])
+			})
+
+			merged := make([]int, 0, (runs+1)/2+1)
+			for i := 0; i < len(bounds); i += 2 {
+				merged = append(merged, bounds[i])
+			}
+			if merged[len(merged)-1] != len(vs) {
+				merged = append(merged, len(vs))
+			}
+			bounds = merged
+			keyed, scratch = scratch, keyed
+		}
+	}
+
+	for i, k := range keyed {
+		vs[i] = k.version
+	}
+	return nil
+}
+
+// inParallel calls f(0) through f(n-1), each on its own goroutine, and waits
+// for them all to return.
+func inParallel(n int, f func(i int)) {
+	if n == 1 {
+		f(0)
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			f(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// mergeKeyedVersions merges the sorted slices a and b into dst, which must be
+// exactly as long as both of them together.
+func mergeKeyedVersions(dst, a, b keyedVersions) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if b[j].less(&a[i]) {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
@@ -0,0 +1,140 @@
+package version
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSort(t *testing.T) {
+	versions := make([]*Version, len(testParseSemVerOrderInputs))
+	for i, s := range testParseSemVerOrderInputs {
+		versions[i] = parseOrFatalSemVer(t, s)
+	}
+
+	shuffled := make([]*Version, len(versions))
+	copy(shuffled, versions)
+	r := rand.New(rand.NewSource(42))
+	r.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	require.NoError(t, Sort(shuffled))
+	assert.Equal(t, versions, shuffled)
+}
+
+func TestSortIsStable(t *testing.T) {
+	versions := []*Version{
+		parseOrFatalGeneric(t, "1.2.0"),
+		parseOrFatalGeneric(t, "1.1"),
+		parseOrFatalGeneric(t, "1.2"),
+		parseOrFatalGeneric(t, "1.2.0.0"),
+	}
+
+	require.NoError(t, Sort(versions))
+	var originals []string
+	for _, v := range versions {
+		originals = append(originals, v.Original)
+	}
+	assert.Equal(t, []string{"1.1", "1.2.0", "1.2", "1.2.0.0"}, originals)
+}
+
+func TestSortWithStrictCompare(t *testing.T) {
+	defer func(strict bool) { StrictCompare = strict }(StrictCompare)
+
+	mixed := []*Version{
+		parseOrFatalSemVer(t, "2.0.0"),
+		parsePythonOrFatal(t, "1.0"),
+	}
+
+	StrictCompare = false
+	assert.NoError(t, Sort(mixed), "mixed types are sorted when StrictCompare is false")
+
+	mixed = []*Version{
+		parseOrFatalSemVer(t, "2.0.0"),
+		parsePythonOrFatal(t, "1.0"),
+	}
+	StrictCompare = true
+	assert.Error(t, Sort(mixed), "mixed types are an error when StrictCompare is true")
+	assert.Equal(t, "2.0.0", mixed[0].Original, "slice is unchanged after an error")
+
+	python := []*Version{
+		parsePythonOrFatal(t, "1.0"),
+		parsePythonOrFatal(t, "0.9-foo"),
+	}
+	require.NoError(t, Sort(python), "legacy and PEP440 python versions can be sorted together")
+	assert.Equal(t, "0.9-foo", python[0].Original)
+}
+
+func TestSortParallelMatchesSort(t *testing.T) {
+	var corpus []*Version
+	for _, versions := range keyTestCorpora(t) {
+		corpus = append(corpus, versions...)
+	}
+
+	// Copy the corpus until there is enough for several workers. Every
+	// Version is distinct, so the positions of equal versions show whether
+	// the sort is stable.
+	var shuffled []*Version
+	for len(shuffled) < 3*parallelSortThreshold+17 {
+		for _, v := range corpus {
+			c := *v
+			shuffled = append(shuffled, &c)
+		}
+	}
+	rand.New(rand.NewSource(1)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	before := make([]string, len(shuffled))
+	for i, v := range shuffled {
+		before[i] = v.String()
+	}
+
+	expected := make([]*Version, len(shuffled))
+	copy(expected, shuffled)
+	require.NoError(t, Sort(expected))
+
+	for _, workers := range []int{-1, 0, 1, 2, 3, 4, 7, 1000} {
+		actual := make([]*Version, len(shuffled))
+		copy(actual, shuffled)
+		require.NoError(t, SortParallel(actual, workers))
+
+		for i := range expected {
+			if expected[i] != actual[i] {
+				t.Fatalf("with %d workers %s sorted into position %d", workers, expected[i], i)
+			}
+		}
+	}
+
+	for i, v := range shuffled {
+		assert.Equal(t, before[i], v.String(), "versions are not modified")
+	}
+}
+
+func TestSortParallelSmallSlices(t *testing.T) {
+	for n := 0; n < 5; n++ {
+		vs := make([]*Version, n)
+		for i := range vs {
+			vs[i] = parseOrFatalGeneric(t, fmt.Sprintf("%d", n-i))
+		}
+		require.NoError(t, SortParallel(vs, 4))
+		for i, v := range vs {
+			assert.Equal(t, fmt.Sprintf("%d", i+1), v.Original)
+		}
+	}
+}
+
+func TestSortParallelWithStrictCompare(t *testing.T) {
+	defer func(strict bool) { StrictCompare = strict }(StrictCompare)
+	StrictCompare = true
+
+	vs := []*Version{parseOrFatalGeneric(t, "2"), parsePythonOrFatal(t, "1")}
+	err := SortParallel(vs, 4)
+	require.Error(t, err)
+	assert.IsType(t, &IncomparableError{}, err)
+	assert.Equal(t, "2", vs[0].Original, "slice is unchanged")
+}
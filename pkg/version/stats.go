@@ -0,0 +1,85 @@
+package version
+
+// Segments made from words by ParseGeneric and the other parsers have up to
+// 10 digits after the decimal point for each letter after the first, and
+// there is no limit on the length of a word. These constants describe the
+// most digits a segment can need for a version string of a given length with
+// the current encodings. See WorstCaseDigits.
+const (
+	// minIntegerDigits covers segments whose integer part does not come from
+	// the digits of a version string: the code point of the first letter of
+	// a word, which has at most 7 digits, and the 1000000000 segment that
+	// ParsePHP adds to datetime versions.
+	minIntegerDigits = 10
+	// fractionalDigitsPerByte is the most fractional digits each byte of a
+	// version string can add to a segment.
+	fractionalDigitsPerByte = 10
+	// maxAddedFractionalDigits covers words that parsers add to a version,
+	// such as the "*final-" that ParsePython adds to legacy versions.
+	maxAddedFractionalDigits = 60
+)
+
+// DigitStats describes the size of the segments of one or more versions, for
+// sizing database columns such as a Postgres numeric[] column.
+type DigitStats struct {
+	// IntegerDigits is the most digits before the decimal point in any
+	// segment.
+	IntegerDigits int
+	// FractionalDigits is the most digits after the decimal point in any
+	// segment.
+	FractionalDigits int
+	// Segments is the most segments in any version.
+	Segments int
+}
+
+// MaxDigits returns the most digits before and after the decimal point in
+// any of v's segments. A column of type NUMERIC(p, s) can hold every segment
+// if s >= fractionalDigits and p - s >= integerDigits.
+func (v *Version) MaxDigits() (integerDigits, fractionalDigits int) {
+	for _, d := range v.Decimal {
+		scale := d.Scale()
+		if i := d.Precision() - scale; i > integerDigits {
+			integerDigits = i
+		}
+		if scale > fractionalDigits {
+			fractionalDigits = scale
+		}
+	}
+	return integerDigits, fractionalDigits
+}
+
+// Stats returns the most digits before and after the decimal point in any
+// segment of the versions in vs, and the most segments in any one version.
+func Stats(vs []*Version) DigitStats {
+	var s DigitStats
+	for _, v := range vs {
+		integerDigits, fractionalDigits := v.MaxDigits()
+		if integerDigits > s.IntegerDigits {
+			s.IntegerDigits = integerDigits
+		}
+		if fractionalDigits > s.FractionalDigits {
+			s.FractionalDigits = fractionalDigits
+		}
+		if len(v.Decimal) > s.Segments {
+			s.Segments = len(v.Decimal)
+		}
+	}
+	return s
+}
+
+// WorstCaseDigits returns the most digits before and after the decimal point
+// that a segment of a version parsed from original can have with the
+// current encodings. This is at most max(10, len(original)) digits before the
+// decimal point and 10*len(original) + 60 digits after it.
+//
+// There is no fixed upper bound, since long words make segments with long
+// fractions. Postgres numeric values can have at most 16383 digits after the
+// decimal point, so versions with words of more than about 1600 letters
+// cannot be stored in a numeric[] column.
+func WorstCaseDigits(original string) (integerDigits, fractionalDigits int) {
+	integerDigits = len(original)
+	if integerDigits < minIntegerDigits {
+		integerDigits = minIntegerDigits
+	}
+	return integerDigits, fractionalDigitsPerByte*len(original) + maxAddedFractionalDigits
+}
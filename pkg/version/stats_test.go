@@ -0,0 +1,95 @@
+package version
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxDigits(t *testing.T) {
+	tests := []struct {
+		version             *Version
+		integer, fractional int
+	}{
+		{parseOrFatalSemVer(t, "1.22.333"), 3, 0},
+		{parseOrFatalGeneric(t, "1.0bet"), 2, 20},
+		{parsePerlOrFatal(t, "1.002003"), 1, 0},
+		{&Version{Decimal: mustStringsToDecimal(t, []string{"-12.50", "0.001"})}, 2, 3},
+		{&Version{Decimal: mustStringsToDecimal(t, []string{"1E+3"})}, 4, 0},
+	}
+
+	for _, tt := range tests {
+		integer, fractional := tt.version.MaxDigits()
+		assert.Equal(t, tt.integer, integer, "integer digits of %s", tt.version.Decimal)
+		assert.Equal(t, tt.fractional, fractional, "fractional digits of %s", tt.version.Decimal)
+	}
+}
+
+func TestStats(t *testing.T) {
+	assert.Equal(t, DigitStats{}, Stats(nil))
+
+	vs := []*Version{
+		parseOrFatalSemVer(t, "1.22.333"),
+		parseOrFatalGeneric(t, "1.0bet"),
+		parseOrFatalGeneric(t, "1.2.3.4.5"),
+	}
+	assert.Equal(t, DigitStats{IntegerDigits: 3, FractionalDigits: 20, Segments: 5}, Stats(vs))
+}
+
+func statsTestVersions(t *testing.T) []*Version {
+	var vs []*Version
+	for _, corpus := range keyTestCorpora(t) {
+		vs = append(vs, corpus...)
+	}
+	for _, s := range perlDecimalBenchmarkStrings {
+		vs = append(vs, parsePerlOrFatal(t, s))
+	}
+	for _, s := range perlVStringBenchmarkStrings {
+		vs = append(vs, parsePerlOrFatal(t, s))
+	}
+
+	pieces := []string{
+		"0", "1", "99", "2020", "123456789012345678901234567890", ".", "-", "_",
+		"+", "!", " ", "a", "alpha", "RC", "post", "dev", "final", "pl", "小",
+		"é", "\U0010FFFF",
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		var b strings.Builder
+		for n := r.Intn(8); n >= 0; n-- {
+			b.WriteString(pieces[r.Intn(len(pieces))])
+		}
+		s := b.String()
+		for _, parse := range []func(string) (*Version, error){
+			func(s string) (*Version, error) { return ParseGeneric(s) },
+			ParsePython,
+			ParseRuby,
+			ParseSemVer,
+			ParsePerl,
+			func(s string) (*Version, error) { return ParsePHP(s) },
+		} {
+			if v, err := parse(s); err == nil {
+				vs = append(vs, v)
+			}
+		}
+	}
+	return vs
+}
+
+// If this fails then an encoding change has made segments bigger than
+// WorstCaseDigits says they can be, which may break database columns that
+// were sized using it.
+func TestWorstCaseDigitsIsNotExceeded(t *testing.T) {
+	vs := statsTestVersions(t)
+	for _, v := range vs {
+		integer, fractional := v.MaxDigits()
+		maxInteger, maxFractional := WorstCaseDigits(v.Original)
+		assert.True(t, integer <= maxInteger, "%s has %d integer digits, more than %d", v, integer, maxInteger)
+		assert.True(t, fractional <= maxFractional, "%s has %d fractional digits, more than %d", v, fractional, maxFractional)
+	}
+
+	stats := Stats(vs)
+	t.Logf("stats for %d versions: %+v", len(vs), stats)
+}
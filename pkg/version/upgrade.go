@@ -0,0 +1,150 @@
+package version
+
+import (
+	"fmt"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// UpgradeKind describes how big a change an upgrade between two versions is,
+// as returned by ClassifyUpgrade.
+type UpgradeKind int
+
+const (
+	// UpgradePreReleaseOnly means the versions have the same release numbers
+	// and only their pre-release parts differ, as in 2.0.0-rc.1 to
+	// 2.0.0-rc.2, or 2.0.0-rc.2 to 2.0.0.
+	UpgradePreReleaseOnly UpgradeKind = iota + 1
+	// UpgradePatch means the major and minor versions are the same.
+	UpgradePatch
+	// UpgradeMinor means the major versions are the same.
+	UpgradeMinor
+	// UpgradeMajor means the major versions differ.
+	UpgradeMajor
+)
+
+var upgradeKindNames = map[UpgradeKind]string{
+	UpgradePreReleaseOnly: "prerelease-only",
+	UpgradePatch:          "patch",
+	UpgradeMinor:          "minor",
+	UpgradeMajor:          "major",
+}
+
+func (k UpgradeKind) String() string {
+	if n, ok := upgradeKindNames[k]; ok {
+		return n
+	}
+	return fmt.Sprintf("UpgradeKind(%d)", int(k))
+}
+
+// UpgradeOption configures ClassifyUpgrade.
+type UpgradeOption func(*upgradeOptions)
+
+type upgradeOptions struct {
+	zeroMajorStrict bool
+}
+
+// WithZeroMajorStrict treats versions with a major version of 0 the way
+// caret ranges do, where anything before 1.0.0 may break at any time. With
+// it, a change of the minor version of a 0.x version is UpgradeMajor, as is a
+// change of the patch version of a 0.0.x version.
+func WithZeroMajorStrict() UpgradeOption {
+	return func(o *upgradeOptions) {
+		o.zeroMajorStrict = true
+	}
+}
+
+// ClassifyUpgrade returns the kind of upgrade from one version to another
+// version. Both must have been parsed as SemVer or Go, which may be mixed, or
+// both as Npm or both as Generic. Generic versions are classified on a best
+// effort basis. Each must start with at least two whole number segments,
+// counting missing segments as zero, and a change after the third segment, as
+// in 1.2.3.4 to 1.2.3.5, is UpgradePatch.
+//
+// It returns an error if to is not greater than from, if the versions were
+// parsed as types that are not comparable, or if either one cannot be
+// classified.
+func ClassifyUpgrade(from, to *Version, opts ...UpgradeOption) (UpgradeKind, error) {
+	var o upgradeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if family(from.ParsedAs) != family(to.ParsedAs) {
+		return 0, &IncomparableError{ParsedAs1: from.ParsedAs, ParsedAs2: to.ParsedAs}
+	}
+	if !canClassifyUpgrade(from.ParsedAs) {
+		return 0, fmt.Errorf("cannot classify an upgrade between %s versions", from.ParsedAs)
+	}
+	for _, v := range []*Version{from, to} {
+		if !hasMajorMinor(v) {
+			return 0, fmt.Errorf("cannot classify an upgrade from %s: it does not start with a major and minor version", v.Original)
+		}
+	}
+
+	switch cmp := Compare(from, to); {
+	case cmp == 0:
+		return 0, fmt.Errorf("%s to %s is not an upgrade, as the versions are equal", from.Original, to.Original)
+	case cmp > 0:
+		return 0, fmt.Errorf("%s to %s is a downgrade", from.Original, to.Original)
+	}
+
+	fromParts, toParts := releaseParts(from), releaseParts(to)
+	if o.zeroMajorStrict && fromParts[0].Sign() == 0 && toParts[0].Sign() == 0 {
+		if compareDecimals(fromParts[1], toParts[1]) != 0 {
+			return UpgradeMajor, nil
+		}
+		if fromParts[1].Sign() == 0 && compareDecimals(fromParts[2], toParts[2]) != 0 {
+			return UpgradeMajor, nil
+		}
+	}
+
+	switch {
+	case compareDecimals(fromParts[0], toParts[0]) != 0:
+		return UpgradeMajor, nil
+	case compareDecimals(fromParts[1], toParts[1]) != 0:
+		return UpgradeMinor, nil
+	case compareDecimals(fromParts[2], toParts[2]) != 0:
+		return UpgradePatch, nil
+	case from.IsPreRelease() || to.IsPreRelease():
+		return UpgradePreReleaseOnly, nil
+	}
+	return UpgradePatch, nil
+}
+
+// canClassifyUpgrade returns true if ClassifyUpgrade can classify upgrades
+// between versions parsed as pa.
+func canClassifyUpgrade(pa ParsedAs) bool {
+	switch pa {
+	case SemVer, Go, Npm, Generic:
+		return true
+	}
+	return false
+}
+
+// hasMajorMinor returns true if the first two segments of v are whole
+// numbers, counting missing segments as zero.
+func hasMajorMinor(v *Version) bool {
+	return leadingWholeNumbers(v, 2) == 2
+}
+
+// leadingWholeNumbers returns how many of the first n segments of v are whole
+// numbers, stopping at the first one that is not.
+func leadingWholeNumbers(v *Version, n int) int {
+	for i := 0; i < n; i++ {
+		if !isWholeNumber(segmentOrZero(v, i)) {
+			return i
+		}
+	}
+	return n
+}
+
+// releaseParts returns the major, minor and patch versions of v. A patch
+// version that is missing or is not a whole number is zero.
+func releaseParts(v *Version) [3]*decimal.Big {
+	parts := [3]*decimal.Big{segmentOrZero(v, 0), segmentOrZero(v, 1), zeroSegment}
+	if leadingWholeNumbers(v, 3) == 3 {
+		parts[2] = segmentOrZero(v, 2)
+	}
+	return parts
+}
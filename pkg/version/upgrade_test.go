@@ -0,0 +1,106 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyUpgrade(t *testing.T) {
+	tests := []struct {
+		from, to string
+		kind     UpgradeKind
+		strict   UpgradeKind
+	}{
+		{"1.2.3", "1.2.4", UpgradePatch, UpgradePatch},
+		{"1.2.3", "1.3.0", UpgradeMinor, UpgradeMinor},
+		{"1.9.9", "2.0.0", UpgradeMajor, UpgradeMajor},
+		{"0.3.1", "0.4.0", UpgradeMinor, UpgradeMajor},
+		{"0.3.1", "0.3.2", UpgradePatch, UpgradePatch},
+		{"0.0.3", "0.0.4", UpgradePatch, UpgradeMajor},
+		{"0.9.0", "1.0.0", UpgradeMajor, UpgradeMajor},
+		{"2.0.0-rc.1", "2.0.0-rc.2", UpgradePreReleaseOnly, UpgradePreReleaseOnly},
+		{"2.0.0-rc.2", "2.0.0", UpgradePreReleaseOnly, UpgradePreReleaseOnly},
+		{"1.9.0", "2.0.0-rc.1", UpgradeMajor, UpgradeMajor},
+		{"1.2.3-beta.1", "1.2.4", UpgradePatch, UpgradePatch},
+		{"1.2.3+build.1", "1.2.4+build.2", UpgradePatch, UpgradePatch},
+	}
+	for _, tt := range tests {
+		from, to := parseOrFatalSemVer(t, tt.from), parseOrFatalSemVer(t, tt.to)
+
+		kind, err := ClassifyUpgrade(from, to)
+		require.NoError(t, err, "%s to %s", tt.from, tt.to)
+		assert.Equal(t, tt.kind, kind, "%s to %s", tt.from, tt.to)
+
+		kind, err = ClassifyUpgrade(from, to, WithZeroMajorStrict())
+		require.NoError(t, err, "%s to %s", tt.from, tt.to)
+		assert.Equal(t, tt.strict, kind, "%s to %s with WithZeroMajorStrict", tt.from, tt.to)
+	}
+}
+
+func TestClassifyUpgradeGo(t *testing.T) {
+	kind, err := ClassifyUpgrade(parseGoOrFatal(t, "v1.2.3"), parseGoOrFatal(t, "v1.3.0"))
+	require.NoError(t, err)
+	assert.Equal(t, UpgradeMinor, kind)
+
+	kind, err = ClassifyUpgrade(parseOrFatalSemVer(t, "1.9.0"), parseGoOrFatal(t, "v2.0.0+incompatible"))
+	require.NoError(t, err)
+	assert.Equal(t, UpgradeMajor, kind)
+}
+
+func TestClassifyUpgradeNpm(t *testing.T) {
+	from, to := parseNpmOrFatal(t, "1.2.3"), parseNpmOrFatal(t, "v1.3.0beta")
+	kind, err := ClassifyUpgrade(from, to)
+	require.NoError(t, err)
+	assert.Equal(t, UpgradeMinor, kind)
+
+	_, err = ClassifyUpgrade(from, parseOrFatalSemVer(t, "1.3.0"))
+	assert.IsType(t, &IncomparableError{}, err)
+}
+
+func TestClassifyUpgradeGeneric(t *testing.T) {
+	tests := []struct {
+		from, to string
+		kind     UpgradeKind
+	}{
+		{"1.2", "1.3", UpgradeMinor},
+		{"1.2", "1.2.1", UpgradePatch},
+		{"1.2.3.4", "1.2.3.5", UpgradePatch},
+		{"1.0.2k", "1.0.2l", UpgradePatch},
+		{"1.2-beta", "1.2", UpgradePreReleaseOnly},
+		{"1.2-beta1", "1.2-beta2", UpgradePreReleaseOnly},
+		{"2013.1", "2014.1", UpgradeMajor},
+	}
+	for _, tt := range tests {
+		kind, err := ClassifyUpgrade(parseOrFatalGeneric(t, tt.from), parseOrFatalGeneric(t, tt.to))
+		require.NoError(t, err, "%s to %s", tt.from, tt.to)
+		assert.Equal(t, tt.kind, kind, "%s to %s", tt.from, tt.to)
+	}
+}
+
+func TestClassifyUpgradeErrors(t *testing.T) {
+	tests := map[string][2]*Version{
+		"downgrade":       {parseOrFatalSemVer(t, "1.2.4"), parseOrFatalSemVer(t, "1.2.3")},
+		"equal":           {parseOrFatalSemVer(t, "1.2.3"), parseOrFatalSemVer(t, "1.2.3+build")},
+		"different types": {parseOrFatalSemVer(t, "1.2.3"), parseOrFatalGeneric(t, "1.2.4")},
+		"go and generic":  {parseGoOrFatal(t, "v1.2.3"), parseOrFatalGeneric(t, "1.2.4")},
+		"python":          {parsePythonOrFatal(t, "1.2.3"), parsePythonOrFatal(t, "1.2.4")},
+		"no minor":        {parseOrFatalGeneric(t, "release"), parseOrFatalGeneric(t, "1.2")},
+	}
+	for name, vs := range tests {
+		_, err := ClassifyUpgrade(vs[0], vs[1])
+		assert.Error(t, err, name)
+	}
+
+	_, err := ClassifyUpgrade(parseOrFatalSemVer(t, "1.2.3"), parseOrFatalGeneric(t, "1.2.4"))
+	assert.IsType(t, &IncomparableError{}, err)
+}
+
+func TestUpgradeKindString(t *testing.T) {
+	assert.Equal(t, "prerelease-only", UpgradePreReleaseOnly.String())
+	assert.Equal(t, "patch", UpgradePatch.String())
+	assert.Equal(t, "minor", UpgradeMinor.String())
+	assert.Equal(t, "major", UpgradeMajor.String())
+	assert.Equal(t, "UpgradeKind(0)", UpgradeKind(0).String())
+}
@@ -36,6 +36,10 @@ package version
 import (
 	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/ericlagergren/decimal"
 )
@@ -63,17 +67,259 @@ const (
 	PythonPEP440
 	// Ruby is for Ruby versions.
 	Ruby
+	// Raw is for strings that could not be parsed as a version, as returned
+	// by NewRaw.
+	Raw
+	// Debian is for Debian package versions.
+	Debian
+	// Maven is for Maven versions, as used by Java and other JVM languages.
+	Maven
+	// NuGet is for NuGet package versions, as used by .NET.
+	NuGet
+	// Npm is for versions from the npm registry, as parsed by node-semver in
+	// loose mode.
+	Npm
+	// Gentoo is for Gentoo ebuild versions.
+	Gentoo
+	// LuaRocks is for LuaRocks rockspec versions.
+	LuaRocks
+	// Hex is for versions of Hex packages, as used by Elixir and Erlang.
+	Hex
+	// Nix is for versions from nixpkgs, as compared by Nix.
+	Nix
+	// CalVer is for calendar versions, as parsed by ParseCalVer.
+	CalVer
+	// JavaRuntime is for Java runtime versions, as described in JEP 223.
+	JavaRuntime
+	// DotNetAssembly is for .NET assembly versions, as with System.Version.
+	DotNetAssembly
+	// FreeBSDPorts is for FreeBSD port versions.
+	FreeBSDPorts
+	// Conan is for Conan 2 package versions.
+	Conan
+	// LinuxKernel is for Linux kernel versions.
+	LinuxKernel
+	// DockerTag is for container image tags that start with a version.
+	DockerTag
+	// CPE is for the version and update attributes of CPE 2.3 names.
+	CPE
+	// WindowsFileVersion is for the file versions of Windows PE files.
+	WindowsFileVersion
+	// GoToolchain is for Go toolchain and release names.
+	GoToolchain
+	// Kubernetes is for Kubernetes versions.
+	Kubernetes
+	// Go is for Go module versions, which are semver versions with a leading
+	// "v".
+	Go
 )
 
+// Option configures optional parsing behavior. Each parsing func documents
+// the options it honors; options that do not apply to a given func are
+// ignored by it.
+type Option func(*options)
+
+type options struct {
+	phpMaxSegments           int
+	ignoreBuildMetadata      bool
+	maxSegments              int
+	segmentOverflow          SegmentOverflow
+	fixedSegments            int
+	calVerLayout             string
+	dotNetMissingPartsAsZero bool
+}
+
+func applyOptions(opts []Option) options {
+	// Passing &o to the options makes it escape, so return early to avoid
+	// allocating when there are none.
+	if len(opts) == 0 {
+		return options{}
+	}
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
 // Version is the struct returned from all parsing funcs.
 type Version struct {
 	// Original is the string that was passed to the parsing func.
 	Original string `json:"version"`
 	// Decimal contains a slice of `*decimal.Big` values. This will always
 	// contain at least one element.
+	//
+	// The values in this slice may be shared with other Versions and must
+	// never be modified in place. To change a segment, replace the pointer
+	// with a new value, or modify a Clone of the Version instead.
+	//
+	// In JSON each segment is written as a string, such as "97.0000000108",
+	// so that consumers which parse JSON numbers as floats cannot lose
+	// precision. Both strings and numbers are accepted when unmarshaling.
 	Decimal []*decimal.Big `json:"sortable_version"`
 	// ParsedAs indicates which type the version was parsed as.
 	ParsedAs ParsedAs `json:"-"`
+	// BuildMetadata contains any build metadata that was split off the
+	// version before parsing, without the leading "+". It does not affect
+	// comparisons. This is only set by parsing funcs that are asked to
+	// ignore build metadata, such as ParseGeneric with
+	// WithIgnoreBuildMetadata, and by ParseNuGet and ParseHex. For
+	// ParseLinuxKernel it is the localversion, such as "-generic" or "+", for
+	// ParseWindowsFileVersion it is the build tag in parentheses, and for
+	// ParseKubernetes it is the distro suffix, such as "eks-b9c9ed7".
+	BuildMetadata string `json:"-"`
+}
+
+// parsers maps each type to the func that parses versions of that type. Some
+// funcs, like ParsePython, can return more than one type, so Parse checks the
+// type of the returned Version.
+var parsers = map[ParsedAs]func(string, ...Option) (*Version, error){
+	Generic:            ParseGeneric,
+	SemVer:             func(s string, _ ...Option) (*Version, error) { return ParseSemVer(s) },
+	PerlDecimal:        func(s string, _ ...Option) (*Version, error) { return ParsePerl(s) },
+	PerlVString:        func(s string, _ ...Option) (*Version, error) { return ParsePerl(s) },
+	PHP:                ParsePHP,
+	PythonLegacy:       func(s string, _ ...Option) (*Version, error) { return ParsePython(s) },
+	PythonPEP440:       func(s string, _ ...Option) (*Version, error) { return ParsePython(s) },
+	Ruby:               func(s string, _ ...Option) (*Version, error) { return ParseRuby(s) },
+	Raw:                func(s string, _ ...Option) (*Version, error) { return NewRaw(s), nil },
+	Debian:             func(s string, _ ...Option) (*Version, error) { return ParseDebian(s) },
+	Maven:              func(s string, _ ...Option) (*Version, error) { return ParseMaven(s) },
+	NuGet:              func(s string, _ ...Option) (*Version, error) { return ParseNuGet(s) },
+	Npm:                func(s string, _ ...Option) (*Version, error) { return ParseNpm(s) },
+	Gentoo:             func(s string, _ ...Option) (*Version, error) { return ParseGentoo(s) },
+	LuaRocks:           func(s string, _ ...Option) (*Version, error) { return ParseLuaRocks(s) },
+	Hex:                func(s string, _ ...Option) (*Version, error) { return ParseHex(s) },
+	Nix:                func(s string, _ ...Option) (*Version, error) { return ParseNix(s) },
+	CalVer:             parseCalVerOption,
+	JavaRuntime:        func(s string, _ ...Option) (*Version, error) { return ParseJavaRuntime(s) },
+	DotNetAssembly:     ParseDotNetAssembly,
+	FreeBSDPorts:       func(s string, _ ...Option) (*Version, error) { return ParseFreeBSDPorts(s) },
+	Conan:              func(s string, _ ...Option) (*Version, error) { return ParseConan(s) },
+	LinuxKernel:        func(s string, _ ...Option) (*Version, error) { return ParseLinuxKernel(s) },
+	DockerTag:          func(s string, _ ...Option) (*Version, error) { return ParseDockerTag(s) },
+	CPE:                func(s string, _ ...Option) (*Version, error) { return parseCPEString(s) },
+	WindowsFileVersion: func(s string, _ ...Option) (*Version, error) { return ParseWindowsFileVersion(s) },
+	GoToolchain:        func(s string, _ ...Option) (*Version, error) { return ParseGoToolchain(s) },
+	Kubernetes:         func(s string, _ ...Option) (*Version, error) { return ParseKubernetes(s) },
+	Go:                 ParseGo,
+}
+
+// Parse parses version as the given type using the matching parsing func,
+// passing along any options. It returns an error if there is no parsing func
+// for the type, or if the version is valid but the parsing func recognizes it
+// as a different type. For example, "1.0" cannot be parsed as a PythonLegacy
+// version because ParsePython treats it as a PythonPEP440 version.
+func Parse(pa ParsedAs, version string, opts ...Option) (*Version, error) {
+	return parseAs(pa, version, false, opts)
+}
+
+// ParseComparable parses version as Parse does, but also accepts a version
+// that the parsing func for pa recognizes as another type that is comparable
+// with pa (see Comparable). For example, ParseComparable(PythonPEP440,
+// "1.0-foo") returns a PythonLegacy version where Parse returns an error.
+// This is for callers that know the ecosystem a version is from, but not
+// which of its types the version is.
+func ParseComparable(pa ParsedAs, version string, opts ...Option) (*Version, error) {
+	return parseAs(pa, version, true, opts)
+}
+
+func parseAs(pa ParsedAs, version string, comparable bool, opts []Option) (*Version, error) {
+	parse, ok := parsers[pa]
+	if !ok {
+		return nil, fmt.Errorf("cannot parse versions as %s", pa)
+	}
+
+	v, err := parse(version, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if v.ParsedAs != pa && !(comparable && Comparable(v.ParsedAs, pa)) {
+		return nil, fmt.Errorf("%s is a %s version, not a %s version", version, v.ParsedAs, pa)
+	}
+	return v, nil
+}
+
+// typeNames maps the short names that commands like parseversion accept for
+// each type, such as "semver", to the type. Perl and Python versions are
+// named for the ecosystem rather than for one of their types, so they map to
+// one of them and should be parsed with ParseComparable. CalVer is left out
+// because it cannot be parsed without a layout.
+var typeNames = map[string]ParsedAs{
+	"conan":              Conan,
+	"cpe":                CPE,
+	"debian":             Debian,
+	"dockertag":          DockerTag,
+	"dotnetassembly":     DotNetAssembly,
+	"freebsdports":       FreeBSDPorts,
+	"generic":            Generic,
+	"gentoo":             Gentoo,
+	"go":                 Go,
+	"gotoolchain":        GoToolchain,
+	"hex":                Hex,
+	"javaruntime":        JavaRuntime,
+	"kubernetes":         Kubernetes,
+	"linuxkernel":        LinuxKernel,
+	"luarocks":           LuaRocks,
+	"maven":              Maven,
+	"nix":                Nix,
+	"npm":                Npm,
+	"nuget":              NuGet,
+	"perl":               PerlDecimal,
+	"php":                PHP,
+	"python":             PythonPEP440,
+	"ruby":               Ruby,
+	"semver":             SemVer,
+	"windowsfileversion": WindowsFileVersion,
+}
+
+// TypeNames returns the names that ParsedAsFromName accepts, sorted
+// alphabetically.
+func TypeNames() []string {
+	names := make([]string, 0, len(typeNames))
+	for n := range typeNames {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParsedAsFromName returns the type for one of the names returned by
+// TypeNames, such as "python", and false if the name is not one of them. The
+// names are those used on the command line, which are not the same as the
+// names returned by ParsedAs.String. Parse versions with ParseComparable and
+// the returned type, so that "perl" and "python" accept every Perl and Python
+// version.
+func ParsedAsFromName(name string) (ParsedAs, bool) {
+	pa, ok := typeNames[name]
+	return pa, ok
+}
+
+// ParseVersionString parses the output of Version.String(), such as "1.2.3
+// (SemVer)", by parsing the original version again as the named type. It
+// returns an error if s is not in that format, if the type name is unknown,
+// or if the original version is no longer valid for that type. Any options
+// are passed along to the parsing func.
+func ParseVersionString(s string, opts ...Option) (*Version, error) {
+	i := strings.LastIndex(s, " (")
+	if i < 0 || !strings.HasSuffix(s, ")") {
+		return nil, fmt.Errorf("not a version string: %s", s)
+	}
+
+	original, name := s[:i], s[i+2:len(s)-1]
+	pa, err := ParsedAsString(name)
+	if err != nil || pa == Unknown {
+		return nil, fmt.Errorf("unknown version type in version string: %s", s)
+	}
+
+	if strings.HasPrefix(original, `"`) {
+		original, err = strconv.Unquote(original)
+		if err != nil {
+			return nil, fmt.Errorf("badly quoted version in version string: %s", s)
+		}
+	}
+
+	return Parse(pa, original, opts...)
 }
 
 // fromStringSlice take a version type and a slice of strings and returns a
@@ -81,12 +327,11 @@ type Version struct {
 // string representation of a number. This returns an error if any element of
 // the slice cannot be converted to a *decimal.Big value.
 func fromStringSlice(pa ParsedAs, original string, strings []string) (*Version, error) {
-	decimals, err := stringsToDecimals(strings)
+	decimals, err := normalizedDecimals(strings)
 	if err != nil {
 		return nil, err
 	}
 
-	decimals = trimTrailingZeros(decimals)
 	return &Version{
 		Original: original,
 		Decimal:  decimals,
@@ -94,6 +339,61 @@ func fromStringSlice(pa ParsedAs, original string, strings []string) (*Version,
 	}, nil
 }
 
+// fillFromStringSlice is fromStringSlice for an existing Version. It reuses
+// the backing array of dst's Decimal slice if it is big enough. If it returns
+// an error, dst is reset to an empty Version.
+func fillFromStringSlice(dst *Version, pa ParsedAs, original string, strings []string) error {
+	if len(strings) == 0 {
+		resetVersion(dst)
+		return errors.New("The provided string slice must have at least one element")
+	}
+
+	decimals := dst.Decimal[:0]
+	if cap(decimals) < len(strings) {
+		decimals = make([]*decimal.Big, 0, len(strings))
+	}
+	for _, s := range strings {
+		d := stringToDecimal(s)
+		var err error
+		switch {
+		case d == nil:
+			err = errors.New("Failed to create decimal.Big from " + s)
+		case !d.IsFinite():
+			err = errors.New("Segment is not a finite decimal: " + s)
+		}
+		if err != nil {
+			dst.Decimal = decimals
+			resetVersion(dst)
+			return err
+		}
+		decimals = append(decimals, d)
+	}
+
+	*dst = Version{
+		Original: original,
+		Decimal:  trimTrailingZeros(decimals),
+		ParsedAs: pa,
+	}
+	return nil
+}
+
+// normalizedDecimals converts strings to decimals and trims any trailing zero
+// segments, which gives the canonical form of the segments that all parsing
+// funcs store. NormalizeSegments and TrimTrailingZeroSegments expose this to
+// code that works with stored segments.
+func normalizedDecimals(strings []string) ([]*decimal.Big, error) {
+	decimals, err := stringsToDecimals(strings)
+	if err != nil {
+		return nil, err
+	}
+	for i, d := range decimals {
+		if !d.IsFinite() {
+			return nil, errors.New("Segment is not a finite decimal: " + strings[i])
+		}
+	}
+	return trimTrailingZeros(decimals), nil
+}
+
 func stringsToDecimals(strings []string) ([]*decimal.Big, error) {
 	if len(strings) == 0 {
 		return nil, errors.New("The provided string slice must have at least one element")
@@ -101,8 +401,8 @@ func stringsToDecimals(strings []string) ([]*decimal.Big, error) {
 
 	decimals := make([]*decimal.Big, len(strings))
 	for i, s := range strings {
-		d := &decimal.Big{}
-		if _, ok := d.SetString(s); !ok {
+		d := stringToDecimal(s)
+		if d == nil {
 			return nil, errors.New("Failed to create decimal.Big from " + s)
 		}
 		decimals[i] = d
@@ -111,6 +411,20 @@ func stringsToDecimals(strings []string) ([]*decimal.Big, error) {
 	return decimals, nil
 }
 
+// stringToDecimal returns the decimal for s, using a shared value where there
+// is one, or nil if s is not a valid decimal.
+func stringToDecimal(s string) *decimal.Big {
+	if d := internedDecimal(s); d != nil {
+		return d
+	}
+
+	d := &decimal.Big{}
+	if _, ok := d.SetString(s); !ok {
+		return nil
+	}
+	return d
+}
+
 func trimTrailingZeros(decimals []*decimal.Big) []*decimal.Big {
 	indexOfLastZero := len(decimals)
 	for i := len(decimals) - 1; i > 0; i-- {
@@ -123,7 +437,47 @@ func trimTrailingZeros(decimals []*decimal.Big) []*decimal.Big {
 	return decimals[0:indexOfLastZero]
 }
 
-var bigZero = decimal.New(0, 0)
+// maxInternedDecimal is the largest integer that internedDecimal has a shared
+// value for. Nearly every segment of a typical version is a small integer, so
+// sharing these saves an allocation for most segments.
+const maxInternedDecimal = 1024
+
+// internedDecimals holds the shared values for the integers 0 through
+// maxInternedDecimal. These are shared by every Version that contains them,
+// so they must never be modified. Nothing in this package modifies a
+// *decimal.Big after it is created, and Clone copies each value into a new
+// *decimal.Big, so clones never share these.
+var internedDecimals = func() []*decimal.Big {
+	decimals := make([]*decimal.Big, maxInternedDecimal+1)
+	for i := range decimals {
+		decimals[i] = decimal.New(int64(i), 0)
+	}
+	return decimals
+}()
+
+// internedDecimal returns the shared value for s if s is a string of ASCII
+// digits whose value is no greater than maxInternedDecimal. Otherwise it
+// returns nil.
+func internedDecimal(s string) *decimal.Big {
+	if s == "" || len(s) > 4 {
+		return nil
+	}
+
+	n := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return nil
+		}
+		n = n*10 + int(c-'0')
+	}
+	if n > maxInternedDecimal {
+		return nil
+	}
+	return internedDecimals[n]
+}
+
+var bigZero = internedDecimals[0]
 
 // Compare returns:
 //   <0 if the version in v1 is less than the version in v2
@@ -132,12 +486,20 @@ var bigZero = decimal.New(0, 0)
 //
 // Versions that differ only by trailing zeros (e.g. "1.2" and "1.2.0") are
 // equal.
+//
+// Compare is permissive and will compare any two versions, even if they were
+// parsed as different types. The result of comparing versions from different
+// versioning schemes is generally meaningless. Use CompareChecked if the
+// versions might not have been parsed the same way.
+//
+// Compare does not allocate, so it is cheap to call from sorting and other
+// comparison heavy code.
 func Compare(v1, v2 *Version) int {
 	min, max, longest, flip := minMax(v1.Decimal, v2.Decimal)
 
 	// find any difference between these versions where they have the same number of segments
 	for i := 0; i < min; i++ {
-		cmp := v1.Decimal[i].Cmp(v2.Decimal[i])
+		cmp := compareDecimals(v1.Decimal[i], v2.Decimal[i])
 		if cmp != 0 {
 			return cmp
 		}
@@ -145,7 +507,7 @@ func Compare(v1, v2 *Version) int {
 
 	// compare remaining segments to zero
 	for i := min; i < max; i++ {
-		cmp := longest[i].Cmp(bigZero)
+		cmp := longest[i].Sign()
 		if cmp != 0 {
 			return cmp * flip
 		}
@@ -154,6 +516,55 @@ func Compare(v1, v2 *Version) int {
 	return 0
 }
 
+// IncomparableError is returned when two versions were parsed as types whose
+// sortable representations cannot be meaningfully compared.
+type IncomparableError struct {
+	ParsedAs1 ParsedAs
+	ParsedAs2 ParsedAs
+}
+
+func (e *IncomparableError) Error() string {
+	return fmt.Sprintf("cannot compare a %s version with a %s version", e.ParsedAs1, e.ParsedAs2)
+}
+
+// comparableFamilies maps types to another type whose versions they can be
+// compared with. Types that are not listed can only be compared with
+// themselves.
+var comparableFamilies = map[ParsedAs]ParsedAs{
+	PerlVString:  PerlDecimal,
+	PythonLegacy: PythonPEP440,
+	Go:           SemVer,
+}
+
+func family(pa ParsedAs) ParsedAs {
+	if f, ok := comparableFamilies[pa]; ok {
+		return f
+	}
+	return pa
+}
+
+// Comparable returns true if versions parsed as pa1 can be meaningfully
+// compared with versions parsed as pa2. Types are comparable with themselves,
+// Perl decimal versions are comparable with Perl v-strings, legacy Python
+// versions are comparable with PEP440 versions, and Go module versions are
+// comparable with semver versions. Raw versions are comparable with every
+// type, as they always sort below parsed versions.
+func Comparable(pa1, pa2 ParsedAs) bool {
+	if pa1 == Raw || pa2 == Raw {
+		return true
+	}
+	return family(pa1) == family(pa2)
+}
+
+// CompareChecked works like Compare, but returns an *IncomparableError if v1
+// and v2 were parsed as types that cannot be compared (see Comparable).
+func CompareChecked(v1, v2 *Version) (int, error) {
+	if !Comparable(v1.ParsedAs, v2.ParsedAs) {
+		return 0, &IncomparableError{ParsedAs1: v1.ParsedAs, ParsedAs2: v2.ParsedAs}
+	}
+	return Compare(v1, v2), nil
+}
+
 // helper function to find the lengths of and longest version segment array
 func minMax(v1 []*decimal.Big, v2 []*decimal.Big) (int, int, []*decimal.Big, int) {
 	l1 := len(v1)
@@ -174,14 +585,59 @@ func (v *Version) Clone() *Version {
 		d[i].Copy(v.Decimal[i])
 	}
 	return &Version{
-		Original: v.Original,
-		Decimal:  d,
-		ParsedAs: v.ParsedAs,
+		Original:      v.Original,
+		Decimal:       d,
+		ParsedAs:      v.ParsedAs,
+		BuildMetadata: v.BuildMetadata,
 	}
 }
 
 // String returns a string representation of the version. Note that this is
-// not the same as v.Original.
+// not the same as v.Original. The string contains the original version
+// followed by the type it was parsed as in parentheses, e.g. "1.2.3
+// (SemVer)". If the original version contains " (" or starts with a double
+// quote it is quoted using Go syntax so that the output is unambiguous. The
+// output can be parsed with ParseVersionString.
 func (v *Version) String() string {
-	return fmt.Sprintf("%s (%s)", v.Original, v.ParsedAs.String())
+	original := v.Original
+	if strings.Contains(original, " (") || strings.HasPrefix(original, `"`) {
+		original = strconv.Quote(original)
+	}
+	return fmt.Sprintf("%s (%s)", original, v.ParsedAs.String())
+}
+
+// Format implements fmt.Formatter. The %s and %v verbs print the same thing
+// as String. %+v adds the segments, as in "1.0-beta (Generic) [1 0 -25]",
+// and %#v prints a call to FromSortable that returns an equal Version, as in
+// `version.FromSortable("1.0-beta", []string{"1", "0", "-25"},
+// version.Generic)`. %q prints String as a quoted Go string. Flags, width and
+// precision are ignored.
+func (v *Version) Format(f fmt.State, verb rune) {
+	if v == nil {
+		io.WriteString(f, "<nil>")
+		return
+	}
+
+	switch verb {
+	case 's':
+		io.WriteString(f, v.String())
+	case 'v':
+		switch {
+		case f.Flag('#'):
+			segments := make([]string, len(v.Decimal))
+			for i, d := range v.Decimal {
+				segments[i] = strconv.Quote(d.String())
+			}
+			fmt.Fprintf(f, "version.FromSortable(%s, []string{%s}, version.%s)",
+				strconv.Quote(v.Original), strings.Join(segments, ", "), v.ParsedAs)
+		case f.Flag('+'):
+			fmt.Fprintf(f, "%s [%s]", v.String(), strings.Join(v.Segments(), " "))
+		default:
+			io.WriteString(f, v.String())
+		}
+	case 'q':
+		io.WriteString(f, strconv.Quote(v.String()))
+	default:
+		fmt.Fprintf(f, "%%!%c(*version.Version=%s)", verb, v.String())
+	}
 }
@@ -0,0 +1,371 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareChecked(t *testing.T) {
+	comparable := [][]ParsedAs{
+		{Generic, Generic},
+		{SemVer, SemVer},
+		{PerlDecimal, PerlDecimal},
+		{PerlDecimal, PerlVString},
+		{PerlVString, PerlDecimal},
+		{PerlVString, PerlVString},
+		{PHP, PHP},
+		{PythonLegacy, PythonLegacy},
+		{PythonLegacy, PythonPEP440},
+		{PythonPEP440, PythonLegacy},
+		{PythonPEP440, PythonPEP440},
+		{Ruby, Ruby},
+		{Go, Go},
+		{Go, SemVer},
+		{SemVer, Go},
+	}
+	for _, pair := range comparable {
+		assert.True(t, Comparable(pair[0], pair[1]), "%s is comparable with %s", pair[0], pair[1])
+	}
+
+	incomparable := [][]ParsedAs{
+		{Generic, SemVer},
+		{SemVer, PythonPEP440},
+		{PythonPEP440, SemVer},
+		{PerlDecimal, PythonPEP440},
+		{PerlVString, PythonLegacy},
+		{PHP, Ruby},
+		{Ruby, Generic},
+		{Go, GoToolchain},
+		{Go, Generic},
+	}
+	for _, pair := range incomparable {
+		assert.False(t, Comparable(pair[0], pair[1]), "%s is not comparable with %s", pair[0], pair[1])
+	}
+
+	legacy := parsePythonOrFatal(t, "1.0-foo")
+	pep440 := parsePythonOrFatal(t, "1.0")
+	require.Equal(t, PythonLegacy, legacy.ParsedAs)
+	cmp, err := CompareChecked(legacy, pep440)
+	assert.NoError(t, err)
+	assert.True(t, cmp < 0, "legacy python versions sort before PEP440 versions")
+
+	perlDecimal := parsePerlOrFatal(t, "1.002003")
+	vstring := parsePerlOrFatal(t, "v1.2.3")
+	cmp, err = CompareChecked(perlDecimal, vstring)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cmp, "1.002003 == v1.2.3")
+
+	semver := parseOrFatalSemVer(t, "1.0.0")
+	cmp, err = CompareChecked(pep440, semver)
+	assert.Error(t, err)
+	assert.Equal(t, 0, cmp)
+	if assert.IsType(t, &IncomparableError{}, err) {
+		assert.Equal(t, &IncomparableError{ParsedAs1: PythonPEP440, ParsedAs2: SemVer}, err)
+	}
+	assert.Equal(t, "cannot compare a PythonPEP440 version with a SemVer version", err.Error())
+}
+
+func parsePerlOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParsePerl(v)
+	require.NoError(t, err, "no error parsing %v as a perl version", v)
+	return ver
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		pa      ParsedAs
+		version string
+	}{
+		{Generic, "1.2.3-foo"},
+		{SemVer, "1.2.3-rc.1"},
+		{PerlDecimal, "1.002003"},
+		{PerlVString, "v1.2.3"},
+		{PHP, "1.0.0-beta2"},
+		{PythonLegacy, "1.0-foo"},
+		{PythonPEP440, "1!2.0.post1"},
+		{Ruby, "1.2.pre.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pa.String(), func(t *testing.T) {
+			v, err := Parse(tt.pa, tt.version)
+			require.NoError(t, err)
+			assert.Equal(t, tt.pa, v.ParsedAs)
+			assert.Equal(t, tt.version, v.Original)
+		})
+	}
+
+	_, err := Parse(Unknown, "1.0")
+	assert.Error(t, err, "cannot parse as Unknown")
+	_, err = Parse(PythonLegacy, "1.0")
+	assert.Error(t, err, "1.0 is a PEP440 version")
+	_, err = Parse(PerlVString, "1.2")
+	assert.Error(t, err, "1.2 is a Perl decimal version")
+	_, err = Parse(SemVer, "1.0")
+	assert.Error(t, err, "1.0 is not a valid semver version")
+
+	v, err := Parse(PHP, "1.2.3.4.5", WithExtraSegments(5))
+	assert.NoError(t, err, "options are passed to the parsing func")
+	assert.Equal(t, PHP, v.ParsedAs)
+}
+
+func TestParseComparable(t *testing.T) {
+	v, err := ParseComparable(PythonPEP440, "1.0-foo")
+	require.NoError(t, err)
+	assert.Equal(t, PythonLegacy, v.ParsedAs)
+
+	v, err = ParseComparable(PerlDecimal, "v1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, PerlVString, v.ParsedAs)
+
+	v, err = ParseComparable(SemVer, "1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, SemVer, v.ParsedAs)
+
+	_, err = ParseComparable(SemVer, "v1.2.3")
+	assert.Error(t, err, "only the parsing func for SemVer is used, so Go versions are not accepted")
+	_, err = ParseComparable(Unknown, "1.0")
+	assert.Error(t, err, "cannot parse as Unknown")
+}
+
+func TestParsedAsFromName(t *testing.T) {
+	names := TypeNames()
+	assert.True(t, sort.StringsAreSorted(names))
+	named := map[ParsedAs]bool{}
+	for _, n := range names {
+		pa, ok := ParsedAsFromName(n)
+		if assert.True(t, ok, n) {
+			assert.Contains(t, parsers, pa, "%s has a parsing func", n)
+			named[pa] = true
+		}
+	}
+	for _, pa := range ParsedAsValues() {
+		switch pa {
+		case Unknown, Raw, CalVer, PerlVString, PythonLegacy:
+			continue
+		}
+		assert.True(t, named[pa], "%s has a name", pa)
+	}
+
+	pa, ok := ParsedAsFromName("python")
+	require.True(t, ok)
+	v, err := ParseComparable(pa, "1.0-foo")
+	require.NoError(t, err)
+	assert.Equal(t, PythonLegacy, v.ParsedAs)
+
+	_, ok = ParsedAsFromName("calver")
+	assert.False(t, ok, "CalVer needs a layout")
+	_, ok = ParsedAsFromName("SemVer")
+	assert.False(t, ok, "names are lowercase")
+}
+
+func TestParseVersionString(t *testing.T) {
+	versions := []*Version{
+		parseOrFatalGeneric(t, "1.2.3-foo"),
+		parseOrFatalGeneric(t, "1.0 (beta)"),
+		parseOrFatalGeneric(t, `"quoted"`),
+		parseOrFatalSemVer(t, "1.2.3-rc.1+build"),
+		parsePerlOrFatal(t, "1.002003"),
+		parsePerlOrFatal(t, "v1.2.3"),
+		parsePerlOrFatal(t, "v1.2"),
+		parsePerlOrFatal(t, "1.0_2"),
+		parsePHPOrFatal(t, "1.0.0-beta2"),
+		parsePythonOrFatal(t, "1.0-foo"),
+		parsePythonOrFatal(t, "1!2.0.post1"),
+		parseRubyOrFatal(t, "1.2.pre.1"),
+		parseRubyOrFatal(t, " 1.0 "),
+		NewRaw("see notes (v2)"),
+		parseDebianOrFatal(t, "1:2.34-0ubuntu3"),
+		parseMavenOrFatal(t, "2.0.0.Final"),
+		parseNuGetOrFatal(t, "2.5.1.6205-Beta+sha.abc"),
+		parseNpmOrFatal(t, " v1.2.3-beta.1+build "),
+		parseGentooOrFatal(t, "1.2.3b_rc1_p2-r1"),
+		parseLuaRocksOrFatal(t, "1.0rc2-1"),
+		parseHexOrFatal(t, "1.0.0-rc.0+build.1"),
+		parseNixOrFatal(t, "2.3pre1-unstable-2024-01-01"),
+		parseCalVerOrFatal(t, "2024.04.1", "YYYY.0M.MICRO"),
+		parseJavaRuntimeOrFatal(t, "11.0.21+9-LTS"),
+		parseDotNetAssemblyOrFatal(t, "4.0.30319.42000"),
+		parseFreeBSDPortsOrFatal(t, "1.2.3rc1_1,1"),
+		parseConanOrFatal(t, "1.2.3.4-alpha.1+build.5"),
+		parseLinuxKernelOrFatal(t, "6.8-rc3-generic"),
+		parseDockerTagOrFatal(t, "1.25.3-alpine3.18"),
+		parseCPEOrFatal(t, "1.1.1", "k"),
+		parseCPEOrFatal(t, "5.0\\(1\\)", "-"),
+		parseWindowsFileVersionOrFatal(t, "10.0.19041.1 (WinBuild.160101.0800)"),
+		parseWindowsFileVersionOrFatal(t, "6, 1, 7601, 17514"),
+		parseGoToolchainOrFatal(t, "go1.22rc1"),
+		parseKubernetesOrFatal(t, "v1.30.0-rc.1-eks-b9c9ed7"),
+		parseGoOrFatal(t, "v1.2.3"),
+		parseGoOrFatal(t, "v2.1.0-beta+incompatible"),
+		parseGoOrFatal(t, "v0.0.0-20220314234659-1baeb1ce4c0b"),
+	}
+
+	seen := map[ParsedAs]bool{}
+	for _, v := range versions {
+		seen[v.ParsedAs] = true
+		t.Run(v.String(), func(t *testing.T) {
+			var opts []Option
+			if v.ParsedAs == CalVer {
+				opts = append(opts, WithCalVerLayout("YYYY.0M.MICRO"))
+			}
+			actual, err := ParseVersionString(v.String(), opts...)
+			require.NoError(t, err)
+			assert.Equal(t, v, actual)
+		})
+	}
+	for _, pa := range ParsedAsValues() {
+		if pa != Unknown {
+			assert.True(t, seen[pa], "round trip is tested for %s", pa)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"1.2.3",
+		"1.2.3 (SemVer",
+		"1.2.3 SemVer)",
+		"1.2.3 (Semver2)",
+		"1.2.3 (semver)",
+		"1.2.3 (Unknown)",
+		"1.2.3 ()",
+		"1.0 (SemVer)",
+		"1.0 (PythonLegacy)",
+		`"1.0 (Generic)`,
+	}
+	for _, s := range invalid {
+		_, err := ParseVersionString(s)
+		assert.Error(t, err, "%q is not a valid version string", s)
+	}
+}
+
+func TestStringQuotesAmbiguousOriginals(t *testing.T) {
+	v := parseOrFatalGeneric(t, "1.0 (beta)")
+	assert.Equal(t, `"1.0 (beta)" (Generic)`, v.String())
+
+	v = parseOrFatalGeneric(t, `"1.0"`)
+	assert.Equal(t, `"\"1.0\"" (Generic)`, v.String())
+
+	v = parseOrFatalGeneric(t, "1.0 beta)")
+	assert.Equal(t, "1.0 beta) (Generic)", v.String())
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		version  *Version
+		expected map[string]string
+	}{
+		{
+			parseOrFatalSemVer(t, "1.2.3"),
+			map[string]string{
+				"%s":   "1.2.3 (SemVer)",
+				"%v":   "1.2.3 (SemVer)",
+				"%+v":  "1.2.3 (SemVer) [1 2 3]",
+				"%#v":  `version.FromSortable("1.2.3", []string{"1", "2", "3"}, version.SemVer)`,
+				"%q":   `"1.2.3 (SemVer)"`,
+				"%20s": "1.2.3 (SemVer)",
+				"%.2v": "1.2.3 (SemVer)",
+				"%d":   "%!d(*version.Version=1.2.3 (SemVer))",
+			},
+		},
+		{
+			parseOrFatalSemVer(t, "1.0.0-alpha.1"),
+			map[string]string{
+				"%s":   "1.0.0-alpha.1 (SemVer)",
+				"%v":   "1.0.0-alpha.1 (SemVer)",
+				"%+v":  "1.0.0-alpha.1 (SemVer) [1 0 0 -1 97.108112104097 0 1 -1]",
+				"%#v":  `version.FromSortable("1.0.0-alpha.1", []string{"1", "0", "0", "-1", "97.108112104097", "0", "1", "-1"}, version.SemVer)`,
+				"%q":   `"1.0.0-alpha.1 (SemVer)"`,
+				"%20s": "1.0.0-alpha.1 (SemVer)",
+				"%.2v": "1.0.0-alpha.1 (SemVer)",
+				"%d":   "%!d(*version.Version=1.0.0-alpha.1 (SemVer))",
+			},
+		},
+		{
+			parseOrFatalGeneric(t, "1.0 (beta)"),
+			map[string]string{
+				"%v":  `"1.0 (beta)" (Generic)`,
+				"%#v": `version.FromSortable("1.0 (beta)", []string{"1", "0", "-25"}, version.Generic)`,
+			},
+		},
+		{
+			nil,
+			map[string]string{
+				"%s":  "<nil>",
+				"%+v": "<nil>",
+				"%#v": "<nil>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for format, expected := range tt.expected {
+			assert.Equal(t, expected, fmt.Sprintf(format, tt.version), "%s formatted with %s", tt.version, format)
+		}
+	}
+}
+
+func TestInternedDecimals(t *testing.T) {
+	for _, s := range []string{"0", "1", "07", "1024", "0000"} {
+		d := internedDecimal(s)
+		require.NotNil(t, d, "%s is interned", s)
+		expected, ok := new(decimal.Big).SetString(s)
+		require.True(t, ok)
+		assert.Equal(t, 0, d.Cmp(expected), "interned value for %s has the right value", s)
+	}
+
+	for _, s := range []string{"", "-1", "1025", "10000", "1.5", "a"} {
+		assert.Nil(t, internedDecimal(s), "%s is not interned", s)
+	}
+
+	v1 := parseOrFatalGeneric(t, "1.2.3")
+	v2 := parseOrFatalGeneric(t, "3.2.1")
+	assert.True(t, v1.Decimal[0] == v2.Decimal[2], "parsed versions share interned values")
+
+	clone := v1.Clone()
+	for i := range v1.Decimal {
+		assert.False(t, v1.Decimal[i] == clone.Decimal[i], "a clone does not share values with the original")
+	}
+
+	clone.Decimal[0].SetMantScale(42, 0)
+	assert.Equal(t, "1", v1.Decimal[0].String(), "modifying a clone does not modify the interned values")
+	assert.Equal(t, "1", internedDecimals[1].String())
+}
+
+func TestInternedDecimalsAreSafeForConcurrentUse(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var versions []*Version
+			for _, s := range pythonTestStrings {
+				v, err := ParsePython(s)
+				if !assert.NoError(t, err) {
+					return
+				}
+				versions = append(versions, v, v.Clone())
+			}
+			for j := 1; j < len(versions); j++ {
+				Compare(versions[j-1], versions[j])
+			}
+			for _, v := range versions {
+				_, err := json.Marshal(v)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, d := range internedDecimals {
+		assert.Equal(t, strconv.Itoa(i), d.String(), "interned value %d is unchanged", i)
+	}
+}
@@ -0,0 +1,121 @@
+// Package versiontest provides assertions about the ordering of versions, for
+// tests of the parsing funcs in the version package and of parsers written
+// elsewhere that produce version.Version values.
+package versiontest
+
+import (
+	"math/rand"
+
+	"github.com/ActiveState/langtools/pkg/version"
+)
+
+// T is the part of testing.TB that the assertions use. *testing.T and
+// *testing.B both implement it.
+type T interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// ParseFunc parses a version string, like version.ParseSemVer.
+type ParseFunc func(string) (*version.Version, error)
+
+// spotChecks is the number of random triples that AssertTotalOrder checks.
+const spotChecks = 1000
+
+// AssertOrdered parses each of inputs with parse and checks that they are in
+// strictly ascending order under version.Compare. Every pair is checked, not
+// just neighbours, in both directions. It calls t.Fatalf if an input cannot
+// be parsed.
+func AssertOrdered(t T, parse ParseFunc, inputs []string) {
+	t.Helper()
+
+	vs := parseAll(t, parse, inputs)
+	for i := range vs {
+		for j := i + 1; j < len(vs); j++ {
+			if cmp := version.Compare(vs[i], vs[j]); cmp >= 0 {
+				t.Errorf("%q should be less than %q, but Compare returned %d", inputs[i], inputs[j], cmp)
+			}
+			if cmp := version.Compare(vs[j], vs[i]); cmp <= 0 {
+				t.Errorf("%q should be greater than %q, but Compare returned %d", inputs[j], inputs[i], cmp)
+			}
+		}
+	}
+	AssertTotalOrder(t, vs)
+}
+
+// AssertAllEqual parses each of group with parse and checks that every pair
+// of them is equal under version.Compare. It calls t.Fatalf if an input
+// cannot be parsed.
+func AssertAllEqual(t T, parse ParseFunc, group []string) {
+	t.Helper()
+
+	vs := parseAll(t, parse, group)
+	for i := range vs {
+		for j := range vs {
+			if cmp := version.Compare(vs[i], vs[j]); cmp != 0 {
+				t.Errorf("%q and %q should be equal, but Compare returned %d", group[i], group[j], cmp)
+			}
+		}
+	}
+}
+
+// AssertTotalOrder checks that version.Compare behaves as a total order on
+// versions, which may be in any order. Each version must be equal to itself,
+// and for a fixed, pseudo-random sample of triples of versions, Compare must
+// be antisymmetric and transitive. The sample is the same on every run, so
+// failures can be reproduced.
+func AssertTotalOrder(t T, versions []*version.Version) {
+	t.Helper()
+
+	for _, v := range versions {
+		if cmp := version.Compare(v, v); cmp != 0 {
+			t.Errorf("%s should be equal to itself, but Compare returned %d", v, cmp)
+		}
+	}
+	if len(versions) == 0 {
+		return
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < spotChecks; i++ {
+		a := versions[r.Intn(len(versions))]
+		b := versions[r.Intn(len(versions))]
+		c := versions[r.Intn(len(versions))]
+
+		ab, bc, ac := sign(version.Compare(a, b)), sign(version.Compare(b, c)), sign(version.Compare(a, c))
+		if ba := sign(version.Compare(b, a)); ab != -ba {
+			t.Errorf("Compare is not antisymmetric: Compare(%s, %s) is %d but Compare(%s, %s) is %d", a, b, ab, b, a, ba)
+		}
+		if ab == bc && ac != ab {
+			t.Errorf("Compare is not transitive: Compare(%s, %s) and Compare(%s, %s) are %d, but Compare(%s, %s) is %d", a, b, b, c, ab, a, c, ac)
+		}
+		if ab <= 0 && bc <= 0 && ac > 0 {
+			t.Errorf("Compare is not transitive: %s <= %s <= %s, but Compare(%s, %s) is %d", a, b, c, a, c, ac)
+		}
+	}
+}
+
+func parseAll(t T, parse ParseFunc, inputs []string) []*version.Version {
+	t.Helper()
+
+	vs := make([]*version.Version, len(inputs))
+	for i, s := range inputs {
+		v, err := parse(s)
+		if err != nil {
+			t.Fatalf("error parsing %q: %s", s, err)
+		}
+		vs[i] = v
+	}
+	return vs
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,99 @@
+package versiontest
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/ActiveState/langtools/pkg/version"
+	"github.com/ericlagergren/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeT records the failures reported to it. Fatalf stops the goroutine it is
+// called from, as it does for a *testing.T.
+type fakeT struct {
+	errors []string
+	fatal  bool
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) Fatalf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+	f.fatal = true
+	runtime.Goexit()
+}
+
+func run(assertion func(T)) *fakeT {
+	f := &fakeT{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assertion(f)
+	}()
+	<-done
+	return f
+}
+
+func parseSemVer(s string) (*version.Version, error) {
+	return version.ParseSemVer(s)
+}
+
+func parseGeneric(s string) (*version.Version, error) {
+	return version.ParseGeneric(s)
+}
+
+func TestAssertOrdered(t *testing.T) {
+	AssertOrdered(t, parseSemVer, []string{"1.0.0-alpha", "1.0.0-beta", "1.0.0", "1.0.1", "2.0.0"})
+
+	f := run(func(t T) { AssertOrdered(t, parseSemVer, []string{"1.0.0", "2.0.0", "1.5.0"}) })
+	assert.False(t, f.fatal)
+	assert.Equal(t, []string{
+		`"2.0.0" should be less than "1.5.0", but Compare returned 1`,
+		`"1.5.0" should be greater than "2.0.0", but Compare returned -1`,
+	}, f.errors)
+
+	f = run(func(t T) { AssertOrdered(t, parseGeneric, []string{"1.0", "1.0.0"}) })
+	assert.Len(t, f.errors, 2, "equal versions are not in strictly ascending order")
+
+	f = run(func(t T) { AssertOrdered(t, parseSemVer, []string{"1.0.0", "1.0"}) })
+	assert.True(t, f.fatal)
+	require.Len(t, f.errors, 1)
+	assert.Contains(t, f.errors[0], `error parsing "1.0"`)
+}
+
+func TestAssertAllEqual(t *testing.T) {
+	AssertAllEqual(t, parseGeneric, []string{"1", "1.0", "1.0.0", "1-0"})
+
+	f := run(func(t T) { AssertAllEqual(t, parseGeneric, []string{"1.0", "1.0.0", "1.1"}) })
+	assert.False(t, f.fatal)
+	assert.Len(t, f.errors, 4, "1.1 is reported against each of the others in both directions")
+}
+
+func TestAssertTotalOrder(t *testing.T) {
+	var vs []*version.Version
+	for _, s := range []string{"1.0", "1.0.0", "2.0", "0.1", "1.0-rc1", "1.0a"} {
+		v, err := version.ParseGeneric(s)
+		require.NoError(t, err)
+		vs = append(vs, v)
+	}
+	AssertTotalOrder(t, vs)
+	AssertTotalOrder(t, nil)
+
+	// A version with a NaN segment is not equal to itself and breaks
+	// antisymmetry.
+	nan := &version.Version{
+		Original: "nan",
+		Decimal:  []*decimal.Big{new(decimal.Big).SetNaN(false)},
+		ParsedAs: version.Generic,
+	}
+
+	f := run(func(t T) { AssertTotalOrder(t, append(vs, nan)) })
+	assert.NotEmpty(t, f.errors)
+}
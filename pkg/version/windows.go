@@ -0,0 +1,44 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// windowsFileVersionRegEx matches the four numbers of a file version,
+// separated by "." or ",", and an optional build tag in parentheses.
+var windowsFileVersionRegEx = regexp.MustCompile(
+	`^\s*([0-9]+)\s*[.,]\s*([0-9]+)\s*[.,]\s*([0-9]+)\s*[.,]\s*([0-9]+)` +
+		`(?:\s*\(([^()]*)\))?\s*$`)
+
+// windowsFileVersionMaxPart is the largest value that a part of a file
+// version may have, as each part is a 16 bit word of VS_FIXEDFILEINFO.
+const windowsFileVersionMaxPart = 65535
+
+// ParseWindowsFileVersion parses the file or product version of a Windows PE
+// file's version resource, such as "10.0.19041.3636", "6, 1, 7601, 17514" or
+// "10.0.19041.1 (WinBuild.160101.0800)". The version must have four numbers
+// from 0 to 65535, separated by "." or ",", and may be followed by a build
+// tag in parentheses. The build tag is ignored when comparing versions, and
+// is stored in the BuildMetadata field of the returned version.
+func ParseWindowsFileVersion(version string) (*Version, error) {
+	m := windowsFileVersionRegEx.FindStringSubmatch(version)
+	if m == nil {
+		return nil, fmt.Errorf("invalid windows file version: %s", version)
+	}
+
+	segments := m[1:5]
+	for _, part := range segments {
+		if n, err := strconv.Atoi(part); err != nil || n > windowsFileVersionMaxPart {
+			return nil, fmt.Errorf("invalid windows file version %s: %s is greater than %d", version, part, windowsFileVersionMaxPart)
+		}
+	}
+
+	v, err := fromStringSlice(WindowsFileVersion, version, segments)
+	if err != nil {
+		return nil, err
+	}
+	v.BuildMetadata = m[5]
+	return v, nil
+}
@@ -0,0 +1,74 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseWindowsFileVersionOrFatal(t *testing.T, v string) *Version {
+	ver, err := ParseWindowsFileVersion(v)
+	require.NoError(t, err, "no error parsing %v as a Windows file version", v)
+	return ver
+}
+
+func TestParseWindowsFileVersion(t *testing.T) {
+	tests := map[string]struct {
+		segments []string
+		buildTag string
+	}{
+		"10.0.19041.3636":                          {[]string{"10", "0", "19041", "3636"}, ""},
+		"6, 1, 7601, 17514":                        {[]string{"6", "1", "7601", "17514"}, ""},
+		"6,1,7601,17514":                           {[]string{"6", "1", "7601", "17514"}, ""},
+		"10.0.19041.1 (WinBuild.160101.0800)":      {[]string{"10", "0", "19041", "1"}, "WinBuild.160101.0800"},
+		"6.1.7601.17514 (win7sp1_rtm.101119-1850)": {[]string{"6", "1", "7601", "17514"}, "win7sp1_rtm.101119-1850"},
+		"1.0.0.0":                 {[]string{"1"}, ""},
+		"65535.65535.65535.65535": {[]string{"65535", "65535", "65535", "65535"}, ""},
+		"01.002.0003.00004":       {[]string{"1", "2", "3", "4"}, ""},
+	}
+	for in, expected := range tests {
+		v := parseWindowsFileVersionOrFatal(t, in)
+		assert.Equal(t, in, v.Original)
+		assert.Equal(t, WindowsFileVersion, v.ParsedAs, in)
+		assert.Equal(t, mustStringsToDecimal(t, expected.segments), v.Decimal, in)
+		assert.Equal(t, expected.buildTag, v.BuildMetadata, in)
+	}
+}
+
+var testParseWindowsFileVersionOrderInputs = []string{
+	"5.1.2600.5512",
+	"6.0.6002.18005",
+	"6, 1, 7600, 16385",
+	"6.1.7601.17514 (win7sp1_rtm.101119-1850)",
+	"6.1.7601.24545",
+	"6.3.9600.16384",
+	"10.0.10240.16384",
+	"10.0.19041.1 (WinBuild.160101.0800)",
+	"10.0.19041.3636",
+	"10, 0, 22621, 2506",
+}
+
+var testParseWindowsFileVersionEqualInputs = [][]string{
+	{"6.1.7601.17514", "6, 1, 7601, 17514"},
+	{"6.1.7601.17514", "6.1.7601.17514 (win7sp1_rtm.101119-1850)"},
+	{"10.0.0.0", "10, 0, 0, 0"},
+}
+
+func TestParseWindowsFileVersionErrors(t *testing.T) {
+	for _, in := range []string{"", "10.0.19041", "10.0.19041.1.2", "10.0.19041.1a", "v10.0.19041.1", "10..0.19041", "-1.0.0.0", "10.0.19041.1 WinBuild", "10.0.19041.1 (WinBuild", "(WinBuild) 10.0.19041.1"} {
+		_, err := ParseWindowsFileVersion(in)
+		if assert.Error(t, err, in) {
+			assert.Equal(t, "invalid windows file version: "+in, err.Error(), in)
+		}
+	}
+
+	for _, in := range []string{"65536.0.0.0", "1.0.0.99999999999999999999"} {
+		_, err := ParseWindowsFileVersion(in)
+		assert.Error(t, err, in)
+	}
+	_, err := ParseWindowsFileVersion("6, 1, 70000, 1")
+	if assert.Error(t, err) {
+		assert.Equal(t, "invalid windows file version 6, 1, 70000, 1: 70000 is greater than 65535", err.Error())
+	}
+}